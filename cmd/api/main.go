@@ -7,51 +7,39 @@ import (
 	"log"
 	"time"
 
-	"github.com/joho/godotenv"
 	_ "github.com/yourusername/cloud-file-storage/docs"
 	"github.com/yourusername/cloud-file-storage/internal/api"
 	auth_handlers "github.com/yourusername/cloud-file-storage/internal/api/handlers/auth"
 	files_handler "github.com/yourusername/cloud-file-storage/internal/api/handlers/files"
+	folders_handler "github.com/yourusername/cloud-file-storage/internal/api/handlers/folders"
 	metrics_handler "github.com/yourusername/cloud-file-storage/internal/api/handlers/metrics"
+	notifications_handler "github.com/yourusername/cloud-file-storage/internal/api/handlers/notifications"
+	storage_handler "github.com/yourusername/cloud-file-storage/internal/api/handlers/storage"
+	tus_handler "github.com/yourusername/cloud-file-storage/internal/api/handlers/tus"
 	users_handler "github.com/yourusername/cloud-file-storage/internal/api/handlers/users"
 	"github.com/yourusername/cloud-file-storage/internal/app"
+	archive_service "github.com/yourusername/cloud-file-storage/internal/app/archive"
 	auth_service "github.com/yourusername/cloud-file-storage/internal/app/auth"
-	event_service "github.com/yourusername/cloud-file-storage/internal/app/event"
+	drop_link_service "github.com/yourusername/cloud-file-storage/internal/app/drop_link"
 	file_service "github.com/yourusername/cloud-file-storage/internal/app/file"
 	file_version_service "github.com/yourusername/cloud-file-storage/internal/app/file_version"
+	folder_service "github.com/yourusername/cloud-file-storage/internal/app/folder"
 	magic_link_service "github.com/yourusername/cloud-file-storage/internal/app/magic_link"
-	public_link_service "github.com/yourusername/cloud-file-storage/internal/app/public_link"
+	multipart_upload_service "github.com/yourusername/cloud-file-storage/internal/app/multipart_upload"
+	quota_service "github.com/yourusername/cloud-file-storage/internal/app/quota"
+	reconcile_service "github.com/yourusername/cloud-file-storage/internal/app/reconcile"
 	session_service "github.com/yourusername/cloud-file-storage/internal/app/session"
+	share_service "github.com/yourusername/cloud-file-storage/internal/app/share"
+	tus_upload_service "github.com/yourusername/cloud-file-storage/internal/app/tus_upload"
 	user_service "github.com/yourusername/cloud-file-storage/internal/app/user"
-	"github.com/yourusername/cloud-file-storage/internal/config"
 	"github.com/yourusername/cloud-file-storage/internal/infra/db"
 	"github.com/yourusername/cloud-file-storage/internal/infra/queue"
 	"github.com/yourusername/cloud-file-storage/internal/infra/smtp"
 	"github.com/yourusername/cloud-file-storage/internal/infra/storage"
+	"github.com/yourusername/cloud-file-storage/internal/wiring"
 	"github.com/yourusername/cloud-file-storage/internal/workers"
-	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
-	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
-func initTracer() func() {
-	exporter, err := stdouttrace.New(
-		stdouttrace.WithPrettyPrint(),
-	)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter),
-	)
-	otel.SetTracerProvider(tp)
-
-	return func() {
-		_ = tp.Shutdown(context.Background())
-	}
-}
-
 // @title Cloud file storage
 // @version 1.0
 // @BasePath /api/v1
@@ -59,18 +47,15 @@ func initTracer() func() {
 // @in header
 // @name Authorization
 func main() {
-	shutdown := initTracer()
+	shutdown := wiring.InitTracer()
 	defer shutdown()
 
-	if err := godotenv.Load(".env"); err != nil {
-		log.Printf("Warning: .env file not found: %v", err)
-	}
 	httpAddr := ""
 	if v := flag.Lookup("http.addr"); v != nil {
 		httpAddr = v.Value.String()
 	}
 
-	cfg, err := config.Load("configs/config.base.yaml", "configs/config.dev.yaml", httpAddr)
+	cfg, err := wiring.LoadConfig(httpAddr)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -87,34 +72,43 @@ func main() {
 	eventConsuer := queue.NewKafkaEventConsumer(eventReader)
 	previewConsumer := queue.NewKafkaPreviewConsumer(reader)
 	previewProducer := queue.NewKafkaPreviewProducer(writer)
+	_, bucketEventReader := queue.NewMockQueue()
+	bucketEventConsumer := queue.NewKafkaBucketEventConsumer(bucketEventReader)
 
-	eventQueryRepository := db.NewEventQueryRepository(dbConn)
 	magicLinkQueryRepo := db.NewMagicLinkQueryRepository(dbConn)
 	sessionQueryRepo := db.NewSessionQueryRepository(dbConn)
 	userQueryRepo := db.NewUserQueryRepository(dbConn)
 	fileVersionQueryRepo := db.NewFileVersionQueryRepository(dbConn)
 	fileQueryRepo := db.NewFileQueryRepository(dbConn)
-	publicLinkQueryRepository := db.NewPublicLinkQueryRepository(dbConn)
+	fileTagQueryRepo := db.NewFileTagQueryRepository(dbConn)
+	folderQueryRepo := db.NewFolderQueryRepository(dbConn)
+	tusQueryRepo := db.NewTusUploadQueryRepository(dbConn)
+	multipartQueryRepo := db.NewMultipartUploadQueryRepository(dbConn)
+	quotaQueryRepo := db.NewQuotaQueryRepository(dbConn)
+	archiveQueryRepo := db.NewArchiveQueryRepository(dbConn)
+	shareQueryRepo := db.NewShareQueryRepository(dbConn)
+	dropLinkQueryRepo := db.NewDropLinkQueryRepository(dbConn)
 
-	eventCommandRepository := db.NewEventCommandRepository()
 	magicLinkCommandRepo := db.NewMagicLinkCommandRepository()
 	sessionCommandRepo := db.NewSessionCommandRepository()
 	userCommandRepo := db.NewUserCommandRepository()
 	fileVersionCommandRepo := db.NewFileVersionCommandRepository()
 	fileCommandRepo := db.NewFileCommandRepository()
-	publicLinkCommandRepository := db.NewPublicLinkCommandRepository()
+	fileTagCommandRepo := db.NewFileTagCommandRepository()
+	folderCommandRepo := db.NewFolderCommandRepository()
+	multipartCommandRepo := db.NewMultipartUploadCommandRepository()
+	tusCommandRepo := db.NewTusUploadCommandRepository()
+	blobCommandRepo := db.NewBlobCommandRepository()
+	quotaCommandRepo := db.NewQuotaCommandRepository()
+	archiveCommandRepo := db.NewArchiveCommandRepository()
+	shareCommandRepo := db.NewShareCommandRepository()
+	dropLinkCommandRepo := db.NewDropLinkCommandRepository()
 
 	uow := app.NewUnitOfWork(dbConn)
 
-	s3, err := storage.NewS3Storage(
-		cfg.Immutable.S3.Endpoint,
-		cfg.Immutable.S3.Bucket,
-		cfg.Immutable.S3.AccessKeyID,
-		cfg.Immutable.S3.SecretAccessKey,
-		cfg.Immutable.S3.Region,
-	)
+	objectStorage, signedStorage, err := storage.NewFromConfig(cfg.Immutable)
 	if err != nil {
-		log.Fatalf("S3 init failed: %v", err)
+		log.Fatalf("Storage init failed: %v", err)
 	}
 
 	mailSender := smtp.NewSMTPMailSender(
@@ -125,12 +119,27 @@ func main() {
 		cfg.Immutable.SMTP.Password,
 	)
 	fmt.Println("Key ", cfg.Immutable.S3.SecretAccessKey)
-	eventService := event_service.NewEventService(eventQueryRepository, eventCommandRepository, eventProducer, "1", *uow)
+	eventService := wiring.NewEventService(dbConn, eventProducer, "1", uow)
 	magicLinkService := magic_link_service.NewMagicLinkService(magicLinkQueryRepo, magicLinkCommandRepo, eventService, *uow)
 	sessionService := session_service.NewSessionService(sessionQueryRepo, sessionCommandRepo, eventService, *uow)
-	versionService := file_version_service.NewFileVersionService(fileQueryRepo, fileCommandRepo, fileVersionQueryRepo, fileVersionCommandRepo, s3, previewConsumer, previewProducer, eventService, *uow)
-	fileService := file_service.NewFileService(fileQueryRepo, fileCommandRepo, fileVersionQueryRepo, fileVersionCommandRepo, eventService, *uow)
-	publicLinkService := public_link_service.NewPublicLinkService(publicLinkQueryRepository, publicLinkCommandRepository, eventService, *uow)
+	quotaService := quota_service.NewQuotaService(quotaQueryRepo, quotaCommandRepo, cfg.Immutable.Quota.DefaultLimit)
+	shareService := share_service.NewShareService(shareQueryRepo, shareCommandRepo, fileQueryRepo, userQueryRepo, eventService, *uow)
+	versionService := file_version_service.NewFileVersionService(fileQueryRepo, fileCommandRepo, fileVersionQueryRepo, fileVersionCommandRepo, blobCommandRepo, quotaService, objectStorage, previewConsumer, previewProducer, eventService, *uow)
+	fileService := file_service.NewFileService(fileQueryRepo, fileCommandRepo, fileTagQueryRepo, fileTagCommandRepo, fileVersionQueryRepo, fileVersionCommandRepo, blobCommandRepo, folderQueryRepo, shareService, quotaService, objectStorage, eventService, *uow)
+	multipartService := multipart_upload_service.NewMultipartUploadService(multipartQueryRepo, multipartCommandRepo, versionService, objectStorage, eventService, *uow, cfg.Immutable.Multipart.UploadTTL)
+	tusService := tus_upload_service.NewTusUploadService(tusQueryRepo, tusCommandRepo, versionService, fileService, objectStorage, eventService, *uow)
+	folderService := folder_service.NewFolderService(folderQueryRepo, folderCommandRepo, fileService, eventService, *uow)
+	publicLinkService, err := wiring.NewPublicLinkService(dbConn, cfg.Immutable, eventService, uow)
+	if err != nil {
+		log.Fatalf("Public link service init failed: %v", err)
+	}
+	dropLinkService := drop_link_service.NewDropLinkService(dropLinkQueryRepo, dropLinkCommandRepo, versionService, eventService, *uow)
+	signedURLService, err := wiring.NewSignedURLService(dbConn, cfg.Immutable, objectStorage, eventService, uow)
+	if err != nil {
+		log.Fatalf("Signed URL service init failed: %v", err)
+	}
+	reconcileService := reconcile_service.NewReconcileService(fileVersionQueryRepo, objectStorage, cfg.Immutable.Reconciler.Grace, cfg.Immutable.Reconciler.Batch)
+	archiveService := archive_service.NewArchiveService(archiveQueryRepo, archiveCommandRepo, fileQueryRepo, fileVersionQueryRepo, folderQueryRepo, shareService, objectStorage, eventService, *uow, cfg.Immutable.Archive.SyncLimit, cfg.Immutable.Archive.LinkTTL, cfg.Immutable.Archive.Retention)
 	userService := user_service.NewUserService(userQueryRepo, userCommandRepo, eventService, *uow, fileService, sessionService)
 	authService := auth_service.NewAuthService(
 		magicLinkService,
@@ -140,21 +149,43 @@ func main() {
 		mailSender,
 	)
 
-	previewWorker := workers.NewPreviewWorker(s3, previewConsumer, versionService)
-	fileChecker := workers.NewFileChecker(versionService, *uow, s3, time.Second*50)
+	previewWorker := workers.NewPreviewWorker(objectStorage, previewConsumer, versionService)
+	fileChecker := workers.NewFileChecker(versionService, *uow, objectStorage, cfg.Immutable.FileChecker.Interval, cfg.Immutable.FileChecker.Grace, cfg.Immutable.FileChecker.Batch)
+	bucketNotificationWorker := workers.NewBucketNotificationWorker(bucketEventConsumer, versionService, time.Second*5)
 	metricWorker := workers.NewMetricsWorker(eventConsuer, time.Second*5)
 	publishWorker := workers.NewPublishEventsWorker(eventService, time.Second*5, 5, 3)
+	trashPurgeWorker := workers.NewTrashPurgeWorker(fileService, cfg.Immutable.Trash.Retention, cfg.Immutable.Trash.PurgeInterval, cfg.Immutable.Trash.PurgeBatch)
+	multipartAbortWorker := workers.NewMultipartAbortWorker(multipartService, cfg.Immutable.Multipart.AbortInterval, cfg.Immutable.Multipart.AbortBatch)
+	uploadReaperWorker := workers.NewUploadReaperWorker(versionService, cfg.Immutable.UploadReaper.AbandonAfter, cfg.Immutable.UploadReaper.Interval, cfg.Immutable.UploadReaper.Batch)
+	reconcilerWorker := workers.NewReconcilerWorker(reconcileService, cfg.Immutable.Reconciler.Interval, cfg.Immutable.Reconciler.Delete)
+	archiveWorker := workers.NewArchiveWorker(archiveService, cfg.Immutable.Archive.Interval, cfg.Immutable.Archive.Batch)
 
 	authHandler := auth_handlers.NewAuthHandler(authService, sessionService)
-	userHandler := users_handler.NewUserHandler(userService)
-	fileHandler := files_handler.NewFileHandler(versionService, fileService, publicLinkService)
+	userHandler := users_handler.NewUserHandler(userService, quotaService)
+	fileHandler := files_handler.NewFileHandler(versionService, fileService, publicLinkService, folderService, multipartService, archiveService, shareService, dropLinkService, signedURLService)
+	folderHandler := folders_handler.NewFolderHandler(folderService)
+	tusHandler := tus_handler.NewTusHandler(tusService, folderService)
 	metricHandler := metrics_handler.NewMetricsHandler()
-	server := api.NewServer(authHandler, userHandler, fileHandler, metricHandler, authService)
+	var storageHandler *storage_handler.StorageHandler
+	if signedStorage != nil {
+		storageHandler = storage_handler.NewStorageHandler(signedStorage)
+	}
+	var notificationHandler *notifications_handler.NotificationHandler
+	if cfg.Immutable.BucketNotifications.WebhookToken != "" {
+		notificationHandler = notifications_handler.NewNotificationHandler(versionService, cfg.Immutable.BucketNotifications.WebhookToken)
+	}
+	server := api.NewServer(authHandler, userHandler, fileHandler, folderHandler, tusHandler, storageHandler, notificationHandler, metricHandler, authService)
 
 	go previewWorker.Handle(context.Background())
 	go fileChecker.Start(context.Background())
+	go bucketNotificationWorker.Handle(context.Background())
 	go publishWorker.Start(context.Background())
 	go metricWorker.Start(context.Background())
+	go trashPurgeWorker.Start(context.Background())
+	go multipartAbortWorker.Start(context.Background())
+	go uploadReaperWorker.Start(context.Background())
+	go reconcilerWorker.Start(context.Background())
+	go archiveWorker.Start(context.Background())
 
 	if err := server.Run(cfg.Immutable.HTTP.Addr); err != nil {
 		log.Fatalf("Server failed to start: %v", err)
@@ -1 +1,55 @@
-package api
+package main
+
+import (
+	"log"
+	"sync"
+
+	"github.com/yourusername/cloud-file-storage/internal/app"
+	"github.com/yourusername/cloud-file-storage/internal/infra/db"
+	"github.com/yourusername/cloud-file-storage/internal/wiring"
+	"github.com/yourusername/cloud-file-storage/internal/workers"
+)
+
+// link-expirer периодически удаляет или архивирует истёкшие публичные ссылки.
+// События PublicLinkExpired пишутся в outbox, публикует их воркер API.
+func main() {
+	cfg, err := wiring.LoadConfig("")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dbConn, err := db.Connect()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer dbConn.Close()
+
+	ctx, stop := wiring.ShutdownContext()
+	defer stop()
+
+	uow := app.NewUnitOfWork(dbConn)
+	eventService := wiring.NewEventService(dbConn, nil, "link-expirer", uow)
+	publicLinkService, err := wiring.NewPublicLinkService(dbConn, cfg.Immutable, eventService, uow)
+	if err != nil {
+		log.Fatalf("Public link service init failed: %v", err)
+	}
+
+	expirerCfg := cfg.Immutable.LinkExpirer
+	worker := workers.NewLinkExpirerWorker(publicLinkService, expirerCfg.Interval, expirerCfg.Retention, expirerCfg.Batch, expirerCfg.Archive)
+
+	var wg sync.WaitGroup
+	if expirerCfg.MetricsAddr != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := wiring.ServeMetrics(ctx, expirerCfg.MetricsAddr); err != nil {
+				log.Printf("Metrics server failed: %v", err)
+				stop()
+			}
+		}()
+	}
+
+	worker.Start(ctx)
+	wg.Wait()
+	log.Println("link-expirer stopped")
+}
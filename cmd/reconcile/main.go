@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/joho/godotenv"
+	reconcile_service "github.com/yourusername/cloud-file-storage/internal/app/reconcile"
+	"github.com/yourusername/cloud-file-storage/internal/config"
+	"github.com/yourusername/cloud-file-storage/internal/infra/db"
+	"github.com/yourusername/cloud-file-storage/internal/infra/storage"
+)
+
+// reconcile сверяет бакет с БД один раз и печатает отчёт. По умолчанию ничего не удаляет:
+// сначала стоит просмотреть список сирот, затем запустить с -delete.
+func main() {
+	deleteOrphans := flag.Bool("delete", false, "delete orphaned objects older than grace")
+	grace := flag.Duration("grace", 24*time.Hour, "skip objects modified more recently than this")
+	batch := flag.Int("batch", 1000, "objects per listing page and versions per database page")
+	flag.Parse()
+
+	if err := godotenv.Load(".env"); err != nil {
+		log.Printf("Warning: .env file not found: %v", err)
+	}
+
+	cfg, err := config.Load("configs/config.base.yaml", "configs/config.dev.yaml", "")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dbConn, err := db.Connect()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer dbConn.Close()
+
+	objectStorage, _, err := storage.NewFromConfig(cfg.Immutable)
+	if err != nil {
+		log.Fatalf("Storage init failed: %v", err)
+	}
+
+	reconcileService := reconcile_service.NewReconcileService(
+		db.NewFileVersionQueryRepository(dbConn),
+		objectStorage,
+		*grace,
+		*batch,
+	)
+
+	report, err := reconcileService.Reconcile(context.Background(), *deleteOrphans)
+	for _, o := range report.Orphans {
+		log.Printf("orphan %s (%d bytes, modified %s)", o.Key, o.Size, o.LastModified.UTC().Format(time.RFC3339))
+	}
+	for _, m := range report.Missing {
+		log.Printf("missing %s (version %s, file %s)", m.Key, m.VersionID, m.FileID)
+	}
+	if err != nil {
+		log.Fatalf("Reconcile failed after %d objects: %v", report.Scanned, err)
+	}
+	log.Printf("Scanned %d objects: %d orphaned, %d deleted, %d versions without object",
+		report.Scanned, len(report.Orphans), report.Deleted, len(report.Missing))
+}
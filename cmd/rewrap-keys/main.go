@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/joho/godotenv"
+	"github.com/yourusername/cloud-file-storage/internal/app"
+	key_rotation_service "github.com/yourusername/cloud-file-storage/internal/app/key_rotation"
+	"github.com/yourusername/cloud-file-storage/internal/config"
+	"github.com/yourusername/cloud-file-storage/internal/domain/encryption"
+	"github.com/yourusername/cloud-file-storage/internal/infra/db"
+	"github.com/yourusername/cloud-file-storage/internal/infra/storage"
+)
+
+// rewrap-keys переоборачивает ключи данных всех объектов текущим мастер-ключом.
+// Порядок ротации: добавить новый ключ в файл ключей и сделать его current, перезапустить API,
+// запустить rewrap-keys и только после этого удалить старый ключ из файла.
+func main() {
+	batch := flag.Int("batch", 100, "versions per database page")
+	flag.Parse()
+
+	if err := godotenv.Load(".env"); err != nil {
+		log.Printf("Warning: .env file not found: %v", err)
+	}
+
+	cfg, err := config.Load("configs/config.base.yaml", "configs/config.dev.yaml", "")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !cfg.Immutable.Storage.Encryption.Enabled {
+		log.Fatal("storage encryption is disabled, nothing to rewrap")
+	}
+
+	dbConn, err := db.Connect()
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer dbConn.Close()
+
+	objectStorage, _, err := storage.NewFromConfig(cfg.Immutable)
+	if err != nil {
+		log.Fatalf("Storage init failed: %v", err)
+	}
+	rewrapper, ok := objectStorage.(encryption.ObjectRewrapper)
+	if !ok {
+		log.Fatal("storage does not support key rewrapping")
+	}
+
+	uow := app.NewUnitOfWork(dbConn)
+	rotationService := key_rotation_service.NewKeyRotationService(
+		db.NewFileVersionQueryRepository(dbConn),
+		db.NewFileVersionCommandRepository(),
+		rewrapper,
+		*uow,
+		*batch,
+	)
+
+	result, err := rotationService.RewrapAll(context.Background())
+	if err != nil {
+		log.Fatalf("Rewrap failed after %d versions: %v", result.Rewrapped, err)
+	}
+	log.Printf("Rewrapped %d versions to key %q, %d versions have no object", result.Rewrapped, rewrapper.CurrentKeyID(), result.Missing)
+}
@@ -61,19 +61,19 @@ func (h *AuthHandler) VerifyMagicLink(ctx *gin.Context) {
 	clientIP := ctx.ClientIP()
 	ip := net.ParseIP(clientIP)
 
-	session, err := h.authSrv.Authenticate(ctx, token, ip)
+	session, tokens, err := h.authSrv.Authenticate(ctx, token, ip)
 	if err != nil {
 
 		_ = ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, PresentVerify(session))
+	ctx.JSON(http.StatusOK, PresentVerify(session, tokens))
 }
 
 // RefreshToken godoc
 // @Summary Refresh access token
-// @Description Get new access token using refresh token
+// @Description Exchange the refresh token for a new token pair; the old pair stops working
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -88,13 +88,13 @@ func (h *AuthHandler) RefreshToken(ctx *gin.Context) {
 		return
 	}
 
-	session, err := h.sessionSrv.RefreshAccessToken(ctx, req.RefreshToken)
+	session, tokens, err := h.sessionSrv.RefreshAccessToken(ctx, req.RefreshToken)
 	if err != nil {
 		_ = ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, PresentRefresh(session))
+	ctx.JSON(http.StatusOK, PresentRefresh(session, tokens))
 }
 
 // Logout godoc
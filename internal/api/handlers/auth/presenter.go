@@ -3,6 +3,7 @@ package auth_handlers
 import (
 	"time"
 
+	session_service "github.com/yourusername/cloud-file-storage/internal/app/session"
 	domainSession "github.com/yourusername/cloud-file-storage/internal/domain/session"
 )
 
@@ -12,20 +13,21 @@ func PresentRequestMagicLinkOK() RequestMagicLinkResponse {
 	return RequestMagicLinkResponse{Message: "magic link sent to email"}
 }
 
-func PresentVerify(session *domainSession.Session) VerifyMagicLinkResponse {
+// PresentVerify токены есть только в этом ответе и в ответе на refresh: в БД лежат их хеши
+func PresentVerify(session *domainSession.Session, tokens *session_service.Tokens) VerifyMagicLinkResponse {
 	return VerifyMagicLinkResponse{
 		Message:      "successfully authenticated",
 		SessionID:    session.ID.String(),
-		AccessToken:  session.TokenHash.String(),
-		RefreshToken: session.RefreshTokenHash.String(),
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
 		ExpiresAt:    session.ExpiresAt.Time().UTC().Format(timeFmt),
 	}
 }
-func PresentRefresh(session *domainSession.Session) RefreshTokenResponse {
+func PresentRefresh(session *domainSession.Session, tokens *session_service.Tokens) RefreshTokenResponse {
 	return RefreshTokenResponse{
 		Message:      "tokens refreshed",
-		AccessToken:  session.TokenHash.String(),
-		RefreshToken: session.RefreshTokenHash.String(),
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
 		ExpiresAt:    session.ExpiresAt.Time().UTC().Format(timeFmt),
 	}
 }
@@ -0,0 +1,140 @@
+package files_handler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/api/middleware"
+	archive_service "github.com/yourusername/cloud-file-storage/internal/app/archive"
+)
+
+// CreateArchive godoc
+// @Summary Download files as ZIP archive
+// @Description Build a ZIP archive from selected files (optionally specific versions) and whole folders. Archives up to the sync threshold are streamed in the response. Larger archives are built in the background: the response is 202 with an archive to poll via GET /files/archive/{archive_id}. Files with the same name get a " (1)" suffix.
+// @Tags files
+// @Security Bearer
+// @Accept json
+// @Produce application/zip
+// @Produce json
+// @Param request body ArchiveInput true "Files and folders to archive"
+// @Success 200 {file} binary "ZIP archive"
+// @Success 202 {object} ArchiveResponse "Archive is being built"
+// @Failure 400 {object} map[string]string "Invalid request or too many files"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 404 {object} map[string]string "File, version or folder not found"
+// @Failure 409 {object} map[string]string "Version content is not uploaded"
+// @Failure 500 {object} map[string]string "Failed to build archive"
+// @Router /files/archive [post]
+func (h *FileHandler) CreateArchive(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var input ArchiveInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	items := make([]archive_service.Item, 0, len(input.Files))
+	for _, f := range input.Files {
+		fileID, err := uuid.Parse(f.FileID)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid file_id format"})
+			return
+		}
+		item := archive_service.Item{FileID: fileID}
+		if f.VersionNum != nil {
+			item.VersionNum = *f.VersionNum
+		}
+		items = append(items, item)
+	}
+
+	folderIDs := make([]uuid.UUID, 0, len(input.FolderIDs))
+	for _, raw := range input.FolderIDs {
+		folderID, err := uuid.Parse(raw)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid folder_id format"})
+			return
+		}
+		folderIDs = append(folderIDs, folderID)
+	}
+
+	a, err := h.archiveService.Plan(ctx, userID, items, folderIDs)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if h.archiveService.Streamable(a) {
+		ctx.Header("Content-Type", "application/zip")
+		ctx.Header("Content-Disposition", `attachment; filename="archive.zip"`)
+		ctx.Status(http.StatusOK)
+		if err := h.archiveService.Write(ctx.Request.Context(), ctx.Writer, a.Entries); err != nil {
+			// Заголовки уже отправлены: клиент получит оборванный архив, сообщить об ошибке можно только в лог
+			log.Printf("failed to stream archive for user %s: %v", userID, err)
+			ctx.Abort()
+		}
+		return
+	}
+
+	if err := h.archiveService.Enqueue(ctx, a); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, PresentArchive(a, "", 0))
+}
+
+// GetArchive godoc
+// @Summary Get archive status
+// @Description Status of an archive built in the background. A ready archive includes a short-lived download URL.
+// @Tags files
+// @Security Bearer
+// @Produce json
+// @Param archive_id path string true "Archive ID" format(uuid)
+// @Success 200 {object} ArchiveResponse "Archive status"
+// @Failure 400 {object} map[string]string "Invalid archive_id format"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Archive not found"
+// @Failure 410 {object} map[string]string "Archive has expired"
+// @Router /files/archive/{archive_id} [get]
+func (h *FileHandler) GetArchive(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	archiveID, err := uuid.Parse(ctx.Param("archive_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid archive_id format"})
+		return
+	}
+
+	a, err := h.archiveService.GetByID(ctx, archiveID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if a.OwnerID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		return
+	}
+
+	var downloadURL string
+	if a.IsReady() {
+		downloadURL, err = h.archiveService.GetDownloadURL(ctx, a)
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, PresentArchive(a, downloadURL, h.archiveService.LinkTTL()))
+}
@@ -0,0 +1,95 @@
+package files_handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/api/middleware"
+	file_service "github.com/yourusername/cloud-file-storage/internal/app/file"
+)
+
+// BatchFiles godoc
+// @Summary Apply operations to several files
+// @Description Apply delete, rename, move, restore and tag operations in one request. Tag adds add_tags and removes remove_tags; tags are case-insensitive and stored in lower case. Each operation checks ownership on its own and gets its own result with the error code ErrorMiddleware would return for a single request. With atomic=true all operations run in one transaction and the first failure rolls back the whole batch (other operations get BATCH_ABORTED). A file may appear in a batch only once.
+// @Tags files
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body BatchInput true "Operations"
+// @Success 200 {object} BatchResponse "Per-operation results in request order"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Router /files/batch [post]
+func (h *FileHandler) BatchFiles(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var input BatchInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ops := make([]file_service.BatchOperation, 0, len(input.Operations))
+	seen := make(map[uuid.UUID]bool, len(input.Operations))
+	for _, in := range input.Operations {
+		fileID, err := uuid.Parse(in.FileID)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid file_id format"})
+			return
+		}
+		if seen[fileID] {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "file_id must be unique within a batch"})
+			return
+		}
+		seen[fileID] = true
+
+		op := file_service.BatchOperation{Action: file_service.BatchAction(in.Op), FileID: fileID}
+		switch op.Action {
+		case file_service.BatchRename:
+			if in.Name == nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "name is required for rename"})
+				return
+			}
+			op.Name = *in.Name
+		case file_service.BatchMove:
+			if in.FolderID != nil && *in.FolderID != "root" {
+				folderID, err := uuid.Parse(*in.FolderID)
+				if err != nil {
+					ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid folder_id format"})
+					return
+				}
+				op.FolderID = &folderID
+			}
+		case file_service.BatchTag:
+			if len(in.AddTags) == 0 && len(in.RemoveTags) == 0 {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "add_tags or remove_tags is required for tag"})
+				return
+			}
+			op.AddTags = in.AddTags
+			op.RemoveTags = in.RemoveTags
+		}
+		ops = append(ops, op)
+	}
+
+	results := h.fileService.Batch(ctx, userID, ops, input.Atomic)
+
+	resp := BatchResponse{Results: make([]BatchItemResponse, 0, len(results))}
+	for i, r := range results {
+		item := BatchItemResponse{FileID: input.Operations[i].FileID, Op: input.Operations[i].Op, OK: r.Err == nil}
+		if r.Err != nil {
+			status, code, message := middleware.MapError(r.Err)
+			item.Error = &BatchErrorResponse{Status: status, Code: code, Message: message}
+			resp.Failed++
+		} else {
+			resp.Succeeded++
+		}
+		resp.Results = append(resp.Results, item)
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,227 @@
+package files_handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/api/middleware"
+	"github.com/yourusername/cloud-file-storage/internal/domain/share"
+)
+
+// CreateDropLink godoc
+// @Summary Create drop link
+// @Description Create an upload-only link: anyone holding it can upload files into a folder
+// @Description or new versions of one file without an account, within the link limits.
+// @Description The token is returned only in this response: the server keeps just its SHA-256 hash.
+// @Tags drop-links
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body DropLinkInput true "Drop link target and limits"
+// @Success 201 {object} DropLinkResponse "Drop link created"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied to folder or file"
+// @Failure 404 {object} map[string]string "Folder or file not found"
+// @Failure 500 {object} map[string]string "Failed to create drop link"
+// @Router /drop-links [post]
+func (h *FileHandler) CreateDropLink(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var input DropLinkInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dur, err := time.ParseDuration(input.ExpiresIn)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid expires_in format (use 15m, 1h, 72h, etc)"})
+		return
+	}
+	expiresAt := time.Now().Add(dur)
+
+	var folderID *uuid.UUID
+	if input.FolderID != nil {
+		var ok bool
+		folderID, ok = h.resolveFolderID(ctx, userID, *input.FolderID)
+		if !ok {
+			return
+		}
+	}
+
+	var fileID *uuid.UUID
+	if input.FileID != nil {
+		id, err := uuid.Parse(*input.FileID)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid file_id format"})
+			return
+		}
+		f, err := h.fileService.GetByID(ctx, id)
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+		if f == nil || f.IsTrashed() {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
+			return
+		}
+		if !h.authorize(ctx, f, userID, share.RoleOwner) {
+			return
+		}
+		fileID = &id
+	}
+
+	link, token, err := h.dropLinkService.Create(ctx, userID, expiresAt, folderID, fileID, input.MaxFiles, input.MaxTotalSize, input.AllowedMimes)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, PresentDropLink(link, token))
+}
+
+// ListDropLinks godoc
+// @Summary List drop links
+// @Description Get the current user's drop links, newest first, including expired ones
+// @Tags drop-links
+// @Security Bearer
+// @Produce json
+// @Success 200 {object} ListDropLinksResponse "Drop links"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 500 {object} map[string]string "Failed to get drop links"
+// @Router /drop-links [get]
+func (h *FileHandler) ListDropLinks(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	links, err := h.dropLinkService.GetByOwnerID(ctx, userID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	resp := PresentDropLinks(links)
+	ctx.JSON(http.StatusOK, ListDropLinksResponse{
+		Links: resp,
+		Total: len(resp),
+	})
+}
+
+// DeleteDropLink godoc
+// @Summary Delete drop link
+// @Description Revoke a drop link; files already uploaded through it are kept
+// @Tags drop-links
+// @Security Bearer
+// @Produce json
+// @Param link_id path string true "Drop Link ID" format(uuid)
+// @Success 200 {object} map[string]string "Link deleted"
+// @Failure 400 {object} map[string]string "Invalid link_id format"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 404 {object} map[string]string "Drop link not found"
+// @Failure 500 {object} map[string]string "Failed to delete drop link"
+// @Router /drop-links/{link_id} [delete]
+func (h *FileHandler) DeleteDropLink(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	linkID, err := uuid.Parse(ctx.Param("link_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid link_id format"})
+		return
+	}
+
+	if err := h.dropLinkService.Delete(ctx, userID, linkID); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Drop link deleted successfully"})
+}
+
+// GetDropLinkInfo godoc
+// @Summary Get drop link limits
+// @Description What can still be uploaded through a drop link (no authentication required)
+// @Tags public
+// @Produce json
+// @Param token path string true "Drop link token"
+// @Success 200 {object} DropLinkInfoResponse "Drop link limits"
+// @Failure 404 {object} map[string]string "Drop link not found or expired"
+// @Router /public-drop-links/{token} [get]
+func (h *FileHandler) GetDropLinkInfo(ctx *gin.Context) {
+	link, err := h.dropLinkService.GetByToken(ctx, ctx.Param("token"))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, PresentDropLinkInfo(link))
+}
+
+// UploadByDropLink godoc
+// @Summary Upload file by drop link
+// @Description Create a file (or a new version of the link's file) on behalf of the link owner and get
+// @Description a presigned URL for the data (no authentication required). The PUT is bound to the declared size.
+// @Tags public
+// @Accept json
+// @Produce json
+// @Param token path string true "Drop link token"
+// @Param request body DropUploadInput true "File metadata"
+// @Success 201 {object} UploadFileResponse "File created with upload URL"
+// @Failure 400 {object} map[string]string "Invalid input or file size exceeds limit"
+// @Failure 403 {object} map[string]string "Drop link file count or total size limit reached"
+// @Failure 404 {object} map[string]string "Drop link not found or expired"
+// @Failure 413 {object} map[string]string "Owner storage quota exceeded"
+// @Failure 415 {object} map[string]string "Mime type is not allowed by the drop link"
+// @Failure 500 {object} map[string]string "Failed to create file"
+// @Router /public-drop-links/{token}/files [post]
+func (h *FileHandler) UploadByDropLink(ctx *gin.Context) {
+	var input DropUploadInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !checkUploadSize(ctx, input.Size, false) {
+		return
+	}
+	checksum, ok := parseChecksum(ctx, input.ChecksumAlgorithm, input.Checksum)
+	if !ok {
+		return
+	}
+
+	file, version, uploadURL, uploadHeaders, err := h.dropLinkService.Upload(
+		ctx,
+		ctx.Param("token"),
+		input.Name,
+		input.Size,
+		input.Mime,
+		checksum,
+	)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, UploadFileResponse{
+		FileID:        file.ID.String(),
+		VersionID:     version.ID.String(),
+		UploadURL:     uploadURL,
+		VersionNum:    version.VersionNum.Int(),
+		Status:        version.Status.String(),
+		ExpiresIn:     "15m",
+		UploadHeaders: uploadHeaders,
+	})
+}
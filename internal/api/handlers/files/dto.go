@@ -1,9 +1,15 @@
 package files_handler
 
 type UploadFileInput struct {
-	Name string `json:"name" binding:"required"`
-	Size uint64 `json:"size" binding:"required,gt=0"`
-	Mime string `json:"mime" binding:"required"`
+	Name     string  `json:"name" binding:"required"`
+	Size     uint64  `json:"size" binding:"required,gt=0"`
+	Mime     string  `json:"mime" binding:"required"`
+	FolderID *string `json:"folder_id" example:"123e4567-e89b-12d3-a456-426614174002"`
+	// Multipart разрешает файлы больше 5GB; данные загружаются через /uploads
+	Multipart bool `json:"multipart" example:"false"`
+	// ChecksumAlgorithm и Checksum необязательны: содержимое сверяется с ними при завершении загрузки
+	ChecksumAlgorithm string `json:"checksum_algorithm" example:"sha256" enums:"sha256,crc32c"`
+	Checksum          string `json:"checksum" example:"47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="`
 }
 
 type UploadFileResponse struct {
@@ -13,6 +19,14 @@ type UploadFileResponse struct {
 	VersionNum int    `json:"version_num" example:"1"`
 	Status     string `json:"status" example:"processing"`
 	ExpiresIn  string `json:"expires_in" example:"15m"`
+	// UploadHeaders заголовки, которые нужно передать в PUT по upload_url
+	UploadHeaders map[string]string `json:"upload_headers,omitempty"`
+}
+
+// ChecksumResponse контрольная сумма, заявленная клиентом при загрузке версии
+type ChecksumResponse struct {
+	Algorithm string `json:"algorithm" example:"sha256"`
+	Value     string `json:"value" example:"47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="`
 }
 
 type FileResponse struct {
@@ -23,23 +37,30 @@ type FileResponse struct {
 	Status       string  `json:"status" example:"ready"`
 	VersionNum   int     `json:"version_num" example:"1"`
 	PreviewS3Key *string `json:"preview_s3_key" example:"files/user-id/file-id/preview.jpg"`
-	CreatedAt    string  `json:"created_at" example:"2025-11-04T12:00:00Z"`
-	UpdatedAt    string  `json:"updated_at" example:"2025-11-04T12:00:00Z"`
+	FolderID     *string `json:"folder_id" example:"123e4567-e89b-12d3-a456-426614174002"`
+	// Checksum текущей версии; nil, если клиент её не заявлял
+	Checksum  *ChecksumResponse `json:"checksum"`
+	CreatedAt string            `json:"created_at" example:"2025-11-04T12:00:00Z"`
+	UpdatedAt string            `json:"updated_at" example:"2025-11-04T12:00:00Z"`
+	DeletedAt *string           `json:"deleted_at,omitempty" example:"2025-11-05T12:00:00Z"`
 }
 
 type FileDetailResponse struct {
-	ID                string  `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
-	OwnerID           string  `json:"owner_id" example:"123e4567-e89b-12d3-a456-426614174000"`
-	Name              string  `json:"name" example:"document.pdf"`
-	Mime              string  `json:"mime" example:"application/pdf"`
-	Size              uint64  `json:"size" example:"1024000"`
-	Status            string  `json:"status" example:"ready"`
-	CurrentVersion    int     `json:"current_version" example:"3"`
-	TotalVersions     int     `json:"total_versions" example:"5"`
-	PreviewS3Key      *string `json:"preview_s3_key" example:"files/user-id/file-id/preview.jpg"`
-	CreatedAt         string  `json:"created_at" example:"2025-11-04T12:00:00Z"`
-	UpdatedAt         string  `json:"updated_at" example:"2025-11-04T12:00:00Z"`
-	UploadedBySession string  `json:"uploaded_by_session_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	ID                string            `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	OwnerID           string            `json:"owner_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name              string            `json:"name" example:"document.pdf"`
+	Mime              string            `json:"mime" example:"application/pdf"`
+	Size              uint64            `json:"size" example:"1024000"`
+	Status            string            `json:"status" example:"ready"`
+	CurrentVersion    int               `json:"current_version" example:"3"`
+	TotalVersions     int               `json:"total_versions" example:"5"`
+	PreviewS3Key      *string           `json:"preview_s3_key" example:"files/user-id/file-id/preview.jpg"`
+	FolderID          *string           `json:"folder_id" example:"123e4567-e89b-12d3-a456-426614174002"`
+	Checksum          *ChecksumResponse `json:"checksum"`
+	Tags              []string          `json:"tags" example:"work"`
+	CreatedAt         string            `json:"created_at" example:"2025-11-04T12:00:00Z"`
+	UpdatedAt         string            `json:"updated_at" example:"2025-11-04T12:00:00Z"`
+	UploadedBySession string            `json:"uploaded_by_session_id" example:"123e4567-e89b-12d3-a456-426614174000"`
 }
 
 type ListFilesResponse struct {
@@ -55,25 +76,47 @@ type GetDownloadURLResponse struct {
 }
 
 type UploadNewVersionInput struct {
-	Name string `json:"name" binding:"required"`
-	Size uint64 `json:"size" binding:"required,gt=0"`
-	Mime string `json:"mime" binding:"required"`
+	Name              string `json:"name" binding:"required"`
+	Size              uint64 `json:"size" binding:"required,gt=0"`
+	Mime              string `json:"mime" binding:"required"`
+	Multipart         bool   `json:"multipart" example:"false"`
+	ChecksumAlgorithm string `json:"checksum_algorithm" example:"sha256" enums:"sha256,crc32c"`
+	Checksum          string `json:"checksum" example:"47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="`
 }
 
 type UpdateFileInput struct {
 	Name string `json:"name" binding:"required,min=1"`
 }
 
+// MoveFileInput folder_id == null переносит файл в корень
+type MoveFileInput struct {
+	FolderID *string `json:"folder_id" example:"123e4567-e89b-12d3-a456-426614174002"`
+}
+
+// CopyFileInput все поля необязательны: без name копия получает имя источника, без folder_id
+// попадает в папку источника ("root" — в корень), без version_num копируется текущая версия
+type CopyFileInput struct {
+	Name       *string `json:"name" binding:"omitempty,min=1,max=255" example:"report (copy).pdf"`
+	FolderID   *string `json:"folder_id" example:"123e4567-e89b-12d3-a456-426614174002"`
+	VersionNum *int    `json:"version_num" binding:"omitempty,gt=0" example:"2"`
+}
+
 type FileVersionResponse struct {
-	ID           string  `json:"id" example:"123e4567-e89b-12d3-a456-426614174001"`
-	FileID       string  `json:"file_id" example:"123e4567-e89b-12d3-a456-426614174000"`
-	VersionNum   int     `json:"version_num" example:"2"`
-	Status       string  `json:"status" example:"ready"`
-	Size         uint64  `json:"size" example:"2048000"`
-	Mime         string  `json:"mime" example:"application/pdf"`
-	PreviewS3Key *string `json:"preview_s3_key" example:"files/user-id/file-id/v2/preview.jpg"`
-	CreatedAt    string  `json:"created_at" example:"2025-11-04T12:00:00Z"`
-	UpdatedAt    string  `json:"updated_at" example:"2025-11-04T12:30:00Z"`
+	ID           string            `json:"id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	FileID       string            `json:"file_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	VersionNum   int               `json:"version_num" example:"2"`
+	Status       string            `json:"status" example:"ready"`
+	Size         uint64            `json:"size" example:"2048000"`
+	Mime         string            `json:"mime" example:"application/pdf"`
+	PreviewS3Key *string           `json:"preview_s3_key" example:"files/user-id/file-id/v2/preview.jpg"`
+	ContentHash  *string           `json:"content_hash" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	Checksum     *ChecksumResponse `json:"checksum"`
+	// FailureReason заполнен у версий в статусе failed
+	FailureReason *string `json:"failure_reason,omitempty" example:"sha256 mismatch: declared ..., uploaded ..."`
+	// DropLinkID ссылка для загрузки, через которую пришла версия
+	DropLinkID *string `json:"drop_link_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174004"`
+	CreatedAt  string  `json:"created_at" example:"2025-11-04T12:00:00Z"`
+	UpdatedAt  string  `json:"updated_at" example:"2025-11-04T12:30:00Z"`
 }
 
 type ListVersionsResponse struct {
@@ -83,15 +126,57 @@ type ListVersionsResponse struct {
 
 type PublicLinkInput struct {
 	ExpiresIn string `json:"expires_in" binding:"required" example:"24h"` // 15m, 1h, 24h, 7d, etc
+	// Password пустой — ссылка открывается без пароля
+	Password string `json:"password,omitempty" binding:"omitempty,min=4,max=72" example:"s3cret"`
+	// MaxDownloads не задан — число скачиваний не ограничено
+	MaxDownloads *int `json:"max_downloads,omitempty" binding:"omitempty,min=1" example:"1"`
+	// VersionMode current — текущая версия файла, latest — новейшая готовая, pinned — VersionID;
+	// не задан — pinned при VersionID, иначе current
+	VersionMode string  `json:"version_mode,omitempty" binding:"omitempty,oneof=current latest pinned" example:"pinned"`
+	VersionID   *string `json:"version_id,omitempty" binding:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174001"`
 }
 
 type PublicLinkResponse struct {
-	ID        string `json:"id" example:"123e4567-e89b-12d3-a456-426614174002"`
-	FileID    string `json:"file_id" example:"123e4567-e89b-12d3-a456-426614174000"`
-	Token     string `json:"token" example:"abc123def456"`
-	IsExpired bool   `json:"is_expired" example:"false"`
-	ExpiresAt string `json:"expires_at" example:"2025-11-05T12:00:00Z"`
-	CreatedAt string `json:"created_at" example:"2025-11-04T12:00:00Z"`
+	ID            string  `json:"id" example:"123e4567-e89b-12d3-a456-426614174002"`
+	FileID        string  `json:"file_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Token         string  `json:"token,omitempty" example:"q5cW2xJb0mZ3kXl8tV1sN9rYwE4aHd7uPgOiFj6KzTc"` // только в ответе на создание
+	IsExpired     bool    `json:"is_expired" example:"false"`
+	HasPassword   bool    `json:"has_password" example:"false"`
+	MaxDownloads  *int    `json:"max_downloads" example:"1"`
+	DownloadCount int     `json:"download_count" example:"0"`
+	VersionMode   string  `json:"version_mode" example:"pinned"`
+	VersionID     *string `json:"version_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	ExpiresAt     string  `json:"expires_at" example:"2025-11-05T12:00:00Z"`
+	CreatedAt     string  `json:"created_at" example:"2025-11-04T12:00:00Z"`
+}
+
+type PublicLinkAccessResponse struct {
+	ID        string  `json:"id" example:"123e4567-e89b-12d3-a456-426614174003"`
+	VersionID *string `json:"version_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	IP        string  `json:"ip" example:"203.0.113.7"`
+	UserAgent string  `json:"user_agent" example:"Mozilla/5.0"`
+	CreatedAt string  `json:"created_at" example:"2025-11-04T12:00:00Z"`
+}
+
+type ListPublicLinkAccessesResponse struct {
+	Accesses []PublicLinkAccessResponse `json:"accesses"`
+	Total    int                        `json:"total" example:"1"`
+}
+
+type UnlockPublicLinkInput struct {
+	Password string `json:"password" form:"password" binding:"required" example:"s3cret"`
+}
+
+type UnlockPublicLinkResponse struct {
+	UnlockToken string `json:"unlock_token" example:"1762340400.9f86d081884c7d65"`
+	ExpiresIn   string `json:"expires_in" example:"10m0s"`
+}
+
+// PublicLinkChallengeResponse ответ на скачивание по ссылке с паролем без действующего токена разблокировки
+type PublicLinkChallengeResponse struct {
+	Code      string `json:"code" example:"PASSWORD_REQUIRED"`
+	Message   string `json:"message" example:"public link is password protected"`
+	UnlockURL string `json:"unlock_url" example:"/api/v1/public-links/unlock/abc123def456"`
 }
 
 type ListPublicLinksResponse struct {
@@ -107,6 +192,58 @@ type PublicDownloadResponse struct {
 	Mime        string `json:"mime" example:"application/pdf"`
 }
 
+// DropLinkInput folder_id и file_id взаимоисключающие; без обоих файлы попадают в корень
+type DropLinkInput struct {
+	ExpiresIn string `json:"expires_in" binding:"required" example:"72h"`
+	// FolderID папка для новых файлов, "root" — корень
+	FolderID *string `json:"folder_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174002"`
+	// FileID файл, в который загрузки попадают новыми версиями
+	FileID *string `json:"file_id,omitempty" binding:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// MaxFiles не задан — число загрузок не ограничено
+	MaxFiles *int `json:"max_files,omitempty" binding:"omitempty,min=1" example:"10"`
+	// MaxTotalSize суммарный размер загрузок в байтах; не задан — не ограничен
+	MaxTotalSize *int64 `json:"max_total_size,omitempty" binding:"omitempty,min=1" example:"104857600"`
+	// AllowedMimes пустой — любые типы; "image/*" разрешает весь тип
+	AllowedMimes []string `json:"allowed_mimes,omitempty" example:"application/pdf,image/*"`
+}
+
+type DropLinkResponse struct {
+	ID           string   `json:"id" example:"123e4567-e89b-12d3-a456-426614174004"`
+	Token        string   `json:"token,omitempty" example:"q5cW2xJb0mZ3kXl8tV1sN9rYwE4aHd7uPgOiFj6KzTc"` // только в ответе на создание
+	FolderID     *string  `json:"folder_id" example:"123e4567-e89b-12d3-a456-426614174002"`
+	FileID       *string  `json:"file_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	MaxFiles     *int     `json:"max_files" example:"10"`
+	MaxTotalSize *int64   `json:"max_total_size" example:"104857600"`
+	AllowedMimes []string `json:"allowed_mimes" example:"application/pdf,image/*"`
+	FileCount    int      `json:"file_count" example:"2"`
+	TotalSize    int64    `json:"total_size" example:"2048000"`
+	IsExpired    bool     `json:"is_expired" example:"false"`
+	ExpiresAt    string   `json:"expires_at" example:"2025-11-07T12:00:00Z"`
+	CreatedAt    string   `json:"created_at" example:"2025-11-04T12:00:00Z"`
+}
+
+type ListDropLinksResponse struct {
+	Links []DropLinkResponse `json:"links"`
+	Total int                `json:"total" example:"1"`
+}
+
+// DropLinkInfoResponse что можно загрузить по ссылке; nil остаток — без ограничения
+type DropLinkInfoResponse struct {
+	Target         string   `json:"target" example:"folder" enums:"folder,file"`
+	AllowedMimes   []string `json:"allowed_mimes" example:"application/pdf,image/*"`
+	RemainingFiles *int     `json:"remaining_files" example:"8"`
+	RemainingSize  *int64   `json:"remaining_size" example:"102809600"`
+	ExpiresAt      string   `json:"expires_at" example:"2025-11-07T12:00:00Z"`
+}
+
+type DropUploadInput struct {
+	Name              string `json:"name" binding:"required"`
+	Size              uint64 `json:"size" binding:"required,gt=0"`
+	Mime              string `json:"mime" binding:"required"`
+	ChecksumAlgorithm string `json:"checksum_algorithm" example:"sha256" enums:"sha256,crc32c"`
+	Checksum          string `json:"checksum" example:"47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="`
+}
+
 type DeleteFileResponse struct {
 	Message string `json:"message" example:"File deleted successfully"`
 }
@@ -114,3 +251,163 @@ type DeleteFileResponse struct {
 type DeleteVersionResponse struct {
 	Message string `json:"message" example:"Version deleted successfully"`
 }
+
+type ListTrashResponse struct {
+	Files []*FileResponse `json:"files"`
+}
+
+type MultipartUploadResponse struct {
+	ID        string `json:"id" example:"123e4567-e89b-12d3-a456-426614174003"`
+	FileID    string `json:"file_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	VersionID string `json:"version_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	PartSize  int64  `json:"part_size" example:"16777216"`
+	PartCount int    `json:"part_count" example:"640"`
+	Status    string `json:"status" example:"initiated"`
+	ExpiresAt string `json:"expires_at" example:"2025-11-05T12:00:00Z"`
+}
+
+type PresignPartsInput struct {
+	PartNumbers []int32 `json:"part_numbers" binding:"required,min=1,max=1000"`
+}
+
+type PartURLResponse struct {
+	PartNumber int32  `json:"part_number" example:"1"`
+	URL        string `json:"url" example:"https://s3.example.com/upload?partNumber=1&uploadId=..."`
+}
+
+type PresignPartsResponse struct {
+	Parts     []PartURLResponse `json:"parts"`
+	ExpiresIn string            `json:"expires_in" example:"15m"`
+}
+
+type CompletedPartInput struct {
+	PartNumber int32  `json:"part_number" binding:"required,gt=0" example:"1"`
+	ETag       string `json:"etag" binding:"required" example:"\"9b2cf535f27731c974343645a3985328\""`
+}
+
+type CompleteMultipartUploadInput struct {
+	Parts []CompletedPartInput `json:"parts" binding:"required,min=1,dive"`
+}
+
+type ArchiveFileInput struct {
+	FileID string `json:"file_id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+	// VersionNum без него в архив попадает текущая версия
+	VersionNum *int `json:"version_num" binding:"omitempty,gt=0" example:"2"`
+}
+
+// ArchiveInput папки попадают в архив целиком, со всеми вложенными папками
+type ArchiveInput struct {
+	Files     []ArchiveFileInput `json:"files" binding:"omitempty,max=10000,dive"`
+	FolderIDs []string           `json:"folder_ids" binding:"omitempty,max=100"`
+}
+
+type ArchiveResponse struct {
+	ID      string `json:"id" example:"123e4567-e89b-12d3-a456-426614174004"`
+	Status  string `json:"status" example:"ready"`
+	Entries int    `json:"entries" example:"42"`
+	Size    int64  `json:"size" example:"1073741824"`
+	// FailureReason заполнен у архивов в статусе failed
+	FailureReason *string `json:"failure_reason,omitempty" example:"file content is missing"`
+	// DownloadURL выдаётся только готовому архиву
+	DownloadURL *string `json:"download_url,omitempty" example:"https://s3.amazonaws.com/bucket/archives/..."`
+	ExpiresIn   *string `json:"expires_in,omitempty" example:"15m0s"`
+	ExpiresAt   string  `json:"expires_at" example:"2025-11-05T12:00:00Z"`
+	CreatedAt   string  `json:"created_at" example:"2025-11-04T12:00:00Z"`
+}
+
+// BatchOperationInput name обязателен для rename; folder_id для move: null или "root" — корень;
+// tag требует хотя бы одну метку в add_tags или remove_tags
+type BatchOperationInput struct {
+	Op         string   `json:"op" binding:"required,oneof=delete rename move restore tag" example:"rename"`
+	FileID     string   `json:"file_id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name       *string  `json:"name" binding:"omitempty,min=1,max=255" example:"report.pdf"`
+	FolderID   *string  `json:"folder_id" example:"123e4567-e89b-12d3-a456-426614174002"`
+	AddTags    []string `json:"add_tags" binding:"omitempty,max=50" example:"work"`
+	RemoveTags []string `json:"remove_tags" binding:"omitempty,max=50" example:"draft"`
+}
+
+// BatchInput atomic == true применяет все операции или ни одной
+type BatchInput struct {
+	Operations []BatchOperationInput `json:"operations" binding:"required,min=1,max=1000,dive"`
+	Atomic     bool                  `json:"atomic" example:"false"`
+}
+
+type BatchErrorResponse struct {
+	Status  int    `json:"status" example:"404"`
+	Code    string `json:"code" example:"FILE_NOT_FOUND"`
+	Message string `json:"message" example:"File not found"`
+}
+
+type BatchItemResponse struct {
+	FileID string              `json:"file_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Op     string              `json:"op" example:"rename"`
+	OK     bool                `json:"ok" example:"true"`
+	Error  *BatchErrorResponse `json:"error,omitempty"`
+}
+
+type BatchResponse struct {
+	Results   []BatchItemResponse `json:"results"`
+	Succeeded int                 `json:"succeeded" example:"9"`
+	Failed    int                 `json:"failed" example:"1"`
+}
+
+// ShareInput повторная выдача тому же пользователю меняет его роль
+type ShareInput struct {
+	Email string `json:"email" binding:"required,email" example:"teammate@example.com"`
+	Role  string `json:"role" binding:"required,oneof=viewer editor owner" example:"editor"`
+}
+
+type ShareResponse struct {
+	ID        string `json:"id" example:"123e4567-e89b-12d3-a456-426614174005"`
+	FileID    string `json:"file_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	UserID    string `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	Role      string `json:"role" example:"editor"`
+	GrantedBy string `json:"granted_by" example:"123e4567-e89b-12d3-a456-426614174003"`
+	CreatedAt string `json:"created_at" example:"2025-11-04T12:00:00Z"`
+	UpdatedAt string `json:"updated_at" example:"2025-11-04T12:00:00Z"`
+}
+
+type ListSharesResponse struct {
+	Shares []ShareResponse `json:"shares"`
+	Total  int             `json:"total" example:"2"`
+}
+
+type SharedFileResponse struct {
+	File    FileResponse `json:"file"`
+	ShareID string       `json:"share_id" example:"123e4567-e89b-12d3-a456-426614174005"`
+	OwnerID string       `json:"owner_id" example:"123e4567-e89b-12d3-a456-426614174003"`
+	Role    string       `json:"role" example:"viewer"`
+	// SharedAt время выдачи доступа
+	SharedAt string `json:"shared_at" example:"2025-11-04T12:00:00Z"`
+}
+
+type ListSharedFilesResponse struct {
+	Files []SharedFileResponse `json:"files"`
+	Total int                  `json:"total" example:"5"`
+}
+
+// SignedURLInput без version_id подписывается текущая версия файла
+type SignedURLInput struct {
+	// ExpiresIn по умолчанию 1h, не больше signed_urls.max_ttl
+	ExpiresIn string  `json:"expires_in" example:"24h"`
+	VersionID *string `json:"version_id,omitempty" binding:"omitempty,uuid" example:"123e4567-e89b-12d3-a456-426614174001"`
+	// Permissions по умолчанию только download
+	Permissions []string `json:"permissions" example:"download,preview" enums:"download,preview"`
+}
+
+// SignedURLResponse URL для каждого выданного разрешения
+type SignedURLResponse struct {
+	DownloadURL string   `json:"download_url,omitempty" example:"/api/v1/signed/files/123e4567-e89b-12d3-a456-426614174000/content?exp=...&sig=..."`
+	PreviewURL  string   `json:"preview_url,omitempty" example:"/api/v1/signed/files/123e4567-e89b-12d3-a456-426614174000/preview?exp=...&sig=..."`
+	FileID      string   `json:"file_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	VersionID   string   `json:"version_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	Permissions []string `json:"permissions" example:"download,preview"`
+	KeyID       string   `json:"key_id" example:"2025-11"`
+	ExpiresAt   string   `json:"expires_at" example:"2025-11-07T12:00:00Z"`
+}
+
+type RevokeSignedURLsResponse struct {
+	Message string `json:"message" example:"Signed URLs revoked successfully"`
+	// RevokedAt URL, выданные до этого момента включительно, больше не действуют
+	RevokedAt string `json:"revoked_at" example:"2025-11-07T12:00:00Z"`
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/yourusername/cloud-file-storage/internal/api/middleware"
+	domainFile "github.com/yourusername/cloud-file-storage/internal/domain/file"
+	"github.com/yourusername/cloud-file-storage/internal/domain/share"
 )
 
 // ListFiles godoc
@@ -17,10 +19,14 @@ import (
 // @Accept json
 // @Produce json
 // @Param q query string false "Search query by file name"
+// @Param folder_id query string false "Scope listing to a folder (uuid or \"root\"); omit to list all files"
 // @Param limit query int false "Results per page" default(20)
 // @Param skip query int false "Number of results to skip" default(0)
 // @Success 200 {object} ListFilesResponse "List of files"
+// @Failure 400 {object} map[string]string "Invalid folder_id format"
 // @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied to folder"
+// @Failure 404 {object} map[string]string "Folder not found"
 // @Failure 500 {object} map[string]string "Failed to list files"
 // @Router /files [get]
 func (h *FileHandler) ListFiles(ctx *gin.Context) {
@@ -47,7 +53,18 @@ func (h *FileHandler) ListFiles(ctx *gin.Context) {
 		skip = 0
 	}
 
-	files, total, err := h.fileService.SearchByName(ctx, userID, query, limit, skip)
+	var files []*domainFile.File
+	var total int64
+
+	if folderIDStr, scoped := ctx.GetQuery("folder_id"); scoped {
+		folderID, ok := h.resolveFolderID(ctx, userID, folderIDStr)
+		if !ok {
+			return
+		}
+		files, total, err = h.fileService.SearchInFolder(ctx, userID, folderID, query, limit, skip)
+	} else {
+		files, total, err = h.fileService.SearchByName(ctx, userID, query, limit, skip)
+	}
 	if err != nil {
 		_ = ctx.Error(err)
 		return
@@ -108,8 +125,7 @@ func (h *FileHandler) GetFile(ctx *gin.Context) {
 		return
 	}
 
-	if f.OwnerID != userID {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+	if !h.authorize(ctx, f, userID, share.RoleViewer) {
 		return
 	}
 
@@ -119,7 +135,13 @@ func (h *FileHandler) GetFile(ctx *gin.Context) {
 		return
 	}
 
-	resp := PresentFileDetail(f, len(versions))
+	tags, err := h.fileService.GetTags(ctx, fileID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	resp := PresentFileDetail(f, len(versions), tags)
 
 	ctx.JSON(http.StatusOK, resp)
 }
@@ -6,11 +6,13 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/yourusername/cloud-file-storage/internal/api/middleware"
+	"github.com/yourusername/cloud-file-storage/internal/domain/share"
 )
 
 // UploadNewVersion godoc
 // @Summary Upload new version of existing file
-// @Description Create a new version of an existing file and get presigned URL for uploading file data
+// @Description Create a new version of an existing file and get presigned URL for uploading file data.
+// @Description An optional checksum (sha256 or crc32c, base64) is verified on completion; send upload_headers with the PUT.
 // @Tags files
 // @Security Bearer
 // @Accept json
@@ -22,6 +24,7 @@ import (
 // @Failure 401 {object} map[string]string "User not authenticated"
 // @Failure 403 {object} map[string]string "Access denied"
 // @Failure 404 {object} map[string]string "File not found"
+// @Failure 413 {object} map[string]string "Storage quota exceeded"
 // @Failure 500 {object} map[string]string "Failed to create new version"
 // @Router /files/{file_id}/versions [post]
 func (h *FileHandler) UploadNewVersion(ctx *gin.Context) {
@@ -64,19 +67,20 @@ func (h *FileHandler) UploadNewVersion(ctx *gin.Context) {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
 		return
 	}
-	if file.OwnerID != userID {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+	if !h.authorize(ctx, file, userID, share.RoleEditor) {
 		return
 	}
 
-	const maxFileSize = 5 * 1024 * 1024 * 1024
-	if input.Size > maxFileSize {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "file size exceeds maximum allowed (5GB)"})
+	if !checkUploadSize(ctx, input.Size, input.Multipart) {
+		return
+	}
+	checksum, ok := parseChecksum(ctx, input.ChecksumAlgorithm, input.Checksum)
+	if !ok {
 		return
 	}
 
 	newVersionNum := file.VersionNum.Int() + 1
-	updatedFile, version, uploadURL, err := h.fileVersionService.UploadNewVersion(
+	updatedFile, version, uploadURL, uploadHeaders, err := h.fileVersionService.UploadNewVersion(
 		ctx,
 		fileID,
 		userID,
@@ -85,6 +89,7 @@ func (h *FileHandler) UploadNewVersion(ctx *gin.Context) {
 		input.Size,
 		input.Mime,
 		newVersionNum,
+		checksum,
 	)
 	if err != nil {
 		_ = ctx.Error(err)
@@ -92,18 +97,19 @@ func (h *FileHandler) UploadNewVersion(ctx *gin.Context) {
 	}
 
 	ctx.JSON(http.StatusCreated, UploadFileResponse{
-		FileID:     updatedFile.ID.String(),
-		VersionID:  version.ID.String(),
-		UploadURL:  uploadURL,
-		VersionNum: version.VersionNum.Int(),
-		Status:     version.Status.String(),
-		ExpiresIn:  "15m",
+		FileID:        updatedFile.ID.String(),
+		VersionID:     version.ID.String(),
+		UploadURL:     uploadURL,
+		VersionNum:    version.VersionNum.Int(),
+		Status:        version.Status.String(),
+		ExpiresIn:     "15m",
+		UploadHeaders: uploadHeaders,
 	})
 }
 
 // DeleteFile godoc
 // @Summary Delete file
-// @Description Delete a file and all its versions
+// @Description Move a file to trash; it can be restored until the retention period ends
 // @Tags files
 // @Security Bearer
 // @Accept json
@@ -139,8 +145,7 @@ func (h *FileHandler) DeleteFile(ctx *gin.Context) {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
 		return
 	}
-	if f.OwnerID != userID {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+	if !h.authorize(ctx, f, userID, share.RoleOwner) {
 		return
 	}
 
@@ -199,8 +204,7 @@ func (h *FileHandler) UpdateFile(ctx *gin.Context) {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
 		return
 	}
-	if f.OwnerID != userID {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+	if !h.authorize(ctx, f, userID, share.RoleEditor) {
 		return
 	}
 
@@ -221,7 +225,8 @@ func (h *FileHandler) UpdateFile(ctx *gin.Context) {
 
 // UploadNewFile godoc
 // @Summary Create new file upload
-// @Description Create a new file and get presigned URL for uploading file data
+// @Description Create a new file and get presigned URL for uploading file data.
+// @Description An optional checksum (sha256 or crc32c, base64) is verified on completion; send upload_headers with the PUT.
 // @Tags files
 // @Security Bearer
 // @Accept json
@@ -230,6 +235,9 @@ func (h *FileHandler) UpdateFile(ctx *gin.Context) {
 // @Success 201 {object} UploadFileResponse "File created with upload URL"
 // @Failure 400 {object} map[string]string "Invalid input or file size exceeds limit"
 // @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied to folder"
+// @Failure 404 {object} map[string]string "Folder not found"
+// @Failure 413 {object} map[string]string "Storage quota exceeded"
 // @Failure 500 {object} map[string]string "Failed to create file"
 // @Router /files [post]
 func (h *FileHandler) UploadNewFile(ctx *gin.Context) {
@@ -261,19 +269,32 @@ func (h *FileHandler) UploadNewFile(ctx *gin.Context) {
 		return
 	}
 
-	const maxFileSize = 5 * 1024 * 1024 * 1024
-	if input.Size > maxFileSize {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "file size exceeds maximum allowed (5GB)"})
+	if !checkUploadSize(ctx, input.Size, input.Multipart) {
 		return
 	}
+	checksum, ok := parseChecksum(ctx, input.ChecksumAlgorithm, input.Checksum)
+	if !ok {
+		return
+	}
+
+	var folderID *uuid.UUID
+	if input.FolderID != nil {
+		var ok bool
+		folderID, ok = h.resolveFolderID(ctx, ownerID, *input.FolderID)
+		if !ok {
+			return
+		}
+	}
 
-	file, version, uploadURL, err := h.fileVersionService.UploadNewFile(
+	file, version, uploadURL, uploadHeaders, err := h.fileVersionService.UploadNewFile(
 		ctx,
 		ownerID,
 		sessionID,
 		input.Name,
 		input.Size,
 		input.Mime,
+		folderID,
+		checksum,
 	)
 	if err != nil {
 		_ = ctx.Error(err)
@@ -281,11 +302,170 @@ func (h *FileHandler) UploadNewFile(ctx *gin.Context) {
 	}
 
 	ctx.JSON(http.StatusCreated, UploadFileResponse{
-		FileID:     file.ID.String(),
-		VersionID:  version.ID.String(),
-		UploadURL:  uploadURL,
-		VersionNum: version.VersionNum.Int(),
-		Status:     version.Status.String(),
-		ExpiresIn:  "15m",
+		FileID:        file.ID.String(),
+		VersionID:     version.ID.String(),
+		UploadURL:     uploadURL,
+		VersionNum:    version.VersionNum.Int(),
+		Status:        version.Status.String(),
+		ExpiresIn:     "15m",
+		UploadHeaders: uploadHeaders,
 	})
 }
+
+// MoveFile godoc
+// @Summary Move file to folder
+// @Description Move a file into another folder; null folder_id moves the file to the root
+// @Tags files
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param file_id path string true "File ID" format(uuid)
+// @Param request body MoveFileInput true "Target folder"
+// @Success 200 {object} FileResponse "File moved"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "File or folder not found"
+// @Failure 500 {object} map[string]string "Failed to move file"
+// @Router /files/{file_id}/move [post]
+func (h *FileHandler) MoveFile(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	fileIDStr := ctx.Param("file_id")
+	fileID, err := uuid.Parse(fileIDStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid file_id format"})
+		return
+	}
+
+	var input MoveFileInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	f, err := h.fileService.GetByID(ctx, fileID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if !h.authorize(ctx, f, userID, share.RoleOwner) {
+		return
+	}
+
+	var folderID *uuid.UUID
+	if input.FolderID != nil {
+		var ok bool
+		// папка выбирается среди папок владельца файла, даже если переносит совладелец
+		folderID, ok = h.resolveFolderID(ctx, f.OwnerID, *input.FolderID)
+		if !ok {
+			return
+		}
+	}
+
+	if err := h.fileService.MoveToFolder(ctx, fileID, folderID); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	updatedFile, err := h.fileService.GetByID(ctx, fileID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, PresentFile(updatedFile))
+}
+
+// CopyFile godoc
+// @Summary Copy file
+// @Description Duplicate a file or one of its versions inside storage, without downloading and re-uploading it.
+// @Description The copy is a new file with a single version 1 and shares the preview of the source.
+// @Tags files
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param file_id path string true "File ID" format(uuid)
+// @Param request body CopyFileInput false "Target name, folder and source version"
+// @Success 201 {object} FileResponse "File copied"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "File, version or folder not found"
+// @Failure 409 {object} map[string]string "Version content is not uploaded"
+// @Failure 413 {object} map[string]string "Storage quota exceeded"
+// @Failure 500 {object} map[string]string "Failed to copy file"
+// @Router /files/{file_id}/copy [post]
+func (h *FileHandler) CopyFile(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	sessionIDValue, exists := ctx.Get("session_id")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "session not found"})
+		return
+	}
+	sessionID, ok := sessionIDValue.(uuid.UUID)
+	if !ok {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "invalid session id format"})
+		return
+	}
+
+	fileID, err := uuid.Parse(ctx.Param("file_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid file_id format"})
+		return
+	}
+
+	var input CopyFileInput
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&input); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	source, err := h.fileService.GetByID(ctx, fileID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if !h.authorize(ctx, source, userID, share.RoleViewer) {
+		return
+	}
+
+	// копия принадлежит вызывающему, поэтому чужую папку источника она не наследует
+	var folderID *uuid.UUID
+	if source.OwnerID == userID {
+		folderID = source.FolderID
+	}
+	if input.FolderID != nil {
+		folderID, ok = h.resolveFolderID(ctx, userID, *input.FolderID)
+		if !ok {
+			return
+		}
+	}
+	var name string
+	if input.Name != nil {
+		name = *input.Name
+	}
+	var versionNum int
+	if input.VersionNum != nil {
+		versionNum = *input.VersionNum
+	}
+
+	copied, _, err := h.fileVersionService.CopyFile(ctx, fileID, userID, sessionID, name, folderID, versionNum)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, PresentFile(copied))
+}
@@ -1,25 +1,132 @@
 package files_handler
 
 import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	archive_service "github.com/yourusername/cloud-file-storage/internal/app/archive"
+	drop_link_service "github.com/yourusername/cloud-file-storage/internal/app/drop_link"
 	file_service "github.com/yourusername/cloud-file-storage/internal/app/file"
 	file_version_service "github.com/yourusername/cloud-file-storage/internal/app/file_version"
+	folder_service "github.com/yourusername/cloud-file-storage/internal/app/folder"
+	multipart_upload_service "github.com/yourusername/cloud-file-storage/internal/app/multipart_upload"
 	public_link_service "github.com/yourusername/cloud-file-storage/internal/app/public_link"
+	share_service "github.com/yourusername/cloud-file-storage/internal/app/share"
+	signed_url_service "github.com/yourusername/cloud-file-storage/internal/app/signed_url"
+	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
+	domainFile "github.com/yourusername/cloud-file-storage/internal/domain/file"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/share"
 )
 
 type FileHandler struct {
 	fileVersionService *file_version_service.FileVersionService
 	publicLinkService  *public_link_service.PublicLinkService
 	fileService        *file_service.FileService
+	folderService      *folder_service.FolderService
+	multipartService   *multipart_upload_service.MultipartUploadService
+	archiveService     *archive_service.ArchiveService
+	shareService       *share_service.ShareService
+	dropLinkService    *drop_link_service.DropLinkService
+	signedURLService   *signed_url_service.SignedURLService
 }
 
 func NewFileHandler(
 	fileVersionService *file_version_service.FileVersionService,
 	fileService *file_service.FileService,
 	publicLinkService *public_link_service.PublicLinkService,
+	folderService *folder_service.FolderService,
+	multipartService *multipart_upload_service.MultipartUploadService,
+	archiveService *archive_service.ArchiveService,
+	shareService *share_service.ShareService,
+	dropLinkService *drop_link_service.DropLinkService,
+	signedURLService *signed_url_service.SignedURLService,
 ) *FileHandler {
 	return &FileHandler{
 		fileVersionService: fileVersionService,
 		fileService:        fileService,
 		publicLinkService:  publicLinkService,
+		folderService:      folderService,
+		multipartService:   multipartService,
+		archiveService:     archiveService,
+		shareService:       shareService,
+		dropLinkService:    dropLinkService,
+		signedURLService:   signedURLService,
+	}
+}
+
+// authorize проверяет, что роль пользователя в файле не ниже required.
+// При ошибке ответ уже записан в ctx.
+func (h *FileHandler) authorize(ctx *gin.Context, f *domainFile.File, userID uuid.UUID, required share.Role) bool {
+	err := h.shareService.Authorize(ctx, f, userID, required)
+	if errors.Is(err, domainerrors.ErrAccessDenied) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		return false
+	}
+	if err != nil {
+		_ = ctx.Error(err)
+		return false
+	}
+	return true
+}
+
+// resolveFolderID разбирает folder_id и проверяет, что папка принадлежит пользователю.
+// Пустая строка и "root" означают корень. При ошибке ответ уже записан в ctx.
+func (h *FileHandler) resolveFolderID(ctx *gin.Context, userID uuid.UUID, raw string) (*uuid.UUID, bool) {
+	if raw == "" || raw == "root" {
+		return nil, true
+	}
+
+	folderID, err := uuid.Parse(raw)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid folder_id format"})
+		return nil, false
+	}
+
+	fd, err := h.folderService.GetByID(ctx, folderID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return nil, false
+	}
+	if fd.OwnerID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		return nil, false
+	}
+
+	return &folderID, true
+}
+
+// parseChecksum разбирает необязательную контрольную сумму из запроса на загрузку.
+// При ошибке ответ уже записан в ctx.
+func parseChecksum(ctx *gin.Context, algorithm, value string) (*file_version.Checksum, bool) {
+	if algorithm == "" && value == "" {
+		return nil, true
+	}
+	checksum, err := file_version.NewChecksum(algorithm, value)
+	if err != nil {
+		_ = ctx.Error(err)
+		return nil, false
+	}
+	return &checksum, true
+}
+
+// Один presigned PUT в S3 ограничен 5GB, больше можно загрузить только multipart
+const singlePutMaxSize = 5 * 1024 * 1024 * 1024
+
+// checkUploadSize при ошибке ответ уже записан в ctx
+func checkUploadSize(ctx *gin.Context, size uint64, multipart bool) bool {
+	if multipart {
+		if size > file_version.MaxFileSize {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "file size exceeds maximum allowed (10GB)"})
+			return false
+		}
+		return true
+	}
+	if size > singlePutMaxSize {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "file size exceeds maximum allowed (5GB), use multipart upload"})
+		return false
 	}
+	return true
 }
@@ -0,0 +1,156 @@
+package files_handler
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+	"github.com/yourusername/cloud-file-storage/internal/api/middleware"
+	domainFile "github.com/yourusername/cloud-file-storage/internal/domain/file"
+	domainVer "github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+)
+
+const siteName = "Cloud File Storage"
+
+// landingPreviewTTL превью на странице живёт столько же, сколько URL скачивания
+const landingPreviewTTL = time.Hour
+
+//go:embed templates/*.html
+var templatesFS embed.FS
+
+var publicLinkTemplates = template.Must(template.ParseFS(templatesFS, "templates/public_link.html"))
+
+// publicLinkPage данные шаблонов templates/public_link.html
+type publicLinkPage struct {
+	Title       string
+	SiteName    string
+	FileName    string
+	Size        string
+	Mime        string
+	PreviewURL  string
+	DownloadURL string
+	PageURL     string
+	UnlockURL   string
+	Error       string
+}
+
+// wantsHTML браузер предпочитает HTML; клиенты API без Accept и с */* получают JSON
+func wantsHTML(ctx *gin.Context) bool {
+	return ctx.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) == gin.MIMEHTML
+}
+
+func renderPublicLinkTemplate(ctx *gin.Context, status int, name string, page publicLinkPage) {
+	// На странице подписанные URL: ни кэшам, ни сайтам по Referer они достаться не должны
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("Referrer-Policy", "no-referrer")
+	ctx.Render(status, render.HTML{Template: publicLinkTemplates, Name: name, Data: page})
+}
+
+func renderPasswordPage(ctx *gin.Context, status int, unlockURL, message string) {
+	renderPublicLinkTemplate(ctx, status, "password", publicLinkPage{
+		Title:     "Password protected file",
+		SiteName:  siteName,
+		UnlockURL: unlockURL,
+		Error:     message,
+	})
+}
+
+func renderErrorPage(ctx *gin.Context, status int, title, message string) {
+	renderPublicLinkTemplate(ctx, status, "error", publicLinkPage{
+		Title:    title,
+		SiteName: siteName,
+		Error:    message,
+	})
+}
+
+// publicLinkFailure ответ об ошибке публичной ссылки: страница для браузера, JSON для API
+func publicLinkFailure(ctx *gin.Context, page bool, status int, message string) {
+	if page {
+		renderErrorPage(ctx, status, "This link is not available", message)
+		return
+	}
+	ctx.JSON(status, gin.H{"error": message})
+}
+
+// publicLinkError внутренняя ошибка публичной ссылки. Маршрут вне ErrorMiddleware, поэтому
+// статус и текст берутся из MapError здесь же, а ctx.Error только записывает ошибку в лог
+func publicLinkError(ctx *gin.Context, page bool, err error) {
+	_ = ctx.Error(err)
+	status, _, message := middleware.MapError(err)
+	publicLinkFailure(ctx, page, status, message)
+}
+
+// renderLandingPage превью показывается только у изображений: остальным PreviewWorker
+// ставит заглушку по умолчанию
+func (h *FileHandler) renderLandingPage(ctx *gin.Context, token, unlockToken string, file *domainFile.File, version *domainVer.FileVersion) {
+	page := publicLinkPage{
+		Title:       file.Name.String(),
+		SiteName:    siteName,
+		FileName:    file.Name.String(),
+		Size:        formatSize(version.Size.Uint64()),
+		Mime:        version.Mime.String(),
+		DownloadURL: publicLinkPageURL(token, unlockToken, true),
+		PageURL:     requestBaseURL(ctx) + publicLinkPageURL(token, "", false),
+	}
+
+	if strings.HasPrefix(version.Mime.String(), "image/") {
+		previewURL, err := h.fileVersionService.GetPreviewURL(ctx, version, landingPreviewTTL)
+		if err != nil {
+			publicLinkError(ctx, true, err)
+			return
+		}
+		if previewURL != nil {
+			page.PreviewURL = *previewURL
+		}
+	}
+
+	renderPublicLinkTemplate(ctx, http.StatusOK, "landing", page)
+}
+
+// publicLinkPageURL адрес страницы ссылки; download ведёт сразу на файл
+func publicLinkPageURL(token, unlockToken string, download bool) string {
+	query := url.Values{}
+	if unlockToken != "" {
+		query.Set("unlock_token", unlockToken)
+	}
+	if download {
+		query.Set("download", "1")
+	}
+
+	u := "/api/v1/public-links/" + url.PathEscape(token)
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+// requestBaseURL схема и хост, по которым пришёл запрос, с учётом прокси перед API
+func requestBaseURL(ctx *gin.Context) string {
+	scheme := "http"
+	if ctx.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := ctx.GetHeader("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	return scheme + "://" + ctx.Request.Host
+}
+
+func formatSize(size uint64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := uint64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
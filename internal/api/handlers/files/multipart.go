@@ -0,0 +1,264 @@
+package files_handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/api/middleware"
+	"github.com/yourusername/cloud-file-storage/internal/domain/multipart_upload"
+	"github.com/yourusername/cloud-file-storage/internal/domain/share"
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
+)
+
+// InitiateMultipartUpload godoc
+// @Summary Start multipart upload
+// @Description Start an S3 multipart upload for a version that is still awaiting data. Repeated calls return the active upload so the client can resume it.
+// @Tags files
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param file_id path string true "File ID" format(uuid)
+// @Param version_num path int true "Version number"
+// @Success 201 {object} MultipartUploadResponse "Multipart upload started"
+// @Failure 400 {object} map[string]string "Invalid parameters"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "File or version not found"
+// @Failure 409 {object} map[string]string "Version is not awaiting upload"
+// @Failure 500 {object} map[string]string "Failed to start upload"
+// @Router /files/{file_id}/versions/{version_num}/uploads [post]
+func (h *FileHandler) InitiateMultipartUpload(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	fileID, err := uuid.Parse(ctx.Param("file_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid file_id format"})
+		return
+	}
+
+	versionNum, err := strconv.Atoi(ctx.Param("version_num"))
+	if err != nil || versionNum <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid version_num format"})
+		return
+	}
+
+	f, err := h.fileService.GetByID(ctx, fileID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if !h.authorize(ctx, f, userID, share.RoleEditor) {
+		return
+	}
+
+	versions, err := h.fileVersionService.GetVersionsByFileID(ctx, fileID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	var versionID *uuid.UUID
+	for _, v := range versions {
+		if v.VersionNum.Int() == versionNum {
+			versionID = &v.ID
+			break
+		}
+	}
+	if versionID == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "version not found"})
+		return
+	}
+
+	upload, err := h.multipartService.Initiate(ctx, *versionID, userID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, PresentMultipartUpload(upload))
+}
+
+// PresignUploadParts godoc
+// @Summary Get upload URLs for parts
+// @Description Generate presigned PUT URLs for the given part numbers (expire in 15 minutes). The ETag header of each PUT response is needed to complete the upload.
+// @Tags files
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param file_id path string true "File ID" format(uuid)
+// @Param version_num path int true "Version number"
+// @Param upload_id path string true "Multipart upload ID" format(uuid)
+// @Param request body PresignPartsInput true "Part numbers"
+// @Success 200 {object} PresignPartsResponse "Part upload URLs"
+// @Failure 400 {object} map[string]string "Invalid input or part number out of range"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Upload not found"
+// @Failure 409 {object} map[string]string "Upload is completed, aborted or expired"
+// @Failure 500 {object} map[string]string "Failed to generate URLs"
+// @Router /files/{file_id}/versions/{version_num}/uploads/{upload_id}/parts [post]
+func (h *FileHandler) PresignUploadParts(ctx *gin.Context) {
+	upload, ok := h.ownedMultipartUpload(ctx)
+	if !ok {
+		return
+	}
+
+	var input PresignPartsInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	urls, err := h.multipartService.PresignParts(ctx, upload.ID, input.PartNumbers)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	parts := make([]PartURLResponse, 0, len(urls))
+	for _, u := range urls {
+		parts = append(parts, PartURLResponse{PartNumber: u.PartNumber, URL: u.URL})
+	}
+
+	ctx.JSON(http.StatusOK, PresignPartsResponse{
+		Parts:     parts,
+		ExpiresIn: "15m",
+	})
+}
+
+// CompleteMultipartUpload godoc
+// @Summary Complete multipart upload
+// @Description Assemble the object from uploaded parts. Every part from 1 to part_count must be listed exactly once with its ETag.
+// @Tags files
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param file_id path string true "File ID" format(uuid)
+// @Param version_num path int true "Version number"
+// @Param upload_id path string true "Multipart upload ID" format(uuid)
+// @Param request body CompleteMultipartUploadInput true "Uploaded parts"
+// @Success 200 {object} MultipartUploadResponse "Upload completed"
+// @Failure 400 {object} map[string]string "Invalid or incomplete part list"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Upload not found"
+// @Failure 409 {object} map[string]string "Upload is completed, aborted or expired"
+// @Failure 422 {object} map[string]string "Content does not match the declared checksum or size; the version is marked failed"
+// @Failure 500 {object} map[string]string "Failed to complete upload"
+// @Router /files/{file_id}/versions/{version_num}/uploads/{upload_id}/complete [post]
+func (h *FileHandler) CompleteMultipartUpload(ctx *gin.Context) {
+	upload, ok := h.ownedMultipartUpload(ctx)
+	if !ok {
+		return
+	}
+
+	var input CompleteMultipartUploadInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	parts := make([]storage.CompletedPart, 0, len(input.Parts))
+	for _, p := range input.Parts {
+		parts = append(parts, storage.CompletedPart{PartNumber: p.PartNumber, ETag: p.ETag})
+	}
+
+	completed, err := h.multipartService.Complete(ctx, upload.ID, parts)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, PresentMultipartUpload(completed))
+}
+
+// AbortMultipartUpload godoc
+// @Summary Abort multipart upload
+// @Description Abort the upload and discard already uploaded parts. The version stays awaiting data and a new upload can be started.
+// @Tags files
+// @Security Bearer
+// @Produce json
+// @Param file_id path string true "File ID" format(uuid)
+// @Param version_num path int true "Version number"
+// @Param upload_id path string true "Multipart upload ID" format(uuid)
+// @Success 204 "Upload aborted"
+// @Failure 400 {object} map[string]string "Invalid parameters"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Upload not found"
+// @Failure 409 {object} map[string]string "Upload is already completed or aborted"
+// @Failure 500 {object} map[string]string "Failed to abort upload"
+// @Router /files/{file_id}/versions/{version_num}/uploads/{upload_id} [delete]
+func (h *FileHandler) AbortMultipartUpload(ctx *gin.Context) {
+	upload, ok := h.ownedMultipartUpload(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.multipartService.Abort(ctx, upload.ID); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ownedMultipartUpload находит загрузку из пути и проверяет, что она относится
+// к указанной версии файла пользователя. При ошибке ответ уже записан в ctx.
+func (h *FileHandler) ownedMultipartUpload(ctx *gin.Context) (*multipart_upload.MultipartUpload, bool) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return nil, false
+	}
+
+	fileID, err := uuid.Parse(ctx.Param("file_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid file_id format"})
+		return nil, false
+	}
+
+	versionNum, err := strconv.Atoi(ctx.Param("version_num"))
+	if err != nil || versionNum <= 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid version_num format"})
+		return nil, false
+	}
+
+	uploadID, err := uuid.Parse(ctx.Param("upload_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid upload_id format"})
+		return nil, false
+	}
+
+	upload, err := h.multipartService.GetByID(ctx, uploadID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return nil, false
+	}
+	if upload.FileID != fileID {
+		_ = ctx.Error(multipart_upload.ErrNotFound)
+		return nil, false
+	}
+	if upload.OwnerID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		return nil, false
+	}
+
+	version, err := h.fileVersionService.GetVersionByID(ctx, upload.VersionID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return nil, false
+	}
+	if version.VersionNum.Int() != versionNum {
+		_ = ctx.Error(multipart_upload.ErrNotFound)
+		return nil, false
+	}
+
+	return upload, true
+}
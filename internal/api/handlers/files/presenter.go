@@ -3,9 +3,16 @@ package files_handler
 import (
 	"time"
 
+	"github.com/google/uuid"
+	share_service "github.com/yourusername/cloud-file-storage/internal/app/share"
+	"github.com/yourusername/cloud-file-storage/internal/domain/archive"
+	"github.com/yourusername/cloud-file-storage/internal/domain/drop_link"
 	domainFile "github.com/yourusername/cloud-file-storage/internal/domain/file"
 	domainVer "github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/multipart_upload"
 	"github.com/yourusername/cloud-file-storage/internal/domain/public_link"
+	"github.com/yourusername/cloud-file-storage/internal/domain/share"
+	"github.com/yourusername/cloud-file-storage/internal/domain/signed_url"
 )
 
 const timeFmt = time.RFC3339
@@ -24,27 +31,62 @@ func PresentFile(f *domainFile.File) FileResponse {
 		Status:       f.Status.String(),
 		VersionNum:   f.VersionNum.Int(),
 		PreviewS3Key: preview,
+		FolderID:     presentFolderID(f),
+		Checksum:     presentChecksum(f.Checksum),
 		CreatedAt:    f.CreatedAt.UTC().Format(time.RFC3339),
 		UpdatedAt:    f.UpdatedAt.UTC().Format(time.RFC3339),
+		DeletedAt:    presentDeletedAt(f),
 	}
 }
 
+func presentChecksum(c *domainVer.Checksum) *ChecksumResponse {
+	if c == nil {
+		return nil
+	}
+	return &ChecksumResponse{Algorithm: string(c.Algorithm()), Value: c.Value()}
+}
+
+func presentDeletedAt(f *domainFile.File) *string {
+	if f.DeletedAt == nil {
+		return nil
+	}
+	t := f.DeletedAt.UTC().Format(timeFmt)
+	return &t
+}
+
+func presentFolderID(f *domainFile.File) *string {
+	if f.FolderID == nil {
+		return nil
+	}
+	id := f.FolderID.String()
+	return &id
+}
+
 func PresentVersion(v *domainVer.FileVersion) FileVersionResponse {
 	var preview *string
 	if v.PreviewS3Key != nil {
 		k := v.PreviewS3Key.String()
 		preview = &k
 	}
+	var contentHash *string
+	if v.ContentHash != nil {
+		h := v.ContentHash.String()
+		contentHash = &h
+	}
 	return FileVersionResponse{
-		ID:           v.ID.String(),
-		FileID:       v.FileId.String(),
-		VersionNum:   v.VersionNum.Int(),
-		Status:       v.Status.String(),
-		Size:         v.Size.Uint64(),
-		Mime:         v.Mime.String(),
-		PreviewS3Key: preview,
-		CreatedAt:    v.CreatedAt.UTC().Format(time.RFC3339),
-		UpdatedAt:    v.UpdatedAt.UTC().Format(time.RFC3339),
+		ID:            v.ID.String(),
+		FileID:        v.FileId.String(),
+		VersionNum:    v.VersionNum.Int(),
+		Status:        v.Status.String(),
+		Size:          v.Size.Uint64(),
+		Mime:          v.Mime.String(),
+		PreviewS3Key:  preview,
+		ContentHash:   contentHash,
+		Checksum:      presentChecksum(v.Checksum),
+		FailureReason: v.FailureReason,
+		DropLinkID:    presentOptionalID(v.DropLinkID),
+		CreatedAt:     v.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:     v.UpdatedAt.UTC().Format(time.RFC3339),
 	}
 }
 
@@ -70,7 +112,7 @@ func PresentVersions(versions []*domainVer.FileVersion) []FileVersionResponse {
 	return out
 }
 
-func PresentFileDetail(f *domainFile.File, totalVersions int) FileDetailResponse {
+func PresentFileDetail(f *domainFile.File, totalVersions int, tags []domainFile.Tag) FileDetailResponse {
 	var preview *string
 	if f.PreviewS3Key != nil {
 		k := f.PreviewS3Key.String()
@@ -86,21 +128,34 @@ func PresentFileDetail(f *domainFile.File, totalVersions int) FileDetailResponse
 		CurrentVersion:    f.VersionNum.Int(),
 		TotalVersions:     totalVersions,
 		PreviewS3Key:      preview,
+		FolderID:          presentFolderID(f),
+		Checksum:          presentChecksum(f.Checksum),
+		Tags:              domainFile.TagStrings(tags),
 		CreatedAt:         f.CreatedAt.UTC().Format(timeFmt),
 		UpdatedAt:         f.UpdatedAt.UTC().Format(timeFmt),
 		UploadedBySession: f.UploadedBySessionId.String(),
 	}
 }
 
-// PresentPublicLink преобразует доменную PublicLink в DTO
-func PresentPublicLink(link *public_link.PublicLink) PublicLinkResponse {
+// PresentPublicLink преобразует доменную PublicLink в DTO; token известен только при создании
+func PresentPublicLink(link *public_link.PublicLink, token string) PublicLinkResponse {
+	var versionID *string
+	if link.VersionID != nil {
+		id := link.VersionID.String()
+		versionID = &id
+	}
 	return PublicLinkResponse{
-		ID:        link.ID.String(),
-		FileID:    link.FileID.String(),
-		Token:     link.TokenHash,
-		ExpiresAt: link.ExpiredAt.UTC().Format(timeFmt),
-		IsExpired: link.IsExpired(),
-		CreatedAt: link.CreatedAt.UTC().Format(timeFmt),
+		ID:            link.ID.String(),
+		FileID:        link.FileID.String(),
+		Token:         token,
+		ExpiresAt:     link.ExpiredAt.UTC().Format(timeFmt),
+		IsExpired:     link.IsExpired(),
+		HasPassword:   link.HasPassword(),
+		MaxDownloads:  link.MaxDownloads,
+		DownloadCount: link.DownloadCount,
+		VersionMode:   link.VersionMode.String(),
+		VersionID:     versionID,
+		CreatedAt:     link.CreatedAt.UTC().Format(timeFmt),
 	}
 }
 
@@ -111,12 +166,31 @@ func PresentPublicLinks(links []*public_link.PublicLink) []*PublicLinkResponse {
 		if l == nil {
 			continue
 		}
-		resp := PresentPublicLink(l)
+		resp := PresentPublicLink(l, "")
 		out = append(out, &resp)
 	}
 	return out
 }
 
+func PresentPublicLinkAccesses(accesses []*public_link.Access) []PublicLinkAccessResponse {
+	out := make([]PublicLinkAccessResponse, 0, len(accesses))
+	for _, a := range accesses {
+		var versionID *string
+		if a.VersionID != nil {
+			id := a.VersionID.String()
+			versionID = &id
+		}
+		out = append(out, PublicLinkAccessResponse{
+			ID:        a.ID.String(),
+			VersionID: versionID,
+			IP:        a.IP,
+			UserAgent: a.UserAgent,
+			CreatedAt: a.CreatedAt.UTC().Format(timeFmt),
+		})
+	}
+	return out
+}
+
 func PresentPublicDownload(file *domainFile.File, version *domainVer.FileVersion, downloadURL string) PublicDownloadResponse {
 	return PublicDownloadResponse{
 		DownloadURL: downloadURL,
@@ -126,3 +200,148 @@ func PresentPublicDownload(file *domainFile.File, version *domainVer.FileVersion
 		Mime:        version.Mime.String(),
 	}
 }
+
+func PresentMultipartUpload(m *multipart_upload.MultipartUpload) MultipartUploadResponse {
+	return MultipartUploadResponse{
+		ID:        m.ID.String(),
+		FileID:    m.FileID.String(),
+		VersionID: m.VersionID.String(),
+		PartSize:  m.PartSize,
+		PartCount: m.PartCount,
+		Status:    m.Status.String(),
+		ExpiresAt: m.ExpiresAt.UTC().Format(timeFmt),
+	}
+}
+
+// PresentArchive downloadURL пустой, если архив ещё не собран
+func PresentArchive(a *archive.Archive, downloadURL string, expiresIn time.Duration) ArchiveResponse {
+	resp := ArchiveResponse{
+		ID:            a.ID.String(),
+		Status:        a.Status.String(),
+		Entries:       len(a.Entries),
+		Size:          a.Size,
+		FailureReason: a.FailureReason,
+		ExpiresAt:     a.ExpiresAt.UTC().Format(timeFmt),
+		CreatedAt:     a.CreatedAt.UTC().Format(timeFmt),
+	}
+	if downloadURL != "" {
+		ttl := expiresIn.String()
+		resp.DownloadURL = &downloadURL
+		resp.ExpiresIn = &ttl
+	}
+	return resp
+}
+
+func PresentShare(s *share.Share) ShareResponse {
+	return ShareResponse{
+		ID:        s.ID.String(),
+		FileID:    s.FileID.String(),
+		UserID:    s.UserID.String(),
+		Role:      s.Role.String(),
+		GrantedBy: s.GrantedBy.String(),
+		CreatedAt: s.CreatedAt.UTC().Format(timeFmt),
+		UpdatedAt: s.UpdatedAt.UTC().Format(timeFmt),
+	}
+}
+
+func PresentShares(shares []*share.Share) []ShareResponse {
+	out := make([]ShareResponse, 0, len(shares))
+	for _, s := range shares {
+		if s == nil {
+			continue
+		}
+		out = append(out, PresentShare(s))
+	}
+	return out
+}
+
+func PresentSharedFiles(files []share_service.SharedFile) []SharedFileResponse {
+	out := make([]SharedFileResponse, 0, len(files))
+	for _, sf := range files {
+		out = append(out, SharedFileResponse{
+			File:     PresentFile(sf.File),
+			ShareID:  sf.Share.ID.String(),
+			OwnerID:  sf.File.OwnerID.String(),
+			Role:     sf.Share.Role.String(),
+			SharedAt: sf.Share.CreatedAt.UTC().Format(timeFmt),
+		})
+	}
+	return out
+}
+
+func presentOptionalID(id *uuid.UUID) *string {
+	if id == nil {
+		return nil
+	}
+	s := id.String()
+	return &s
+}
+
+// PresentDropLink token известен только при создании
+func PresentDropLink(link *drop_link.DropLink, token string) DropLinkResponse {
+	return DropLinkResponse{
+		ID:           link.ID.String(),
+		Token:        token,
+		FolderID:     presentOptionalID(link.FolderID),
+		FileID:       presentOptionalID(link.FileID),
+		MaxFiles:     link.MaxFiles,
+		MaxTotalSize: link.MaxTotalSize,
+		AllowedMimes: link.AllowedMimes,
+		FileCount:    link.FileCount,
+		TotalSize:    link.TotalSize,
+		IsExpired:    link.IsExpired(),
+		ExpiresAt:    link.ExpiredAt.UTC().Format(timeFmt),
+		CreatedAt:    link.CreatedAt.UTC().Format(timeFmt),
+	}
+}
+
+func PresentDropLinks(links []*drop_link.DropLink) []DropLinkResponse {
+	out := make([]DropLinkResponse, 0, len(links))
+	for _, l := range links {
+		if l == nil {
+			continue
+		}
+		out = append(out, PresentDropLink(l, ""))
+	}
+	return out
+}
+
+// PresentDropLinkInfo то, что видит загружающий: без токена, счётчиков и id владельца
+func PresentDropLinkInfo(link *drop_link.DropLink) DropLinkInfoResponse {
+	resp := DropLinkInfoResponse{
+		Target:       "folder",
+		AllowedMimes: link.AllowedMimes,
+		ExpiresAt:    link.ExpiredAt.UTC().Format(timeFmt),
+	}
+	if link.TargetsFile() {
+		resp.Target = "file"
+	}
+	if link.MaxFiles != nil {
+		remaining := max(*link.MaxFiles-link.FileCount, 0)
+		resp.RemainingFiles = &remaining
+	}
+	if link.MaxTotalSize != nil {
+		remaining := max(*link.MaxTotalSize-link.TotalSize, 0)
+		resp.RemainingSize = &remaining
+	}
+	return resp
+}
+
+// PresentSignedURL query — подписанная часть URL, одна на все разрешения
+func PresentSignedURL(claims *signed_url.Claims, query string) SignedURLResponse {
+	base := "/api/v1/signed/files/" + claims.FileID.String()
+	resp := SignedURLResponse{
+		FileID:      claims.FileID.String(),
+		VersionID:   claims.VersionID.String(),
+		Permissions: claims.Permissions.Strings(),
+		KeyID:       claims.KeyID,
+		ExpiresAt:   claims.ExpiresAt.UTC().Format(timeFmt),
+	}
+	if claims.Permissions.Has(signed_url.PermissionDownload) {
+		resp.DownloadURL = base + "/content?" + query
+	}
+	if claims.Permissions.Has(signed_url.PermissionPreview) {
+		resp.PreviewURL = base + "/preview?" + query
+	}
+	return resp
+}
@@ -1,13 +1,17 @@
 package files_handler
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/yourusername/cloud-file-storage/internal/api/middleware"
+	domainFile "github.com/yourusername/cloud-file-storage/internal/domain/file"
 	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/public_link"
+	"github.com/yourusername/cloud-file-storage/internal/domain/share"
 )
 
 // DeletePublicLink godoc
@@ -54,8 +58,7 @@ func (h *FileHandler) DeletePublicLink(ctx *gin.Context) {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
 		return
 	}
-	if f.OwnerID != userID {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+	if !h.authorize(ctx, f, userID, share.RoleOwner) {
 		return
 	}
 
@@ -69,7 +72,7 @@ func (h *FileHandler) DeletePublicLink(ctx *gin.Context) {
 
 // GetPublicLinks godoc
 // @Summary Get public links for file
-// @Description Get list of all public links for a file
+// @Description Get list of all public links for a file (without tokens)
 // @Tags files
 // @Security Bearer
 // @Accept json
@@ -104,8 +107,7 @@ func (h *FileHandler) GetPublicLinks(ctx *gin.Context) {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
 		return
 	}
-	if f.OwnerID != userID {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+	if !h.authorize(ctx, f, userID, share.RoleOwner) {
 		return
 	}
 
@@ -123,9 +125,71 @@ func (h *FileHandler) GetPublicLinks(ctx *gin.Context) {
 	})
 }
 
+// GetPublicLinkAccesses godoc
+// @Summary Get public link access log
+// @Description Get downloads made through a public link, newest first
+// @Tags files
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param file_id path string true "File ID" format(uuid)
+// @Param link_id path string true "Public Link ID" format(uuid)
+// @Success 200 {object} ListPublicLinkAccessesResponse "Access log"
+// @Failure 400 {object} map[string]string "Invalid parameters"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "File or link not found"
+// @Failure 500 {object} map[string]string "Failed to get access log"
+// @Router /files/{file_id}/public-links/{link_id}/accesses [get]
+func (h *FileHandler) GetPublicLinkAccesses(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	fileID, err := uuid.Parse(ctx.Param("file_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid file_id format"})
+		return
+	}
+
+	linkID, err := uuid.Parse(ctx.Param("link_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid link_id format"})
+		return
+	}
+
+	f, err := h.fileService.GetByID(ctx, fileID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if f == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
+		return
+	}
+	if !h.authorize(ctx, f, userID, share.RoleOwner) {
+		return
+	}
+
+	accesses, err := h.publicLinkService.GetAccesses(ctx, fileID, linkID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	resp := PresentPublicLinkAccesses(accesses)
+	ctx.JSON(http.StatusOK, ListPublicLinkAccessesResponse{
+		Accesses: resp,
+		Total:    len(resp),
+	})
+}
+
 // CreatePublicLink godoc
 // @Summary Create public link for file
-// @Description Generate a public shareable link for downloading the file
+// @Description Generate a public shareable link for downloading the file.
+// @Description The token is returned only in this response: the server keeps just its SHA-256 hash.
 // @Tags files
 // @Security Bearer
 // @Accept json
@@ -167,8 +231,7 @@ func (h *FileHandler) CreatePublicLink(ctx *gin.Context) {
 		ctx.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
 		return
 	}
-	if f.OwnerID != userID {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+	if !h.authorize(ctx, f, userID, share.RoleOwner) {
 		return
 	}
 
@@ -182,8 +245,6 @@ func (h *FileHandler) CreatePublicLink(ctx *gin.Context) {
 		expiresAt = time.Now().Add(dur)
 	}
 
-	token := uuid.New().String()[:16]
-
 	if f.Status.Equal(file_version.FileStatusProcessing) {
 		ctx.Error(file_version.ErrVersionProcessing)
 		return
@@ -194,84 +255,226 @@ func (h *FileHandler) CreatePublicLink(ctx *gin.Context) {
 		return
 	}
 
-	link, err := h.publicLinkService.Create(ctx, fileID, userID, token, expiresAt)
+	var versionID *uuid.UUID
+	if input.VersionID != nil {
+		id, err := uuid.Parse(*input.VersionID)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid version_id format"})
+			return
+		}
+		versionID = &id
+	}
+
+	link, token, err := h.publicLinkService.Create(ctx, fileID, userID, expiresAt, input.Password, input.MaxDownloads, input.VersionMode, versionID)
 	if err != nil {
 		_ = ctx.Error(err)
 		return
 	}
 
-	resp := PresentPublicLink(link)
+	resp := PresentPublicLink(link, token)
 
 	ctx.JSON(http.StatusCreated, resp)
 }
 
 // DownloadByPublicLink godoc
 // @Summary Download file by public link
-// @Description Get presigned download URL for file using public link token (no authentication required)
+// @Description Get presigned download URL for file using public link token (no authentication required).
+// @Description Browsers (Accept: text/html) get an HTML landing page with preview and OpenGraph tags instead;
+// @Description download=1 counts the download and redirects to the file.
 // @Tags public
 // @Accept json
-// @Produce json
+// @Produce json,html
 // @Param token path string true "Public link token"
+// @Param X-Unlock-Token header string false "Unlock token of a password protected link"
+// @Param unlock_token query string false "Unlock token of a password protected link"
+// @Param download query string false "Redirect to the file instead of describing it"
 // @Success 200 {object} PublicDownloadResponse "Download URL generated"
+// @Success 302 "Redirect to the file (download=1)"
 // @Failure 400 {object} map[string]string "Invalid token format"
-// @Failure 404 {object} map[string]string "Public link not found or expired"
+// @Failure 401 {object} PublicLinkChallengeResponse "Password required or unlock token invalid"
+// @Failure 404 {object} map[string]string "Public link not found, expired or out of downloads"
+// @Failure 410 {object} map[string]string "Pinned version was deleted"
 // @Failure 500 {object} map[string]string "Failed to generate download URL"
 // @Router /public-links/{token} [get]
 func (h *FileHandler) DownloadByPublicLink(ctx *gin.Context) {
 	token := ctx.Param("token")
+	page := wantsHTML(ctx)
+	download := ctx.Query("download") != ""
+
 	if token == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "token is required"})
+		publicLinkFailure(ctx, page, http.StatusBadRequest, "token is required")
 		return
 	}
 
 	link, err := h.publicLinkService.GetByToken(ctx, token)
+	if errors.Is(err, public_link.ErrNotFound) || errors.Is(err, public_link.ErrExpired) {
+		publicLinkFailure(ctx, page, http.StatusNotFound, err.Error())
+		return
+	}
 	if err != nil {
-		_ = ctx.Error(err)
+		publicLinkError(ctx, page, err)
 		return
 	}
 	if link == nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "public link not found"})
+		publicLinkFailure(ctx, page, http.StatusNotFound, "public link not found")
 		return
 	}
 
-	if link.IsExpired() {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "public link has expired"})
+	if !link.IsActive() {
+		publicLinkFailure(ctx, page, http.StatusNotFound, "public link has expired")
+		return
+	}
+
+	unlockToken := ctx.GetHeader("X-Unlock-Token")
+	if unlockToken == "" {
+		unlockToken = ctx.Query("unlock_token")
+	}
+	if err := h.publicLinkService.CheckUnlock(link, unlockToken); err != nil {
+		unlockURL := "/api/v1/public-links/unlock/" + token
+		if page {
+			message := ""
+			if errors.Is(err, public_link.ErrInvalidUnlockToken) {
+				message = "The unlock has expired, enter the password again."
+			}
+			renderPasswordPage(ctx, http.StatusUnauthorized, unlockURL, message)
+			return
+		}
+
+		code := "PASSWORD_REQUIRED"
+		if errors.Is(err, public_link.ErrInvalidUnlockToken) {
+			code = "INVALID_UNLOCK_TOKEN"
+		}
+		ctx.Header("WWW-Authenticate", `Password realm="public-link"`)
+		ctx.JSON(http.StatusUnauthorized, PublicLinkChallengeResponse{
+			Code:      code,
+			Message:   err.Error(),
+			UnlockURL: unlockURL,
+		})
 		return
 	}
 
+	// Файл в корзине сохраняет свои ссылки, но открывать их нельзя
 	file, err := h.fileService.GetByID(ctx, link.FileID)
+	if errors.Is(err, domainFile.ErrNotFound) {
+		publicLinkFailure(ctx, page, http.StatusNotFound, "file not found")
+		return
+	}
 	if err != nil {
-		_ = ctx.Error(err)
+		publicLinkError(ctx, page, err)
 		return
 	}
-	if file == nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
+
+	version, err := h.publicLinkService.ResolveVersion(ctx, link, file)
+	if errors.Is(err, public_link.ErrPinnedVersionDeleted) {
+		publicLinkFailure(ctx, page, http.StatusGone, err.Error())
+		return
+	}
+	if errors.Is(err, file_version.ErrVersionNotFound) {
+		publicLinkFailure(ctx, page, http.StatusNotFound, "file version not found")
+		return
+	}
+	if err != nil {
+		publicLinkError(ctx, page, err)
+		return
+	}
+
+	// Страница сама по себе скачиванием не считается: его засчитывает кнопка, ведущая на download=1
+	if page && !download {
+		h.renderLandingPage(ctx, token, unlockToken, file, version)
 		return
 	}
 
-	versions, err := h.fileVersionService.GetVersionsByFileID(ctx, file.ID)
+	downloadURL, err := h.fileVersionService.GetDownloadURL(ctx, version.ID, 1*time.Hour)
 	if err != nil {
-		_ = ctx.Error(err)
+		publicLinkError(ctx, page, err)
+		return
+	}
+
+	// Скачивание засчитывается после выдачи URL, чтобы ошибка подписи не тратила лимит
+	err = h.publicLinkService.RegisterDownload(ctx, link, version.ID, ctx.ClientIP(), ctx.Request.UserAgent())
+	if errors.Is(err, public_link.ErrExhausted) {
+		publicLinkFailure(ctx, page, http.StatusNotFound, "public link has expired")
+		return
+	}
+	if err != nil {
+		publicLinkError(ctx, page, err)
 		return
 	}
 
-	var currentVersion *file_version.FileVersion
-	for _, v := range versions {
-		if v.VersionNum.Int() == file.VersionNum.Int() {
-			currentVersion = v
-			break
+	if download {
+		ctx.Header("Cache-Control", "no-store")
+		ctx.Redirect(http.StatusFound, *downloadURL)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, PresentPublicDownload(file, version, *downloadURL))
+}
+
+// UnlockPublicLink godoc
+// @Summary Unlock password protected public link
+// @Description Check the link password and issue a short-lived unlock token (no authentication required).
+// @Description A form post from the landing page is redirected back to the page with the unlock token.
+// @Tags public
+// @Accept json,x-www-form-urlencoded
+// @Produce json,html
+// @Param token path string true "Public link token"
+// @Param request body UnlockPublicLinkInput true "Link password"
+// @Success 200 {object} UnlockPublicLinkResponse "Unlock token issued"
+// @Success 303 "Redirect to the landing page (form post)"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 401 {object} map[string]string "Wrong password"
+// @Failure 404 {object} map[string]string "Public link not found"
+// @Failure 429 {object} map[string]string "Too many wrong passwords"
+// @Router /public-links/unlock/{token} [post]
+func (h *FileHandler) UnlockPublicLink(ctx *gin.Context) {
+	token := ctx.Param("token")
+	form := ctx.ContentType() == gin.MIMEPOSTForm
+	unlockURL := "/api/v1/public-links/unlock/" + token
+
+	var input UnlockPublicLinkInput
+	bind := ctx.ShouldBindJSON
+	if form {
+		bind = ctx.ShouldBind
+	}
+	if err := bind(&input); err != nil {
+		if form {
+			renderPasswordPage(ctx, http.StatusBadRequest, unlockURL, "Enter the password.")
+			return
 		}
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-	if currentVersion == nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "file version not found"})
+
+	link, err := h.publicLinkService.GetByToken(ctx, token)
+	if err != nil {
+		if form {
+			status, _, _ := middleware.MapError(err)
+			renderErrorPage(ctx, status, "This link is not available", err.Error())
+			return
+		}
+		_ = ctx.Error(err)
 		return
 	}
 
-	downloadURL, err := h.fileVersionService.GetDownloadURL(ctx, currentVersion.ID, 1*time.Hour)
+	unlockToken, err := h.publicLinkService.Unlock(ctx, link, input.Password, ctx.ClientIP())
 	if err != nil {
+		if form {
+			status, _, message := middleware.MapError(err)
+			renderPasswordPage(ctx, status, unlockURL, message)
+			return
+		}
 		_ = ctx.Error(err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, PresentPublicDownload(file, currentVersion, *downloadURL))
+	if form {
+		// 303, чтобы браузер перешёл на страницу ссылки GET-запросом
+		ctx.Redirect(http.StatusSeeOther, publicLinkPageURL(token, unlockToken, false))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, UnlockPublicLinkResponse{
+		UnlockToken: unlockToken,
+		ExpiresIn:   h.publicLinkService.UnlockTTL().String(),
+	})
 }
@@ -0,0 +1,195 @@
+package files_handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/api/middleware"
+	"github.com/yourusername/cloud-file-storage/internal/domain/share"
+)
+
+// CreateShare godoc
+// @Summary Share file with user
+// @Description Grant a registered user access to the file. viewer can read and download, editor can also upload new versions, rename and restore versions, owner can also delete, move and manage links and shares.
+// @Description Sharing again with the same user changes the role.
+// @Tags files
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param file_id path string true "File ID" format(uuid)
+// @Param request body ShareInput true "User email and role"
+// @Success 201 {object} ShareResponse "Access granted"
+// @Failure 400 {object} map[string]string "Invalid input or sharing with the file owner"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "File or user not found"
+// @Failure 500 {object} map[string]string "Failed to share file"
+// @Router /files/{file_id}/shares [post]
+func (h *FileHandler) CreateShare(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	fileID, err := uuid.Parse(ctx.Param("file_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid file_id format"})
+		return
+	}
+
+	var input ShareInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	f, err := h.fileService.GetByID(ctx, fileID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if !h.authorize(ctx, f, userID, share.RoleOwner) {
+		return
+	}
+
+	sh, err := h.shareService.Grant(ctx, f, userID, input.Email, input.Role)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, PresentShare(sh))
+}
+
+// ListShares godoc
+// @Summary List file shares
+// @Description Get users the file is shared with and their roles
+// @Tags files
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param file_id path string true "File ID" format(uuid)
+// @Success 200 {object} ListSharesResponse "File shares"
+// @Failure 400 {object} map[string]string "Invalid file_id format"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "File not found"
+// @Failure 500 {object} map[string]string "Failed to list shares"
+// @Router /files/{file_id}/shares [get]
+func (h *FileHandler) ListShares(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	fileID, err := uuid.Parse(ctx.Param("file_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid file_id format"})
+		return
+	}
+
+	f, err := h.fileService.GetByID(ctx, fileID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if !h.authorize(ctx, f, userID, share.RoleOwner) {
+		return
+	}
+
+	shares, err := h.shareService.ListByFile(ctx, fileID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	resp := PresentShares(shares)
+	ctx.JSON(http.StatusOK, ListSharesResponse{
+		Shares: resp,
+		Total:  len(resp),
+	})
+}
+
+// DeleteShare godoc
+// @Summary Revoke file share
+// @Description Revoke a user's access to the file. Users with the owner role can revoke any share; a user can always give up their own share.
+// @Tags files
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param file_id path string true "File ID" format(uuid)
+// @Param share_id path string true "Share ID" format(uuid)
+// @Success 204 "Share revoked"
+// @Failure 400 {object} map[string]string "Invalid parameters"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "File or share not found"
+// @Failure 500 {object} map[string]string "Failed to revoke share"
+// @Router /files/{file_id}/shares/{share_id} [delete]
+func (h *FileHandler) DeleteShare(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	fileID, err := uuid.Parse(ctx.Param("file_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid file_id format"})
+		return
+	}
+
+	shareID, err := uuid.Parse(ctx.Param("share_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid share_id format"})
+		return
+	}
+
+	f, err := h.fileService.GetByID(ctx, fileID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	// право на отзыв проверяет сервис: свой доступ можно отозвать и без роли owner
+	if err := h.shareService.Revoke(ctx, f, shareID, userID); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListSharedWithMe godoc
+// @Summary List files shared with me
+// @Description Get files of other users the current user has access to, with the granted role
+// @Tags files
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Success 200 {object} ListSharedFilesResponse "Shared files"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 500 {object} map[string]string "Failed to list shared files"
+// @Router /files/shared [get]
+func (h *FileHandler) ListSharedWithMe(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	files, err := h.shareService.SharedWith(ctx, userID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	resp := PresentSharedFiles(files)
+	ctx.JSON(http.StatusOK, ListSharedFilesResponse{
+		Files: resp,
+		Total: len(resp),
+	})
+}
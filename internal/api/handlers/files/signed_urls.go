@@ -0,0 +1,197 @@
+package files_handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/api/middleware"
+	"github.com/yourusername/cloud-file-storage/internal/domain/share"
+	"github.com/yourusername/cloud-file-storage/internal/domain/signed_url"
+)
+
+// CreateSignedURL godoc
+// @Summary Create signed URL
+// @Description Sign a stateless URL to a file version. The URL is checked by its HMAC signature without
+// @Description a database lookup and works until it expires or the owner revokes all signed URLs of the file.
+// @Tags files
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param file_id path string true "File ID" format(uuid)
+// @Param request body SignedURLInput true "Version, permissions and lifetime"
+// @Success 201 {object} SignedURLResponse "Signed URL created"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "File or version not found"
+// @Failure 409 {object} map[string]string "Version content is not uploaded"
+// @Failure 500 {object} map[string]string "Failed to sign URL"
+// @Router /files/{file_id}/signed-urls [post]
+func (h *FileHandler) CreateSignedURL(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	fileID, err := uuid.Parse(ctx.Param("file_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid file_id format"})
+		return
+	}
+
+	var input SignedURLInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var ttl time.Duration
+	if input.ExpiresIn != "" {
+		ttl, err = time.ParseDuration(input.ExpiresIn)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid expires_in format (use 15m, 1h, 72h, etc)"})
+			return
+		}
+	}
+
+	var versionID *uuid.UUID
+	if input.VersionID != nil {
+		id, err := uuid.Parse(*input.VersionID)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid version_id format"})
+			return
+		}
+		versionID = &id
+	}
+
+	f, err := h.fileService.GetByID(ctx, fileID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if f == nil || f.IsTrashed() {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
+		return
+	}
+	if !h.authorize(ctx, f, userID, share.RoleOwner) {
+		return
+	}
+
+	claims, query, err := h.signedURLService.Mint(ctx, f, versionID, input.Permissions, ttl)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, PresentSignedURL(claims, query))
+}
+
+// RevokeSignedURLs godoc
+// @Summary Revoke signed URLs
+// @Description Invalidate every signed URL of the file issued so far. Other API nodes
+// @Description pick up the revocation within signed_urls.state_ttl.
+// @Tags files
+// @Security Bearer
+// @Produce json
+// @Param file_id path string true "File ID" format(uuid)
+// @Success 200 {object} RevokeSignedURLsResponse "Signed URLs revoked"
+// @Failure 400 {object} map[string]string "Invalid file_id format"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "File not found"
+// @Failure 500 {object} map[string]string "Failed to revoke signed URLs"
+// @Router /files/{file_id}/signed-urls [delete]
+func (h *FileHandler) RevokeSignedURLs(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	fileID, err := uuid.Parse(ctx.Param("file_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid file_id format"})
+		return
+	}
+
+	f, err := h.fileService.GetByID(ctx, fileID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if f == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "file not found"})
+		return
+	}
+	if !h.authorize(ctx, f, userID, share.RoleOwner) {
+		return
+	}
+
+	epoch, err := h.signedURLService.Revoke(ctx, fileID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, RevokeSignedURLsResponse{
+		Message:   "Signed URLs revoked successfully",
+		RevokedAt: time.Unix(epoch, 0).UTC().Format(timeFmt),
+	})
+}
+
+// DownloadBySignedURL godoc
+// @Summary Download by signed URL
+// @Description Redirect to the version content (no authentication required, access is checked by the signature)
+// @Tags public
+// @Param file_id path string true "File ID" format(uuid)
+// @Param v query string true "Version ID"
+// @Param perm query string true "Granted permissions"
+// @Param iat query int true "Issued at, unix seconds"
+// @Param exp query int true "Expires at, unix seconds"
+// @Param kid query string true "Signing key ID"
+// @Param sig query string true "Signature"
+// @Success 302 "Redirect to the storage URL"
+// @Failure 403 {object} map[string]string "Invalid, expired or revoked signed URL"
+// @Failure 404 {object} map[string]string "File or version not found"
+// @Router /signed/files/{file_id}/content [get]
+func (h *FileHandler) DownloadBySignedURL(ctx *gin.Context) {
+	h.redirectBySignedURL(ctx, signed_url.PermissionDownload)
+}
+
+// PreviewBySignedURL godoc
+// @Summary Preview by signed URL
+// @Description Redirect to the version preview; the URL must grant the preview permission
+// @Tags public
+// @Param file_id path string true "File ID" format(uuid)
+// @Param v query string true "Version ID"
+// @Param perm query string true "Granted permissions"
+// @Param iat query int true "Issued at, unix seconds"
+// @Param exp query int true "Expires at, unix seconds"
+// @Param kid query string true "Signing key ID"
+// @Param sig query string true "Signature"
+// @Success 302 "Redirect to the storage URL"
+// @Failure 403 {object} map[string]string "Invalid, expired or revoked signed URL"
+// @Failure 404 {object} map[string]string "File, version or preview not found"
+// @Router /signed/files/{file_id}/preview [get]
+func (h *FileHandler) PreviewBySignedURL(ctx *gin.Context) {
+	h.redirectBySignedURL(ctx, signed_url.PermissionPreview)
+}
+
+func (h *FileHandler) redirectBySignedURL(ctx *gin.Context, permission signed_url.Permission) {
+	fileID, err := uuid.Parse(ctx.Param("file_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid file_id format"})
+		return
+	}
+
+	storageURL, err := h.signedURLService.Resolve(ctx, fileID, ctx.Request.URL.Query(), permission)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.Redirect(http.StatusFound, storageURL)
+}
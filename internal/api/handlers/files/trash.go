@@ -0,0 +1,149 @@
+package files_handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/api/middleware"
+	"github.com/yourusername/cloud-file-storage/internal/domain/share"
+)
+
+// ListTrash godoc
+// @Summary List trashed files
+// @Description Get files of the current user that are in trash, most recently deleted first
+// @Tags files
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Success 200 {object} ListTrashResponse "Trashed files"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 500 {object} map[string]string "Failed to list trash"
+// @Router /files/trash [get]
+func (h *FileHandler) ListTrash(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	files, err := h.fileService.GetTrash(ctx, userID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	items := PresentFiles(files)
+
+	fileResponses := make([]*FileResponse, 0, len(items))
+	for i := range items {
+		item := items[i]
+		fileResponses = append(fileResponses, &item)
+	}
+
+	ctx.JSON(http.StatusOK, ListTrashResponse{
+		Files: fileResponses,
+	})
+}
+
+// RestoreFile godoc
+// @Summary Restore file from trash
+// @Description Restore a trashed file to its folder, or to the root if the folder no longer exists
+// @Tags files
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param file_id path string true "File ID" format(uuid)
+// @Success 200 {object} FileResponse "File restored"
+// @Failure 400 {object} map[string]string "Invalid file_id format"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "File not found"
+// @Failure 409 {object} map[string]string "File is not in trash"
+// @Failure 500 {object} map[string]string "Failed to restore file"
+// @Router /files/{file_id}/restore [post]
+func (h *FileHandler) RestoreFile(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	fileIDStr := ctx.Param("file_id")
+	fileID, err := uuid.Parse(fileIDStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid file_id format"})
+		return
+	}
+
+	f, err := h.fileService.GetTrashedByID(ctx, fileID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if !h.authorize(ctx, f, userID, share.RoleOwner) {
+		return
+	}
+
+	if err := h.fileService.Restore(ctx, fileID); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	restored, err := h.fileService.GetByID(ctx, fileID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, PresentFile(restored))
+}
+
+// DeleteFilePermanently godoc
+// @Summary Permanently delete file
+// @Description Permanently delete a trashed file, all its versions and stored objects
+// @Tags files
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param file_id path string true "File ID" format(uuid)
+// @Success 200 {object} DeleteFileResponse "File permanently deleted"
+// @Failure 400 {object} map[string]string "Invalid file_id format"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "File not found"
+// @Failure 409 {object} map[string]string "File is not in trash"
+// @Failure 500 {object} map[string]string "Failed to delete file"
+// @Router /files/{file_id}/permanent [delete]
+func (h *FileHandler) DeleteFilePermanently(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	fileIDStr := ctx.Param("file_id")
+	fileID, err := uuid.Parse(fileIDStr)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid file_id format"})
+		return
+	}
+
+	f, err := h.fileService.GetTrashedByID(ctx, fileID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if !h.authorize(ctx, f, userID, share.RoleOwner) {
+		return
+	}
+
+	if err := h.fileService.Purge(ctx, fileID); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, DeleteFileResponse{
+		Message: "File permanently deleted",
+	})
+}
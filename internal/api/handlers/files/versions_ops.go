@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/yourusername/cloud-file-storage/internal/api/middleware"
 	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/share"
 )
 
 // GetVersionDownloadURL godoc
@@ -51,8 +52,7 @@ func (h *FileHandler) GetVersionDownloadURL(ctx *gin.Context) {
 		return
 	}
 
-	if file.OwnerID != userID {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+	if !h.authorize(ctx, file, userID, share.RoleViewer) {
 		return
 	}
 
@@ -160,8 +160,7 @@ func (h *FileHandler) RestoreFileVersion(ctx *gin.Context) {
 		return
 	}
 
-	if f.OwnerID != userID {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+	if !h.authorize(ctx, f, userID, share.RoleEditor) {
 		return
 	}
 
@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/yourusername/cloud-file-storage/internal/api/middleware"
+	"github.com/yourusername/cloud-file-storage/internal/domain/share"
 )
 
 // GetFileVersions godoc
@@ -48,8 +49,7 @@ func (h *FileHandler) GetFileVersions(ctx *gin.Context) {
 		return
 	}
 
-	if f.OwnerID != userID {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+	if !h.authorize(ctx, f, userID, share.RoleViewer) {
 		return
 	}
 
@@ -0,0 +1,37 @@
+package folders_handler
+
+type CreateFolderInput struct {
+	Name     string  `json:"name" binding:"required,min=1"`
+	ParentID *string `json:"parent_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+type RenameFolderInput struct {
+	Name string `json:"name" binding:"required,min=1"`
+}
+
+// MoveFolderInput parent_id == null переносит папку в корень
+type MoveFolderInput struct {
+	ParentID *string `json:"parent_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+}
+
+type FolderResponse struct {
+	ID        string  `json:"id" example:"123e4567-e89b-12d3-a456-426614174002"`
+	ParentID  *string `json:"parent_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name      string  `json:"name" example:"Documents"`
+	CreatedAt string  `json:"created_at" example:"2025-11-04T12:00:00Z"`
+	UpdatedAt string  `json:"updated_at" example:"2025-11-04T12:00:00Z"`
+}
+
+type FolderDetailResponse struct {
+	FolderResponse
+	Path     []*FolderResponse `json:"path"`
+	Children []*FolderResponse `json:"children"`
+}
+
+type ListFoldersResponse struct {
+	Folders []*FolderResponse `json:"folders"`
+}
+
+type DeleteFolderResponse struct {
+	Message string `json:"message" example:"Folder deleted successfully"`
+}
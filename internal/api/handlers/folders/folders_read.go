@@ -0,0 +1,120 @@
+package folders_handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/api/middleware"
+)
+
+// ListFolders godoc
+// @Summary List folders
+// @Description Get child folders of a parent folder; omit parent_id to list root folders
+// @Tags folders
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param parent_id query string false "Parent folder ID" format(uuid)
+// @Success 200 {object} ListFoldersResponse "List of folders"
+// @Failure 400 {object} map[string]string "Invalid parent_id format"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Folder not found"
+// @Failure 500 {object} map[string]string "Failed to list folders"
+// @Router /folders [get]
+func (h *FolderHandler) ListFolders(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var parentID *uuid.UUID
+	if parentIDStr := ctx.Query("parent_id"); parentIDStr != "" {
+		id, ok := h.ownedFolderID(ctx, userID, parentIDStr, "invalid parent_id format")
+		if !ok {
+			return
+		}
+		parentID = &id
+	}
+
+	folders, err := h.folderService.GetChildren(ctx, userID, parentID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ListFoldersResponse{
+		Folders: PresentFolders(folders),
+	})
+}
+
+// GetFolder godoc
+// @Summary Get folder
+// @Description Get folder metadata with its path from the root and its child folders
+// @Tags folders
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param folder_id path string true "Folder ID" format(uuid)
+// @Success 200 {object} FolderDetailResponse "Folder details"
+// @Failure 400 {object} map[string]string "Invalid folder_id format"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Folder not found"
+// @Failure 500 {object} map[string]string "Failed to get folder"
+// @Router /folders/{folder_id} [get]
+func (h *FolderHandler) GetFolder(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	folderID, ok := h.ownedFolderID(ctx, userID, ctx.Param("folder_id"), "invalid folder_id format")
+	if !ok {
+		return
+	}
+
+	f, err := h.folderService.GetByID(ctx, folderID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	path, err := h.folderService.GetPath(ctx, folderID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	children, err := h.folderService.GetChildren(ctx, userID, &folderID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, PresentFolderDetail(f, path, children))
+}
+
+// ownedFolderID разбирает ID папки и проверяет владельца. При ошибке ответ уже записан в ctx.
+func (h *FolderHandler) ownedFolderID(ctx *gin.Context, userID uuid.UUID, raw string, badFormatMsg string) (uuid.UUID, bool) {
+	folderID, err := uuid.Parse(raw)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": badFormatMsg})
+		return uuid.Nil, false
+	}
+
+	f, err := h.folderService.GetByID(ctx, folderID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return uuid.Nil, false
+	}
+	if f.OwnerID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		return uuid.Nil, false
+	}
+
+	return folderID, true
+}
@@ -0,0 +1,200 @@
+package folders_handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/api/middleware"
+)
+
+// CreateFolder godoc
+// @Summary Create folder
+// @Description Create a folder in the root or inside a parent folder
+// @Tags folders
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body CreateFolderInput true "Folder data"
+// @Success 201 {object} FolderResponse "Folder created"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied to parent folder"
+// @Failure 404 {object} map[string]string "Parent folder not found"
+// @Failure 409 {object} map[string]string "Folder with this name already exists"
+// @Failure 500 {object} map[string]string "Failed to create folder"
+// @Router /folders [post]
+func (h *FolderHandler) CreateFolder(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var input CreateFolderInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var parentID *uuid.UUID
+	if input.ParentID != nil {
+		id, ok := h.ownedFolderID(ctx, userID, *input.ParentID, "invalid parent_id format")
+		if !ok {
+			return
+		}
+		parentID = &id
+	}
+
+	f, err := h.folderService.Create(ctx, userID, parentID, input.Name)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, PresentFolder(f))
+}
+
+// RenameFolder godoc
+// @Summary Rename folder
+// @Description Change folder name
+// @Tags folders
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param folder_id path string true "Folder ID" format(uuid)
+// @Param request body RenameFolderInput true "New folder name"
+// @Success 200 {object} FolderResponse "Folder renamed"
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Folder not found"
+// @Failure 409 {object} map[string]string "Folder with this name already exists"
+// @Failure 500 {object} map[string]string "Failed to rename folder"
+// @Router /folders/{folder_id} [patch]
+func (h *FolderHandler) RenameFolder(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var input RenameFolderInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	folderID, ok := h.ownedFolderID(ctx, userID, ctx.Param("folder_id"), "invalid folder_id format")
+	if !ok {
+		return
+	}
+
+	if err := h.folderService.Rename(ctx, folderID, input.Name); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	f, err := h.folderService.GetByID(ctx, folderID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, PresentFolder(f))
+}
+
+// MoveFolder godoc
+// @Summary Move folder
+// @Description Move a folder with all its contents under another parent; null parent_id moves it to the root
+// @Tags folders
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param folder_id path string true "Folder ID" format(uuid)
+// @Param request body MoveFolderInput true "Target parent folder"
+// @Success 200 {object} FolderResponse "Folder moved"
+// @Failure 400 {object} map[string]string "Invalid input or move into own subtree"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Folder not found"
+// @Failure 409 {object} map[string]string "Folder with this name already exists"
+// @Failure 500 {object} map[string]string "Failed to move folder"
+// @Router /folders/{folder_id}/move [post]
+func (h *FolderHandler) MoveFolder(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var input MoveFolderInput
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	folderID, ok := h.ownedFolderID(ctx, userID, ctx.Param("folder_id"), "invalid folder_id format")
+	if !ok {
+		return
+	}
+
+	var parentID *uuid.UUID
+	if input.ParentID != nil {
+		id, ok := h.ownedFolderID(ctx, userID, *input.ParentID, "invalid parent_id format")
+		if !ok {
+			return
+		}
+		parentID = &id
+	}
+
+	if err := h.folderService.Move(ctx, folderID, parentID); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	f, err := h.folderService.GetByID(ctx, folderID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, PresentFolder(f))
+}
+
+// DeleteFolder godoc
+// @Summary Delete folder
+// @Description Delete a folder together with all nested folders; their files are moved to trash
+// @Tags folders
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param folder_id path string true "Folder ID" format(uuid)
+// @Success 200 {object} DeleteFolderResponse "Folder deleted"
+// @Failure 400 {object} map[string]string "Invalid folder_id format or files are processing"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Folder not found"
+// @Failure 500 {object} map[string]string "Failed to delete folder"
+// @Router /folders/{folder_id} [delete]
+func (h *FolderHandler) DeleteFolder(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	folderID, ok := h.ownedFolderID(ctx, userID, ctx.Param("folder_id"), "invalid folder_id format")
+	if !ok {
+		return
+	}
+
+	if err := h.folderService.Delete(ctx, folderID); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, DeleteFolderResponse{
+		Message: "Folder deleted successfully",
+	})
+}
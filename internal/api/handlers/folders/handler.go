@@ -0,0 +1,15 @@
+package folders_handler
+
+import (
+	folder_service "github.com/yourusername/cloud-file-storage/internal/app/folder"
+)
+
+type FolderHandler struct {
+	folderService *folder_service.FolderService
+}
+
+func NewFolderHandler(folderService *folder_service.FolderService) *FolderHandler {
+	return &FolderHandler{
+		folderService: folderService,
+	}
+}
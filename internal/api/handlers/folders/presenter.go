@@ -0,0 +1,44 @@
+package folders_handler
+
+import (
+	"time"
+
+	"github.com/yourusername/cloud-file-storage/internal/domain/folder"
+)
+
+const timeFmt = time.RFC3339
+
+func PresentFolder(f *folder.Folder) *FolderResponse {
+	var parentID *string
+	if f.ParentID != nil {
+		id := f.ParentID.String()
+		parentID = &id
+	}
+	return &FolderResponse{
+		ID:        f.ID.String(),
+		ParentID:  parentID,
+		Name:      f.Name.String(),
+		CreatedAt: f.CreatedAt.UTC().Format(timeFmt),
+		UpdatedAt: f.UpdatedAt.UTC().Format(timeFmt),
+	}
+}
+
+func PresentFolders(folders []*folder.Folder) []*FolderResponse {
+	out := make([]*FolderResponse, 0, len(folders))
+	for _, f := range folders {
+		if f == nil {
+			continue
+		}
+		out = append(out, PresentFolder(f))
+	}
+	return out
+}
+
+// PresentFolderDetail path идёт от корня до самой папки включительно
+func PresentFolderDetail(f *folder.Folder, path []*folder.Folder, children []*folder.Folder) FolderDetailResponse {
+	return FolderDetailResponse{
+		FolderResponse: *PresentFolder(f),
+		Path:           PresentFolders(path),
+		Children:       PresentFolders(children),
+	}
+}
@@ -0,0 +1,65 @@
+package notifications_handler
+
+import (
+	"crypto/subtle"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	file_version_service "github.com/yourusername/cloud-file-storage/internal/app/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/infra/storage"
+)
+
+// Уведомление несёт метаданные, а не содержимое объектов
+const maxNotificationSize = 1 << 20
+
+// NotificationHandler принимает уведомления бакета от webhook цели S3/MinIO
+type NotificationHandler struct {
+	versionSrv *file_version_service.FileVersionService
+	token      string
+}
+
+func NewNotificationHandler(versionSrv *file_version_service.FileVersionService, token string) *NotificationHandler {
+	return &NotificationHandler{versionSrv: versionSrv, token: token}
+}
+
+// HandleBucketNotification godoc
+// @Summary Receive bucket notification
+// @Description Webhook target for S3/MinIO bucket notifications. s3:ObjectCreated:* events on a pending version key complete its upload; other events and keys are ignored.
+// @Tags storage
+// @Accept json
+// @Param Authorization header string true "Webhook token, optionally prefixed with Bearer"
+// @Success 204 "Notification processed"
+// @Failure 400 {object} map[string]string "Malformed notification"
+// @Failure 401 {object} map[string]string "Invalid webhook token"
+// @Failure 500 {object} map[string]string "Internal server error, the sender should retry"
+// @Router /storage/notifications [post]
+func (h *NotificationHandler) HandleBucketNotification(ctx *gin.Context) {
+	token := strings.TrimPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid webhook token"})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, maxNotificationSize))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	events, err := storage.ParseBucketNotification(body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	for _, e := range events {
+		if _, err := h.versionSrv.CompleteUploadByKey(ctx.Request.Context(), e.Key); err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
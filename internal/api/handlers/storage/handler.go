@@ -0,0 +1,133 @@
+package storage_handler
+
+import (
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	domain_storage "github.com/yourusername/cloud-file-storage/internal/domain/storage"
+	"github.com/yourusername/cloud-file-storage/internal/infra/storage"
+)
+
+// StorageHandler обслуживает подписанные URL локального и шифрующего хранилищ вместо presigned URL S3
+type StorageHandler struct {
+	storage storage.SignedStorage
+}
+
+func NewStorageHandler(storage storage.SignedStorage) *StorageHandler {
+	return &StorageHandler{storage: storage}
+}
+
+// PutObject godoc
+// @Summary Upload object by signed URL
+// @Description Local or encrypted storage replacement for an S3 presigned PUT. The URL comes from the upload endpoints and is valid until it expires.
+// @Tags storage
+// @Accept octet-stream
+// @Param key path string true "Object key"
+// @Param expires query int true "Expiry, unix seconds"
+// @Param signature query string true "URL signature"
+// @Success 200 "Object stored"
+// @Failure 400 {object} map[string]string "Invalid key or size mismatch"
+// @Failure 403 {object} map[string]string "Invalid or expired signature"
+// @Router /storage/objects/{key} [put]
+func (h *StorageHandler) PutObject(ctx *gin.Context) {
+	key := strings.TrimPrefix(ctx.Param("key"), "/")
+
+	size, err := h.storage.VerifyObjectURL(http.MethodPut, key, ctx.Request.URL.Query())
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	if size >= 0 && ctx.Request.ContentLength != size {
+		_ = ctx.Error(domain_storage.ErrSizeMismatch)
+		return
+	}
+
+	err = h.storage.Put(ctx.Request.Context(), key, ctx.Request.Body, domain_storage.ObjectInfo{
+		ContentType:   ctx.GetHeader("Content-Type"),
+		ContentLength: ctx.Request.ContentLength,
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// GetObject godoc
+// @Summary Download object by signed URL
+// @Description Local or encrypted storage replacement for an S3 presigned GET. Supports Range requests.
+// @Tags storage
+// @Produce octet-stream
+// @Param key path string true "Object key"
+// @Param expires query int true "Expiry, unix seconds"
+// @Param signature query string true "URL signature"
+// @Success 200 {file} binary "Object content"
+// @Success 206 {file} binary "Requested range"
+// @Failure 403 {object} map[string]string "Invalid or expired signature"
+// @Failure 404 {object} map[string]string "Object not found"
+// @Router /storage/objects/{key} [get]
+func (h *StorageHandler) GetObject(ctx *gin.Context) {
+	key := strings.TrimPrefix(ctx.Param("key"), "/")
+
+	if _, err := h.storage.VerifyObjectURL(http.MethodGet, key, ctx.Request.URL.Query()); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	f, info, err := h.storage.Open(ctx.Request.Context(), key)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	defer f.Close()
+
+	if info.ContentType != "" {
+		ctx.Header("Content-Type", info.ContentType)
+	}
+	// Время изменения хранилище не отдаёт, условные запросы по If-Modified-Since не поддерживаются
+	http.ServeContent(ctx.Writer, ctx.Request, path.Base(key), time.Time{}, f)
+}
+
+// UploadPart godoc
+// @Summary Upload multipart part by signed URL
+// @Description Local or encrypted storage replacement for an S3 presigned UploadPart. The part ETag is returned in the ETag header.
+// @Tags storage
+// @Accept octet-stream
+// @Param upload_id path string true "Storage upload ID"
+// @Param part_number path int true "Part number"
+// @Param expires query int true "Expiry, unix seconds"
+// @Param signature query string true "URL signature"
+// @Success 200 "Part stored, ETag in header"
+// @Failure 400 {object} map[string]string "Invalid part number"
+// @Failure 403 {object} map[string]string "Invalid or expired signature"
+// @Failure 404 {object} map[string]string "Upload not found"
+// @Failure 413 {object} map[string]string "Part too large"
+// @Router /storage/uploads/{upload_id}/parts/{part_number} [put]
+func (h *StorageHandler) UploadPart(ctx *gin.Context) {
+	uploadID := ctx.Param("upload_id")
+
+	partNumber, err := strconv.ParseInt(ctx.Param("part_number"), 10, 32)
+	if err != nil || partNumber < 1 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid part_number"})
+		return
+	}
+
+	if err := h.storage.VerifyPartURL(uploadID, int32(partNumber), ctx.Request.URL.Query()); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	etag, err := h.storage.WritePart(ctx.Request.Context(), uploadID, int32(partNumber), ctx.Request.Body, ctx.Request.ContentLength)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.Header("ETag", etag)
+	ctx.Status(http.StatusOK)
+}
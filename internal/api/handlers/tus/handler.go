@@ -0,0 +1,100 @@
+package tus_handler
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/api/middleware"
+	folder_service "github.com/yourusername/cloud-file-storage/internal/app/folder"
+	tus_upload_service "github.com/yourusername/cloud-file-storage/internal/app/tus_upload"
+	"github.com/yourusername/cloud-file-storage/internal/domain/tus_upload"
+)
+
+type TusHandler struct {
+	tusService    *tus_upload_service.TusUploadService
+	folderService *folder_service.FolderService
+}
+
+func NewTusHandler(tusService *tus_upload_service.TusUploadService, folderService *folder_service.FolderService) *TusHandler {
+	return &TusHandler{
+		tusService:    tusService,
+		folderService: folderService,
+	}
+}
+
+// ownedUpload находит загрузку из пути и проверяет владельца. При ошибке ответ уже записан в ctx.
+func (h *TusHandler) ownedUpload(ctx *gin.Context) (*tus_upload.TusUpload, bool) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return nil, false
+	}
+
+	uploadID, err := uuid.Parse(ctx.Param("upload_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid upload_id format"})
+		return nil, false
+	}
+
+	u, err := h.tusService.GetByID(ctx, uploadID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return nil, false
+	}
+	if u.OwnerID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		return nil, false
+	}
+
+	return u, true
+}
+
+// resolveFolderID пустая строка означает корень. При ошибке ответ уже записан в ctx.
+func (h *TusHandler) resolveFolderID(ctx *gin.Context, userID uuid.UUID, raw string) (*uuid.UUID, bool) {
+	if raw == "" || raw == "root" {
+		return nil, true
+	}
+
+	folderID, err := uuid.Parse(raw)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid folder_id format"})
+		return nil, false
+	}
+
+	fd, err := h.folderService.GetByID(ctx, folderID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return nil, false
+	}
+	if fd.OwnerID != userID {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "access denied"})
+		return nil, false
+	}
+
+	return &folderID, true
+}
+
+// parseMetadata разбирает Upload-Metadata: пары "ключ base64(значение)" через запятую
+func parseMetadata(header string) (map[string]string, bool) {
+	meta := make(map[string]string)
+	if strings.TrimSpace(header) == "" {
+		return meta, true
+	}
+
+	for _, pair := range strings.Split(header, ",") {
+		key, encoded, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			return nil, false
+		}
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, false
+		}
+		meta[key] = string(value)
+	}
+
+	return meta, true
+}
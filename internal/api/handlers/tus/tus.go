@@ -0,0 +1,204 @@
+package tus_handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yourusername/cloud-file-storage/internal/api/middleware"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/tus_upload"
+)
+
+const tusExtensions = "creation,termination,checksum"
+
+// Options godoc
+// @Summary tus server capabilities
+// @Description Report supported tus version, extensions, checksum algorithms and the maximum upload size
+// @Tags uploads
+// @Success 204 "Capabilities in Tus-* headers"
+// @Router /uploads [options]
+func (h *TusHandler) Options(ctx *gin.Context) {
+	ctx.Header("Tus-Version", middleware.TusVersion)
+	ctx.Header("Tus-Extension", tusExtensions)
+	ctx.Header("Tus-Max-Size", strconv.FormatInt(file_version.MaxFileSize, 10))
+	ctx.Header("Tus-Checksum-Algorithm", tus_upload.SupportedChecksumAlgorithms)
+	ctx.Status(http.StatusNoContent)
+}
+
+// CreateUpload godoc
+// @Summary Create resumable upload
+// @Description tus creation extension. Creates a file in processing state; data is sent with PATCH to the returned Location. Upload-Metadata keys: filename (required), filetype, folder_id.
+// @Tags uploads
+// @Security Bearer
+// @Param Tus-Resumable header string true "Protocol version" default(1.0.0)
+// @Param Upload-Length header int true "Total upload size in bytes"
+// @Param Upload-Metadata header string true "Comma separated key base64(value) pairs"
+// @Success 201 "Upload created, URL in Location header"
+// @Failure 400 {object} map[string]string "Invalid Upload-Length or Upload-Metadata"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied to folder"
+// @Failure 412 {object} map[string]string "Unsupported tus version"
+// @Failure 413 {object} map[string]string "Upload exceeds Tus-Max-Size or storage quota"
+// @Failure 500 {object} map[string]string "Failed to create upload"
+// @Router /uploads [post]
+func (h *TusHandler) CreateUpload(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	sessionID, err := middleware.GetSessionID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "session not found"})
+		return
+	}
+
+	length, err := strconv.ParseInt(ctx.GetHeader("Upload-Length"), 10, 64)
+	if err != nil || length < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid Upload-Length header"})
+		return
+	}
+	if length > file_version.MaxFileSize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file size exceeds maximum allowed (10GB)"})
+		return
+	}
+
+	meta, ok := parseMetadata(ctx.GetHeader("Upload-Metadata"))
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid Upload-Metadata header"})
+		return
+	}
+	if meta["filename"] == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "filename is required in Upload-Metadata"})
+		return
+	}
+	mime := meta["filetype"]
+	if mime == "" {
+		mime = "application/octet-stream"
+	}
+
+	folderID, ok := h.resolveFolderID(ctx, userID, meta["folder_id"])
+	if !ok {
+		return
+	}
+
+	u, err := h.tusService.Create(ctx, userID, sessionID, meta["filename"], length, mime, folderID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.Header("Location", ctx.Request.URL.Path+"/"+u.ID.String())
+	ctx.Header("X-File-ID", u.FileID.String())
+	ctx.Status(http.StatusCreated)
+}
+
+// GetUploadOffset godoc
+// @Summary Get upload offset
+// @Description tus HEAD request: returns how many bytes the server has received
+// @Tags uploads
+// @Security Bearer
+// @Param Tus-Resumable header string true "Protocol version" default(1.0.0)
+// @Param upload_id path string true "Upload ID" format(uuid)
+// @Success 200 "Upload-Offset and Upload-Length headers"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Upload not found"
+// @Router /uploads/{upload_id} [head]
+func (h *TusHandler) GetUploadOffset(ctx *gin.Context) {
+	u, ok := h.ownedUpload(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.Header("Upload-Offset", strconv.FormatInt(u.Offset, 10))
+	ctx.Header("Upload-Length", strconv.FormatInt(u.Length, 10))
+	ctx.Header("X-File-ID", u.FileID.String())
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Status(http.StatusOK)
+}
+
+// PatchUpload godoc
+// @Summary Upload a chunk
+// @Description tus PATCH request: appends the body at Upload-Offset. If Upload-Checksum is given and does not match, the chunk is discarded. When the last byte arrives the file goes to processing the same way as a presigned upload.
+// @Tags uploads
+// @Security Bearer
+// @Accept application/offset+octet-stream
+// @Param Tus-Resumable header string true "Protocol version" default(1.0.0)
+// @Param Upload-Offset header int true "Offset the body starts at"
+// @Param Upload-Checksum header string false "Algorithm and base64 digest of the body, e.g. sha1 Kq5sNclPz7QV2+lfQIuc6R7oRu0="
+// @Param upload_id path string true "Upload ID" format(uuid)
+// @Success 204 "New offset in Upload-Offset header"
+// @Failure 400 {object} map[string]string "Invalid headers"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Upload not found"
+// @Failure 409 {object} map[string]string "Upload-Offset does not match"
+// @Failure 413 {object} map[string]string "Body exceeds Upload-Length"
+// @Failure 415 {object} map[string]string "Invalid Content-Type"
+// @Failure 460 {object} map[string]string "Checksum mismatch"
+// @Router /uploads/{upload_id} [patch]
+func (h *TusHandler) PatchUpload(ctx *gin.Context) {
+	if ctx.ContentType() != "application/offset+octet-stream" {
+		ctx.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "content type must be application/offset+octet-stream"})
+		return
+	}
+
+	offset, err := strconv.ParseInt(ctx.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid Upload-Offset header"})
+		return
+	}
+
+	var checksum *tus_upload.Checksum
+	if header := ctx.GetHeader("Upload-Checksum"); header != "" {
+		c, err := tus_upload.ParseChecksum(header)
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+		checksum = &c
+	}
+
+	u, ok := h.ownedUpload(ctx)
+	if !ok {
+		return
+	}
+
+	u, err = h.tusService.WriteChunk(ctx, u, offset, ctx.Request.Body, checksum)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.Header("Upload-Offset", strconv.FormatInt(u.Offset, 10))
+	ctx.Status(http.StatusNoContent)
+}
+
+// TerminateUpload godoc
+// @Summary Terminate upload
+// @Description tus termination extension. An unfinished upload is deleted together with its file; a finished one only forgets the upload state.
+// @Tags uploads
+// @Security Bearer
+// @Param Tus-Resumable header string true "Protocol version" default(1.0.0)
+// @Param upload_id path string true "Upload ID" format(uuid)
+// @Success 204 "Upload terminated"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Access denied"
+// @Failure 404 {object} map[string]string "Upload not found"
+// @Failure 500 {object} map[string]string "Failed to terminate upload"
+// @Router /uploads/{upload_id} [delete]
+func (h *TusHandler) TerminateUpload(ctx *gin.Context) {
+	u, ok := h.ownedUpload(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.tusService.Terminate(ctx, u); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -28,3 +28,16 @@ type DeleteAccountRequest struct {
 type DeleteAccountResponse struct {
 	Message string `json:"message" example:"Account successfully deleted"`
 }
+
+type UsageEntryResponse struct {
+	Versions int64 `json:"versions" example:"12"`
+	Bytes    int64 `json:"bytes" example:"1048576"`
+}
+
+type UsageResponse struct {
+	LimitBytes     int64                         `json:"limit_bytes" example:"10737418240"`
+	UsedBytes      int64                         `json:"used_bytes" example:"1048576"`
+	AvailableBytes int64                         `json:"available_bytes" example:"10736369664"`
+	ByStatus       map[string]UsageEntryResponse `json:"by_status"`
+	ByMime         map[string]UsageEntryResponse `json:"by_mime"`
+}
@@ -5,16 +5,18 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/yourusername/cloud-file-storage/internal/api/middleware"
+	quota_service "github.com/yourusername/cloud-file-storage/internal/app/quota"
 	user_service "github.com/yourusername/cloud-file-storage/internal/app/user"
 	"github.com/yourusername/cloud-file-storage/internal/domain/user"
 )
 
 type UserHandler struct {
-	userSrv *user_service.UserService
+	userSrv  *user_service.UserService
+	quotaSrv *quota_service.QuotaService
 }
 
-func NewUserHandler(userSrv *user_service.UserService) *UserHandler {
-	return &UserHandler{userSrv: userSrv}
+func NewUserHandler(userSrv *user_service.UserService, quotaSrv *quota_service.QuotaService) *UserHandler {
+	return &UserHandler{userSrv: userSrv, quotaSrv: quotaSrv}
 }
 
 // UpdateProfile godoc
@@ -3,6 +3,7 @@ package users_handler
 import (
 	"time"
 
+	"github.com/yourusername/cloud-file-storage/internal/domain/quota"
 	domainUser "github.com/yourusername/cloud-file-storage/internal/domain/user"
 )
 
@@ -27,3 +28,21 @@ func PresentUpdateProfile(u *domainUser.User) UpdateProfileResponse {
 		UpdatedAt:       u.UpdatedAt.UTC().Format(timeFmt),
 	}
 }
+
+func presentUsageEntries(entries []quota.UsageEntry) map[string]UsageEntryResponse {
+	out := make(map[string]UsageEntryResponse, len(entries))
+	for _, e := range entries {
+		out[e.Key] = UsageEntryResponse{Versions: e.Versions, Bytes: e.Bytes}
+	}
+	return out
+}
+
+func PresentUsage(u *quota.Usage) UsageResponse {
+	return UsageResponse{
+		LimitBytes:     u.Quota.LimitBytes,
+		UsedBytes:      u.Quota.UsedBytes,
+		AvailableBytes: u.Quota.Available(),
+		ByStatus:       presentUsageEntries(u.ByStatus),
+		ByMime:         presentUsageEntries(u.ByMime),
+	}
+}
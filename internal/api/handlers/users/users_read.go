@@ -40,3 +40,30 @@ func (h *UserHandler) GetMe(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, PresentUser(u))
 }
+
+// GetUsage godoc
+// @Summary Get storage usage
+// @Description Retrieve quota limit and used bytes with a breakdown by version status and MIME type
+// @Tags users
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Success 200 {object} UsageResponse "Storage usage"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /users/me/usage [get]
+func (h *UserHandler) GetUsage(ctx *gin.Context) {
+	userID, err := middleware.GetUserID(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	usage, err := h.quotaSrv.GetUsage(ctx, userID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, PresentUsage(usage))
+}
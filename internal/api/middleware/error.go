@@ -6,12 +6,22 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/yourusername/cloud-file-storage/internal/domain/archive"
 	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
+	"github.com/yourusername/cloud-file-storage/internal/domain/drop_link"
+	"github.com/yourusername/cloud-file-storage/internal/domain/encryption"
 	"github.com/yourusername/cloud-file-storage/internal/domain/file"
 	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/folder"
 	"github.com/yourusername/cloud-file-storage/internal/domain/magic_link"
+	"github.com/yourusername/cloud-file-storage/internal/domain/multipart_upload"
 	"github.com/yourusername/cloud-file-storage/internal/domain/public_link"
+	"github.com/yourusername/cloud-file-storage/internal/domain/quota"
 	"github.com/yourusername/cloud-file-storage/internal/domain/session"
+	"github.com/yourusername/cloud-file-storage/internal/domain/share"
+	"github.com/yourusername/cloud-file-storage/internal/domain/signed_url"
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
+	"github.com/yourusername/cloud-file-storage/internal/domain/tus_upload"
 	"github.com/yourusername/cloud-file-storage/internal/domain/user"
 )
 
@@ -43,6 +53,13 @@ func ErrorMiddleware() gin.HandlerFunc {
 	}
 }
 
+// MapError статус, код и сообщение, которыми ErrorMiddleware ответил бы на err.
+// Нужен ответам, в которых у каждого элемента своя ошибка.
+func MapError(err error) (int, string, string) {
+	status, ae := mapError(err)
+	return status, ae.Code, ae.Message
+}
+
 func mapError(err error) (int, apiError) {
 	switch {
 
@@ -56,6 +73,8 @@ func mapError(err error) (int, apiError) {
 		return http.StatusBadRequest, apiError{Code: "INVALID_IP", Message: "Invalid IP"}
 	case errors.Is(err, domainerrors.ErrTransactionNotFound):
 		return http.StatusNotFound, apiError{Code: "TRANSACTION_NOT_FOUND", Message: "Transaction not found"}
+	case errors.Is(err, domainerrors.ErrAccessDenied):
+		return http.StatusForbidden, apiError{Code: "ACCESS_DENIED", Message: "Access denied"}
 
 	case errors.Is(err, user.ErrNotFound):
 		return http.StatusNotFound, apiError{Code: "USER_NOT_FOUND", Message: "User not found"}
@@ -81,6 +100,52 @@ func mapError(err error) (int, apiError) {
 		return http.StatusNotFound, apiError{Code: "PUBLIC_LINK_NOT_FOUND", Message: "Public link not found"}
 	case errors.Is(err, public_link.ErrInvalidExpiryTime):
 		return http.StatusBadRequest, apiError{Code: "INVALID_EXPIRES_AT", Message: "expiresAt must be in the future"}
+	case errors.Is(err, public_link.ErrExpired):
+		return http.StatusNotFound, apiError{Code: "PUBLIC_LINK_EXPIRED", Message: "Public link has expired"}
+	case errors.Is(err, public_link.ErrExhausted):
+		return http.StatusNotFound, apiError{Code: "PUBLIC_LINK_EXHAUSTED", Message: "Public link download limit reached"}
+	case errors.Is(err, public_link.ErrInvalidMaxDownloads):
+		return http.StatusBadRequest, apiError{Code: "INVALID_MAX_DOWNLOADS", Message: "max_downloads must be positive"}
+	case errors.Is(err, public_link.ErrInvalidVersionMode):
+		return http.StatusBadRequest, apiError{Code: "INVALID_VERSION_MODE", Message: "version_mode must be current, latest or pinned; version_id is required for pinned only"}
+	case errors.Is(err, public_link.ErrPinnedVersionDeleted):
+		return http.StatusGone, apiError{Code: "PINNED_VERSION_DELETED", Message: "Pinned version of the public link was deleted"}
+	case errors.Is(err, public_link.ErrInvalidPassword):
+		return http.StatusBadRequest, apiError{Code: "INVALID_LINK_PASSWORD", Message: "Link password must be between 4 and 72 bytes"}
+	case errors.Is(err, public_link.ErrPasswordRequired):
+		return http.StatusUnauthorized, apiError{Code: "PASSWORD_REQUIRED", Message: "Public link is password protected"}
+	case errors.Is(err, public_link.ErrWrongPassword):
+		return http.StatusUnauthorized, apiError{Code: "WRONG_LINK_PASSWORD", Message: "Wrong link password"}
+	case errors.Is(err, public_link.ErrInvalidUnlockToken):
+		return http.StatusUnauthorized, apiError{Code: "INVALID_UNLOCK_TOKEN", Message: "Invalid or expired unlock token"}
+	case errors.Is(err, public_link.ErrLocked):
+		return http.StatusTooManyRequests, apiError{Code: "LINK_LOCKED", Message: "Too many wrong passwords, try again later"}
+
+	case errors.Is(err, drop_link.ErrNotFound):
+		return http.StatusNotFound, apiError{Code: "DROP_LINK_NOT_FOUND", Message: "Drop link not found"}
+	case errors.Is(err, drop_link.ErrExpired):
+		return http.StatusNotFound, apiError{Code: "DROP_LINK_EXPIRED", Message: "Drop link has expired"}
+	case errors.Is(err, drop_link.ErrInvalidTarget):
+		return http.StatusBadRequest, apiError{Code: "INVALID_DROP_LINK_TARGET", Message: "Drop link targets either a folder or a file, not both"}
+	case errors.Is(err, drop_link.ErrInvalidLimits):
+		return http.StatusBadRequest, apiError{Code: "INVALID_DROP_LINK_LIMITS", Message: "Limits must be positive and allowed mime types must look like type/subtype"}
+	case errors.Is(err, drop_link.ErrMimeNotAllowed):
+		return http.StatusUnsupportedMediaType, apiError{Code: "MIME_NOT_ALLOWED", Message: "Mime type is not allowed by the drop link"}
+	case errors.Is(err, drop_link.ErrLimitReached):
+		return http.StatusForbidden, apiError{Code: "DROP_LINK_LIMIT_REACHED", Message: "Drop link file count or total size limit reached"}
+
+	case errors.Is(err, signed_url.ErrInvalidSignature):
+		return http.StatusForbidden, apiError{Code: "INVALID_SIGNED_URL", Message: "Signed URL is malformed or its signature is invalid"}
+	case errors.Is(err, signed_url.ErrExpired):
+		return http.StatusForbidden, apiError{Code: "SIGNED_URL_EXPIRED", Message: "Signed URL has expired"}
+	case errors.Is(err, signed_url.ErrRevoked):
+		return http.StatusForbidden, apiError{Code: "SIGNED_URL_REVOKED", Message: "Signed URL was revoked by the file owner"}
+	case errors.Is(err, signed_url.ErrPermissionDenied):
+		return http.StatusForbidden, apiError{Code: "SIGNED_URL_PERMISSION_DENIED", Message: "Signed URL does not grant this permission"}
+	case errors.Is(err, signed_url.ErrInvalidPermissions):
+		return http.StatusBadRequest, apiError{Code: "INVALID_SIGNED_URL_PERMISSIONS", Message: "Permissions must be download or preview"}
+	case errors.Is(err, signed_url.ErrInvalidTTL):
+		return http.StatusBadRequest, apiError{Code: "INVALID_SIGNED_URL_TTL", Message: "expires_in must be positive and not exceed the configured maximum"}
 
 	case errors.Is(err, magic_link.ErrNotFound):
 		return http.StatusNotFound, apiError{Code: "MAGIC_LINK_NOT_FOUND", Message: "Magic link not found"}
@@ -98,9 +163,95 @@ func mapError(err error) (int, apiError) {
 	case errors.Is(err, file_version.ErrVersionProcessing):
 
 		return http.StatusBadRequest, apiError{Code: "VERSION_PROCESSING", Message: "Cannot delete file, some versions are processing"}
+	case errors.Is(err, file_version.ErrVersionNotStored):
+		return http.StatusConflict, apiError{Code: "VERSION_NOT_STORED", Message: "Version content is not uploaded"}
+	case errors.Is(err, file_version.ErrInvalidChecksum):
+		return http.StatusBadRequest, apiError{Code: "INVALID_CHECKSUM", Message: "Checksum must be a base64 sha256 or crc32c digest"}
+	case errors.Is(err, file_version.ErrChecksumMismatch):
+		return http.StatusUnprocessableEntity, apiError{Code: "CHECKSUM_MISMATCH", Message: "Uploaded content does not match declared checksum or size"}
 
 	case errors.Is(err, file.ErrNotFound):
 		return http.StatusNotFound, apiError{Code: "FILE_NOT_FOUND", Message: "File not found"}
+	case errors.Is(err, file.ErrNotInTrash):
+		return http.StatusConflict, apiError{Code: "FILE_NOT_IN_TRASH", Message: "File is not in trash"}
+	case errors.Is(err, file.ErrInvalidTag):
+		return http.StatusBadRequest, apiError{Code: "INVALID_TAG", Message: "Tag must be 1 to 64 characters without control characters"}
+	case errors.Is(err, file.ErrBatchAborted):
+		return http.StatusConflict, apiError{Code: "BATCH_ABORTED", Message: "Operation rolled back because another operation in the batch failed"}
+
+	case errors.Is(err, folder.ErrNotFound):
+		return http.StatusNotFound, apiError{Code: "FOLDER_NOT_FOUND", Message: "Folder not found"}
+	case errors.Is(err, folder.ErrInvalidName):
+		return http.StatusBadRequest, apiError{Code: "INVALID_FOLDER_NAME", Message: "Folder name must be 1-255 characters and must not contain slashes"}
+	case errors.Is(err, folder.ErrNameConflict):
+		return http.StatusConflict, apiError{Code: "FOLDER_NAME_CONFLICT", Message: "Folder with this name already exists"}
+	case errors.Is(err, folder.ErrInvalidMove):
+		return http.StatusBadRequest, apiError{Code: "INVALID_FOLDER_MOVE", Message: "Folder cannot be moved into itself or its descendant"}
+
+	case errors.Is(err, multipart_upload.ErrNotFound):
+		return http.StatusNotFound, apiError{Code: "MULTIPART_UPLOAD_NOT_FOUND", Message: "Multipart upload not found"}
+	case errors.Is(err, multipart_upload.ErrNotActive):
+		return http.StatusConflict, apiError{Code: "MULTIPART_UPLOAD_NOT_ACTIVE", Message: "Multipart upload is already completed, aborted or expired"}
+	case errors.Is(err, multipart_upload.ErrVersionNotPending):
+		return http.StatusConflict, apiError{Code: "VERSION_NOT_PENDING", Message: "Version is not awaiting upload"}
+	case errors.Is(err, multipart_upload.ErrInvalidPartNumber):
+		return http.StatusBadRequest, apiError{Code: "INVALID_PART_NUMBER", Message: "Part number is out of range"}
+	case errors.Is(err, multipart_upload.ErrInvalidParts):
+		return http.StatusBadRequest, apiError{Code: "INVALID_PARTS", Message: "Every part must be listed exactly once with its ETag"}
+
+	case errors.Is(err, tus_upload.ErrNotFound):
+		return http.StatusNotFound, apiError{Code: "UPLOAD_NOT_FOUND", Message: "Upload not found"}
+	case errors.Is(err, tus_upload.ErrOffsetMismatch):
+		return http.StatusConflict, apiError{Code: "UPLOAD_OFFSET_MISMATCH", Message: "Upload-Offset does not match the current offset"}
+	case errors.Is(err, tus_upload.ErrLengthExceeded):
+		return http.StatusRequestEntityTooLarge, apiError{Code: "UPLOAD_LENGTH_EXCEEDED", Message: "Upload data exceeds Upload-Length"}
+	case errors.Is(err, tus_upload.ErrInvalidChecksum):
+		return http.StatusBadRequest, apiError{Code: "INVALID_CHECKSUM", Message: "Invalid Upload-Checksum header"}
+	case errors.Is(err, tus_upload.ErrUnsupportedChecksum):
+		return http.StatusBadRequest, apiError{Code: "UNSUPPORTED_CHECKSUM", Message: "Checksum algorithm is not supported"}
+	case errors.Is(err, tus_upload.ErrChecksumMismatch):
+		// 460 определён расширением checksum протокола tus
+		return 460, apiError{Code: "CHECKSUM_MISMATCH", Message: "Checksum mismatch"}
+
+	case errors.Is(err, archive.ErrNotFound):
+		return http.StatusNotFound, apiError{Code: "ARCHIVE_NOT_FOUND", Message: "Archive not found"}
+	case errors.Is(err, archive.ErrEmpty):
+		return http.StatusBadRequest, apiError{Code: "EMPTY_ARCHIVE", Message: "Archive must contain at least one file or folder"}
+	case errors.Is(err, archive.ErrTooManyEntries):
+		return http.StatusBadRequest, apiError{Code: "TOO_MANY_ARCHIVE_ENTRIES", Message: "Archive contains too many files"}
+	case errors.Is(err, archive.ErrNotReady):
+		return http.StatusConflict, apiError{Code: "ARCHIVE_NOT_READY", Message: "Archive is not built yet"}
+	case errors.Is(err, archive.ErrExpired):
+		return http.StatusGone, apiError{Code: "ARCHIVE_EXPIRED", Message: "Archive has expired"}
+
+	case errors.Is(err, share.ErrNotFound):
+		return http.StatusNotFound, apiError{Code: "SHARE_NOT_FOUND", Message: "Share not found"}
+	case errors.Is(err, share.ErrInvalidRole):
+		return http.StatusBadRequest, apiError{Code: "INVALID_SHARE_ROLE", Message: "Role must be viewer, editor or owner"}
+	case errors.Is(err, share.ErrCannotShareWithOwner):
+		return http.StatusBadRequest, apiError{Code: "CANNOT_SHARE_WITH_OWNER", Message: "File cannot be shared with its owner"}
+
+	case errors.Is(err, quota.ErrQuotaExceeded):
+		return http.StatusRequestEntityTooLarge, apiError{Code: "QUOTA_EXCEEDED", Message: "Storage quota exceeded"}
+
+	case errors.Is(err, storage.ErrObjectNotFound):
+		return http.StatusNotFound, apiError{Code: "OBJECT_NOT_FOUND", Message: "Object not found"}
+	case errors.Is(err, storage.ErrUploadNotFound):
+		return http.StatusNotFound, apiError{Code: "STORAGE_UPLOAD_NOT_FOUND", Message: "Storage upload not found"}
+	case errors.Is(err, storage.ErrInvalidKey):
+		return http.StatusBadRequest, apiError{Code: "INVALID_OBJECT_KEY", Message: "Invalid object key"}
+	case errors.Is(err, storage.ErrSizeMismatch):
+		return http.StatusBadRequest, apiError{Code: "SIZE_MISMATCH", Message: "Body size does not match the signed size"}
+	case errors.Is(err, storage.ErrInvalidSignature):
+		return http.StatusForbidden, apiError{Code: "INVALID_SIGNATURE", Message: "Invalid URL signature"}
+	case errors.Is(err, storage.ErrURLExpired):
+		return http.StatusForbidden, apiError{Code: "URL_EXPIRED", Message: "URL has expired"}
+	case errors.Is(err, storage.ErrPartTooLarge):
+		return http.StatusRequestEntityTooLarge, apiError{Code: "PART_TOO_LARGE", Message: "Multipart part is too large"}
+
+	case errors.Is(err, encryption.ErrInvalidEnvelope), errors.Is(err, encryption.ErrDecryptionFailed):
+		// Повреждённый объект — проблема сервера, а не клиента
+		return http.StatusInternalServerError, apiError{Code: "OBJECT_CORRUPTED", Message: "Stored object is corrupted"}
 
 	default:
 		return http.StatusInternalServerError, apiError{Code: "INTERNAL_ERROR", Message: "Internal server error"}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const TusVersion = "1.0.0"
+
+// TusResumableMiddleware добавляет Tus-Resumable ко всем ответам и отклоняет запросы
+// с неподдерживаемой версией протокола (OPTIONS по спецификации проверять не нужно)
+func TusResumableMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Header("Tus-Resumable", TusVersion)
+
+		if ctx.Request.Method != http.MethodOptions && ctx.GetHeader("Tus-Resumable") != TusVersion {
+			ctx.Header("Tus-Version", TusVersion)
+			ctx.AbortWithStatusJSON(http.StatusPreconditionFailed, gin.H{
+				"error": "unsupported tus version",
+			})
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -9,37 +9,53 @@ import (
 
 	auth_handlers "github.com/yourusername/cloud-file-storage/internal/api/handlers/auth"
 	files_handler "github.com/yourusername/cloud-file-storage/internal/api/handlers/files"
+	folders_handler "github.com/yourusername/cloud-file-storage/internal/api/handlers/folders"
 	metrics_handler "github.com/yourusername/cloud-file-storage/internal/api/handlers/metrics"
+	notifications_handler "github.com/yourusername/cloud-file-storage/internal/api/handlers/notifications"
+	storage_handler "github.com/yourusername/cloud-file-storage/internal/api/handlers/storage"
+	tus_handler "github.com/yourusername/cloud-file-storage/internal/api/handlers/tus"
 	users_handler "github.com/yourusername/cloud-file-storage/internal/api/handlers/users"
 	"github.com/yourusername/cloud-file-storage/internal/api/middleware"
 	auth_service "github.com/yourusername/cloud-file-storage/internal/app/auth"
 )
 
 type Server struct {
-	router         *gin.Engine
-	authHandler    *auth_handlers.AuthHandler
-	userHandler    *users_handler.UserHandler
-	fileHandler    *files_handler.FileHandler
-	metricsHandler *metrics_handler.MetricsHandler
-	authSrv        *auth_service.AuthService
+	router              *gin.Engine
+	authHandler         *auth_handlers.AuthHandler
+	userHandler         *users_handler.UserHandler
+	fileHandler         *files_handler.FileHandler
+	folderHandler       *folders_handler.FolderHandler
+	tusHandler          *tus_handler.TusHandler
+	storageHandler      *storage_handler.StorageHandler
+	notificationHandler *notifications_handler.NotificationHandler
+	metricsHandler      *metrics_handler.MetricsHandler
+	authSrv             *auth_service.AuthService
 }
 
 func NewServer(
 	authHandler *auth_handlers.AuthHandler,
 	userHandler *users_handler.UserHandler,
 	fileHandler *files_handler.FileHandler,
+	folderHandler *folders_handler.FolderHandler,
+	tusHandler *tus_handler.TusHandler,
+	storageHandler *storage_handler.StorageHandler,
+	notificationHandler *notifications_handler.NotificationHandler,
 	metricsHandler *metrics_handler.MetricsHandler,
 	authSrv *auth_service.AuthService,
 ) *Server {
 	router := gin.Default()
 
 	s := &Server{
-		router:         router,
-		authHandler:    authHandler,
-		userHandler:    userHandler,
-		fileHandler:    fileHandler,
-		metricsHandler: metricsHandler,
-		authSrv:        authSrv,
+		router:              router,
+		authHandler:         authHandler,
+		userHandler:         userHandler,
+		fileHandler:         fileHandler,
+		folderHandler:       folderHandler,
+		tusHandler:          tusHandler,
+		storageHandler:      storageHandler,
+		notificationHandler: notificationHandler,
+		metricsHandler:      metricsHandler,
+		authSrv:             authSrv,
 	}
 	s.setupRoutes()
 	return s
@@ -66,6 +82,17 @@ func (s *Server) setupRoutes() {
 
 		v1.POST("/auth/tokens/refresh", s.authHandler.RefreshToken)
 
+		// :token нельзя поставить перед /unlock — в дереве POST этот сегмент уже занят :file_id
+		v1.POST("/public-links/unlock/:token", s.fileHandler.UnlockPublicLink)
+
+		// Загрузка по ссылке для загрузки, доступ по токену, а не по сессии
+		v1.GET("/public-drop-links/:token", s.fileHandler.GetDropLinkInfo)
+		v1.POST("/public-drop-links/:token/files", s.fileHandler.UploadByDropLink)
+
+		// Подписанные URL на версии файлов, доступ проверяется по HMAC подписи без сессии
+		v1.GET("/signed/files/:file_id/content", s.fileHandler.DownloadBySignedURL)
+		v1.GET("/signed/files/:file_id/preview", s.fileHandler.PreviewBySignedURL)
+
 		authProtected := auth.Group("")
 		authProtected.Use(middleware.AuthMiddleware(s.authSrv))
 
@@ -81,6 +108,7 @@ func (s *Server) setupRoutes() {
 
 		{
 			users.GET("/me", s.userHandler.GetMe)
+			users.GET("/me/usage", s.userHandler.GetUsage)
 			users.PATCH("/me", s.userHandler.UpdateProfile)
 			users.DELETE("/me", s.userHandler.DeleteAccount)
 		}
@@ -91,16 +119,89 @@ func (s *Server) setupRoutes() {
 		{
 			files.POST("", s.fileHandler.UploadNewFile)
 			files.GET("", s.fileHandler.ListFiles)
+			files.GET("/trash", s.fileHandler.ListTrash)
+			files.GET("/shared", s.fileHandler.ListSharedWithMe)
+			files.POST("/archive", s.fileHandler.CreateArchive)
+			files.POST("/batch", s.fileHandler.BatchFiles)
+			files.GET("/archive/:archive_id", s.fileHandler.GetArchive)
 			files.GET("/:file_id", s.fileHandler.GetFile)
 			files.POST("/:file_id/versions", s.fileHandler.UploadNewVersion)
 			files.GET("/:file_id/versions", s.fileHandler.GetFileVersions)
 			files.GET("/:file_id/versions/:version_num/content", s.fileHandler.GetVersionDownloadURL)
+			files.POST("/:file_id/versions/:version_num/uploads", s.fileHandler.InitiateMultipartUpload)
+			files.POST("/:file_id/versions/:version_num/uploads/:upload_id/parts", s.fileHandler.PresignUploadParts)
+			files.POST("/:file_id/versions/:version_num/uploads/:upload_id/complete", s.fileHandler.CompleteMultipartUpload)
+			files.DELETE("/:file_id/versions/:version_num/uploads/:upload_id", s.fileHandler.AbortMultipartUpload)
 			files.PATCH("/:file_id", s.fileHandler.UpdateFile)
+			files.POST("/:file_id/move", s.fileHandler.MoveFile)
+			files.POST("/:file_id/copy", s.fileHandler.CopyFile)
 			files.DELETE("/:file_id", s.fileHandler.DeleteFile)
+			files.POST("/:file_id/restore", s.fileHandler.RestoreFile)
+			files.DELETE("/:file_id/permanent", s.fileHandler.DeleteFilePermanently)
 			files.POST("/:file_id/versions/:version_num/restore", s.fileHandler.RestoreFileVersion)
 			files.POST("/:file_id/public-links", s.fileHandler.CreatePublicLink)
 			files.GET("/:file_id/public-links", s.fileHandler.GetPublicLinks)
 			files.DELETE("/:file_id/public-links/:link_id", s.fileHandler.DeletePublicLink)
+			files.GET("/:file_id/public-links/:link_id/accesses", s.fileHandler.GetPublicLinkAccesses)
+			files.POST("/:file_id/signed-urls", s.fileHandler.CreateSignedURL)
+			files.DELETE("/:file_id/signed-urls", s.fileHandler.RevokeSignedURLs)
+			files.POST("/:file_id/shares", s.fileHandler.CreateShare)
+			files.GET("/:file_id/shares", s.fileHandler.ListShares)
+			files.DELETE("/:file_id/shares/:share_id", s.fileHandler.DeleteShare)
+		}
+
+		dropLinks := v1.Group("/drop-links")
+		dropLinks.Use(middleware.AuthMiddleware(s.authSrv))
+
+		{
+			dropLinks.POST("", s.fileHandler.CreateDropLink)
+			dropLinks.GET("", s.fileHandler.ListDropLinks)
+			dropLinks.DELETE("/:link_id", s.fileHandler.DeleteDropLink)
+		}
+
+		folders := v1.Group("/folders")
+		folders.Use(middleware.AuthMiddleware(s.authSrv))
+
+		{
+			folders.POST("", s.folderHandler.CreateFolder)
+			folders.GET("", s.folderHandler.ListFolders)
+			folders.GET("/:folder_id", s.folderHandler.GetFolder)
+			folders.PATCH("/:folder_id", s.folderHandler.RenameFolder)
+			folders.POST("/:folder_id/move", s.folderHandler.MoveFolder)
+			folders.DELETE("/:folder_id", s.folderHandler.DeleteFolder)
+		}
+
+		// Возобновляемые загрузки по протоколу tus 1.0
+		uploads := v1.Group("/uploads")
+		uploads.Use(middleware.TusResumableMiddleware())
+		uploads.OPTIONS("", s.tusHandler.Options)
+		uploads.OPTIONS("/:upload_id", s.tusHandler.Options)
+
+		uploadsProtected := uploads.Group("")
+		uploadsProtected.Use(middleware.AuthMiddleware(s.authSrv))
+
+		{
+			uploadsProtected.POST("", s.tusHandler.CreateUpload)
+			uploadsProtected.HEAD("/:upload_id", s.tusHandler.GetUploadOffset)
+			uploadsProtected.PATCH("/:upload_id", s.tusHandler.PatchUpload)
+			uploadsProtected.DELETE("/:upload_id", s.tusHandler.TerminateUpload)
+		}
+
+		// Подписанные URL локального и шифрующего хранилищ, доступ проверяется по подписи, а не по сессии.
+		// Есть только при storage.driver: local или storage.encryption.enabled.
+		if s.storageHandler != nil {
+			objects := v1.Group("/storage")
+
+			{
+				objects.PUT("/objects/*key", s.storageHandler.PutObject)
+				objects.GET("/objects/*key", s.storageHandler.GetObject)
+				objects.PUT("/uploads/:upload_id/parts/:part_number", s.storageHandler.UploadPart)
+			}
+		}
+
+		// Webhook уведомлений бакета, доступ по токену из bucket_notifications.webhook_token
+		if s.notificationHandler != nil {
+			v1.POST("/storage/notifications", s.notificationHandler.HandleBucketNotification)
 		}
 	}
 }
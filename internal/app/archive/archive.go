@@ -0,0 +1,371 @@
+package archive_service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/app"
+	event_service "github.com/yourusername/cloud-file-storage/internal/app/event"
+	share_service "github.com/yourusername/cloud-file-storage/internal/app/share"
+	"github.com/yourusername/cloud-file-storage/internal/domain/archive"
+	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/folder"
+	"github.com/yourusername/cloud-file-storage/internal/domain/share"
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
+)
+
+const (
+	defaultSyncLimit = 512 * 1024 * 1024
+	defaultLinkTTL   = 15 * time.Minute
+	defaultRetention = 24 * time.Hour
+	// staleBuildAfter архив в building дольше этого считается брошенным упавшим воркером
+	staleBuildAfter = 6 * time.Hour
+)
+
+// Item файл для архива; VersionNum == 0 означает текущую версию
+type Item struct {
+	FileID     uuid.UUID
+	VersionNum int
+}
+
+// ArchiveService собирает ZIP из нескольких файлов и папок. Небольшие архивы отдаются
+// потоком прямо в ответ, большие собирает воркер во временный объект archives/.
+type ArchiveService struct {
+	queryRepo        archive.QueryRepository
+	commandRepo      archive.CommandRepository
+	fileQueryRepo    file.QueryRepository
+	versionQueryRepo file_version.QueryRepository
+	folderQueryRepo  folder.QueryRepository
+	shareService     *share_service.ShareService
+	storage          storage.Storage
+	eventService     *event_service.EventService
+	uow              app.UnitOfWork
+	syncLimit        int64
+	linkTTL          time.Duration
+	retention        time.Duration
+}
+
+// NewArchiveService syncLimit — наибольший суммарный размер файлов, который отдаётся потоком сразу;
+// linkTTL — срок ссылки на собранный архив, retention — срок хранения собранного архива
+func NewArchiveService(
+	queryRepo archive.QueryRepository,
+	commandRepo archive.CommandRepository,
+	fileQueryRepo file.QueryRepository,
+	versionQueryRepo file_version.QueryRepository,
+	folderQueryRepo folder.QueryRepository,
+	shareService *share_service.ShareService,
+	storage storage.Storage,
+	eventService *event_service.EventService,
+	uow app.UnitOfWork,
+	syncLimit int64,
+	linkTTL time.Duration,
+	retention time.Duration,
+) *ArchiveService {
+	if syncLimit <= 0 {
+		syncLimit = defaultSyncLimit
+	}
+	if linkTTL <= 0 {
+		linkTTL = defaultLinkTTL
+	}
+	if retention <= 0 {
+		retention = defaultRetention
+	}
+	return &ArchiveService{
+		queryRepo:        queryRepo,
+		commandRepo:      commandRepo,
+		fileQueryRepo:    fileQueryRepo,
+		versionQueryRepo: versionQueryRepo,
+		folderQueryRepo:  folderQueryRepo,
+		shareService:     shareService,
+		storage:          storage,
+		eventService:     eventService,
+		uow:              uow,
+		syncLimit:        syncLimit,
+		linkTTL:          linkTTL,
+		retention:        retention,
+	}
+}
+
+func (s *ArchiveService) LinkTTL() time.Duration {
+	return s.linkTTL
+}
+
+// Plan проверяет доступ ко всем файлам и папкам и раскладывает их по путям внутри архива.
+// Архив не сохраняется: его либо отдают потоком, либо ставят в очередь через Enqueue.
+// Файлы, выданные пользователю хотя бы на просмотр, доступны как свои; папки — только свои.
+// Недоступные файлы и папки неотличимы от несуществующих.
+func (s *ArchiveService) Plan(ctx context.Context, ownerID uuid.UUID, items []Item, folderIDs []uuid.UUID) (*archive.Archive, error) {
+	if len(items) == 0 && len(folderIDs) == 0 {
+		return nil, archive.ErrEmpty
+	}
+
+	names := archive.NewNames()
+	entries := make([]archive.Entry, 0, len(items))
+
+	for _, item := range items {
+		f, err := s.fileQueryRepo.GetByID(ctx, item.FileID)
+		if err != nil {
+			return nil, err
+		}
+		if f == nil || f.IsTrashed() {
+			return nil, file.ErrNotFound
+		}
+		if err := s.shareService.Authorize(ctx, f, ownerID, share.RoleViewer); err != nil {
+			if errors.Is(err, domainerrors.ErrAccessDenied) {
+				return nil, file.ErrNotFound
+			}
+			return nil, err
+		}
+
+		versionNum := item.VersionNum
+		if versionNum == 0 {
+			versionNum = f.VersionNum.Int()
+		}
+		v, err := s.findVersion(ctx, f.ID, versionNum)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			return nil, file_version.ErrVersionNotFound
+		}
+		if !isStored(v) {
+			return nil, file_version.ErrVersionNotStored
+		}
+
+		entries = append(entries, fileEntry(names.File("", f.Name.String()), v))
+		if len(entries) > archive.MaxEntries {
+			return nil, archive.ErrTooManyEntries
+		}
+	}
+
+	for _, folderID := range folderIDs {
+		var err error
+		entries, err = s.planFolder(ctx, ownerID, folderID, names, entries)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return archive.NewArchive(ownerID, entries, s.retention), nil
+}
+
+// planFolder добавляет папку со всеми вложенными папками и их файлами.
+// Файлы, текущая версия которых ещё не загружена, пропускаются.
+func (s *ArchiveService) planFolder(ctx context.Context, ownerID, folderID uuid.UUID, names *archive.Names, entries []archive.Entry) ([]archive.Entry, error) {
+	root, err := s.folderQueryRepo.GetByID(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil || root.OwnerID != ownerID {
+		return nil, folder.ErrNotFound
+	}
+
+	descendants, err := s.folderQueryRepo.GetDescendants(ctx, root.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*folder.Folder, len(descendants)+1)
+	byID[root.ID] = root
+	for _, fd := range descendants {
+		byID[fd.ID] = fd
+	}
+
+	paths := make(map[uuid.UUID]string, len(byID))
+	var pathOf func(fd *folder.Folder) string
+	pathOf = func(fd *folder.Folder) string {
+		if p, ok := paths[fd.ID]; ok {
+			return p
+		}
+		parentPath := ""
+		if fd.ID != root.ID && fd.ParentID != nil {
+			if parent, ok := byID[*fd.ParentID]; ok {
+				parentPath = pathOf(parent)
+			}
+		}
+		p := names.Dir(parentPath, fd.Name.String())
+		paths[fd.ID] = p
+		entries = append(entries, archive.Entry{Name: p, ModifiedAt: fd.UpdatedAt})
+		return p
+	}
+
+	// GetDescendants отдаёт самые глубокие папки первыми, поэтому путь родителя вычисляется рекурсивно
+	folders := append([]*folder.Folder{root}, descendants...)
+	for _, fd := range folders {
+		pathOf(fd)
+	}
+
+	for _, fd := range folders {
+		files, err := s.fileQueryRepo.GetByFolderID(ctx, fd.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			v, err := s.findVersion(ctx, f.ID, f.VersionNum.Int())
+			if err != nil {
+				return nil, err
+			}
+			if v == nil || !isStored(v) {
+				continue
+			}
+			entries = append(entries, fileEntry(names.File(paths[fd.ID], f.Name.String()), v))
+		}
+		if len(entries) > archive.MaxEntries {
+			return nil, archive.ErrTooManyEntries
+		}
+	}
+
+	return entries, nil
+}
+
+func (s *ArchiveService) findVersion(ctx context.Context, fileID uuid.UUID, versionNum int) (*file_version.FileVersion, error) {
+	versions, err := s.versionQueryRepo.GetByFileID(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range versions {
+		if v.VersionNum.Int() == versionNum {
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+func isStored(v *file_version.FileVersion) bool {
+	return v.Status.Equal(file_version.FileStatusReady) || v.Status.Equal(file_version.FileStatusUploaded)
+}
+
+func fileEntry(name string, v *file_version.FileVersion) archive.Entry {
+	return archive.Entry{
+		Name:       name,
+		S3Key:      v.S3Key.String(),
+		Mime:       v.Mime.String(),
+		Size:       int64(v.Size.Uint64()),
+		ModifiedAt: v.UpdatedAt,
+	}
+}
+
+// Streamable true — архив достаточно мал, чтобы отдать его потоком в ответ на запрос
+func (s *ArchiveService) Streamable(a *archive.Archive) bool {
+	return a.Size <= s.syncLimit
+}
+
+// Enqueue сохраняет архив для сборки воркером
+func (s *ArchiveService) Enqueue(ctx context.Context, a *archive.Archive) error {
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		return s.commandRepo.Save(ctx, a)
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.eventService != nil {
+		eventName, payload := archive.NewArchiveRequestedEvent(a)
+		_, _ = s.eventService.Create(ctx, eventName, payload)
+	}
+
+	return nil
+}
+
+func (s *ArchiveService) GetByID(ctx context.Context, id uuid.UUID) (*archive.Archive, error) {
+	a, err := s.queryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, archive.ErrNotFound
+	}
+	return a, nil
+}
+
+// GetDownloadURL выдаёт короткоживущую ссылку на собранный архив
+func (s *ArchiveService) GetDownloadURL(ctx context.Context, a *archive.Archive) (string, error) {
+	if a.IsExpired() {
+		return "", archive.ErrExpired
+	}
+	if !a.IsReady() {
+		return "", archive.ErrNotReady
+	}
+	return s.storage.GenerateDownloadURL(ctx, a.S3Key, s.linkTTL)
+}
+
+// ClaimPending забирает архивы в сборку; зависшие в building дольше staleBuildAfter забираются повторно
+func (s *ArchiveService) ClaimPending(ctx context.Context, limit int) ([]*archive.Archive, error) {
+	var archives []*archive.Archive
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		archives, err = s.commandRepo.ClaimPending(ctx, time.Now().Add(-staleBuildAfter), limit)
+		return err
+	})
+	return archives, err
+}
+
+// Build собирает архив в объект хранилища. При ошибке архив помечается failed,
+// а незавершённая загрузка прерывается.
+func (s *ArchiveService) Build(ctx context.Context, a *archive.Archive) error {
+	buildErr := s.upload(ctx, a)
+
+	if buildErr != nil {
+		reason := "failed to build archive"
+		if errors.Is(buildErr, storage.ErrObjectNotFound) {
+			reason = "file content is missing"
+		}
+		a.MarkFailed(reason)
+	} else {
+		a.MarkReady(s.retention)
+	}
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		return s.commandRepo.Save(ctx, a)
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.eventService != nil {
+		eventName, payload := archive.NewArchiveReadyEvent(a)
+		if buildErr != nil {
+			eventName, payload = archive.NewArchiveFailedEvent(a)
+		}
+		_, _ = s.eventService.Create(ctx, eventName, payload)
+	}
+
+	return buildErr
+}
+
+func (s *ArchiveService) upload(ctx context.Context, a *archive.Archive) error {
+	uploadID, err := s.storage.CreateMultipartUpload(ctx, a.S3Key, archiveContentType)
+	if err != nil {
+		return err
+	}
+
+	w := newMultipartWriter(ctx, s.storage, a.S3Key, uploadID, partSizeFor(a))
+	if err := s.Write(ctx, w, a.Entries); err != nil {
+		_ = s.storage.AbortMultipartUpload(ctx, a.S3Key, uploadID)
+		return err
+	}
+	if err := w.Close(); err != nil {
+		_ = s.storage.AbortMultipartUpload(ctx, a.S3Key, uploadID)
+		return err
+	}
+	return nil
+}
+
+// GetExpired возвращает архивы, срок хранения которых истёк
+func (s *ArchiveService) GetExpired(ctx context.Context, limit int) ([]*archive.Archive, error) {
+	return s.queryRepo.GetExpired(ctx, time.Now(), limit)
+}
+
+// Delete удаляет объект архива и запись о нём
+func (s *ArchiveService) Delete(ctx context.Context, a *archive.Archive) error {
+	if err := s.storage.Delete(ctx, a.S3Key); err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+		return err
+	}
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		return s.commandRepo.Delete(ctx, a.ID)
+	})
+}
@@ -0,0 +1,142 @@
+package archive_service
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/yourusername/cloud-file-storage/internal/domain/archive"
+	"github.com/yourusername/cloud-file-storage/internal/domain/multipart_upload"
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
+)
+
+const archiveContentType = "application/zip"
+
+// Write пишет ZIP потоком: содержимое каждого файла читается из хранилища и сразу сжимается в w,
+// ни архив, ни файлы целиком в памяти и на диске не оказываются
+func (s *ArchiveService) Write(ctx context.Context, w io.Writer, entries []archive.Entry) error {
+	zw := zip.NewWriter(w)
+
+	for _, e := range entries {
+		header := &zip.FileHeader{
+			Name:     e.Name,
+			Modified: e.ModifiedAt,
+			Method:   compressionMethod(e.Mime),
+		}
+		if e.IsDir() {
+			header.Method = zip.Store
+			if _, err := zw.CreateHeader(header); err != nil {
+				return err
+			}
+			continue
+		}
+
+		fw, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if err := s.copyObject(ctx, fw, e); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func (s *ArchiveService) copyObject(ctx context.Context, w io.Writer, e archive.Entry) error {
+	obj, err := s.storage.Get(ctx, e.S3Key)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", e.Name, err)
+	}
+	defer obj.Body.Close()
+
+	if _, err := io.Copy(w, obj.Body); err != nil {
+		return fmt.Errorf("failed to write %s: %w", e.Name, err)
+	}
+	return nil
+}
+
+// compressionMethod уже сжатые форматы повторно не сжимаются
+func compressionMethod(mime string) uint16 {
+	switch {
+	case strings.HasPrefix(mime, "image/") && mime != "image/svg+xml" && mime != "image/bmp":
+		return zip.Store
+	case strings.HasPrefix(mime, "video/"), strings.HasPrefix(mime, "audio/"):
+		return zip.Store
+	}
+	switch mime {
+	case "application/zip", "application/gzip", "application/x-7z-compressed",
+		"application/x-rar-compressed", "application/vnd.rar", "application/x-bzip2", "application/x-xz":
+		return zip.Store
+	}
+	return zip.Deflate
+}
+
+// partSizeFor размер части подбирается по размеру содержимого с запасом на заголовки ZIP,
+// чтобы архив уложился в лимит частей S3
+func partSizeFor(a *archive.Archive) int64 {
+	estimate := a.Size + a.Size/64 + int64(len(a.Entries))*1024
+	partSize, _ := multipart_upload.CalculateParts(uint64(estimate))
+	return partSize
+}
+
+// multipartWriter выгружает поток в объект частями одинакового размера; последняя часть может быть меньше
+type multipartWriter struct {
+	ctx      context.Context
+	storage  storage.Storage
+	key      string
+	uploadID string
+	buf      []byte
+	parts    []storage.CompletedPart
+}
+
+func newMultipartWriter(ctx context.Context, st storage.Storage, key, uploadID string, partSize int64) *multipartWriter {
+	return &multipartWriter{
+		ctx:      ctx,
+		storage:  st,
+		key:      key,
+		uploadID: uploadID,
+		buf:      make([]byte, 0, partSize),
+	}
+}
+
+func (w *multipartWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := copy(w.buf[len(w.buf):cap(w.buf)], p)
+		w.buf = w.buf[:len(w.buf)+n]
+		p = p[n:]
+		written += n
+
+		if len(w.buf) == cap(w.buf) {
+			if err := w.flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// Close выгружает остаток и собирает объект; S3 не собирает объект без частей,
+// поэтому хотя бы одна часть отправляется всегда
+func (w *multipartWriter) Close() error {
+	if len(w.buf) > 0 || len(w.parts) == 0 {
+		if err := w.flush(); err != nil {
+			return err
+		}
+	}
+	return w.storage.CompleteMultipartUpload(w.ctx, w.key, w.uploadID, w.parts)
+}
+
+func (w *multipartWriter) flush() error {
+	partNumber := int32(len(w.parts) + 1)
+	etag, err := w.storage.UploadPart(w.ctx, w.key, w.uploadID, partNumber, w.buf)
+	if err != nil {
+		return err
+	}
+	w.parts = append(w.parts, storage.CompletedPart{PartNumber: partNumber, ETag: etag})
+	w.buf = w.buf[:0]
+	return nil
+}
@@ -15,7 +15,6 @@ import (
 	"github.com/yourusername/cloud-file-storage/internal/domain/notification"
 	"github.com/yourusername/cloud-file-storage/internal/domain/session"
 	"github.com/yourusername/cloud-file-storage/internal/domain/user"
-	"github.com/yourusername/cloud-file-storage/internal/domain/value_objects"
 )
 
 type AuthService struct {
@@ -37,8 +36,7 @@ func NewAuthService(mlService *magic_link_service.MagicLinkService, sService *se
 }
 
 func (a *AuthService) RequestMagicLink(ctx context.Context, userID uuid.UUID, deviceInfo string, ip net.IP) (*magic_link.MagicLink, error) {
-	tokenHash := generateTokenHash()
-	m, err := a.magicLinkService.Create(ctx, userID, tokenHash, deviceInfo, "login", ip)
+	m, token, err := a.magicLinkService.Create(ctx, userID, deviceInfo, "login", ip)
 	fmt.Println(err)
 	if err != nil {
 		return nil, err
@@ -47,19 +45,14 @@ func (a *AuthService) RequestMagicLink(ctx context.Context, userID uuid.UUID, de
 	if m == nil {
 		return nil, fmt.Errorf("magic link creation returned nil")
 	}
-	fmt.Println(tokenHash, " was sended")
-	tokenObj, err := value_objects.NewTokenHash(tokenHash)
-	if err != nil {
-		return nil, err
-	}
 
 	u, err := a.userService.GetByID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	a.mailSender.SendMagicLink(ctx, tokenObj, u.Email.String(), "https://localhost:8030")
-	fmt.Println(m.TokenHash.String())
+	// Токен уходит только в письмо: в БД и логах его нет
+	a.mailSender.SendMagicLink(ctx, token, u.Email.String(), "https://localhost:8030")
 
 	return m, nil
 }
@@ -83,39 +76,33 @@ func (a *AuthService) RegisterWithMagicLink(ctx context.Context, email string, d
 	return a.RequestMagicLink(ctx, u.ID, deviceInfo, ip)
 }
 
-func (a *AuthService) Authenticate(ctx context.Context, tokenHash string, ip net.IP) (*session.Session, error) {
-	link, err := a.magicLinkService.GetByTokenHash(ctx, tokenHash)
+// Authenticate обменивает токен магической ссылки на сессию и её токены
+func (a *AuthService) Authenticate(ctx context.Context, token string, ip net.IP) (*session.Session, *session_service.Tokens, error) {
+	link, err := a.magicLinkService.GetByToken(ctx, token)
 	if err != nil {
-		return nil, magic_link.ErrMagicLink
+		return nil, nil, magic_link.ErrMagicLink
 	}
 
 	if !link.IsValid() {
-		return nil, magic_link.ErrMagicLink
+		return nil, nil, magic_link.ErrMagicLink
 	}
 
 	if err := a.magicLinkService.MarkAsUsed(ctx, link.ID); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if err := a.userService.VerifyEmail(ctx, link.UserID); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	expiresAt := time.Now().Add(a.sessionTTL)
-	session, err := a.sessionService.Create(
+	return a.sessionService.Create(
 		ctx,
 		link.UserID,
-		generateTokenHash(),
-		generateTokenHash(),
 		link.DeviceInfo.String(),
 		ip,
 		expiresAt,
 	)
-	if err != nil {
-		return nil, err
-	}
-
-	return session, nil
 }
 
 func (a *AuthService) ValidateSession(ctx context.Context, sessionID uuid.UUID) (*session.Session, error) {
@@ -156,7 +143,3 @@ func (a *AuthService) ValidateSessionByAccessToken(ctx context.Context, accessTo
 func (a *AuthService) RevokeSession(ctx context.Context, sessionID uuid.UUID) error {
 	return a.sessionService.Revoke(ctx, sessionID)
 }
-
-func generateTokenHash() string {
-	return uuid.New().String()
-}
@@ -0,0 +1,192 @@
+package drop_link_service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/app"
+	event_service "github.com/yourusername/cloud-file-storage/internal/app/event"
+	file_version_service "github.com/yourusername/cloud-file-storage/internal/app/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/drop_link"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/value_objects"
+)
+
+const defaultDropLinkTTL = 24 * time.Hour
+
+type DropLinkService struct {
+	queryRepo      drop_link.QueryRepository
+	commandRepo    drop_link.CommandRepository
+	versionService *file_version_service.FileVersionService
+	eventService   *event_service.EventService
+	uow            app.UnitOfWork
+}
+
+func NewDropLinkService(
+	queryRepo drop_link.QueryRepository,
+	commandRepo drop_link.CommandRepository,
+	versionService *file_version_service.FileVersionService,
+	eventService *event_service.EventService,
+	uow app.UnitOfWork,
+) *DropLinkService {
+	return &DropLinkService{
+		queryRepo:      queryRepo,
+		commandRepo:    commandRepo,
+		versionService: versionService,
+		eventService:   eventService,
+		uow:            uow,
+	}
+}
+
+// Create создаёт ссылку для загрузки. Права владельца на folderID и fileID проверяет вызывающий;
+// оба nil — файлы попадают в корень владельца. nil лимиты и пустой allowedMimes — без ограничений.
+// Токен ссылки возвращается только здесь, в БД хранится его хеш.
+func (s *DropLinkService) Create(
+	ctx context.Context,
+	ownerID uuid.UUID,
+	expiresAtRaw time.Time,
+	folderID, fileID *uuid.UUID,
+	maxFiles *int,
+	maxTotalSize *int64,
+	allowedMimes []string,
+) (*drop_link.DropLink, string, error) {
+	var link *drop_link.DropLink
+
+	token, tokenHash, err := value_objects.NewToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		expiresAt := expiresAtRaw
+		if expiresAt.IsZero() {
+			expiresAt = time.Now().Add(defaultDropLinkTTL)
+		}
+		expiresAtVO, err := value_objects.NewExpiresAt(expiresAt)
+		if err != nil {
+			return err
+		}
+
+		link = drop_link.NewDropLink(ownerID, tokenHash.String(), expiresAtVO.Time())
+		if err := link.SetTarget(folderID, fileID); err != nil {
+			return err
+		}
+		if err := link.SetLimits(maxFiles, maxTotalSize, allowedMimes); err != nil {
+			return err
+		}
+
+		return s.commandRepo.Save(ctx, link)
+	})
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if s.eventService != nil {
+		eventName, payload := drop_link.NewDropLinkCreatedEvent(link)
+		_, _ = s.eventService.Create(ctx, eventName, payload)
+	}
+
+	return link, token, nil
+}
+
+// Delete удаляет ссылку владельца; уже загруженные по ней файлы остаются
+func (s *DropLinkService) Delete(ctx context.Context, ownerID, id uuid.UUID) error {
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		link, err := s.queryRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if link == nil || link.OwnerID != ownerID {
+			return drop_link.ErrNotFound
+		}
+
+		return s.commandRepo.Delete(ctx, id)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if s.eventService != nil {
+		eventName, payload := drop_link.NewDropLinkDeletedEvent(id)
+		_, _ = s.eventService.Create(ctx, eventName, payload)
+	}
+
+	return nil
+}
+
+// GetByOwnerID все ссылки владельца, включая истёкшие
+func (s *DropLinkService) GetByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]*drop_link.DropLink, error) {
+	return s.queryRepo.GetByOwnerID(ctx, ownerID)
+}
+
+// GetByToken получает ссылку по хешу токена с проверкой срока действия
+func (s *DropLinkService) GetByToken(ctx context.Context, token string) (*drop_link.DropLink, error) {
+	tokenHash, err := value_objects.HashToken(token)
+	if err != nil {
+		return nil, drop_link.ErrNotFound
+	}
+
+	link, err := s.queryRepo.GetByTokenHash(ctx, tokenHash.String())
+	if err != nil {
+		return nil, err
+	}
+	if link == nil || !tokenHash.Matches(link.TokenHash) {
+		return nil, drop_link.ErrNotFound
+	}
+	if link.IsExpired() {
+		return nil, drop_link.ErrExpired
+	}
+	return link, nil
+}
+
+// Upload принимает файл по ссылке и возвращает подписанный URL для загрузки данных.
+// Загрузка засчитывается в лимиты ссылки сразу, по заявленному размеру, и не возвращается,
+// даже если данные так и не загрузят. Квоту занимает владелец ссылки.
+func (s *DropLinkService) Upload(
+	ctx context.Context,
+	token string,
+	name string,
+	size uint64,
+	mime string,
+	checksum *file_version.Checksum,
+) (*file.File, *file_version.FileVersion, string, map[string]string, error) {
+	var f *file.File
+	var version *file_version.FileVersion
+	var uploadURL string
+	var uploadHeaders map[string]string
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		link, err := s.GetByToken(ctx, token)
+		if err != nil {
+			return err
+		}
+		if err := link.Accept(int64(size), mime); err != nil {
+			return err
+		}
+
+		reserved, err := s.commandRepo.ReserveUpload(ctx, link.ID, int64(size))
+		if err != nil {
+			return err
+		}
+		if !reserved {
+			return drop_link.ErrLimitReached
+		}
+
+		if link.TargetsFile() {
+			f, version, uploadURL, uploadHeaders, err = s.versionService.UploadNewVersionFromDropLink(ctx, *link.FileID, link.ID, name, size, mime, checksum)
+		} else {
+			f, version, uploadURL, uploadHeaders, err = s.versionService.UploadNewFileFromDropLink(ctx, link.OwnerID, link.ID, name, size, mime, link.FolderID, checksum)
+		}
+		return err
+	})
+
+	if err != nil {
+		return nil, nil, "", nil, err
+	}
+
+	return f, version, uploadURL, uploadHeaders, nil
+}
@@ -0,0 +1,131 @@
+package file_service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file"
+	"github.com/yourusername/cloud-file-storage/internal/domain/folder"
+	"github.com/yourusername/cloud-file-storage/internal/domain/share"
+)
+
+type BatchAction string
+
+const (
+	BatchDelete  BatchAction = "delete"
+	BatchRename  BatchAction = "rename"
+	BatchMove    BatchAction = "move"
+	BatchRestore BatchAction = "restore"
+	BatchTag     BatchAction = "tag"
+)
+
+// BatchOperation операция пакета. Name нужен только rename, FolderID — только move (nil — корень),
+// AddTags и RemoveTags — только tag.
+type BatchOperation struct {
+	Action     BatchAction
+	FileID     uuid.UUID
+	Name       string
+	FolderID   *uuid.UUID
+	AddTags    []string
+	RemoveTags []string
+}
+
+// BatchResult результат операции с тем же индексом; Err == nil — операция применена
+type BatchResult struct {
+	Err error
+}
+
+var errUnknownBatchAction = errors.New("unknown batch action")
+
+// Batch применяет операции над файлами, к которым у userID есть доступ: rename и tag требуют роли editor,
+// остальные операции — роли owner. Операции выполняются по порядку.
+// atomic == true выполняет все операции в одной транзакции: первая ошибка откатывает пакет,
+// остальные операции получают file.ErrBatchAborted. Иначе каждая операция выполняется
+// в своей транзакции и ошибка одной не влияет на другие.
+// Файлы читаются вне транзакции пакета, поэтому один файл должен встречаться в пакете не больше раза.
+func (s *FileService) Batch(ctx context.Context, userID uuid.UUID, ops []BatchOperation, atomic bool) []BatchResult {
+	results := make([]BatchResult, len(ops))
+
+	if !atomic {
+		for i, op := range ops {
+			results[i].Err = s.applyBatchOperation(ctx, userID, op)
+		}
+		return results
+	}
+
+	failed := -1
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		for i, op := range ops {
+			if err := s.applyBatchOperation(ctx, userID, op); err != nil {
+				failed = i
+				return err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		return results
+	}
+
+	for i := range results {
+		if i == failed {
+			results[i].Err = err
+		} else if failed >= 0 {
+			results[i].Err = file.ErrBatchAborted
+		} else {
+			// Все операции прошли, но не удалось зафиксировать транзакцию
+			results[i].Err = err
+		}
+	}
+	return results
+}
+
+func (s *FileService) applyBatchOperation(ctx context.Context, userID uuid.UUID, op BatchOperation) error {
+	var f *file.File
+	var err error
+	if op.Action == BatchRestore {
+		f, err = s.GetTrashedByID(ctx, op.FileID)
+	} else {
+		f, err = s.GetByID(ctx, op.FileID)
+	}
+	if err != nil {
+		return err
+	}
+	required := share.RoleOwner
+	if op.Action == BatchRename || op.Action == BatchTag {
+		required = share.RoleEditor
+	}
+	if err := s.shareService.Authorize(ctx, f, userID, required); err != nil {
+		return err
+	}
+
+	switch op.Action {
+	case BatchDelete:
+		return s.Delete(ctx, op.FileID)
+	case BatchRename:
+		return s.RenameFile(ctx, op.FileID, op.Name)
+	case BatchMove:
+		if op.FolderID != nil {
+			fd, err := s.folderQueryRepo.GetByID(ctx, *op.FolderID)
+			if err != nil {
+				return err
+			}
+			if fd == nil {
+				return folder.ErrNotFound
+			}
+			// переносить можно только в папки владельца файла
+			if fd.OwnerID != f.OwnerID {
+				return domainerrors.ErrAccessDenied
+			}
+		}
+		return s.MoveToFolder(ctx, op.FileID, op.FolderID)
+	case BatchRestore:
+		return s.Restore(ctx, op.FileID)
+	case BatchTag:
+		return s.Tag(ctx, op.FileID, op.AddTags, op.RemoveTags)
+	default:
+		return errUnknownBatchAction
+	}
+}
@@ -2,19 +2,33 @@ package file_service
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/yourusername/cloud-file-storage/internal/app"
 	event_service "github.com/yourusername/cloud-file-storage/internal/app/event"
+	quota_service "github.com/yourusername/cloud-file-storage/internal/app/quota"
+	share_service "github.com/yourusername/cloud-file-storage/internal/app/share"
+	"github.com/yourusername/cloud-file-storage/internal/domain/blob"
 	"github.com/yourusername/cloud-file-storage/internal/domain/file"
 	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/folder"
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
 )
 
 type FileService struct {
 	fileQueryRepo      file.QueryRepository
 	fileCommandRepo    file.CommandRepository
+	tagQueryRepo       file.TagQueryRepository
+	tagCommandRepo     file.TagCommandRepository
 	versionQueryRepo   file_version.QueryRepository
 	versionCommandRepo file_version.CommandRepository
+	blobCommandRepo    blob.CommandRepository
+	folderQueryRepo    folder.QueryRepository
+	shareService       *share_service.ShareService
+	quotaService       *quota_service.QuotaService
+	storage            storage.Storage
 	eventService       *event_service.EventService
 	uow                app.UnitOfWork
 }
@@ -22,22 +36,49 @@ type FileService struct {
 func NewFileService(
 	fileQueryRepo file.QueryRepository,
 	fileCommandRepo file.CommandRepository,
+	tagQueryRepo file.TagQueryRepository,
+	tagCommandRepo file.TagCommandRepository,
 	versionQueryRepo file_version.QueryRepository,
 	versionCommandRepo file_version.CommandRepository,
+	blobCommandRepo blob.CommandRepository,
+	folderQueryRepo folder.QueryRepository,
+	shareService *share_service.ShareService,
+	quotaService *quota_service.QuotaService,
+	storage storage.Storage,
 	eventService *event_service.EventService,
 	uow app.UnitOfWork,
 ) *FileService {
 	return &FileService{
 		fileQueryRepo:      fileQueryRepo,
 		fileCommandRepo:    fileCommandRepo,
+		tagQueryRepo:       tagQueryRepo,
+		tagCommandRepo:     tagCommandRepo,
 		versionQueryRepo:   versionQueryRepo,
 		versionCommandRepo: versionCommandRepo,
+		blobCommandRepo:    blobCommandRepo,
+		folderQueryRepo:    folderQueryRepo,
+		shareService:       shareService,
+		quotaService:       quotaService,
+		storage:            storage,
 		eventService:       eventService,
 		uow:                uow,
 	}
 }
 
+// GetByID возвращает активный файл; файлы из корзины считаются ненайденными
 func (s *FileService) GetByID(ctx context.Context, fileID uuid.UUID) (*file.File, error) {
+	f, err := s.fileQueryRepo.GetByID(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+	if f == nil || f.IsTrashed() {
+		return nil, file.ErrNotFound
+	}
+	return f, nil
+}
+
+// GetTrashedByID возвращает файл, только если он лежит в корзине
+func (s *FileService) GetTrashedByID(ctx context.Context, fileID uuid.UUID) (*file.File, error) {
 	f, err := s.fileQueryRepo.GetByID(ctx, fileID)
 	if err != nil {
 		return nil, err
@@ -45,9 +86,16 @@ func (s *FileService) GetByID(ctx context.Context, fileID uuid.UUID) (*file.File
 	if f == nil {
 		return nil, file.ErrNotFound
 	}
+	if !f.IsTrashed() {
+		return nil, file.ErrNotInTrash
+	}
 	return f, nil
 }
 
+func (s *FileService) GetTrash(ctx context.Context, userID uuid.UUID) ([]*file.File, error) {
+	return s.fileQueryRepo.GetTrashedByUserID(ctx, userID)
+}
+
 func (s *FileService) GetAllByUser(ctx context.Context, userID uuid.UUID) ([]*file.File, error) {
 	return s.fileQueryRepo.GetByUserID(ctx, userID)
 }
@@ -75,6 +123,57 @@ func (s *FileService) SearchByName(ctx context.Context, userID uuid.UUID, query
 	return s.fileQueryRepo.SearchByName(ctx, userID, query, limit, skip)
 }
 
+// SearchInFolder поиск файлов пользователя внутри папки (nil — корень) с пагинацией
+func (s *FileService) SearchInFolder(ctx context.Context, userID uuid.UUID, folderID *uuid.UUID, query string, limit int, skip int) ([]*file.File, int64, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if skip < 0 {
+		skip = 0
+	}
+
+	return s.fileQueryRepo.SearchInFolder(ctx, userID, folderID, query, limit, skip)
+}
+
+func (s *FileService) GetByFolderID(ctx context.Context, folderID uuid.UUID) ([]*file.File, error) {
+	return s.fileQueryRepo.GetByFolderID(ctx, folderID)
+}
+
+// MoveToFolder переносит файл в папку; folderID == nil переносит файл в корень
+func (s *FileService) MoveToFolder(ctx context.Context, fileID uuid.UUID, folderID *uuid.UUID) error {
+	var f *file.File
+	var oldFolderID *uuid.UUID
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		f, err = s.fileQueryRepo.GetByID(ctx, fileID)
+		if err != nil {
+			return err
+		}
+		if f == nil {
+			return file.ErrNotFound
+		}
+
+		oldFolderID = f.FolderID
+		f.MoveToFolder(folderID)
+		return s.fileCommandRepo.Save(ctx, f)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if s.eventService != nil {
+		eventName, payload := file.NewFileMovedEvent(f, oldFolderID)
+		_, _ = s.eventService.Create(ctx, eventName, payload)
+	}
+
+	return nil
+}
+
 func (s *FileService) RenameFile(ctx context.Context, fileID uuid.UUID, newName string) error {
 	var f *file.File
 
@@ -109,14 +208,15 @@ func (s *FileService) RenameFile(ctx context.Context, fileID uuid.UUID, newName
 	return nil
 }
 
+// Delete перемещает файл в корзину. Версии и объекты в хранилище остаются до Purge.
 func (s *FileService) Delete(ctx context.Context, fileID uuid.UUID) error {
 	var f *file.File
 
 	err := s.uow.Do(ctx, func(ctx context.Context) error {
 		var err error
-		f, err = s.fileQueryRepo.GetByID(ctx, fileID)
-		if err != nil || f == nil {
-			return file.ErrNotFound
+		f, err = s.GetByID(ctx, fileID)
+		if err != nil {
+			return err
 		}
 
 		versions, err := s.versionQueryRepo.GetByFileID(ctx, fileID)
@@ -130,10 +230,89 @@ func (s *FileService) Delete(ctx context.Context, fileID uuid.UUID) error {
 			}
 		}
 
+		f.MoveToTrash()
+		return s.fileCommandRepo.Save(ctx, f)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if s.eventService != nil {
+		eventName, payload := file.NewFileTrashedEvent(f)
+		_, _ = s.eventService.Create(ctx, eventName, payload)
+	}
+
+	return nil
+}
+
+// Restore возвращает файл из корзины в исходную папку (или в корень, если папка удалена)
+func (s *FileService) Restore(ctx context.Context, fileID uuid.UUID) error {
+	var f *file.File
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		f, err = s.GetTrashedByID(ctx, fileID)
+		if err != nil {
+			return err
+		}
+
+		f.RestoreFromTrash()
+		return s.fileCommandRepo.Save(ctx, f)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if s.eventService != nil {
+		eventName, payload := file.NewFileRestoredEvent(f)
+		_, _ = s.eventService.Create(ctx, eventName, payload)
+	}
+
+	return nil
+}
+
+// Purge окончательно удаляет файл и все его версии. Объект в хранилище удаляется,
+// только если на его blob больше не ссылаются версии других файлов.
+func (s *FileService) Purge(ctx context.Context, fileID uuid.UUID) error {
+	var f *file.File
+	var versions []*file_version.FileVersion
+	released := make(map[uuid.UUID]bool)
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		f, err = s.fileQueryRepo.GetByID(ctx, fileID)
+		if err != nil {
+			return err
+		}
+		if f == nil {
+			return file.ErrNotFound
+		}
+
+		versions, err = s.versionQueryRepo.GetByFileID(ctx, fileID)
+		if err != nil {
+			return err
+		}
+
+		var size int64
+		for _, v := range versions {
+			size += int64(v.Size.Uint64())
+		}
+		if err := s.quotaService.Release(ctx, f.OwnerID, size); err != nil {
+			return err
+		}
+
 		for _, v := range versions {
 			if err := s.versionCommandRepo.Delete(ctx, v.ID); err != nil {
 				return err
 			}
+
+			last, err := s.releaseBlob(ctx, v)
+			if err != nil {
+				return err
+			}
+			released[v.ID] = last
 		}
 
 		return s.fileCommandRepo.Delete(ctx, fileID)
@@ -143,10 +322,73 @@ func (s *FileService) Delete(ctx context.Context, fileID uuid.UUID) error {
 		return err
 	}
 
+	// Объекты удаляются после коммита: строки в БД уже не ссылаются на них
+	versionIDs := make([]uuid.UUID, 0, len(versions))
+	previews := make([]string, 0, len(versions)+1)
+	for _, v := range versions {
+		versionIDs = append(versionIDs, v.ID)
+		if v.PreviewS3Key != nil {
+			previews = append(previews, v.PreviewS3Key.String())
+		}
+		if released[v.ID] {
+			s.deleteObject(ctx, v.S3Key.String())
+		}
+	}
+	if f.PreviewS3Key != nil {
+		previews = append(previews, f.PreviewS3Key.String())
+	}
+	s.deleteUnreferencedPreviews(ctx, previews)
+
 	if s.eventService != nil {
-		eventName, payload := file.NewFileDeletedEvent(f)
+		eventName, payload := file.NewFilePurgedEvent(f, versionIDs)
 		_, _ = s.eventService.Create(ctx, eventName, payload)
 	}
 
 	return nil
 }
+
+// GetExpiredTrash возвращает файлы, пролежавшие в корзине дольше retention
+func (s *FileService) GetExpiredTrash(ctx context.Context, retention time.Duration, limit int) ([]*file.File, error) {
+	return s.fileQueryRepo.GetTrashedBefore(ctx, time.Now().Add(-retention), limit)
+}
+
+// releaseBlob снимает ссылку версии на blob; true — объект больше никому не нужен.
+// Версии без хеша (незавершённые и загруженные до дедупликации) владеют своим объектом единолично.
+func (s *FileService) releaseBlob(ctx context.Context, version *file_version.FileVersion) (bool, error) {
+	if version.ContentHash == nil {
+		return true, nil
+	}
+	return s.blobCommandRepo.Release(ctx, *version.ContentHash)
+}
+
+// deleteUnreferencedPreviews удаляет превью, на которые больше не ссылаются версии: превью общее
+// у всех версий blob и у копий файла. Превью по умолчанию лежит вне files/ и не удаляется никогда.
+func (s *FileService) deleteUnreferencedPreviews(ctx context.Context, keys []string) {
+	candidates := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if strings.HasPrefix(key, "files/") {
+			candidates = append(candidates, key)
+		}
+	}
+	if len(candidates) == 0 {
+		return
+	}
+
+	// Ошибку проверки пропускаем: оставшиеся объекты подберёт сверка хранилища с БД
+	referenced, err := s.versionQueryRepo.GetReferencedKeys(ctx, candidates)
+	if err != nil {
+		return
+	}
+	for _, key := range candidates {
+		if !referenced[key] {
+			s.deleteObject(ctx, key)
+		}
+	}
+}
+
+func (s *FileService) deleteObject(ctx context.Context, key string) {
+	if s.storage == nil || key == "" {
+		return
+	}
+	_ = s.storage.Delete(ctx, key)
+}
@@ -0,0 +1,52 @@
+package file_service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file"
+)
+
+// GetTags метки файла по алфавиту
+func (s *FileService) GetTags(ctx context.Context, fileID uuid.UUID) ([]file.Tag, error) {
+	return s.tagQueryRepo.GetByFileID(ctx, fileID)
+}
+
+// Tag ставит файлу метки add и снимает метки remove; метка из обоих списков остаётся снятой
+func (s *FileService) Tag(ctx context.Context, fileID uuid.UUID, add, remove []string) error {
+	addTags, err := file.NewTags(add)
+	if err != nil {
+		return err
+	}
+	removeTags, err := file.NewTags(remove)
+	if err != nil {
+		return err
+	}
+
+	var f *file.File
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		f, err = s.fileQueryRepo.GetByID(ctx, fileID)
+		if err != nil {
+			return err
+		}
+		if f == nil {
+			return file.ErrNotFound
+		}
+
+		if err := s.tagCommandRepo.Add(ctx, fileID, addTags); err != nil {
+			return err
+		}
+		return s.tagCommandRepo.Remove(ctx, fileID, removeTags)
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.eventService != nil {
+		eventName, payload := file.NewFileTaggedEvent(f, addTags, removeTags)
+		_, _ = s.eventService.Create(ctx, eventName, payload)
+	}
+
+	return nil
+}
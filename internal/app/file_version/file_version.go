@@ -2,25 +2,45 @@ package file_version_service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"hash/crc32"
+	"io"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/yourusername/cloud-file-storage/internal/app"
 	event_service "github.com/yourusername/cloud-file-storage/internal/app/event"
+	quota_service "github.com/yourusername/cloud-file-storage/internal/app/quota"
+	"github.com/yourusername/cloud-file-storage/internal/domain/blob"
+	"github.com/yourusername/cloud-file-storage/internal/domain/drop_link"
 	"github.com/yourusername/cloud-file-storage/internal/domain/file"
 	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
 	"github.com/yourusername/cloud-file-storage/internal/domain/queue"
+	"github.com/yourusername/cloud-file-storage/internal/domain/quota"
 	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
 )
 
 const uploadURLTTL = 15 * time.Minute
 
+// abandonedReason причина, с которой брошенная загрузка переводится в failed
+const abandonedReason = "upload abandoned: no object was uploaded before the upload expired"
+
+// errAlreadyCompleted откатывает транзакцию, если версию завершил параллельный вызов
+var errAlreadyCompleted = errors.New("upload already completed")
+
 type FileVersionService struct {
 	fileQueryRepo      file.QueryRepository
 	fileCommandRepo    file.CommandRepository
 	versionQueryRepo   file_version.QueryRepository
 	versionCommandRepo file_version.CommandRepository
+	blobCommandRepo    blob.CommandRepository
+	quotaService       *quota_service.QuotaService
 	storage            storage.Storage
 	previewConsumer    queue.PreviewConsumer
 	previewProducer    queue.PreviewProducer
@@ -33,6 +53,8 @@ func NewFileVersionService(
 	fileCommandRepo file.CommandRepository,
 	versionQueryRepo file_version.QueryRepository,
 	versionCommandRepo file_version.CommandRepository,
+	blobCommandRepo blob.CommandRepository,
+	quotaService *quota_service.QuotaService,
 	storage storage.Storage,
 	previewConsumer queue.PreviewConsumer,
 	previewProducer queue.PreviewProducer,
@@ -44,6 +66,8 @@ func NewFileVersionService(
 		fileCommandRepo:    fileCommandRepo,
 		versionQueryRepo:   versionQueryRepo,
 		versionCommandRepo: versionCommandRepo,
+		blobCommandRepo:    blobCommandRepo,
+		quotaService:       quotaService,
 		storage:            storage,
 		previewConsumer:    previewConsumer,
 		eventService:       eventService,
@@ -85,6 +109,112 @@ func (s *FileVersionService) UpdatePreview(ctx context.Context, versionID uuid.U
 func (s *FileVersionService) GetVersionsByStatus(ctx context.Context, status file_version.FileStatus) ([]*file_version.FileVersion, error) {
 	return s.versionQueryRepo.GetAllByStatus(ctx, status)
 }
+
+// GetStaleProcessing возвращает версии, которые ждут загрузки дольше olderThan
+func (s *FileVersionService) GetStaleProcessing(ctx context.Context, olderThan time.Duration, limit int) ([]*file_version.FileVersion, error) {
+	return s.versionQueryRepo.GetProcessingBefore(ctx, time.Now().Add(-olderThan), limit)
+}
+
+// GetAbandonedUploads возвращает версии без данных, загрузку которых не начали или бросили дольше olderThan назад
+func (s *FileVersionService) GetAbandonedUploads(ctx context.Context, olderThan time.Duration, limit int) ([]*file_version.FileVersion, error) {
+	return s.versionQueryRepo.GetAbandoned(ctx, time.Now().Add(-olderThan), limit)
+}
+
+// AbandonUpload снимает брошенную загрузку и освобождает зарезервированную под неё квоту.
+// Единственная версия удаляется вместе с файлом, иначе версия переходит в failed, а файл
+// откатывается к последней загруженной версии. false — версия уже не ждёт данных или объект появился.
+func (s *FileVersionService) AbandonUpload(ctx context.Context, versionID uuid.UUID) (bool, error) {
+	version, err := s.versionQueryRepo.GetByID(ctx, versionID)
+	if err != nil {
+		return false, err
+	}
+	if version == nil || !version.Status.Equal(file_version.FileStatusProcessing) {
+		return false, nil
+	}
+
+	// Объект есть, но уведомление потерялось: такую версию завершит FileChecker
+	exists, err := s.storage.FileExists(ctx, version.S3Key.String())
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+
+	var f *file.File
+	var removed bool
+	reserved := int64(version.Size.Uint64())
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		// Версию могли завершить после проверки выше: тогда она уже не в processing и не трогается
+		marked, err := s.versionCommandRepo.MarkAbandoned(ctx, version.ID, abandonedReason)
+		if err != nil {
+			return err
+		}
+		if !marked {
+			return errAlreadyCompleted
+		}
+		version.MarkFailedWithReason(abandonedReason)
+		version.SetSize(file_version.FileSize{})
+
+		f, err = s.fileQueryRepo.GetByID(ctx, version.FileId)
+		if err != nil {
+			return err
+		}
+		if f == nil {
+			return file.ErrNotFound
+		}
+
+		versions, err := s.versionQueryRepo.GetByFileID(ctx, f.ID)
+		if err != nil {
+			return err
+		}
+
+		// Данных у версии нет, поэтому удаление версии позже квоту не вернёт
+		if err := s.quotaService.Release(ctx, f.OwnerID, reserved); err != nil {
+			return err
+		}
+
+		if len(versions) == 1 {
+			removed = true
+			// Версии, multipart и tus загрузки удаляются каскадом
+			return s.fileCommandRepo.Delete(ctx, f.ID)
+		}
+
+		if !f.VersionNum.Equal(version.VersionNum) {
+			return nil
+		}
+		if last := lastAvailableVersion(versions); last != nil {
+			f.UpdateFromVersion(last)
+		} else {
+			f.UpdateFromVersion(version)
+		}
+		return s.fileCommandRepo.Save(ctx, f)
+	})
+	if errors.Is(err, errAlreadyCompleted) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if s.eventService != nil {
+		eventName, payload := file.NewFileVersionAbandonedEvent(f, version.ID, version.VersionNum.Int(), removed)
+		_, _ = s.eventService.Create(ctx, eventName, payload)
+	}
+
+	return true, nil
+}
+
+// lastAvailableVersion последняя версия с загруженными данными; versions отсортированы по убыванию номера
+func lastAvailableVersion(versions []*file_version.FileVersion) *file_version.FileVersion {
+	for _, v := range versions {
+		if v.Status.Equal(file_version.FileStatusReady) || v.Status.Equal(file_version.FileStatusUploaded) {
+			return v
+		}
+	}
+	return nil
+}
+
 func (s *FileVersionService) GetFileByID(ctx context.Context, fileID uuid.UUID) (*file.File, error) {
 	return s.fileQueryRepo.GetByID(ctx, fileID)
 }
@@ -132,10 +262,35 @@ func (s *FileVersionService) GetDownloadURL(ctx context.Context, versionID uuid.
 	return &url, nil
 }
 
-func (s *FileVersionService) UploadNewFile(ctx context.Context, ownerID, sessionID uuid.UUID, name string, size uint64, mime string) (*file.File, *file_version.FileVersion, string, error) {
+// GetPreviewURL подписанный URL превью версии; nil, если превью ещё нет
+func (s *FileVersionService) GetPreviewURL(ctx context.Context, version *file_version.FileVersion, duration time.Duration) (*string, error) {
+	if version.PreviewS3Key == nil {
+		return nil, nil
+	}
+
+	url, err := s.storage.GenerateDownloadURL(ctx, version.PreviewS3Key.String(), duration)
+	if err != nil {
+		return nil, err
+	}
+	return &url, nil
+}
+
+// UploadNewFile создаёт файл и первую версию. checksum необязателен: если он задан,
+// подписанный PUT требует заголовок с ним, а CompleteUpload сверяет содержимое.
+func (s *FileVersionService) UploadNewFile(ctx context.Context, ownerID, sessionID uuid.UUID, name string, size uint64, mime string, folderID *uuid.UUID, checksum *file_version.Checksum) (*file.File, *file_version.FileVersion, string, map[string]string, error) {
+	return s.uploadNewFile(ctx, ownerID, sessionID, nil, name, size, mime, folderID, checksum)
+}
+
+// UploadNewFileFromDropLink как UploadNewFile, но без сессии: версия запоминает ссылку для загрузки dropLinkID
+func (s *FileVersionService) UploadNewFileFromDropLink(ctx context.Context, ownerID, dropLinkID uuid.UUID, name string, size uint64, mime string, folderID *uuid.UUID, checksum *file_version.Checksum) (*file.File, *file_version.FileVersion, string, map[string]string, error) {
+	return s.uploadNewFile(ctx, ownerID, uuid.Nil, &dropLinkID, name, size, mime, folderID, checksum)
+}
+
+func (s *FileVersionService) uploadNewFile(ctx context.Context, ownerID, sessionID uuid.UUID, dropLinkID *uuid.UUID, name string, size uint64, mime string, folderID *uuid.UUID, checksum *file_version.Checksum) (*file.File, *file_version.FileVersion, string, map[string]string, error) {
 	var f *file.File
 	var version *file_version.FileVersion
 	var uploadURL string
+	var uploadHeaders map[string]string
 
 	err := s.uow.Do(ctx, func(ctx context.Context) error {
 		fileNameVO, err := file.NewFileName(name)
@@ -153,6 +308,7 @@ func (s *FileVersionService) UploadNewFile(ctx context.Context, ownerID, session
 		versionNumVO, _ := file_version.NewFileVersionNum(1)
 
 		f = file.NewFile(ownerID, fileNameVO, fileSizeVO, mimeVO, versionNumVO, sessionID)
+		f.FolderID = folderID
 
 		key := generateS3Key(ownerID, f.ID, versionNumVO.Int(), fileNameVO.String())
 		s3Key, err := file_version.NewS3Key(key)
@@ -161,7 +317,13 @@ func (s *FileVersionService) UploadNewFile(ctx context.Context, ownerID, session
 		}
 
 		version = file_version.NewFileVersion(f.ID, sessionID, s3Key, mimeVO, fileSizeVO, versionNumVO)
+		version.Checksum = checksum
+		version.DropLinkID = dropLinkID
+		f.Checksum = checksum
 
+		if err := s.quotaService.Reserve(ctx, ownerID, int64(size)); err != nil {
+			return err
+		}
 		if err := s.fileCommandRepo.Save(ctx, f); err != nil {
 			return err
 		}
@@ -169,7 +331,11 @@ func (s *FileVersionService) UploadNewFile(ctx context.Context, ownerID, session
 			return err
 		}
 
-		uploadURL, err = s.storage.GenerateUploadURLWithSize(ctx, s3Key.String(), uploadURLTTL, int64(version.Size.Uint64()))
+		if checksum != nil {
+			uploadURL, uploadHeaders, err = s.generateChecksumUploadURL(ctx, version)
+		} else {
+			uploadURL, err = s.storage.GenerateUploadURLWithSize(ctx, s3Key.String(), uploadURLTTL, int64(version.Size.Uint64()))
+		}
 		if err != nil {
 			return err
 		}
@@ -178,17 +344,29 @@ func (s *FileVersionService) UploadNewFile(ctx context.Context, ownerID, session
 	})
 
 	if err != nil {
-		return nil, nil, "", err
+		return nil, nil, "", nil, err
 	}
 
 	if s.eventService != nil {
-		eventName, payload := file.NewFileVersionUploadedEvent(f.ID, version.ID, ownerID, f.Name.String(), version.VersionNum.Int())
+		eventName, payload := file.NewFileVersionUploadedEvent(f.ID, version.ID, ownerID, ownerID, f.Name.String(), version.VersionNum.Int())
 		_, _ = s.eventService.Create(ctx, eventName, payload)
 	}
 
-	return f, version, uploadURL, nil
+	return f, version, uploadURL, uploadHeaders, nil
 }
 
+// generateChecksumUploadURL подписывает PUT вместе с размером и заявленной контрольной суммой версии
+func (s *FileVersionService) generateChecksumUploadURL(ctx context.Context, version *file_version.FileVersion) (string, map[string]string, error) {
+	return s.storage.GenerateUploadURLWithChecksum(ctx, version.S3Key.String(), uploadURLTTL, int64(version.Size.Uint64()), storage.Checksum{
+		Algorithm: string(version.Checksum.Algorithm()),
+		Value:     version.Checksum.Value(),
+	})
+}
+
+// CompleteUpload считает SHA-256 загруженного объекта и связывает версию с blob.
+// Если такие байты уже хранятся, версия переключается на существующий объект, а загруженная копия удаляется.
+// Если клиент заявил контрольную сумму, а содержимое с ней или с размером не совпало, версия
+// переходит в failed и возвращается ErrChecksumMismatch.
 func (s *FileVersionService) CompleteUpload(ctx context.Context, versionID uuid.UUID) error {
 	version, err := s.versionQueryRepo.GetByID(ctx, versionID)
 	if err != nil {
@@ -198,10 +376,74 @@ func (s *FileVersionService) CompleteUpload(ctx context.Context, versionID uuid.
 	if err != nil {
 		return err
 	}
+
+	uploadedKey := version.S3Key
+	var digest objectDigest
+	if version.ContentHash == nil {
+		// Объект читается до транзакции, чтобы не держать её открытой на время хеширования
+		digest, err = s.hashObject(ctx, uploadedKey.String())
+		if err != nil {
+			return err
+		}
+		if reason := verifyChecksum(version, digest); reason != "" {
+			return s.failUpload(ctx, version, file, file_version.ErrChecksumMismatch, reason)
+		}
+	}
+	hash, size, keyID := digest.hash, digest.size, digest.keyID
+
 	version.MarkUploaded()
 	file.MarkUploaded()
 
+	declaredSize := version.Size
+	declared := int64(declaredSize.Uint64())
 	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if version.ContentHash == nil {
+			// Квота резервировалась по заявленному размеру, учитываем фактический.
+			// Загруженное сверх заявленного резервируется с проверкой лимита.
+			if size != declared {
+				actual, err := file_version.NewFileSize(uint64(size))
+				if err != nil {
+					return err
+				}
+				if size > declared {
+					err = s.quotaService.Reserve(ctx, file.OwnerID, size-declared)
+				} else {
+					err = s.quotaService.Adjust(ctx, file.OwnerID, size-declared)
+				}
+				if err != nil {
+					return err
+				}
+				version.Size = actual
+				if file.VersionNum.Equal(version.VersionNum) {
+					file.Size = actual
+				}
+			}
+
+			b, err := s.blobCommandRepo.Acquire(ctx, blob.NewBlob(hash, uploadedKey, size))
+			if err != nil {
+				return err
+			}
+
+			attached, err := s.versionCommandRepo.AttachBlob(ctx, version.ID, b.Hash, b.S3Key)
+			if err != nil {
+				return err
+			}
+			if !attached {
+				return errAlreadyCompleted
+			}
+			version.AttachBlob(b.Hash, b.S3Key)
+
+			// Одинаковое содержимое уже хранится под другим ключом, и зашифровано оно своим ключом данных
+			if b.S3Key != uploadedKey {
+				info, err := s.storage.Stat(ctx, b.S3Key.String())
+				if err != nil {
+					return err
+				}
+				keyID = info.KeyID
+			}
+			version.SetKeyID(keyID)
+		}
+
 		if err := s.versionCommandRepo.Save(ctx, version); err != nil {
 			return err
 		}
@@ -214,18 +456,167 @@ func (s *FileVersionService) CompleteUpload(ctx context.Context, versionID uuid.
 
 	})
 
+	if errors.Is(err, errAlreadyCompleted) {
+		return nil
+	}
+	if errors.Is(err, quota.ErrQuotaExceeded) {
+		// Лишние байты квоте не засчитаны, объект не нужен: версия остаётся с заявленным размером
+		_ = s.storage.Delete(ctx, uploadedKey.String())
+		version.Size = declaredSize
+		if file.VersionNum.Equal(version.VersionNum) {
+			file.Size = declaredSize
+		}
+		return s.failUpload(ctx, version, file, err, fmt.Sprintf("uploaded %d bytes, declared %d", size, declared))
+	}
 	if err != nil {
 		return err
 	}
 
+	if version.S3Key != uploadedKey {
+		_ = s.storage.Delete(ctx, uploadedKey.String())
+	}
+
+	// Владелец узнаёт о файле, пришедшем по ссылке для загрузки, когда данные уже лежат в хранилище
+	if version.DropLinkID != nil && s.eventService != nil {
+		eventName, payload := drop_link.NewDropLinkFileReceivedEvent(*version.DropLinkID, file.OwnerID, file.ID, version.ID, file.Name.String(), version.Mime.String(), version.Size.Uint64())
+		_, _ = s.eventService.Create(ctx, eventName, payload)
+	}
+
 	err = s.previewProducer.Produce(ctx, versionID)
 
 	return err
 }
-func (s *FileVersionService) UploadNewVersion(ctx context.Context, fileID, ownerID, sessionID uuid.UUID, name string, size uint64, mime string, versionNum int) (*file.File, *file_version.FileVersion, string, error) {
+
+// CompleteUploadByKey завершает загрузку по уведомлению о созданном объекте. false — объект не ждёт
+// ни одна версия: это превью, промежуточные данные tus или загрузка, которую уже завершили.
+func (s *FileVersionService) CompleteUploadByKey(ctx context.Context, key string) (bool, error) {
+	s3Key, err := file_version.NewS3Key(key)
+	if err != nil {
+		return false, nil
+	}
+
+	version, err := s.versionQueryRepo.GetProcessingByS3Key(ctx, s3Key)
+	if err != nil {
+		return false, err
+	}
+	if version == nil {
+		return false, nil
+	}
+
+	return true, s.CompleteUpload(ctx, version.ID)
+}
+
+// objectDigest дайджесты и размер содержимого, посчитанные за одно чтение объекта
+type objectDigest struct {
+	hash   file_version.ContentHash
+	sha256 []byte
+	crc32c uint32
+	size   int64
+	// keyID мастер-ключ, которым зашифрован объект
+	keyID string
+}
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// hashObject считает SHA-256, CRC32C и размер содержимого
+func (s *FileVersionService) hashObject(ctx context.Context, key string) (objectDigest, error) {
+	obj, err := s.storage.Get(ctx, key)
+	if err != nil {
+		return objectDigest{}, err
+	}
+	defer obj.Body.Close()
+
+	h := sha256.New()
+	c := crc32.New(crc32cTable)
+	size, err := io.Copy(io.MultiWriter(h, c), obj.Body)
+	if err != nil {
+		return objectDigest{}, err
+	}
+
+	sum := h.Sum(nil)
+	hash, err := file_version.NewContentHash(hex.EncodeToString(sum))
+	if err != nil {
+		return objectDigest{}, err
+	}
+	return objectDigest{hash: hash, sha256: sum, crc32c: c.Sum32(), size: size, keyID: obj.KeyID}, nil
+}
+
+// verifyChecksum сверяет содержимое с заявленными клиентом размером и контрольной суммой.
+// Возвращает причину несовпадения или пустую строку.
+func verifyChecksum(version *file_version.FileVersion, d objectDigest) string {
+	if version.Checksum == nil {
+		return ""
+	}
+	if declared := int64(version.Size.Uint64()); d.size != declared {
+		return fmt.Sprintf("size mismatch: declared %d bytes, uploaded %d", declared, d.size)
+	}
+
+	var actual string
+	switch version.Checksum.Algorithm() {
+	case file_version.ChecksumSHA256:
+		actual = base64.StdEncoding.EncodeToString(d.sha256)
+	case file_version.ChecksumCRC32C:
+		actual = base64.StdEncoding.EncodeToString(binary.BigEndian.AppendUint32(nil, d.crc32c))
+	}
+	if actual != version.Checksum.Value() {
+		return fmt.Sprintf("%s mismatch: declared %s, uploaded %s", version.Checksum.Algorithm(), version.Checksum.Value(), actual)
+	}
+	return ""
+}
+
+// failUpload переводит версию в failed и возвращает cause с причиной. Объект и резерв квоты
+// остаются за версией и освобождаются при её удалении, как у любой другой версии.
+func (s *FileVersionService) failUpload(ctx context.Context, version *file_version.FileVersion, f *file.File, cause error, reason string) error {
+	version.MarkFailedWithReason(reason)
+	if f.VersionNum.Equal(version.VersionNum) {
+		f.MarkFailed()
+	}
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.versionCommandRepo.Save(ctx, version); err != nil {
+			return err
+		}
+		return s.fileCommandRepo.Save(ctx, f)
+	})
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%w: %s", cause, reason)
+}
+
+// releaseBlob снимает ссылку версии на blob; true — объект больше никому не нужен.
+// Версии без хеша (незавершённые и загруженные до дедупликации) владеют своим объектом единолично.
+func (s *FileVersionService) releaseBlob(ctx context.Context, version *file_version.FileVersion) (bool, error) {
+	if version.ContentHash == nil {
+		return true, nil
+	}
+	return s.blobCommandRepo.Release(ctx, *version.ContentHash)
+}
+
+// UploadNewVersion uploaderID — владелец или редактор файла; объект и квота всегда относятся к владельцу
+func (s *FileVersionService) UploadNewVersion(ctx context.Context, fileID, uploaderID, sessionID uuid.UUID, name string, size uint64, mime string, versionNum int, checksum *file_version.Checksum) (*file.File, *file_version.FileVersion, string, map[string]string, error) {
+	return s.uploadNewVersion(ctx, fileID, uploaderID, sessionID, nil, name, size, mime, versionNum, checksum)
+}
+
+// UploadNewVersionFromDropLink новая версия от имени владельца без сессии. Загрузчик анонимен,
+// поэтому подписанный PUT, как у новых файлов, ограничен заявленным размером.
+func (s *FileVersionService) UploadNewVersionFromDropLink(ctx context.Context, fileID, dropLinkID uuid.UUID, name string, size uint64, mime string, checksum *file_version.Checksum) (*file.File, *file_version.FileVersion, string, map[string]string, error) {
+	f, err := s.fileQueryRepo.GetByID(ctx, fileID)
+	if err != nil {
+		return nil, nil, "", nil, err
+	}
+	if f == nil || f.IsTrashed() {
+		return nil, nil, "", nil, file.ErrNotFound
+	}
+	return s.uploadNewVersion(ctx, fileID, f.OwnerID, uuid.Nil, &dropLinkID, name, size, mime, f.VersionNum.Int()+1, checksum)
+}
+
+func (s *FileVersionService) uploadNewVersion(ctx context.Context, fileID, uploaderID, sessionID uuid.UUID, dropLinkID *uuid.UUID, name string, size uint64, mime string, versionNum int, checksum *file_version.Checksum) (*file.File, *file_version.FileVersion, string, map[string]string, error) {
 	var f *file.File
 	var version *file_version.FileVersion
 	var uploadURL string
+	var uploadHeaders map[string]string
 
 	err := s.uow.Do(ctx, func(ctx context.Context) error {
 		var err error
@@ -237,19 +628,34 @@ func (s *FileVersionService) UploadNewVersion(ctx context.Context, fileID, owner
 			return file.ErrNotFound
 		}
 
+		// Текущая версия файла может быть не последней после отката или восстановления,
+		// поэтому номер не должен повторять уже существующий
+		versions, err := s.versionQueryRepo.GetByFileID(ctx, f.ID)
+		if err != nil {
+			return err
+		}
+		if len(versions) > 0 && versions[0].VersionNum.Int() >= versionNum {
+			versionNum = versions[0].VersionNum.Int() + 1
+		}
+
 		fileNameVO, _ := file.NewFileName(name)
 		fileSizeVO, _ := file_version.NewFileSize(size)
 		mimeVO, _ := file_version.NewMimeType(mime)
 		versionNumVO, _ := file_version.NewFileVersionNum(versionNum)
 
-		s3Key := generateS3Key(ownerID, f.ID, versionNumVO.Int(), fileNameVO.String())
+		s3Key := generateS3Key(f.OwnerID, f.ID, versionNumVO.Int(), fileNameVO.String())
 		s3, err := file_version.NewS3Key(s3Key)
 		if err != nil {
 			return err
 		}
 
 		version = file_version.NewFileVersion(f.ID, sessionID, s3, mimeVO, fileSizeVO, versionNumVO)
+		version.Checksum = checksum
+		version.DropLinkID = dropLinkID
 
+		if err := s.quotaService.Reserve(ctx, f.OwnerID, int64(size)); err != nil {
+			return err
+		}
 		f.UpdateFromVersion(version)
 
 		if err := s.fileCommandRepo.Save(ctx, f); err != nil {
@@ -259,7 +665,11 @@ func (s *FileVersionService) UploadNewVersion(ctx context.Context, fileID, owner
 			return err
 		}
 
-		uploadURL, err = s.storage.GenerateUploadURL(ctx, s3Key, uploadURLTTL)
+		if checksum != nil {
+			uploadURL, uploadHeaders, err = s.generateChecksumUploadURL(ctx, version)
+		} else {
+			uploadURL, err = s.storage.GenerateUploadURLWithSize(ctx, s3Key, uploadURLTTL, int64(version.Size.Uint64()))
+		}
 		if err != nil {
 			return err
 		}
@@ -268,15 +678,106 @@ func (s *FileVersionService) UploadNewVersion(ctx context.Context, fileID, owner
 	})
 
 	if err != nil {
-		return nil, nil, "", err
+		return nil, nil, "", nil, err
+	}
+
+	if s.eventService != nil {
+		eventName, payload := file.NewFileVersionUploadedEvent(f.ID, version.ID, f.OwnerID, uploaderID, f.Name.String(), f.VersionNum.Int())
+		_, _ = s.eventService.Create(ctx, eventName, payload)
+	}
+
+	return f, version, uploadURL, uploadHeaders, nil
+}
+
+// CopyFile копирует версию versionNum файла (0 — текущую) в новый файл с единственной версией 1.
+// Объект копируется внутри хранилища; копия владеет своим объектом и не ссылается на blob,
+// как версии, загруженные до дедупликации. Превью источника копия использует то же.
+// name == "" оставляет имя источника, folderID — папка копии, nil — корень.
+func (s *FileVersionService) CopyFile(ctx context.Context, fileID, ownerID, sessionID uuid.UUID, name string, folderID *uuid.UUID, versionNum int) (*file.File, *file_version.FileVersion, error) {
+	source, err := s.fileQueryRepo.GetByID(ctx, fileID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if source == nil || source.IsTrashed() {
+		return nil, nil, file.ErrNotFound
+	}
+	if versionNum == 0 {
+		versionNum = source.VersionNum.Int()
+	}
+
+	versions, err := s.versionQueryRepo.GetByFileID(ctx, fileID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var sourceVersion *file_version.FileVersion
+	for _, v := range versions {
+		if v.VersionNum.Int() == versionNum {
+			sourceVersion = v
+			break
+		}
+	}
+	if sourceVersion == nil {
+		return nil, nil, file_version.ErrVersionNotFound
+	}
+	if !sourceVersion.Status.Equal(file_version.FileStatusReady) && !sourceVersion.Status.Equal(file_version.FileStatusUploaded) {
+		return nil, nil, file_version.ErrVersionNotStored
+	}
+
+	if name == "" {
+		name = source.Name.String()
+	}
+	fileNameVO, err := file.NewFileName(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	versionNumVO, _ := file_version.NewFileVersionNum(1)
+
+	f := file.NewFile(ownerID, fileNameVO, sourceVersion.Size, sourceVersion.Mime, versionNumVO, sessionID)
+	f.FolderID = folderID
+
+	s3Key, err := file_version.NewS3Key(generateS3Key(ownerID, f.ID, versionNumVO.Int(), fileNameVO.String()))
+	if err != nil {
+		return nil, nil, err
+	}
+	version := file_version.NewFileVersion(f.ID, sessionID, s3Key, sourceVersion.Mime, sourceVersion.Size, versionNumVO)
+	version.PreviewS3Key = sourceVersion.PreviewS3Key
+	version.Checksum = sourceVersion.Checksum
+	version.KeyID = sourceVersion.KeyID
+	if err := version.SetStatus(sourceVersion.Status); err != nil {
+		return nil, nil, err
+	}
+	f.UpdateFromVersion(version)
+
+	// Объект копируется до транзакции: строка БД не должна ссылаться на объект, которого ещё нет
+	if err := s.storage.Copy(ctx, sourceVersion.S3Key.String(), s3Key.String()); err != nil {
+		return nil, nil, err
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.quotaService.Reserve(ctx, ownerID, int64(version.Size.Uint64())); err != nil {
+			return err
+		}
+		if err := s.fileCommandRepo.Save(ctx, f); err != nil {
+			return err
+		}
+		return s.versionCommandRepo.Save(ctx, version)
+	})
+	if err != nil {
+		_ = s.storage.Delete(ctx, s3Key.String())
+		return nil, nil, err
+	}
+
+	// Превью источника ещё строится: у копии оно будет своё
+	if version.PreviewS3Key == nil && version.Status.Equal(file_version.FileStatusUploaded) {
+		_ = s.previewProducer.Produce(ctx, version.ID)
 	}
 
 	if s.eventService != nil {
-		eventName, payload := file.NewFileVersionUploadedEvent(f.ID, version.ID, ownerID, f.Name.String(), f.VersionNum.Int())
+		eventName, payload := file.NewFileCopiedEvent(f, source.ID, sourceVersion.ID)
 		_, _ = s.eventService.Create(ctx, eventName, payload)
 	}
 
-	return f, version, uploadURL, nil
+	return f, version, nil
 }
 
 func (s *FileVersionService) RestoreVersion(ctx context.Context, fileID, versionID uuid.UUID) error {
@@ -319,6 +820,7 @@ func (s *FileVersionService) RestoreVersion(ctx context.Context, fileID, version
 
 func (s *FileVersionService) DeleteVersion(ctx context.Context, fileID, versionID uuid.UUID) error {
 	var version *file_version.FileVersion
+	var released bool
 
 	err := s.uow.Do(ctx, func(ctx context.Context) error {
 		f, err := s.fileQueryRepo.GetByID(ctx, fileID)
@@ -338,7 +840,15 @@ func (s *FileVersionService) DeleteVersion(ctx context.Context, fileID, versionI
 			return file_version.ErrVersionProcessing
 		}
 
-		return s.versionCommandRepo.Delete(ctx, versionID)
+		if err := s.quotaService.Release(ctx, f.OwnerID, int64(version.Size.Uint64())); err != nil {
+			return err
+		}
+		if err := s.versionCommandRepo.Delete(ctx, versionID); err != nil {
+			return err
+		}
+
+		released, err = s.releaseBlob(ctx, version)
+		return err
 	})
 
 	if err != nil {
@@ -347,9 +857,14 @@ func (s *FileVersionService) DeleteVersion(ctx context.Context, fileID, versionI
 
 	if version.PreviewS3Key != nil {
 		_ = s.previewConsumer.Remove(ctx, version.ID)
-		_ = s.storage.Delete(ctx, version.PreviewS3Key.String())
 	}
-	_ = s.storage.Delete(ctx, version.S3Key.String())
+	if released {
+		_ = s.storage.Delete(ctx, version.S3Key.String())
+	}
+	// Превью общее у всех версий blob и у копий файла, поэтому удаляется, только когда на него не ссылаются
+	if version.PreviewS3Key != nil {
+		s.deleteUnreferencedPreview(ctx, version.PreviewS3Key.String())
+	}
 
 	if s.eventService != nil {
 		eventName, payload := file.NewFileVersionDeletedEvent(fileID, version.ID)
@@ -358,3 +873,17 @@ func (s *FileVersionService) DeleteVersion(ctx context.Context, fileID, versionI
 
 	return nil
 }
+
+// deleteUnreferencedPreview удаляет превью, если на него больше не ссылается ни одна версия.
+// Превью по умолчанию лежит вне files/ и не удаляется никогда. Ошибку проверки пропускаем:
+// оставшийся объект подберёт сверка хранилища с БД.
+func (s *FileVersionService) deleteUnreferencedPreview(ctx context.Context, key string) {
+	if !strings.HasPrefix(key, "files/") {
+		return
+	}
+	referenced, err := s.versionQueryRepo.GetReferencedKeys(ctx, []string{key})
+	if err != nil || referenced[key] {
+		return
+	}
+	_ = s.storage.Delete(ctx, key)
+}
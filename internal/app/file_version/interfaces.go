@@ -14,8 +14,8 @@ type FileVersionServiceInterface interface {
 	GetVersionsByFileID(ctx context.Context, fileID uuid.UUID) ([]*file_version.FileVersion, error)
 	GetVersionByID(ctx context.Context, versionID uuid.UUID) (*file_version.FileVersion, error)
 	GetAllVersions(ctx context.Context) ([]*file_version.FileVersion, error)
-	UploadNewFile(ctx context.Context, ownerID, sessionID uuid.UUID, name string, size uint64, mime string) (*file.File, *file_version.FileVersion, string, error)
-	UploadNewVersion(ctx context.Context, fileID, ownerID, sessionID uuid.UUID, name string, size uint64, mime string, versionNum int) (*file.File, *file_version.FileVersion, string, error)
+	UploadNewFile(ctx context.Context, ownerID, sessionID uuid.UUID, name string, size uint64, mime string, folderID *uuid.UUID, checksum *file_version.Checksum) (*file.File, *file_version.FileVersion, string, map[string]string, error)
+	UploadNewVersion(ctx context.Context, fileID, uploaderID, sessionID uuid.UUID, name string, size uint64, mime string, versionNum int, checksum *file_version.Checksum) (*file.File, *file_version.FileVersion, string, map[string]string, error)
 	RestoreVersion(ctx context.Context, fileID, versionID uuid.UUID) error
 	DeleteVersion(ctx context.Context, fileID, versionID uuid.UUID) error
 }
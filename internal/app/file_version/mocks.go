@@ -16,8 +16,8 @@ type MockFileVersionService struct {
 	GetVersionsByFileIDFunc func(ctx context.Context, fileID uuid.UUID) ([]*file_version.FileVersion, error)
 	GetVersionByIDFunc      func(ctx context.Context, versionID uuid.UUID) (*file_version.FileVersion, error)
 	GetAllVersionsFunc      func(ctx context.Context) ([]*file_version.FileVersion, error)
-	UploadNewFileFunc       func(ctx context.Context, ownerID, sessionID uuid.UUID, name string, size uint64, mime string) (*file.File, *file_version.FileVersion, string, error)
-	UploadNewVersionFunc    func(ctx context.Context, fileID, ownerID, sessionID uuid.UUID, name string, size uint64, mime string, versionNum int) (*file.File, *file_version.FileVersion, string, error)
+	UploadNewFileFunc       func(ctx context.Context, ownerID, sessionID uuid.UUID, name string, size uint64, mime string, folderID *uuid.UUID, checksum *file_version.Checksum) (*file.File, *file_version.FileVersion, string, map[string]string, error)
+	UploadNewVersionFunc    func(ctx context.Context, fileID, ownerID, sessionID uuid.UUID, name string, size uint64, mime string, versionNum int, checksum *file_version.Checksum) (*file.File, *file_version.FileVersion, string, map[string]string, error)
 	RestoreVersionFunc      func(ctx context.Context, fileID, versionID uuid.UUID) error
 	DeleteVersionFunc       func(ctx context.Context, fileID, versionID uuid.UUID) error
 
@@ -62,6 +62,8 @@ type UploadNewFileCall struct {
 	Name      string
 	Size      uint64
 	Mime      string
+	FolderID  *uuid.UUID
+	Checksum  *file_version.Checksum
 }
 
 type UploadNewVersionCall struct {
@@ -73,6 +75,7 @@ type UploadNewVersionCall struct {
 	Size       uint64
 	Mime       string
 	VersionNum int
+	Checksum   *file_version.Checksum
 }
 
 type RestoreVersionCall struct {
@@ -140,7 +143,7 @@ func (m *MockFileVersionService) GetAllVersions(ctx context.Context) ([]*file_ve
 	return []*file_version.FileVersion{}, nil
 }
 
-func (m *MockFileVersionService) UploadNewFile(ctx context.Context, ownerID, sessionID uuid.UUID, name string, size uint64, mime string) (*file.File, *file_version.FileVersion, string, error) {
+func (m *MockFileVersionService) UploadNewFile(ctx context.Context, ownerID, sessionID uuid.UUID, name string, size uint64, mime string, folderID *uuid.UUID, checksum *file_version.Checksum) (*file.File, *file_version.FileVersion, string, map[string]string, error) {
 	m.UploadNewFileCalls = append(m.UploadNewFileCalls, UploadNewFileCall{
 		Ctx:       ctx,
 		OwnerID:   ownerID,
@@ -148,14 +151,16 @@ func (m *MockFileVersionService) UploadNewFile(ctx context.Context, ownerID, ses
 		Name:      name,
 		Size:      size,
 		Mime:      mime,
+		FolderID:  folderID,
+		Checksum:  checksum,
 	})
 	if m.UploadNewFileFunc != nil {
-		return m.UploadNewFileFunc(ctx, ownerID, sessionID, name, size, mime)
+		return m.UploadNewFileFunc(ctx, ownerID, sessionID, name, size, mime, folderID, checksum)
 	}
-	return nil, nil, "", nil
+	return nil, nil, "", nil, nil
 }
 
-func (m *MockFileVersionService) UploadNewVersion(ctx context.Context, fileID, ownerID, sessionID uuid.UUID, name string, size uint64, mime string, versionNum int) (*file.File, *file_version.FileVersion, string, error) {
+func (m *MockFileVersionService) UploadNewVersion(ctx context.Context, fileID, ownerID, sessionID uuid.UUID, name string, size uint64, mime string, versionNum int, checksum *file_version.Checksum) (*file.File, *file_version.FileVersion, string, map[string]string, error) {
 	m.UploadNewVersionCalls = append(m.UploadNewVersionCalls, UploadNewVersionCall{
 		Ctx:        ctx,
 		FileID:     fileID,
@@ -165,11 +170,12 @@ func (m *MockFileVersionService) UploadNewVersion(ctx context.Context, fileID, o
 		Size:       size,
 		Mime:       mime,
 		VersionNum: versionNum,
+		Checksum:   checksum,
 	})
 	if m.UploadNewVersionFunc != nil {
-		return m.UploadNewVersionFunc(ctx, fileID, ownerID, sessionID, name, size, mime, versionNum)
+		return m.UploadNewVersionFunc(ctx, fileID, ownerID, sessionID, name, size, mime, versionNum, checksum)
 	}
-	return nil, nil, "", nil
+	return nil, nil, "", nil, nil
 }
 
 func (m *MockFileVersionService) RestoreVersion(ctx context.Context, fileID, versionID uuid.UUID) error {
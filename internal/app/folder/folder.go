@@ -0,0 +1,257 @@
+package folder_service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/app"
+	event_service "github.com/yourusername/cloud-file-storage/internal/app/event"
+	file_service "github.com/yourusername/cloud-file-storage/internal/app/file"
+	"github.com/yourusername/cloud-file-storage/internal/domain/folder"
+)
+
+type FolderService struct {
+	folderQueryRepo   folder.QueryRepository
+	folderCommandRepo folder.CommandRepository
+	fileService       *file_service.FileService
+	eventService      *event_service.EventService
+	uow               app.UnitOfWork
+}
+
+func NewFolderService(
+	folderQueryRepo folder.QueryRepository,
+	folderCommandRepo folder.CommandRepository,
+	fileService *file_service.FileService,
+	eventService *event_service.EventService,
+	uow app.UnitOfWork,
+) *FolderService {
+	return &FolderService{
+		folderQueryRepo:   folderQueryRepo,
+		folderCommandRepo: folderCommandRepo,
+		fileService:       fileService,
+		eventService:      eventService,
+		uow:               uow,
+	}
+}
+
+func (s *FolderService) GetByID(ctx context.Context, folderID uuid.UUID) (*folder.Folder, error) {
+	f, err := s.folderQueryRepo.GetByID(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+	if f == nil {
+		return nil, folder.ErrNotFound
+	}
+	return f, nil
+}
+
+// GetChildren возвращает вложенные папки; parentID == nil означает корень пользователя
+func (s *FolderService) GetChildren(ctx context.Context, ownerID uuid.UUID, parentID *uuid.UUID) ([]*folder.Folder, error) {
+	return s.folderQueryRepo.GetChildren(ctx, ownerID, parentID)
+}
+
+// GetPath возвращает путь от корня до папки включительно
+func (s *FolderService) GetPath(ctx context.Context, folderID uuid.UUID) ([]*folder.Folder, error) {
+	path, err := s.folderQueryRepo.GetAncestors(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+	if len(path) == 0 {
+		return nil, folder.ErrNotFound
+	}
+	return path, nil
+}
+
+func (s *FolderService) Create(ctx context.Context, ownerID uuid.UUID, parentID *uuid.UUID, name string) (*folder.Folder, error) {
+	var f *folder.Folder
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		nameVO, err := folder.NewFolderName(name)
+		if err != nil {
+			return err
+		}
+
+		if err := s.checkParent(ctx, ownerID, parentID); err != nil {
+			return err
+		}
+		if err := s.checkNameFree(ctx, ownerID, parentID, nameVO, uuid.Nil); err != nil {
+			return err
+		}
+
+		f = folder.NewFolder(ownerID, parentID, nameVO)
+		return s.folderCommandRepo.Save(ctx, f)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if s.eventService != nil {
+		eventName, payload := folder.NewFolderCreatedEvent(f)
+		_, _ = s.eventService.Create(ctx, eventName, payload)
+	}
+
+	return f, nil
+}
+
+func (s *FolderService) Rename(ctx context.Context, folderID uuid.UUID, newName string) error {
+	var f *folder.Folder
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		f, err = s.GetByID(ctx, folderID)
+		if err != nil {
+			return err
+		}
+
+		nameVO, err := folder.NewFolderName(newName)
+		if err != nil {
+			return err
+		}
+		if err := s.checkNameFree(ctx, f.OwnerID, f.ParentID, nameVO, f.ID); err != nil {
+			return err
+		}
+
+		f.Rename(nameVO)
+		return s.folderCommandRepo.Save(ctx, f)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if s.eventService != nil {
+		eventName, payload := folder.NewFolderRenamedEvent(f)
+		_, _ = s.eventService.Create(ctx, eventName, payload)
+	}
+
+	return nil
+}
+
+// Move переносит папку вместе со всем содержимым; parentID == nil переносит в корень
+func (s *FolderService) Move(ctx context.Context, folderID uuid.UUID, parentID *uuid.UUID) error {
+	var f *folder.Folder
+	var oldParentID *uuid.UUID
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		f, err = s.GetByID(ctx, folderID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.checkParent(ctx, f.OwnerID, parentID); err != nil {
+			return err
+		}
+
+		// Новый родитель не может быть самой папкой или её потомком
+		if parentID != nil {
+			ancestors, err := s.folderQueryRepo.GetAncestors(ctx, *parentID)
+			if err != nil {
+				return err
+			}
+			for _, a := range ancestors {
+				if a.ID == f.ID {
+					return folder.ErrInvalidMove
+				}
+			}
+		}
+
+		if err := s.checkNameFree(ctx, f.OwnerID, parentID, f.Name, f.ID); err != nil {
+			return err
+		}
+
+		oldParentID = f.ParentID
+		f.MoveTo(parentID)
+		return s.folderCommandRepo.Save(ctx, f)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if s.eventService != nil {
+		eventName, payload := folder.NewFolderMovedEvent(f, oldParentID)
+		_, _ = s.eventService.Create(ctx, eventName, payload)
+	}
+
+	return nil
+}
+
+// Delete удаляет папку и все вложенные папки в одной транзакции, файлы из них попадают в корзину
+func (s *FolderService) Delete(ctx context.Context, folderID uuid.UUID) error {
+	var deleted []*folder.Folder
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		f, err := s.GetByID(ctx, folderID)
+		if err != nil {
+			return err
+		}
+
+		descendants, err := s.folderQueryRepo.GetDescendants(ctx, folderID)
+		if err != nil {
+			return err
+		}
+
+		// Потомки отсортированы от самых глубоких, сама папка удаляется последней
+		for _, d := range append(descendants, f) {
+			files, err := s.fileService.GetByFolderID(ctx, d.ID)
+			if err != nil {
+				return err
+			}
+			for _, fl := range files {
+				if err := s.fileService.Delete(ctx, fl.ID); err != nil {
+					return err
+				}
+			}
+
+			if err := s.folderCommandRepo.Delete(ctx, d.ID); err != nil {
+				return err
+			}
+			deleted = append(deleted, d)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if s.eventService != nil {
+		for _, d := range deleted {
+			eventName, payload := folder.NewFolderDeletedEvent(d)
+			_, _ = s.eventService.Create(ctx, eventName, payload)
+		}
+	}
+
+	return nil
+}
+
+func (s *FolderService) checkParent(ctx context.Context, ownerID uuid.UUID, parentID *uuid.UUID) error {
+	if parentID == nil {
+		return nil
+	}
+
+	parent, err := s.folderQueryRepo.GetByID(ctx, *parentID)
+	if err != nil {
+		return err
+	}
+	if parent == nil || parent.OwnerID != ownerID {
+		return folder.ErrNotFound
+	}
+	return nil
+}
+
+func (s *FolderService) checkNameFree(ctx context.Context, ownerID uuid.UUID, parentID *uuid.UUID, name folder.FolderName, exceptID uuid.UUID) error {
+	siblings, err := s.folderQueryRepo.GetChildren(ctx, ownerID, parentID)
+	if err != nil {
+		return err
+	}
+	for _, sib := range siblings {
+		if sib.ID != exceptID && sib.Name.String() == name.String() {
+			return folder.ErrNameConflict
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package key_rotation_service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/app"
+	"github.com/yourusername/cloud-file-storage/internal/domain/encryption"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
+)
+
+const defaultBatchSize = 100
+
+// Result итог прохода ротации
+type Result struct {
+	// Rewrapped версии, ключ которых записан заново
+	Rewrapped int
+	// Missing версии, объекта которых нет в хранилище
+	Missing int
+}
+
+// KeyRotationService переоборачивает ключи данных объектов текущим мастер-ключом,
+// после чего старый мастер-ключ можно убрать из провайдера
+type KeyRotationService struct {
+	versionQueryRepo   file_version.QueryRepository
+	versionCommandRepo file_version.CommandRepository
+	rewrapper          encryption.ObjectRewrapper
+	uow                app.UnitOfWork
+	batchSize          int
+}
+
+func NewKeyRotationService(
+	versionQueryRepo file_version.QueryRepository,
+	versionCommandRepo file_version.CommandRepository,
+	rewrapper encryption.ObjectRewrapper,
+	uow app.UnitOfWork,
+	batchSize int,
+) *KeyRotationService {
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	return &KeyRotationService{
+		versionQueryRepo:   versionQueryRepo,
+		versionCommandRepo: versionCommandRepo,
+		rewrapper:          rewrapper,
+		uow:                uow,
+		batchSize:          batchSize,
+	}
+}
+
+// RewrapAll обходит все версии с устаревшим ключом. Объект и его превью переоборачиваются до записи
+// в БД, поэтому прерванный проход безопасно запустить заново: уже переобёрнутые объекты пропускаются.
+func (s *KeyRotationService) RewrapAll(ctx context.Context) (Result, error) {
+	var result Result
+	currentKeyID := s.rewrapper.CurrentKeyID()
+	afterID := uuid.Nil
+
+	for {
+		versions, err := s.versionQueryRepo.GetWithStaleKey(ctx, currentKeyID, afterID, s.batchSize)
+		if err != nil {
+			return result, err
+		}
+		if len(versions) == 0 {
+			return result, nil
+		}
+
+		for _, v := range versions {
+			afterID = v.ID
+
+			rewrapped, err := s.rewrapVersion(ctx, v)
+			if err != nil {
+				return result, err
+			}
+			if rewrapped {
+				result.Rewrapped++
+			} else {
+				result.Missing++
+			}
+		}
+	}
+}
+
+// rewrapVersion false — объекта версии нет в хранилище
+func (s *KeyRotationService) rewrapVersion(ctx context.Context, v *file_version.FileVersion) (bool, error) {
+	keyID, err := s.rewrapper.Rewrap(ctx, v.S3Key.String())
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if v.PreviewS3Key != nil {
+		if _, err := s.rewrapper.Rewrap(ctx, v.PreviewS3Key.String()); err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+			return false, err
+		}
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		return s.versionCommandRepo.SetKeyID(ctx, v.S3Key, keyID)
+	})
+	return err == nil, err
+}
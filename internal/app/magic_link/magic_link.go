@@ -35,22 +35,22 @@ func NewMagicLinkService(
 	}
 }
 
+// Create создаёт ссылку с новым токеном. Токен возвращается только здесь, в БД — его хеш
 func (s *MagicLinkService) Create(
 	ctx context.Context,
 	userID uuid.UUID,
-	tokenHashRaw string,
 	deviceInfoRaw string,
 	purposeRaw string,
 	ip net.IP,
-) (*magic_link.MagicLink, error) {
+) (*magic_link.MagicLink, string, error) {
 	var createdLink *magic_link.MagicLink
 
-	err := s.uow.Do(ctx, func(ctx context.Context) error {
-		tokenHash, err := value_objects.NewTokenHash(tokenHashRaw)
-		if err != nil {
-			return err
-		}
+	token, tokenHash, err := value_objects.NewToken()
+	if err != nil {
+		return nil, "", err
+	}
 
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
 		deviceInfo, err := value_objects.NewDeviceInfo(deviceInfoRaw)
 		if err != nil {
 			return err
@@ -82,7 +82,7 @@ func (s *MagicLinkService) Create(
 	})
 
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	if s.eventService != nil {
@@ -90,7 +90,7 @@ func (s *MagicLinkService) Create(
 		_, _ = s.eventService.Create(ctx, eventName, payload)
 	}
 
-	return createdLink, nil
+	return createdLink, token, nil
 }
 
 func (s *MagicLinkService) MarkAsUsed(ctx context.Context, id uuid.UUID) error {
@@ -166,8 +166,9 @@ func (s *MagicLinkService) GetByUserID(ctx context.Context, userID uuid.UUID) ([
 	return s.queryRepo.GetByUserID(ctx, userID)
 }
 
-func (s *MagicLinkService) GetByTokenHash(ctx context.Context, tokenHashRaw string) (*magic_link.MagicLink, error) {
-	tokenHash, err := value_objects.NewTokenHash(tokenHashRaw)
+// GetByToken ищет ссылку по хешу токена из письма
+func (s *MagicLinkService) GetByToken(ctx context.Context, token string) (*magic_link.MagicLink, error) {
+	tokenHash, err := value_objects.HashToken(token)
 	if err != nil {
 		return nil, err
 	}
@@ -176,7 +177,7 @@ func (s *MagicLinkService) GetByTokenHash(ctx context.Context, tokenHashRaw stri
 	if err != nil {
 		return nil, err
 	}
-	if link == nil {
+	if link == nil || !tokenHash.Matches(link.TokenHash.String()) {
 		return nil, magic_link.ErrNotFound
 	}
 
@@ -0,0 +1,232 @@
+package multipart_upload_service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/app"
+	event_service "github.com/yourusername/cloud-file-storage/internal/app/event"
+	file_version_service "github.com/yourusername/cloud-file-storage/internal/app/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/multipart_upload"
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
+)
+
+const (
+	partURLTTL       = 15 * time.Minute
+	defaultUploadTTL = 24 * time.Hour
+)
+
+type PartURL struct {
+	PartNumber int32
+	URL        string
+}
+
+type MultipartUploadService struct {
+	queryRepo      multipart_upload.QueryRepository
+	commandRepo    multipart_upload.CommandRepository
+	versionService *file_version_service.FileVersionService
+	storage        storage.Storage
+	eventService   *event_service.EventService
+	uow            app.UnitOfWork
+	uploadTTL      time.Duration
+}
+
+func NewMultipartUploadService(
+	queryRepo multipart_upload.QueryRepository,
+	commandRepo multipart_upload.CommandRepository,
+	versionService *file_version_service.FileVersionService,
+	storage storage.Storage,
+	eventService *event_service.EventService,
+	uow app.UnitOfWork,
+	uploadTTL time.Duration,
+) *MultipartUploadService {
+	if uploadTTL <= 0 {
+		uploadTTL = defaultUploadTTL
+	}
+	return &MultipartUploadService{
+		queryRepo:      queryRepo,
+		commandRepo:    commandRepo,
+		versionService: versionService,
+		storage:        storage,
+		eventService:   eventService,
+		uow:            uow,
+		uploadTTL:      uploadTTL,
+	}
+}
+
+func (s *MultipartUploadService) GetByID(ctx context.Context, id uuid.UUID) (*multipart_upload.MultipartUpload, error) {
+	m, err := s.queryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, multipart_upload.ErrNotFound
+	}
+	return m, nil
+}
+
+// Initiate начинает multipart загрузку версии, ожидающей данных.
+// Повторный вызов возвращает уже активную загрузку, чтобы клиент мог продолжить её.
+func (s *MultipartUploadService) Initiate(ctx context.Context, versionID, ownerID uuid.UUID) (*multipart_upload.MultipartUpload, error) {
+	version, err := s.versionService.GetVersionByID(ctx, versionID)
+	if err != nil {
+		return nil, err
+	}
+	if !version.Status.Equal(file_version.FileStatusProcessing) {
+		return nil, multipart_upload.ErrVersionNotPending
+	}
+
+	existing, err := s.queryRepo.GetActiveByVersionID(ctx, versionID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && !existing.IsExpired() {
+		return existing, nil
+	}
+
+	uploadID, err := s.storage.CreateMultipartUpload(ctx, version.S3Key.String(), version.Mime.String())
+	if err != nil {
+		return nil, err
+	}
+
+	m := multipart_upload.NewMultipartUpload(version.FileId, version.ID, ownerID, version.S3Key, uploadID, version.Size.Uint64(), s.uploadTTL)
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		return s.commandRepo.Save(ctx, m)
+	})
+	if err != nil {
+		_ = s.storage.AbortMultipartUpload(ctx, m.S3Key.String(), uploadID)
+		return nil, err
+	}
+
+	if s.eventService != nil {
+		eventName, payload := multipart_upload.NewMultipartUploadInitiatedEvent(m)
+		_, _ = s.eventService.Create(ctx, eventName, payload)
+	}
+
+	return m, nil
+}
+
+// PresignParts выдаёт presigned URL для указанных частей
+func (s *MultipartUploadService) PresignParts(ctx context.Context, id uuid.UUID, partNumbers []int32) ([]PartURL, error) {
+	m, err := s.getActive(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]PartURL, 0, len(partNumbers))
+	for _, n := range partNumbers {
+		if err := m.ValidatePartNumber(n); err != nil {
+			return nil, err
+		}
+		url, err := s.storage.GenerateUploadPartURL(ctx, m.S3Key.String(), m.UploadID, n, partURLTTL)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, PartURL{PartNumber: n, URL: url})
+	}
+
+	return urls, nil
+}
+
+// Complete собирает объект из загруженных частей и помечает версию загруженной
+func (s *MultipartUploadService) Complete(ctx context.Context, id uuid.UUID, parts []storage.CompletedPart) (*multipart_upload.MultipartUpload, error) {
+	m, err := s.getActive(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted, err := validateParts(m, parts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.CompleteMultipartUpload(ctx, m.S3Key.String(), m.UploadID, sorted); err != nil {
+		return nil, err
+	}
+
+	m.MarkCompleted()
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		return s.commandRepo.Save(ctx, m)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.versionService.CompleteUpload(ctx, m.VersionID); err != nil {
+		return nil, err
+	}
+
+	if s.eventService != nil {
+		eventName, payload := multipart_upload.NewMultipartUploadCompletedEvent(m)
+		_, _ = s.eventService.Create(ctx, eventName, payload)
+	}
+
+	return m, nil
+}
+
+// Abort прерывает загрузку и освобождает уже загруженные части в S3
+func (s *MultipartUploadService) Abort(ctx context.Context, id uuid.UUID) error {
+	m, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !m.IsActive() {
+		return multipart_upload.ErrNotActive
+	}
+
+	_ = s.storage.AbortMultipartUpload(ctx, m.S3Key.String(), m.UploadID)
+
+	m.MarkAborted()
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		return s.commandRepo.Save(ctx, m)
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.eventService != nil {
+		eventName, payload := multipart_upload.NewMultipartUploadAbortedEvent(m)
+		_, _ = s.eventService.Create(ctx, eventName, payload)
+	}
+
+	return nil
+}
+
+// GetExpired возвращает пачку брошенных загрузок для воркера
+func (s *MultipartUploadService) GetExpired(ctx context.Context, limit int) ([]*multipart_upload.MultipartUpload, error) {
+	return s.queryRepo.GetExpiredActive(ctx, time.Now(), limit)
+}
+
+func (s *MultipartUploadService) getActive(ctx context.Context, id uuid.UUID) (*multipart_upload.MultipartUpload, error) {
+	m, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !m.IsActive() || m.IsExpired() {
+		return nil, multipart_upload.ErrNotActive
+	}
+	return m, nil
+}
+
+// validateParts проверяет, что переданы все части ровно по одному разу, и сортирует их для S3
+func validateParts(m *multipart_upload.MultipartUpload, parts []storage.CompletedPart) ([]storage.CompletedPart, error) {
+	if len(parts) != m.PartCount {
+		return nil, multipart_upload.ErrInvalidParts
+	}
+
+	sorted := make([]storage.CompletedPart, len(parts))
+	copy(sorted, parts)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].PartNumber < sorted[j].PartNumber })
+
+	for i, p := range sorted {
+		if p.PartNumber != int32(i+1) || p.ETag == "" {
+			return nil, multipart_upload.ErrInvalidParts
+		}
+	}
+
+	return sorted, nil
+}
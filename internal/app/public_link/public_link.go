@@ -2,55 +2,130 @@ package public_link_service
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/yourusername/cloud-file-storage/internal/app"
 	event_service "github.com/yourusername/cloud-file-storage/internal/app/event"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
 	"github.com/yourusername/cloud-file-storage/internal/domain/public_link"
 	"github.com/yourusername/cloud-file-storage/internal/domain/value_objects"
+	"golang.org/x/crypto/bcrypt"
 )
 
-const defaultPublicLinkTTL = 15 * time.Minute
+const (
+	defaultPublicLinkTTL = 15 * time.Minute
+	defaultUnlockTTL     = 10 * time.Minute
+	defaultMaxAttempts   = 5
+	defaultLockout       = 15 * time.Minute
+)
 
 type PublicLinkService struct {
 	queryRepo    public_link.PublicLinkQueryRepository
 	commandRepo  public_link.PublicLinkCommandRepository
+	versionRepo  file_version.QueryRepository
 	eventService *event_service.EventService
 	uow          app.UnitOfWork
+	unlockKey    []byte
+	unlockTTL    time.Duration
+	maxAttempts  int
+	lockout      time.Duration
 }
 
+// NewPublicLinkService unlockKey подписывает токены разблокировки ссылок с паролем и должен быть
+// одинаковым на всех узлах, иначе токен одного узла не примут другие.
+// maxAttempts неверных паролей с одного IP за lockout блокируют ввод пароля с него на lockout.
 func NewPublicLinkService(
 	queryRepo public_link.PublicLinkQueryRepository,
 	commandRepo public_link.PublicLinkCommandRepository,
+	versionRepo file_version.QueryRepository,
 	eventService *event_service.EventService,
 	uow app.UnitOfWork,
-) *PublicLinkService {
+	unlockKey string,
+	unlockTTL time.Duration,
+	maxAttempts int,
+	lockout time.Duration,
+) (*PublicLinkService, error) {
+	if unlockKey == "" {
+		return nil, errors.New("public link unlock signing key is not set")
+	}
+	if unlockTTL <= 0 {
+		unlockTTL = defaultUnlockTTL
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	if lockout <= 0 {
+		lockout = defaultLockout
+	}
 	return &PublicLinkService{
 		queryRepo:    queryRepo,
 		commandRepo:  commandRepo,
+		versionRepo:  versionRepo,
 		eventService: eventService,
 		uow:          uow,
-	}
+		unlockKey:    []byte(unlockKey),
+		unlockTTL:    unlockTTL,
+		maxAttempts:  maxAttempts,
+		lockout:      lockout,
+	}, nil
 }
 
-// Create создаёт новую публичную ссылку в транзакции
+// Create создаёт новую публичную ссылку в транзакции; пустой password — ссылка без пароля,
+// nil maxDownloads — без ограничения числа скачиваний. Пустой versionModeRaw — pinned, если
+// передан versionID, иначе current; закрепить можно только готовую версию этого файла.
+// Токен ссылки возвращается только здесь, в БД хранится его хеш.
 func (s *PublicLinkService) Create(
 	ctx context.Context,
 	fileID, createdByUserID uuid.UUID,
-	tokenHashRaw string,
 	expiresAtRaw time.Time,
-) (*public_link.PublicLink, error) {
+	password string,
+	maxDownloads *int,
+	versionModeRaw string,
+	versionID *uuid.UUID,
+) (*public_link.PublicLink, string, error) {
 	var link *public_link.PublicLink
 
-	err := s.uow.Do(ctx, func(ctx context.Context) error {
-		now := time.Now()
+	if err := public_link.ValidateMaxDownloads(maxDownloads); err != nil {
+		return nil, "", err
+	}
 
-		tokenHash, err := value_objects.NewTokenHash(tokenHashRaw)
+	if versionModeRaw == "" && versionID != nil {
+		versionModeRaw = public_link.VersionModePinned.String()
+	}
+	versionMode, err := public_link.NewVersionMode(versionModeRaw)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var passwordHash *string
+	if password != "" {
+		if err := public_link.ValidatePassword(password); err != nil {
+			return nil, "", err
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 		if err != nil {
-			return err
+			return nil, "", err
 		}
+		h := string(hash)
+		passwordHash = &h
+	}
+
+	token, tokenHash, err := value_objects.NewToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		now := time.Now()
 
 		expiresAt := expiresAtRaw
 		if expiresAt.IsZero() {
@@ -67,12 +142,29 @@ func (s *PublicLinkService) Create(
 			tokenHash.String(),
 			expiresAtVO.Time(),
 		)
+		link.PasswordHash = passwordHash
+		link.MaxDownloads = maxDownloads
+		if err := link.PinVersion(versionMode, versionID); err != nil {
+			return err
+		}
+		if versionID != nil {
+			version, err := s.versionRepo.GetByID(ctx, *versionID)
+			if err != nil {
+				return err
+			}
+			if version == nil || version.FileId != fileID {
+				return file_version.ErrVersionNotFound
+			}
+			if !version.Status.Equal(file_version.FileStatusReady) {
+				return file_version.ErrVersionNotStored
+			}
+		}
 
 		return s.commandRepo.Save(ctx, link)
 	})
 
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	if s.eventService != nil {
@@ -80,7 +172,7 @@ func (s *PublicLinkService) Create(
 		_, _ = s.eventService.Create(ctx, eventName, payload)
 	}
 
-	return link, nil
+	return link, token, nil
 }
 
 // Delete удаляет публичную ссылку в транзакции
@@ -121,23 +213,28 @@ func (s *PublicLinkService) GetByID(ctx context.Context, id uuid.UUID) (*public_
 	if link == nil {
 		return nil, public_link.ErrNotFound
 	}
-	if link.IsExpired() {
-		return nil, errors.New("link has expired")
+	if !link.IsActive() {
+		return nil, public_link.ErrExpired
 	}
 	return link, nil
 }
 
-// GetByID получает ссылку по ID с проверкой срока действия
+// GetByToken получает ссылку по хешу токена с проверкой срока действия
 func (s *PublicLinkService) GetByToken(ctx context.Context, token string) (*public_link.PublicLink, error) {
-	link, err := s.queryRepo.GetByToken(ctx, token)
+	tokenHash, err := value_objects.HashToken(token)
+	if err != nil {
+		return nil, public_link.ErrNotFound
+	}
+
+	link, err := s.queryRepo.GetByTokenHash(ctx, tokenHash.String())
 	if err != nil {
 		return nil, err
 	}
-	if link == nil {
+	if link == nil || !tokenHash.Matches(link.TokenHash) {
 		return nil, public_link.ErrNotFound
 	}
-	if link.IsExpired() {
-		return nil, errors.New("link has expired")
+	if !link.IsActive() {
+		return nil, public_link.ErrExpired
 	}
 	return link, nil
 }
@@ -156,10 +253,213 @@ func (s *PublicLinkService) GetByFileID(ctx context.Context, fileID uuid.UUID) (
 
 	active := make([]*public_link.PublicLink, 0, len(links))
 	for _, l := range links {
-		if l != nil && !l.IsExpired() {
+		if l != nil && l.IsActive() {
 			active = append(active, l)
 		}
 	}
 
 	return active, nil
 }
+
+// ResolveVersion версия файла f, которую отдаёт ссылка в своём режиме
+func (s *PublicLinkService) ResolveVersion(ctx context.Context, link *public_link.PublicLink, f *file.File) (*file_version.FileVersion, error) {
+	if link.VersionMode.Equal(public_link.VersionModePinned) {
+		// version_id обнуляется внешним ключом при удалении версии
+		if link.VersionID == nil {
+			return nil, public_link.ErrPinnedVersionDeleted
+		}
+		version, err := s.versionRepo.GetByID(ctx, *link.VersionID)
+		if err != nil {
+			return nil, err
+		}
+		if version == nil || version.FileId != f.ID {
+			return nil, public_link.ErrPinnedVersionDeleted
+		}
+		return version, nil
+	}
+
+	versions, err := s.versionRepo.GetByFileID(ctx, f.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var resolved *file_version.FileVersion
+	for _, v := range versions {
+		if link.VersionMode.Equal(public_link.VersionModeLatest) {
+			if v.Status.Equal(file_version.FileStatusReady) && (resolved == nil || v.VersionNum.Int() > resolved.VersionNum.Int()) {
+				resolved = v
+			}
+			continue
+		}
+		if v.VersionNum.Equal(f.VersionNum) {
+			resolved = v
+			break
+		}
+	}
+	if resolved == nil {
+		return nil, file_version.ErrVersionNotFound
+	}
+	return resolved, nil
+}
+
+// RegisterDownload засчитывает скачивание версии versionID по ссылке и пишет его в журнал.
+// ErrExhausted, если лимит скачиваний выбран, в том числе параллельным запросом.
+func (s *PublicLinkService) RegisterDownload(ctx context.Context, link *public_link.PublicLink, versionID uuid.UUID, ip, userAgent string) error {
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		count, ok, err := s.commandRepo.IncrementDownloads(ctx, link.ID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return public_link.ErrExhausted
+		}
+		link.DownloadCount = count
+
+		return s.commandRepo.SaveAccess(ctx, public_link.NewAccess(link.ID, versionID, ip, userAgent))
+	})
+	if err != nil {
+		return err
+	}
+
+	if link.IsExhausted() && s.eventService != nil {
+		eventName, payload := public_link.NewPublicLinkExhaustedEvent(link)
+		_, _ = s.eventService.Create(ctx, eventName, payload)
+	}
+
+	return nil
+}
+
+// ExpireBatch удаляет или переносит в архив до limit ссылок, истёкших раньше before.
+// События PublicLinkExpired пишутся в той же транзакции, что и удаление.
+func (s *PublicLinkService) ExpireBatch(ctx context.Context, before time.Time, limit int, archive bool) (int, error) {
+	var ids []uuid.UUID
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if archive {
+			ids, err = s.commandRepo.ArchiveExpired(ctx, before, limit)
+		} else {
+			ids, err = s.commandRepo.DeleteExpired(ctx, before, limit)
+		}
+		if err != nil {
+			return err
+		}
+
+		if s.eventService == nil {
+			return nil
+		}
+		for _, id := range ids {
+			eventName, payload := public_link.NewPublicLinkExpiredEvent(id)
+			if _, err := s.eventService.Create(ctx, eventName, payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+// GetAccesses журнал скачиваний ссылки linkID файла fileID, в том числе истёкшей или исчерпанной
+func (s *PublicLinkService) GetAccesses(ctx context.Context, fileID, linkID uuid.UUID) ([]*public_link.Access, error) {
+	link, err := s.queryRepo.GetByID(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+	if link == nil || link.FileID != fileID {
+		return nil, public_link.ErrNotFound
+	}
+	return s.queryRepo.GetAccesses(ctx, linkID)
+}
+
+// Unlock проверяет пароль ссылки и выдаёт токен разблокировки, действующий UnlockTTL.
+// Неверные пароли считаются по паре ссылка и IP; после maxAttempts ввод с IP блокируется.
+func (s *PublicLinkService) Unlock(ctx context.Context, link *public_link.PublicLink, password, ip string) (string, error) {
+	if !link.HasPassword() {
+		return "", nil
+	}
+
+	now := time.Now()
+	attempts, err := s.queryRepo.GetUnlockAttempts(ctx, link.ID, ip)
+	if err != nil {
+		return "", err
+	}
+	if attempts != nil && attempts.IsLocked(now) {
+		return "", public_link.ErrLocked
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(*link.PasswordHash), []byte(password)) != nil {
+		var lockedUntil *time.Time
+		err := s.uow.Do(ctx, func(ctx context.Context) error {
+			failures, err := s.commandRepo.RegisterUnlockFailure(ctx, link.ID, ip, now, now.Add(-s.lockout))
+			if err != nil {
+				return err
+			}
+			if failures < s.maxAttempts {
+				return nil
+			}
+			until := now.Add(s.lockout)
+			lockedUntil = &until
+			return s.commandRepo.LockUnlock(ctx, link.ID, ip, until)
+		})
+		if err != nil {
+			return "", err
+		}
+
+		if lockedUntil != nil && s.eventService != nil {
+			eventName, payload := public_link.NewPublicLinkLockedEvent(link.ID, ip, *lockedUntil)
+			_, _ = s.eventService.Create(ctx, eventName, payload)
+		}
+		return "", public_link.ErrWrongPassword
+	}
+
+	if attempts != nil {
+		err := s.uow.Do(ctx, func(ctx context.Context) error {
+			return s.commandRepo.ResetUnlockAttempts(ctx, link.ID, ip)
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	expires := now.Add(s.unlockTTL).Unix()
+	return strconv.FormatInt(expires, 10) + "." + s.signUnlock(link, expires), nil
+}
+
+// CheckUnlock nil для ссылок без пароля и для действующего токена разблокировки
+func (s *PublicLinkService) CheckUnlock(link *public_link.PublicLink, token string) error {
+	if !link.HasPassword() {
+		return nil
+	}
+	if token == "" {
+		return public_link.ErrPasswordRequired
+	}
+
+	expiresRaw, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return public_link.ErrInvalidUnlockToken
+	}
+	expires, err := strconv.ParseInt(expiresRaw, 10, 64)
+	if err != nil {
+		return public_link.ErrInvalidUnlockToken
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.signUnlock(link, expires))) {
+		return public_link.ErrInvalidUnlockToken
+	}
+	if time.Now().Unix() > expires {
+		return public_link.ErrInvalidUnlockToken
+	}
+	return nil
+}
+
+func (s *PublicLinkService) UnlockTTL() time.Duration {
+	return s.unlockTTL
+}
+
+// signUnlock в подпись входит хеш пароля, поэтому смена пароля отзывает выданные токены
+func (s *PublicLinkService) signUnlock(link *public_link.PublicLink, expires int64) string {
+	mac := hmac.New(sha256.New, s.unlockKey)
+	fmt.Fprintf(mac, "%s\n%d\n%s", link.ID, expires, *link.PasswordHash)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,83 @@
+package quota_service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/domain/quota"
+)
+
+type QuotaService struct {
+	queryRepo    quota.QueryRepository
+	commandRepo  quota.CommandRepository
+	defaultLimit int64
+}
+
+func NewQuotaService(
+	queryRepo quota.QueryRepository,
+	commandRepo quota.CommandRepository,
+	defaultLimit int64,
+) *QuotaService {
+	return &QuotaService{
+		queryRepo:    queryRepo,
+		commandRepo:  commandRepo,
+		defaultLimit: defaultLimit,
+	}
+}
+
+// Reserve занимает size байт квоты пользователя. Вызывается внутри транзакции
+// до сохранения новой версии, чтобы параллельные загрузки не превысили лимит.
+func (s *QuotaService) Reserve(ctx context.Context, userID uuid.UUID, size int64) error {
+	q, err := s.commandRepo.Lock(ctx, quota.NewQuota(userID, s.defaultLimit))
+	if err != nil {
+		return err
+	}
+	if err := q.Reserve(size); err != nil {
+		return err
+	}
+	return s.commandRepo.Save(ctx, q)
+}
+
+// Adjust исправляет занятый объём, когда фактический размер загрузки отличается от заявленного
+func (s *QuotaService) Adjust(ctx context.Context, userID uuid.UUID, delta int64) error {
+	if delta == 0 {
+		return nil
+	}
+	q, err := s.commandRepo.Lock(ctx, quota.NewQuota(userID, s.defaultLimit))
+	if err != nil {
+		return err
+	}
+	q.Adjust(delta)
+	return s.commandRepo.Save(ctx, q)
+}
+
+// Release освобождает size байт. Вызывается внутри транзакции до удаления версий.
+func (s *QuotaService) Release(ctx context.Context, userID uuid.UUID, size int64) error {
+	return s.Adjust(ctx, userID, -size)
+}
+
+// GetUsage возвращает квоту пользователя с разбивкой по статусам версий и MIME-типам
+func (s *QuotaService) GetUsage(ctx context.Context, userID uuid.UUID) (*quota.Usage, error) {
+	byStatus, err := s.queryRepo.GetUsageByStatus(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	byMime, err := s.queryRepo.GetUsageByMime(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := s.queryRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if q == nil {
+		// Квота создаётся при первой загрузке, до этого занятый объём считается по версиям
+		q = quota.NewQuota(userID, s.defaultLimit)
+		for _, e := range byStatus {
+			q.UsedBytes += e.Bytes
+		}
+	}
+
+	return &quota.Usage{Quota: q, ByStatus: byStatus, ByMime: byMime}, nil
+}
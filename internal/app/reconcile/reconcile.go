@@ -0,0 +1,156 @@
+package reconcile_service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
+)
+
+const (
+	defaultBatchSize = 1000
+	// objectsPrefix под ним лежат объекты версий и их превью
+	objectsPrefix = "files/"
+)
+
+// Orphan объект хранилища, на который не ссылается ни одна строка БД
+type Orphan struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// MissingObject версия, объекта которой нет в хранилище
+type MissingObject struct {
+	VersionID uuid.UUID
+	FileID    uuid.UUID
+	Key       string
+}
+
+// Report итог прохода сверки
+type Report struct {
+	// Scanned объекты хранилища, просмотренные под префиксом files/
+	Scanned int
+	Orphans []Orphan
+	// Deleted удалённые сироты; ноль, если удаление не запрашивали
+	Deleted int
+	Missing []MissingObject
+}
+
+// ReconcileService сверяет бакет с БД. Объекты расходятся с БД, когда удаление из хранилища
+// не удалось или не выполнялось, а строки версий — когда объект удалили в обход приложения.
+type ReconcileService struct {
+	versionQueryRepo file_version.QueryRepository
+	storage          storage.Storage
+	grace            time.Duration
+	batchSize        int
+}
+
+// NewReconcileService grace защищает объекты, которые только что записаны и ещё не привязаны
+// к строке БД: превью сохраняется в хранилище раньше, чем его ключ попадает в версию
+func NewReconcileService(
+	versionQueryRepo file_version.QueryRepository,
+	storage storage.Storage,
+	grace time.Duration,
+	batchSize int,
+) *ReconcileService {
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	return &ReconcileService{
+		versionQueryRepo: versionQueryRepo,
+		storage:          storage,
+		grace:            grace,
+		batchSize:        batchSize,
+	}
+}
+
+// Reconcile находит сирот старше grace и версии без объекта. deleteOrphans удаляет найденных сирот,
+// иначе проход только отчитывается.
+func (s *ReconcileService) Reconcile(ctx context.Context, deleteOrphans bool) (Report, error) {
+	var report Report
+	if err := s.findOrphans(ctx, deleteOrphans, &report); err != nil {
+		return report, err
+	}
+	if err := s.findMissing(ctx, &report); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+func (s *ReconcileService) findOrphans(ctx context.Context, deleteOrphans bool, report *Report) error {
+	cutoff := time.Now().Add(-s.grace)
+	startAfter := ""
+
+	for {
+		objects, err := s.storage.List(ctx, objectsPrefix, startAfter, s.batchSize)
+		if err != nil {
+			return err
+		}
+		if len(objects) == 0 {
+			return nil
+		}
+		startAfter = objects[len(objects)-1].Key
+		report.Scanned += len(objects)
+
+		candidates := make([]storage.ObjectSummary, 0, len(objects))
+		keys := make([]string, 0, len(objects))
+		for _, obj := range objects {
+			if obj.LastModified.After(cutoff) {
+				continue
+			}
+			candidates = append(candidates, obj)
+			keys = append(keys, obj.Key)
+		}
+		if len(keys) == 0 {
+			continue
+		}
+
+		referenced, err := s.versionQueryRepo.GetReferencedKeys(ctx, keys)
+		if err != nil {
+			return err
+		}
+
+		for _, obj := range candidates {
+			if referenced[obj.Key] {
+				continue
+			}
+			report.Orphans = append(report.Orphans, Orphan{Key: obj.Key, Size: obj.Size, LastModified: obj.LastModified})
+			if !deleteOrphans {
+				continue
+			}
+			if err := s.storage.Delete(ctx, obj.Key); err != nil {
+				return err
+			}
+			report.Deleted++
+		}
+	}
+}
+
+func (s *ReconcileService) findMissing(ctx context.Context, report *Report) error {
+	afterID := uuid.Nil
+
+	for {
+		versions, err := s.versionQueryRepo.GetStored(ctx, afterID, s.batchSize)
+		if err != nil {
+			return err
+		}
+		if len(versions) == 0 {
+			return nil
+		}
+
+		for _, v := range versions {
+			afterID = v.ID
+
+			exists, err := s.storage.FileExists(ctx, v.S3Key.String())
+			if err != nil {
+				return err
+			}
+			if !exists {
+				report.Missing = append(report.Missing, MissingObject{VersionID: v.ID, FileID: v.FileId, Key: v.S3Key.String()})
+			}
+		}
+	}
+}
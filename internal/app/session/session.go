@@ -33,28 +33,41 @@ func NewSessionService(
 	}
 }
 
+// Tokens токены сессии для клиента; в БД остаются только их хеши
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+}
+
+// newTokens новая пара токенов и их хеши
+func newTokens() (*Tokens, value_objects.TokenHash, value_objects.TokenHash, error) {
+	accessToken, tokenHash, err := value_objects.NewToken()
+	if err != nil {
+		return nil, value_objects.TokenHash{}, value_objects.TokenHash{}, err
+	}
+	refreshToken, refreshTokenHash, err := value_objects.NewToken()
+	if err != nil {
+		return nil, value_objects.TokenHash{}, value_objects.TokenHash{}, err
+	}
+	return &Tokens{AccessToken: accessToken, RefreshToken: refreshToken}, tokenHash, refreshTokenHash, nil
+}
+
+// Create создаёт сессию с новой парой токенов; кроме как здесь и в RefreshAccessToken, их не узнать
 func (s *SessionService) Create(
 	ctx context.Context,
 	userID uuid.UUID,
-	tokenHashRaw string,
-	refreshTokenHashRaw string,
 	deviceInfoRaw string,
 	ip net.IP,
 	expiresAt time.Time,
-) (*session.Session, error) {
+) (*session.Session, *Tokens, error) {
 	var createdSession *session.Session
 
-	err := s.uow.Do(ctx, func(ctx context.Context) error {
-		tokenHash, err := value_objects.NewTokenHash(tokenHashRaw)
-		if err != nil {
-			return err
-		}
-
-		refreshTokenHash, err := value_objects.NewTokenHash(refreshTokenHashRaw)
-		if err != nil {
-			return err
-		}
+	tokens, tokenHash, refreshTokenHash, err := newTokens()
+	if err != nil {
+		return nil, nil, err
+	}
 
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
 		deviceInfo, err := value_objects.NewDeviceInfo(deviceInfoRaw)
 		if err != nil {
 			return err
@@ -81,7 +94,7 @@ func (s *SessionService) Create(
 	})
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if s.eventService != nil {
@@ -89,7 +102,7 @@ func (s *SessionService) Create(
 		_, _ = s.eventService.Create(ctx, eventName, payload)
 	}
 
-	return createdSession, nil
+	return createdSession, tokens, nil
 }
 
 func (s *SessionService) Delete(ctx context.Context, sessionID uuid.UUID) error {
@@ -177,7 +190,7 @@ func (s *SessionService) CleanupExpired(ctx context.Context) error {
 }
 
 func (s *SessionService) GetByAccessToken(ctx context.Context, accessToken string) (*session.Session, error) {
-	tokenHash, err := value_objects.NewTokenHash(accessToken)
+	tokenHash, err := value_objects.HashToken(accessToken)
 	if err != nil {
 		return nil, err
 	}
@@ -186,7 +199,7 @@ func (s *SessionService) GetByAccessToken(ctx context.Context, accessToken strin
 	if err != nil {
 		return nil, err
 	}
-	if sess == nil {
+	if sess == nil || !tokenHash.Matches(sess.TokenHash.String()) {
 		return nil, session.ErrNotFound
 	}
 	return sess, nil
@@ -240,23 +253,40 @@ func (s *SessionService) RevokeAllForUser(ctx context.Context, userID uuid.UUID)
 	return nil
 }
 
-func (s *SessionService) RefreshAccessToken(ctx context.Context, refreshTokenRaw string) (*session.Session, error) {
-	refreshTokenHash, err := value_objects.NewTokenHash(refreshTokenRaw)
+// RefreshAccessToken выдаёт новую пару токенов по refresh токену; прежняя пара перестаёт действовать
+func (s *SessionService) RefreshAccessToken(ctx context.Context, refreshTokenRaw string) (*session.Session, *Tokens, error) {
+	refreshTokenHash, err := value_objects.HashToken(refreshTokenRaw)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	sess, err := s.queryRepo.GetByRefreshToken(ctx, &refreshTokenHash)
+	tokens, newTokenHash, newRefreshTokenHash, err := newTokens()
 	if err != nil {
-		return nil, err
-	}
-	if sess == nil {
-		return nil, session.ErrNotFound
+		return nil, nil, err
 	}
 
-	if sess.IsRevoked || sess.IsExpired() {
-		return nil, session.ErrInvalidSession
+	var sess *session.Session
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		sess, err = s.queryRepo.GetByRefreshToken(ctx, &refreshTokenHash)
+		if err != nil {
+			return err
+		}
+		if sess == nil || !refreshTokenHash.Matches(sess.RefreshTokenHash.String()) {
+			return session.ErrNotFound
+		}
+
+		if sess.IsRevoked || sess.IsExpired() {
+			return session.ErrInvalidSession
+		}
+
+		sess.RotateTokens(newTokenHash, newRefreshTokenHash)
+		return s.commandRepo.Save(ctx, sess)
+	})
+
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return sess, nil
+	return sess, tokens, nil
 }
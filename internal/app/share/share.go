@@ -0,0 +1,165 @@
+package share_service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/app"
+	event_service "github.com/yourusername/cloud-file-storage/internal/app/event"
+	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file"
+	"github.com/yourusername/cloud-file-storage/internal/domain/share"
+	"github.com/yourusername/cloud-file-storage/internal/domain/user"
+)
+
+// SharedFile файл из списка «доступные мне» вместе с выданным доступом
+type SharedFile struct {
+	File  *file.File
+	Share *share.Share
+}
+
+// ShareService выдаёт доступ к файлам другим пользователям и проверяет права на файл.
+// Владелец файла всегда имеет роль owner, остальные — роль из выданного доступа.
+type ShareService struct {
+	queryRepo     share.QueryRepository
+	commandRepo   share.CommandRepository
+	fileQueryRepo file.QueryRepository
+	userQueryRepo user.QueryRepository
+	eventService  *event_service.EventService
+	uow           app.UnitOfWork
+}
+
+func NewShareService(
+	queryRepo share.QueryRepository,
+	commandRepo share.CommandRepository,
+	fileQueryRepo file.QueryRepository,
+	userQueryRepo user.QueryRepository,
+	eventService *event_service.EventService,
+	uow app.UnitOfWork,
+) *ShareService {
+	return &ShareService{
+		queryRepo:     queryRepo,
+		commandRepo:   commandRepo,
+		fileQueryRepo: fileQueryRepo,
+		userQueryRepo: userQueryRepo,
+		eventService:  eventService,
+		uow:           uow,
+	}
+}
+
+// Authorize возвращает domainerrors.ErrAccessDenied, если роль пользователя ниже required
+func (s *ShareService) Authorize(ctx context.Context, f *file.File, userID uuid.UUID, required share.Role) error {
+	if f.OwnerID == userID {
+		return nil
+	}
+
+	sh, err := s.queryRepo.GetByFileAndUser(ctx, f.ID, userID)
+	if err != nil {
+		return err
+	}
+	if sh == nil || !sh.Role.Allows(required) {
+		return domainerrors.ErrAccessDenied
+	}
+	return nil
+}
+
+// Grant выдаёт доступ пользователю с указанным email; повторная выдача меняет роль
+func (s *ShareService) Grant(ctx context.Context, f *file.File, grantedBy uuid.UUID, email string, rawRole string) (*share.Share, error) {
+	role, err := share.NewRole(rawRole)
+	if err != nil {
+		return nil, err
+	}
+
+	grantee, err := s.userQueryRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if grantee == nil {
+		return nil, user.ErrNotFound
+	}
+	if grantee.ID == f.OwnerID {
+		return nil, share.ErrCannotShareWithOwner
+	}
+
+	var sh *share.Share
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		existing, err := s.queryRepo.GetByFileAndUser(ctx, f.ID, grantee.ID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			existing.ChangeRole(role, grantedBy)
+			sh = existing
+		} else {
+			sh = share.NewShare(f.ID, grantee.ID, role, grantedBy)
+		}
+		return s.commandRepo.Save(ctx, sh)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.eventService != nil {
+		eventName, payload := share.NewFileSharedEvent(sh)
+		_, _ = s.eventService.Create(ctx, eventName, payload)
+	}
+
+	return sh, nil
+}
+
+// Revoke отзывает доступ: получатель может отказаться от своего доступа сам,
+// чужие доступы отзывает только пользователь с ролью owner
+func (s *ShareService) Revoke(ctx context.Context, f *file.File, shareID, userID uuid.UUID) error {
+	sh, err := s.queryRepo.GetByID(ctx, shareID)
+	if err != nil {
+		return err
+	}
+	if sh == nil || sh.FileID != f.ID {
+		return share.ErrNotFound
+	}
+	if sh.UserID != userID {
+		if err := s.Authorize(ctx, f, userID, share.RoleOwner); err != nil {
+			return err
+		}
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		return s.commandRepo.Delete(ctx, sh.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.eventService != nil {
+		eventName, payload := share.NewFileShareRevokedEvent(sh)
+		_, _ = s.eventService.Create(ctx, eventName, payload)
+	}
+
+	return nil
+}
+
+func (s *ShareService) ListByFile(ctx context.Context, fileID uuid.UUID) ([]*share.Share, error) {
+	return s.queryRepo.GetByFileID(ctx, fileID)
+}
+
+// SharedWith файлы других пользователей, доступные userID
+func (s *ShareService) SharedWith(ctx context.Context, userID uuid.UUID) ([]SharedFile, error) {
+	shares, err := s.queryRepo.GetSharedWithUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]SharedFile, 0, len(shares))
+	for _, sh := range shares {
+		f, err := s.fileQueryRepo.GetByID(ctx, sh.FileID)
+		if err != nil {
+			return nil, err
+		}
+		// файл могли удалить между запросами
+		if f == nil || f.IsTrashed() {
+			continue
+		}
+		out = append(out, SharedFile{File: f, Share: sh})
+	}
+	return out, nil
+}
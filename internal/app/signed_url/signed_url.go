@@ -0,0 +1,345 @@
+package signed_url_service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/app"
+	event_service "github.com/yourusername/cloud-file-storage/internal/app/event"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/signed_url"
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
+)
+
+const (
+	defaultKeyID    = "default"
+	defaultTTL      = time.Hour
+	defaultMaxTTL   = 7 * 24 * time.Hour
+	defaultStateTTL = 30 * time.Second
+	// maxStorageURLTTL URL хранилища, на который ведёт подписанный URL, живёт не дольше
+	maxStorageURLTTL = time.Hour
+	// maxCachedStates после этого числа файлов из кэша выметаются протухшие записи
+	maxCachedStates = 10000
+)
+
+type cachedState struct {
+	state    *signed_url.FileState
+	loadedAt time.Time
+}
+
+// SignedURLService подписанные URL на версии файлов. Подпись и срок проверяются без БД;
+// отзыв — по эпохе файла, которую узел помнит stateTTL.
+type SignedURLService struct {
+	queryRepo    signed_url.QueryRepository
+	commandRepo  signed_url.CommandRepository
+	versionRepo  file_version.QueryRepository
+	storage      storage.Storage
+	eventService *event_service.EventService
+	uow          app.UnitOfWork
+	keyID        string
+	keys         map[string][]byte
+	maxTTL       time.Duration
+	stateTTL     time.Duration
+
+	mu     sync.Mutex
+	states map[uuid.UUID]cachedState
+}
+
+// NewSignedURLService signingKey подписывает новые URL под keyID, verifyKeys — прежние ключи по их
+// KeyID: ими URL только проверяются, пока не истекут. Отзыв на другом узле вступает в силу
+// не позже чем через stateTTL.
+func NewSignedURLService(
+	queryRepo signed_url.QueryRepository,
+	commandRepo signed_url.CommandRepository,
+	versionRepo file_version.QueryRepository,
+	storage storage.Storage,
+	eventService *event_service.EventService,
+	uow app.UnitOfWork,
+	keyID string,
+	signingKey string,
+	verifyKeys map[string]string,
+	maxTTL time.Duration,
+	stateTTL time.Duration,
+) (*SignedURLService, error) {
+	if signingKey == "" {
+		return nil, errors.New("signed URL signing key is not set")
+	}
+	if keyID == "" {
+		keyID = defaultKeyID
+	}
+	keys := make(map[string][]byte, len(verifyKeys)+1)
+	for kid, key := range verifyKeys {
+		if key != "" {
+			keys[kid] = []byte(key)
+		}
+	}
+	keys[keyID] = []byte(signingKey)
+
+	if maxTTL <= 0 {
+		maxTTL = defaultMaxTTL
+	}
+	if stateTTL <= 0 {
+		stateTTL = defaultStateTTL
+	}
+	return &SignedURLService{
+		queryRepo:    queryRepo,
+		commandRepo:  commandRepo,
+		versionRepo:  versionRepo,
+		storage:      storage,
+		eventService: eventService,
+		uow:          uow,
+		keyID:        keyID,
+		keys:         keys,
+		maxTTL:       maxTTL,
+		stateTTL:     stateTTL,
+		states:       make(map[uuid.UUID]cachedState),
+	}, nil
+}
+
+// Mint подписывает URL на версию versionID файла f; nil — текущая версия. Права на файл проверяет
+// вызывающий. Нулевой ttl — час, больше maxTTL нельзя. Возвращает query string подписанного URL.
+func (s *SignedURLService) Mint(
+	ctx context.Context,
+	f *file.File,
+	versionID *uuid.UUID,
+	permissionsRaw []string,
+	ttl time.Duration,
+) (*signed_url.Claims, string, error) {
+	permissions, err := signed_url.NewPermissions(permissionsRaw)
+	if err != nil {
+		return nil, "", err
+	}
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+	if ttl < 0 || ttl > s.maxTTL {
+		return nil, "", signed_url.ErrInvalidTTL
+	}
+
+	version, err := s.mintedVersion(ctx, f, versionID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	claims, err := signed_url.NewClaims(s.keyID, f.ID, version.ID, permissions, ttl)
+	if err != nil {
+		return nil, "", err
+	}
+	return claims, s.Sign(claims), nil
+}
+
+func (s *SignedURLService) mintedVersion(ctx context.Context, f *file.File, versionID *uuid.UUID) (*file_version.FileVersion, error) {
+	var version *file_version.FileVersion
+	if versionID != nil {
+		v, err := s.versionRepo.GetByID(ctx, *versionID)
+		if err != nil {
+			return nil, err
+		}
+		version = v
+	} else {
+		versions, err := s.versionRepo.GetByFileID(ctx, f.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range versions {
+			if v.VersionNum.Equal(f.VersionNum) {
+				version = v
+				break
+			}
+		}
+	}
+
+	if version == nil || version.FileId != f.ID {
+		return nil, file_version.ErrVersionNotFound
+	}
+	if !version.Status.Equal(file_version.FileStatusReady) {
+		return nil, file_version.ErrVersionNotStored
+	}
+	return version, nil
+}
+
+// Sign query string URL с подписью текущим ключом. Годится и для claims, собранных вне сервиса
+// доверенным процессом с тем же ключом: БД для этого не нужна.
+func (s *SignedURLService) Sign(claims *signed_url.Claims) string {
+	claims.KeyID = s.keyID
+	values := url.Values{}
+	values.Set("v", claims.VersionID.String())
+	values.Set("perm", claims.Permissions.String())
+	values.Set("iat", strconv.FormatInt(claims.IssuedAt.Unix(), 10))
+	values.Set("exp", strconv.FormatInt(claims.ExpiresAt.Unix(), 10))
+	values.Set("kid", claims.KeyID)
+	values.Set("sig", sign(s.keys[s.keyID], claims))
+	return values.Encode()
+}
+
+// Verify проверяет подпись, срок и разрешение permission URL файла fileID без обращения к БД
+func (s *SignedURLService) Verify(fileID uuid.UUID, query url.Values, permission signed_url.Permission) (*signed_url.Claims, error) {
+	key, ok := s.keys[query.Get("kid")]
+	if !ok {
+		return nil, signed_url.ErrInvalidSignature
+	}
+
+	versionID, err := uuid.Parse(query.Get("v"))
+	if err != nil {
+		return nil, signed_url.ErrInvalidSignature
+	}
+	permissions, err := signed_url.ParsePermissions(query.Get("perm"))
+	if err != nil {
+		return nil, signed_url.ErrInvalidSignature
+	}
+	issuedAt, err := strconv.ParseInt(query.Get("iat"), 10, 64)
+	if err != nil {
+		return nil, signed_url.ErrInvalidSignature
+	}
+	expiresAt, err := strconv.ParseInt(query.Get("exp"), 10, 64)
+	if err != nil {
+		return nil, signed_url.ErrInvalidSignature
+	}
+
+	claims := &signed_url.Claims{
+		KeyID:       query.Get("kid"),
+		FileID:      fileID,
+		VersionID:   versionID,
+		Permissions: permissions,
+		IssuedAt:    time.Unix(issuedAt, 0),
+		ExpiresAt:   time.Unix(expiresAt, 0),
+	}
+	// perm принимается только в том виде, в каком его пишет Sign
+	if permissions.String() != query.Get("perm") {
+		return nil, signed_url.ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(query.Get("sig")), []byte(sign(key, claims))) {
+		return nil, signed_url.ErrInvalidSignature
+	}
+
+	if claims.IsExpired(time.Now()) {
+		return nil, signed_url.ErrExpired
+	}
+	if !permissions.Has(permission) {
+		return nil, signed_url.ErrPermissionDenied
+	}
+	return claims, nil
+}
+
+// Resolve проверяет URL и возвращает URL хранилища с содержимым или превью версии
+func (s *SignedURLService) Resolve(ctx context.Context, fileID uuid.UUID, query url.Values, permission signed_url.Permission) (string, error) {
+	claims, err := s.Verify(fileID, query, permission)
+	if err != nil {
+		return "", err
+	}
+
+	state, err := s.fileState(ctx, fileID)
+	if err != nil {
+		return "", err
+	}
+	if state == nil || state.Trashed {
+		return "", file.ErrNotFound
+	}
+	if claims.RevokedBy(state.Epoch) {
+		return "", signed_url.ErrRevoked
+	}
+
+	version, err := s.versionRepo.GetByID(ctx, claims.VersionID)
+	if err != nil {
+		return "", err
+	}
+	if version == nil || version.FileId != fileID {
+		return "", file_version.ErrVersionNotFound
+	}
+	if !version.Status.Equal(file_version.FileStatusReady) {
+		return "", file_version.ErrVersionNotStored
+	}
+
+	key := version.S3Key
+	if permission.Equal(signed_url.PermissionPreview) {
+		if version.PreviewS3Key == nil {
+			return "", file_version.ErrVersionNotFound
+		}
+		key = *version.PreviewS3Key
+	}
+
+	ttl := time.Until(claims.ExpiresAt)
+	if ttl > maxStorageURLTTL {
+		ttl = maxStorageURLTTL
+	}
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+	return s.storage.GenerateDownloadURL(ctx, key.String(), ttl)
+}
+
+// Revoke отзывает все выданные URL файла; новые URL действуют со следующей секунды
+func (s *SignedURLService) Revoke(ctx context.Context, fileID uuid.UUID) (int64, error) {
+	epoch := time.Now().Unix()
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		ok, err := s.commandRepo.SetEpoch(ctx, fileID, epoch)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return file.ErrNotFound
+		}
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	// На этом узле отзыв действует сразу, остальные перечитают эпоху через stateTTL
+	s.mu.Lock()
+	delete(s.states, fileID)
+	s.mu.Unlock()
+
+	if s.eventService != nil {
+		eventName, payload := signed_url.NewSignedURLsRevokedEvent(fileID, epoch)
+		_, _ = s.eventService.Create(ctx, eventName, payload)
+	}
+
+	return epoch, nil
+}
+
+func (s *SignedURLService) fileState(ctx context.Context, fileID uuid.UUID) (*signed_url.FileState, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	cached, ok := s.states[fileID]
+	s.mu.Unlock()
+	if ok && now.Sub(cached.loadedAt) < s.stateTTL {
+		return cached.state, nil
+	}
+
+	state, err := s.queryRepo.GetFileState(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if len(s.states) >= maxCachedStates {
+		for id, c := range s.states {
+			if now.Sub(c.loadedAt) >= s.stateTTL {
+				delete(s.states, id)
+			}
+		}
+	}
+	s.states[fileID] = cachedState{state: state, loadedAt: now}
+	s.mu.Unlock()
+
+	return state, nil
+}
+
+func sign(key []byte, claims *signed_url.Claims) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(claims.Payload()))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,295 @@
+package tus_upload_service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"hash"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/app"
+	event_service "github.com/yourusername/cloud-file-storage/internal/app/event"
+	file_service "github.com/yourusername/cloud-file-storage/internal/app/file"
+	file_version_service "github.com/yourusername/cloud-file-storage/internal/app/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
+	"github.com/yourusername/cloud-file-storage/internal/domain/tus_upload"
+)
+
+type TusUploadService struct {
+	queryRepo      tus_upload.QueryRepository
+	commandRepo    tus_upload.CommandRepository
+	versionService *file_version_service.FileVersionService
+	fileService    *file_service.FileService
+	storage        storage.Storage
+	eventService   *event_service.EventService
+	uow            app.UnitOfWork
+}
+
+func NewTusUploadService(
+	queryRepo tus_upload.QueryRepository,
+	commandRepo tus_upload.CommandRepository,
+	versionService *file_version_service.FileVersionService,
+	fileService *file_service.FileService,
+	storage storage.Storage,
+	eventService *event_service.EventService,
+	uow app.UnitOfWork,
+) *TusUploadService {
+	return &TusUploadService{
+		queryRepo:      queryRepo,
+		commandRepo:    commandRepo,
+		versionService: versionService,
+		fileService:    fileService,
+		storage:        storage,
+		eventService:   eventService,
+		uow:            uow,
+	}
+}
+
+func (s *TusUploadService) GetByID(ctx context.Context, id uuid.UUID) (*tus_upload.TusUpload, error) {
+	u, err := s.queryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, tus_upload.ErrNotFound
+	}
+	return u, nil
+}
+
+// Create создаёт файл в статусе processing и multipart загрузку в S3, в которую будут складываться PATCH запросы
+func (s *TusUploadService) Create(ctx context.Context, ownerID, sessionID uuid.UUID, name string, length int64, mime string, folderID *uuid.UUID) (*tus_upload.TusUpload, error) {
+	f, version, _, _, err := s.versionService.UploadNewFile(ctx, ownerID, sessionID, name, uint64(length), mime, folderID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	multipartID, err := s.storage.CreateMultipartUpload(ctx, version.S3Key.String(), version.Mime.String())
+	if err != nil {
+		_ = s.fileService.Purge(ctx, f.ID)
+		return nil, err
+	}
+
+	u := tus_upload.NewTusUpload(f.ID, version.ID, ownerID, version.S3Key, multipartID, length)
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		return s.commandRepo.Create(ctx, u)
+	})
+	if err != nil {
+		_ = s.storage.AbortMultipartUpload(ctx, u.S3Key.String(), multipartID)
+		_ = s.fileService.Purge(ctx, f.ID)
+		return nil, err
+	}
+
+	if s.eventService != nil {
+		eventName, payload := tus_upload.NewTusUploadCreatedEvent(u)
+		_, _ = s.eventService.Create(ctx, eventName, payload)
+	}
+
+	// Пустой файл завершается сразу, PATCH для него не придёт
+	if length == 0 {
+		if err := s.complete(ctx, u); err != nil {
+			return nil, err
+		}
+	}
+
+	return u, nil
+}
+
+// WriteChunk принимает тело PATCH начиная с offset и частями по PartSize отправляет его в S3.
+// Если соединение оборвалось, принятые байты сохраняются, и клиент продолжает с нового смещения;
+// при переданной контрольной сумме частично принятое тело отбрасывается.
+func (s *TusUploadService) WriteChunk(ctx context.Context, u *tus_upload.TusUpload, offset int64, body io.Reader, checksum *tus_upload.Checksum) (*tus_upload.TusUpload, error) {
+	if offset != u.Offset {
+		return nil, tus_upload.ErrOffsetMismatch
+	}
+	if u.IsComplete() {
+		return u, s.complete(ctx, u)
+	}
+
+	reader := io.LimitReader(body, u.Remaining()+1)
+	var h hash.Hash
+	if checksum != nil {
+		h = checksum.NewHash()
+		reader = io.TeeReader(reader, h)
+	}
+
+	buf := make([]byte, 0, tus_upload.PartSize)
+	if u.PendingSize > 0 {
+		pending, err := s.storage.Get(ctx, u.PendingKey())
+		if err != nil {
+			return nil, err
+		}
+		buf = buf[:u.PendingSize]
+		_, err = io.ReadFull(pending.Body, buf)
+		pending.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	parts := make([]storage.CompletedPart, 0)
+	nextPart := u.NextPartNumber()
+	var written int64
+	var readErr error
+
+	for {
+		n, err := io.ReadFull(reader, buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+		written += int64(n)
+		if written > u.Remaining() {
+			return nil, tus_upload.ErrLengthExceeded
+		}
+
+		if len(buf) == cap(buf) {
+			etag, err := s.storage.UploadPart(ctx, u.S3Key.String(), u.MultipartID, nextPart, buf)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, storage.CompletedPart{PartNumber: nextPart, ETag: etag})
+			nextPart++
+			buf = buf[:0]
+		}
+
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			readErr = err
+			break
+		}
+	}
+
+	if readErr != nil && checksum != nil {
+		return nil, readErr
+	}
+	if readErr == nil && checksum != nil && !checksum.Matches(h) {
+		return nil, tus_upload.ErrChecksumMismatch
+	}
+	if written == 0 {
+		if readErr != nil {
+			return nil, readErr
+		}
+		return u, nil
+	}
+
+	// Клиент мог оборвать соединение вместе с контекстом запроса, а принятое нужно сохранить
+	ctx = context.WithoutCancel(ctx)
+
+	prevOffset := u.Offset
+	prevPendingKey := u.PendingKey()
+	hadPending := u.PendingSize > 0
+	final := readErr == nil && prevOffset+written == u.Length
+
+	pendingSize := int64(len(buf))
+	if final {
+		if len(buf) > 0 {
+			etag, err := s.storage.UploadPart(ctx, u.S3Key.String(), u.MultipartID, nextPart, buf)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, storage.CompletedPart{PartNumber: nextPart, ETag: etag})
+		}
+		pendingSize = 0
+	} else if pendingSize > 0 {
+		err := s.storage.Put(ctx, u.PendingKeyAt(prevOffset+written), bytes.NewReader(buf), storage.ObjectInfo{
+			ContentType:   "application/octet-stream",
+			ContentLength: pendingSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := u.Advance(written, parts, pendingSize); err != nil {
+		return nil, err
+	}
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		return s.commandRepo.Update(ctx, u, prevOffset)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if hadPending {
+		_ = s.storage.Delete(ctx, prevPendingKey)
+	}
+
+	if final {
+		if err := s.complete(ctx, u); err != nil {
+			return nil, err
+		}
+	}
+
+	if readErr != nil {
+		return u, readErr
+	}
+	return u, nil
+}
+
+// Terminate отменяет загрузку. Недогруженный файл удаляется целиком, завершённый остаётся.
+func (s *TusUploadService) Terminate(ctx context.Context, u *tus_upload.TusUpload) error {
+	if u.IsComplete() {
+		err := s.uow.Do(ctx, func(ctx context.Context) error {
+			return s.commandRepo.Delete(ctx, u.ID)
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		_ = s.storage.AbortMultipartUpload(ctx, u.S3Key.String(), u.MultipartID)
+		if u.PendingSize > 0 {
+			_ = s.storage.Delete(ctx, u.PendingKey())
+		}
+		// Строка tus_uploads удаляется каскадно вместе с файлом
+		if err := s.fileService.Purge(ctx, u.FileID); err != nil {
+			return err
+		}
+	}
+
+	if s.eventService != nil {
+		eventName, payload := tus_upload.NewTusUploadTerminatedEvent(u)
+		_, _ = s.eventService.Create(ctx, eventName, payload)
+	}
+
+	return nil
+}
+
+// complete собирает объект в S3 и переводит версию по тому же пути, что и FileChecker.
+// Повторный вызов безопасен: версия, уже вышедшая из processing, не трогается.
+func (s *TusUploadService) complete(ctx context.Context, u *tus_upload.TusUpload) error {
+	version, err := s.versionService.GetVersionByID(ctx, u.VersionID)
+	if err != nil {
+		return err
+	}
+	if !version.Status.Equal(file_version.FileStatusProcessing) {
+		return nil
+	}
+
+	// S3 не собирает объект без частей, поэтому пустой файл загружается одной пустой частью
+	parts := u.Parts
+	if len(parts) == 0 {
+		etag, err := s.storage.UploadPart(ctx, u.S3Key.String(), u.MultipartID, 1, []byte{})
+		if err != nil {
+			return err
+		}
+		parts = []storage.CompletedPart{{PartNumber: 1, ETag: etag}}
+	}
+
+	if err := s.storage.CompleteMultipartUpload(ctx, u.S3Key.String(), u.MultipartID, parts); err != nil {
+		return err
+	}
+
+	if err := s.versionService.CompleteUpload(ctx, u.VersionID); err != nil {
+		return err
+	}
+
+	if s.eventService != nil {
+		eventName, payload := tus_upload.NewTusUploadCompletedEvent(u)
+		_, _ = s.eventService.Create(ctx, eventName, payload)
+	}
+
+	return nil
+}
@@ -178,9 +178,14 @@ func (s *UserService) DeleteAccount(ctx context.Context, userID uuid.UUID) error
 		if err != nil {
 			return err
 		}
-		for _, f := range files {
+		trashed, err := s.fileService.GetTrash(ctx, userID)
+		if err != nil {
+			return err
+		}
+		// Аккаунт удаляется безвозвратно, поэтому файлы не попадают в корзину
+		for _, f := range append(files, trashed...) {
 			if f != nil {
-				if err := s.fileService.Delete(ctx, f.ID); err != nil {
+				if err := s.fileService.Purge(ctx, f.ID); err != nil {
 					return err
 				}
 			}
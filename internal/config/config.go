@@ -35,9 +35,95 @@ type Immutable struct {
 		SecretAccessKey string `koanf:"secretaccesskey"`
 		UsePathStyle    bool   `koanf:"use_path_style"`
 	} `koanf:"s3"`
+	Storage struct {
+		// s3 или local
+		Driver string `koanf:"driver"`
+		// BaseURL и SigningKey нужны подписанным URL API: у локального хранилища и при шифровании
+		BaseURL    string `koanf:"base_url"`
+		SigningKey string `koanf:"signing_key"`
+		Local      struct {
+			Root string `koanf:"root"`
+		} `koanf:"local"`
+		Encryption struct {
+			Enabled bool `koanf:"enabled"`
+			// KeysFile JSON с мастер-ключами для LocalKeyProvider
+			KeysFile string `koanf:"keys_file"`
+		} `koanf:"encryption"`
+	} `koanf:"storage"`
+	// JWT SigningKey подписывает подписанные URL под KeyID; VerifyKeys — прежние ключи по их id,
+	// ими выданные URL проверяются до истечения, пока ключ ротируется
 	JWT struct {
-		SigningKey string `koanf:"signingkey"`
+		SigningKey string            `koanf:"signingkey"`
+		KeyID      string            `koanf:"key_id"`
+		VerifyKeys map[string]string `koanf:"verify_keys"`
 	} `koanf:"jwt"`
+	// SignedURLs MaxTTL — предельный срок подписанного URL; StateTTL — сколько узел помнит эпоху
+	// отзыва файла, отзыв на других узлах вступает в силу не позже чем через StateTTL
+	SignedURLs struct {
+		MaxTTL   time.Duration `koanf:"max_ttl"`
+		StateTTL time.Duration `koanf:"state_ttl"`
+	} `koanf:"signed_urls"`
+	Trash struct {
+		Retention     time.Duration `koanf:"retention"`
+		PurgeInterval time.Duration `koanf:"purge_interval"`
+		PurgeBatch    int           `koanf:"purge_batch"`
+	} `koanf:"trash"`
+	Multipart struct {
+		UploadTTL     time.Duration `koanf:"upload_ttl"`
+		AbortInterval time.Duration `koanf:"abort_interval"`
+		AbortBatch    int           `koanf:"abort_batch"`
+	} `koanf:"multipart"`
+	Quota struct {
+		DefaultLimit int64 `koanf:"default_limit"`
+	} `koanf:"quota"`
+	BucketNotifications struct {
+		// WebhookToken пустой — webhook выключен, уведомления приходят только через Kafka
+		WebhookToken string `koanf:"webhook_token"`
+	} `koanf:"bucket_notifications"`
+	// FileChecker подбирает загрузки, уведомление о которых потерялось
+	FileChecker struct {
+		Interval time.Duration `koanf:"interval"`
+		Grace    time.Duration `koanf:"grace"`
+		Batch    int           `koanf:"batch"`
+	} `koanf:"file_checker"`
+	// UploadReaper снимает загрузки без данных старше AbandonAfter
+	UploadReaper struct {
+		AbandonAfter time.Duration `koanf:"abandon_after"`
+		Interval     time.Duration `koanf:"interval"`
+		Batch        int           `koanf:"batch"`
+	} `koanf:"upload_reaper"`
+	// Reconciler сверяет бакет с БД; Delete включает удаление сирот старше Grace
+	Reconciler struct {
+		Interval time.Duration `koanf:"interval"`
+		Grace    time.Duration `koanf:"grace"`
+		Batch    int           `koanf:"batch"`
+		Delete   bool          `koanf:"delete"`
+	} `koanf:"reconciler"`
+	// Archive архивы до SyncLimit байт отдаются потоком, большие собирает воркер и хранит Retention
+	Archive struct {
+		SyncLimit int64         `koanf:"sync_limit"`
+		LinkTTL   time.Duration `koanf:"link_ttl"`
+		Retention time.Duration `koanf:"retention"`
+		Interval  time.Duration `koanf:"interval"`
+		Batch     int           `koanf:"batch"`
+	} `koanf:"archive"`
+	// PublicLinks токен разблокировки ссылки с паролем живёт UnlockTTL;
+	// MaxAttempts неверных паролей с одного IP блокируют ввод на Lockout
+	PublicLinks struct {
+		UnlockSigningKey string        `koanf:"unlock_signing_key"`
+		UnlockTTL        time.Duration `koanf:"unlock_ttl"`
+		MaxAttempts      int           `koanf:"max_attempts"`
+		Lockout          time.Duration `koanf:"lockout"`
+	} `koanf:"public_links"`
+	// LinkExpirer процесс link-expirer удаляет ссылки, истёкшие больше Retention назад;
+	// Archive переносит их в public_links_archive, MetricsAddr — адрес /metrics процесса
+	LinkExpirer struct {
+		Interval    time.Duration `koanf:"interval"`
+		Retention   time.Duration `koanf:"retention"`
+		Batch       int           `koanf:"batch"`
+		Archive     bool          `koanf:"archive"`
+		MetricsAddr string        `koanf:"metrics_addr"`
+	} `koanf:"link_expirer"`
 }
 
 type Dynamic struct {
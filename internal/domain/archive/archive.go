@@ -0,0 +1,140 @@
+package archive
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	uuid "github.com/google/uuid"
+)
+
+// MaxEntries ограничивает число файлов и папок в одном архиве
+const MaxEntries = 10000
+
+// Entry элемент архива. У папок Name оканчивается на "/", а S3Key пуст.
+type Entry struct {
+	Name       string
+	S3Key      string
+	Mime       string
+	Size       int64
+	ModifiedAt time.Time
+}
+
+func (e Entry) IsDir() bool {
+	return strings.HasSuffix(e.Name, "/")
+}
+
+// Archive ZIP архив, который собирается воркером во временный объект.
+// Архивы до порога синхронной отдачи в БД не попадают и отдаются потоком сразу.
+type Archive struct {
+	ID      uuid.UUID
+	OwnerID uuid.UUID
+
+	Status  Status
+	Entries []Entry
+	// Size суммарный размер содержимого без учёта сжатия
+	Size  int64
+	S3Key string
+
+	FailureReason *string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// ExpiresAt после этого момента объект архива и запись о нём удаляются
+	ExpiresAt time.Time
+}
+
+func NewArchive(ownerID uuid.UUID, entries []Entry, retention time.Duration) *Archive {
+	now := time.Now()
+	id := uuid.New()
+
+	var size int64
+	for _, e := range entries {
+		size += e.Size
+	}
+
+	return &Archive{
+		ID:        id,
+		OwnerID:   ownerID,
+		Status:    StatusPending,
+		Entries:   entries,
+		Size:      size,
+		S3Key:     fmt.Sprintf("archives/%s/%s.zip", ownerID, id),
+		CreatedAt: now,
+		UpdatedAt: now,
+		ExpiresAt: now.Add(retention),
+	}
+}
+
+func (a *Archive) IsReady() bool {
+	return a.Status.Equal(StatusReady)
+}
+
+func (a *Archive) IsExpired() bool {
+	return time.Now().After(a.ExpiresAt)
+}
+
+func (a *Archive) MarkBuilding() {
+	a.Status = StatusBuilding
+	a.UpdatedAt = time.Now()
+}
+
+// MarkReady срок хранения отсчитывается от момента сборки
+func (a *Archive) MarkReady(retention time.Duration) {
+	now := time.Now()
+	a.Status = StatusReady
+	a.FailureReason = nil
+	a.UpdatedAt = now
+	a.ExpiresAt = now.Add(retention)
+}
+
+func (a *Archive) MarkFailed(reason string) {
+	a.Status = StatusFailed
+	a.FailureReason = &reason
+	a.UpdatedAt = time.Now()
+}
+
+// Names выдаёт уникальные пути внутри архива: совпадающие имена в одной папке
+// получают суффикс " (1)", " (2)" и т.д. перед расширением
+type Names struct {
+	used map[string]bool
+}
+
+func NewNames() *Names {
+	return &Names{used: make(map[string]bool)}
+}
+
+// File возвращает уникальный путь файла name внутри dir; dir пуст или оканчивается на "/"
+func (n *Names) File(dir, name string) string {
+	return n.unique(dir, sanitizeName(name))
+}
+
+// Dir возвращает уникальный путь папки name внутри dir с "/" на конце
+func (n *Names) Dir(dir, name string) string {
+	return n.unique(dir, sanitizeName(name)) + "/"
+}
+
+func (n *Names) unique(dir, name string) string {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	if base == "" {
+		base, ext = name, ""
+	}
+
+	candidate := dir + name
+	for i := 1; n.used[strings.ToLower(candidate)]; i++ {
+		candidate = fmt.Sprintf("%s%s (%d)%s", dir, base, i, ext)
+	}
+	n.used[strings.ToLower(candidate)] = true
+	return candidate
+}
+
+// sanitizeName не даёт имени файла выйти за пределы своей папки при распаковке
+func sanitizeName(name string) string {
+	name = strings.NewReplacer("/", "_", "\\", "_").Replace(name)
+	if name == "" || name == "." || name == ".." {
+		return "_"
+	}
+	return name
+}
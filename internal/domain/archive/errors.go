@@ -0,0 +1,11 @@
+package archive
+
+import "errors"
+
+var (
+	ErrNotFound       = errors.New("archive not found")
+	ErrEmpty          = errors.New("archive must contain at least one file or folder")
+	ErrTooManyEntries = errors.New("archive contains too many files")
+	ErrNotReady       = errors.New("archive is not built yet")
+	ErrExpired        = errors.New("archive has expired")
+)
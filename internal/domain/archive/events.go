@@ -0,0 +1,26 @@
+package archive
+
+func NewArchiveRequestedEvent(a *Archive) (string, map[string]interface{}) {
+	return "ArchiveRequested", map[string]interface{}{
+		"archive_id": a.ID,
+		"owner_id":   a.OwnerID,
+		"entries":    len(a.Entries),
+		"size":       a.Size,
+	}
+}
+
+func NewArchiveReadyEvent(a *Archive) (string, map[string]interface{}) {
+	return "ArchiveReady", map[string]interface{}{
+		"archive_id": a.ID,
+		"owner_id":   a.OwnerID,
+		"s3_key":     a.S3Key,
+	}
+}
+
+func NewArchiveFailedEvent(a *Archive) (string, map[string]interface{}) {
+	return "ArchiveFailed", map[string]interface{}{
+		"archive_id": a.ID,
+		"owner_id":   a.OwnerID,
+		"reason":     a.FailureReason,
+	}
+}
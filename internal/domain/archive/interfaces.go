@@ -0,0 +1,21 @@
+package archive
+
+import (
+	"context"
+	"time"
+
+	uuid "github.com/google/uuid"
+)
+
+type QueryRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*Archive, error)
+	// GetExpired возвращает собранные и упавшие архивы, срок хранения которых истёк
+	GetExpired(ctx context.Context, now time.Time, limit int) ([]*Archive, error)
+}
+
+type CommandRepository interface {
+	Save(ctx context.Context, archive *Archive) error
+	// ClaimPending переводит в building ожидающие архивы и зависшие в building дольше staleBefore
+	ClaimPending(ctx context.Context, staleBefore time.Time, limit int) ([]*Archive, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,31 @@
+package archive
+
+import "errors"
+
+type Status struct {
+	value string
+}
+
+var (
+	StatusPending  = Status{value: "pending"}
+	StatusBuilding = Status{value: "building"}
+	StatusReady    = Status{value: "ready"}
+	StatusFailed   = Status{value: "failed"}
+)
+
+func NewStatus(value string) (Status, error) {
+	switch value {
+	case "pending", "building", "ready", "failed":
+		return Status{value: value}, nil
+	default:
+		return Status{}, errors.New("invalid archive status")
+	}
+}
+
+func (s Status) String() string {
+	return s.value
+}
+
+func (s Status) Equal(other Status) bool {
+	return s.value == other.value
+}
@@ -0,0 +1,31 @@
+package blob
+
+import (
+	"time"
+
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+)
+
+// Blob содержимое, общее для всех версий с одинаковым SHA-256.
+// S3Key — ключ объекта, с которым эти байты были загружены впервые.
+type Blob struct {
+	Hash     file_version.ContentHash
+	S3Key    file_version.S3Key
+	Size     int64
+	RefCount int
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewBlob(hash file_version.ContentHash, key file_version.S3Key, size int64) *Blob {
+	now := time.Now()
+	return &Blob{
+		Hash:      hash,
+		S3Key:     key,
+		Size:      size,
+		RefCount:  1,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
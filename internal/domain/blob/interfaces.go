@@ -0,0 +1,14 @@
+package blob
+
+import (
+	"context"
+
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+)
+
+type CommandRepository interface {
+	// Acquire добавляет ссылку на blob с таким хешем или создаёт его из b и возвращает актуальную запись
+	Acquire(ctx context.Context, b *Blob) (*Blob, error)
+	// Release снимает ссылку; true означает, что ссылка была последней и объект можно удалять
+	Release(ctx context.Context, hash file_version.ContentHash) (bool, error)
+}
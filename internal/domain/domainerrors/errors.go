@@ -8,4 +8,5 @@ var (
 	ErrInvalidDeviceInfo   = errors.New("invalid device info")
 	ErrInvalidIP           = errors.New("invalid IP")
 	ErrTransactionNotFound = errors.New("transactio not found")
+	ErrAccessDenied        = errors.New("access denied")
 )
@@ -0,0 +1,132 @@
+package drop_link
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DropLink ссылка для загрузки: держатель токена без аккаунта загружает файлы в папку
+// владельца или новые версии одного его файла
+type DropLink struct {
+	ID      uuid.UUID
+	OwnerID uuid.UUID
+
+	TokenHash string
+
+	// FolderID папка для новых файлов; nil вместе с FileID == nil — корень владельца
+	FolderID *uuid.UUID
+	// FileID файл, в который загрузки попадают новыми версиями
+	FileID *uuid.UUID
+
+	// MaxFiles nil — число загрузок не ограничено
+	MaxFiles *int
+	// MaxTotalSize nil — суммарный размер не ограничен
+	MaxTotalSize *int64
+	// AllowedMimes пустой — принимаются любые типы; "image/*" разрешает весь тип
+	AllowedMimes []string
+
+	FileCount int
+	TotalSize int64
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ExpiredAt time.Time
+}
+
+func NewDropLink(ownerID uuid.UUID, tokenHash string, expiresAt time.Time) *DropLink {
+	now := time.Now()
+
+	return &DropLink{
+		ID:           uuid.New(),
+		OwnerID:      ownerID,
+		TokenHash:    tokenHash,
+		AllowedMimes: []string{},
+		ExpiredAt:    expiresAt,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// SetTarget folderID и fileID взаимоисключающие
+func (d *DropLink) SetTarget(folderID, fileID *uuid.UUID) error {
+	if folderID != nil && fileID != nil {
+		return ErrInvalidTarget
+	}
+	d.FolderID = folderID
+	d.FileID = fileID
+	return nil
+}
+
+func (d *DropLink) SetLimits(maxFiles *int, maxTotalSize *int64, allowedMimes []string) error {
+	if maxFiles != nil && *maxFiles < 1 {
+		return ErrInvalidLimits
+	}
+	if maxTotalSize != nil && *maxTotalSize < 1 {
+		return ErrInvalidLimits
+	}
+
+	mimes := make([]string, 0, len(allowedMimes))
+	for _, m := range allowedMimes {
+		m = normalizeMime(m)
+		if m == "" || !strings.Contains(m, "/") {
+			return ErrInvalidLimits
+		}
+		mimes = append(mimes, m)
+	}
+
+	d.MaxFiles = maxFiles
+	d.MaxTotalSize = maxTotalSize
+	d.AllowedMimes = mimes
+	return nil
+}
+
+func (d *DropLink) IsExpired() bool {
+	return time.Now().After(d.ExpiredAt)
+}
+
+// TargetsFile загрузки становятся версиями FileID, а не новыми файлами
+func (d *DropLink) TargetsFile() bool {
+	return d.FileID != nil
+}
+
+// AllowsMime параметры типа вроде "; charset=utf-8" при сравнении отбрасываются
+func (d *DropLink) AllowsMime(mime string) bool {
+	if len(d.AllowedMimes) == 0 {
+		return true
+	}
+	mime = normalizeMime(mime)
+	for _, allowed := range d.AllowedMimes {
+		if allowed == mime {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok && strings.HasPrefix(mime, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Accept проверяет загрузку по снимку счётчиков; окончательно лимиты
+// проверяет атомарный CommandRepository.ReserveUpload
+func (d *DropLink) Accept(size int64, mime string) error {
+	if d.IsExpired() {
+		return ErrExpired
+	}
+	if !d.AllowsMime(mime) {
+		return ErrMimeNotAllowed
+	}
+	if d.MaxFiles != nil && d.FileCount >= *d.MaxFiles {
+		return ErrLimitReached
+	}
+	if d.MaxTotalSize != nil && d.TotalSize+size > *d.MaxTotalSize {
+		return ErrLimitReached
+	}
+	return nil
+}
+
+func normalizeMime(mime string) string {
+	mime, _, _ = strings.Cut(mime, ";")
+	return strings.ToLower(strings.TrimSpace(mime))
+}
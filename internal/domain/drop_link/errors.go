@@ -0,0 +1,12 @@
+package drop_link
+
+import "errors"
+
+var (
+	ErrNotFound       = errors.New("drop link not found")
+	ErrExpired        = errors.New("drop link has expired")
+	ErrInvalidTarget  = errors.New("drop link targets either a folder or a file, not both")
+	ErrInvalidLimits  = errors.New("drop link limits must be positive and allowed mime types must look like type/subtype")
+	ErrMimeNotAllowed = errors.New("mime type is not allowed by the drop link")
+	ErrLimitReached   = errors.New("drop link file count or total size limit reached")
+)
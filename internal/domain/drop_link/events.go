@@ -0,0 +1,37 @@
+package drop_link
+
+import (
+	"github.com/google/uuid"
+)
+
+func NewDropLinkCreatedEvent(link *DropLink) (string, map[string]interface{}) {
+	return "DropLinkCreated", map[string]interface{}{
+		"link_id":        link.ID,
+		"owner_id":       link.OwnerID,
+		"folder_id":      link.FolderID,
+		"file_id":        link.FileID,
+		"expires_at":     link.ExpiredAt,
+		"max_files":      link.MaxFiles,
+		"max_total_size": link.MaxTotalSize,
+		"allowed_mimes":  link.AllowedMimes,
+	}
+}
+
+func NewDropLinkDeletedEvent(linkID uuid.UUID) (string, map[string]interface{}) {
+	return "DropLinkDeleted", map[string]interface{}{
+		"link_id": linkID,
+	}
+}
+
+// NewDropLinkFileReceivedEvent уведомление владельцу: файл по ссылке загружен полностью
+func NewDropLinkFileReceivedEvent(linkID, ownerID, fileID, versionID uuid.UUID, name, mime string, size uint64) (string, map[string]interface{}) {
+	return "DropLinkFileReceived", map[string]interface{}{
+		"link_id":    linkID,
+		"owner_id":   ownerID,
+		"file_id":    fileID,
+		"version_id": versionID,
+		"name":       name,
+		"mime":       mime,
+		"size":       size,
+	}
+}
@@ -0,0 +1,23 @@
+package drop_link
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type QueryRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*DropLink, error)
+	// GetByTokenHash в БД хранится только SHA-256 токена
+	GetByTokenHash(ctx context.Context, tokenHash string) (*DropLink, error)
+	// GetByOwnerID ссылки владельца, новые первыми
+	GetByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]*DropLink, error)
+}
+
+type CommandRepository interface {
+	Save(ctx context.Context, link *DropLink) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ReserveUpload атомарно засчитывает загрузку размера size; false — ссылка истекла
+	// или лимит числа файлов или суммарного размера выбран, счётчики не изменились
+	ReserveUpload(ctx context.Context, id uuid.UUID, size int64) (bool, error)
+}
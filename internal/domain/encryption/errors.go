@@ -0,0 +1,10 @@
+package encryption
+
+import "errors"
+
+var (
+	ErrKeyNotFound      = errors.New("master key not found")
+	ErrInvalidWrapped   = errors.New("wrapped data key is invalid")
+	ErrInvalidEnvelope  = errors.New("encrypted object is malformed")
+	ErrDecryptionFailed = errors.New("object decryption failed")
+)
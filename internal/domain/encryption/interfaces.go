@@ -0,0 +1,21 @@
+package encryption
+
+import "context"
+
+// KeyProvider хранит мастер-ключи и шифрует ими ключи данных (envelope encryption).
+// Мастер-ключи не покидают провайдер: наружу выдаются только обёрнутые ключи данных.
+type KeyProvider interface {
+	// CurrentKeyID идентификатор мастер-ключа, которым оборачиваются новые ключи данных
+	CurrentKeyID() string
+	// Wrap шифрует ключ данных текущим мастер-ключом и возвращает его идентификатор
+	Wrap(ctx context.Context, dataKey []byte) (keyID string, wrapped []byte, err error)
+	Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// ObjectRewrapper переоборачивает ключ данных объекта текущим мастер-ключом.
+// Содержимое объекта при этом не перешифровывается.
+type ObjectRewrapper interface {
+	// Rewrap возвращает идентификатор мастер-ключа, которым теперь обёрнут ключ данных объекта
+	Rewrap(ctx context.Context, key string) (string, error)
+	CurrentKeyID() string
+}
@@ -3,5 +3,9 @@ package file
 import "errors"
 
 var (
-	ErrNotFound = errors.New("file not found")
+	ErrNotFound   = errors.New("file not found")
+	ErrNotInTrash = errors.New("file is not in trash")
+	ErrInvalidTag = errors.New("tag must be 1 to 64 characters without control characters")
+	// ErrBatchAborted операция откатена, потому что другая операция атомарного пакета завершилась ошибкой
+	ErrBatchAborted = errors.New("operation rolled back because another operation in the batch failed")
 )
@@ -11,30 +11,78 @@ func NewFileRenamedEvent(f *File) (string, map[string]interface{}) {
 	}
 }
 
-func NewFileDeletedEvent(f *File) (string, map[string]interface{}) {
-	return "FileDeleted", map[string]interface{}{
-		"file_id":  f.ID,
-		"owner_id": f.OwnerID,
+func NewFileTaggedEvent(f *File, added, removed []Tag) (string, map[string]interface{}) {
+	return "FileTagged", map[string]interface{}{
+		"file_id": f.ID,
+		"added":   TagStrings(added),
+		"removed": TagStrings(removed),
+	}
+}
+
+func NewFileMovedEvent(f *File, oldFolderID *uuid.UUID) (string, map[string]interface{}) {
+	return "FileMoved", map[string]interface{}{
+		"file_id":       f.ID,
+		"old_folder_id": oldFolderID,
+		"folder_id":     f.FolderID,
+	}
+}
+
+func NewFileTrashedEvent(f *File) (string, map[string]interface{}) {
+	return "FileTrashed", map[string]interface{}{
+		"file_id":    f.ID,
+		"owner_id":   f.OwnerID,
+		"deleted_at": f.DeletedAt,
+	}
+}
+
+func NewFileRestoredEvent(f *File) (string, map[string]interface{}) {
+	return "FileRestored", map[string]interface{}{
+		"file_id":   f.ID,
+		"owner_id":  f.OwnerID,
+		"folder_id": f.FolderID,
+	}
+}
+
+func NewFilePurgedEvent(f *File, versionIDs []uuid.UUID) (string, map[string]interface{}) {
+	return "FilePurged", map[string]interface{}{
+		"file_id":     f.ID,
+		"owner_id":    f.OwnerID,
+		"version_ids": versionIDs,
 	}
 }
 
 func NewFileCreatedEvent(f *File) (string, map[string]interface{}) {
 	return "FileCreated", map[string]interface{}{
-		"file_id":  f.ID,
-		"owner_id": f.OwnerID,
-		"name":     f.Name.String(),
-		"size":     f.Size,
-		"mime":     f.Mime.String(),
+		"file_id":   f.ID,
+		"owner_id":  f.OwnerID,
+		"folder_id": f.FolderID,
+		"name":      f.Name.String(),
+		"size":      f.Size,
+		"mime":      f.Mime.String(),
+	}
+}
+
+func NewFileCopiedEvent(f *File, sourceFileID, sourceVersionID uuid.UUID) (string, map[string]interface{}) {
+	return "FileCopied", map[string]interface{}{
+		"file_id":           f.ID,
+		"owner_id":          f.OwnerID,
+		"folder_id":         f.FolderID,
+		"name":              f.Name.String(),
+		"size":              f.Size,
+		"source_file_id":    sourceFileID,
+		"source_version_id": sourceVersionID,
 	}
 }
 
-func NewFileVersionUploadedEvent(fileID, versionID uuid.UUID, ownerID uuid.UUID, name string, versionNum int) (string, map[string]interface{}) {
+// NewFileVersionUploadedEvent uploadedBy отличается от ownerID, когда версию загружает редактор
+func NewFileVersionUploadedEvent(fileID, versionID uuid.UUID, ownerID, uploadedBy uuid.UUID, name string, versionNum int) (string, map[string]interface{}) {
 	return "FileVersionUploaded", map[string]interface{}{
-		"file_id":    fileID,
-		"version_id": versionID,
-		"owner_id":   ownerID,
-		"name":       name,
-		"version":    versionNum,
+		"file_id":     fileID,
+		"version_id":  versionID,
+		"owner_id":    ownerID,
+		"uploaded_by": uploadedBy,
+		"name":        name,
+		"version":     versionNum,
 	}
 }
 
@@ -45,6 +93,18 @@ func NewFileVersionDeletedEvent(fileID, versionID uuid.UUID) (string, map[string
 	}
 }
 
+// NewFileVersionAbandonedEvent removed — вместе с версией удалён файл, у которого других версий не было
+func NewFileVersionAbandonedEvent(f *File, versionID uuid.UUID, versionNum int, removed bool) (string, map[string]interface{}) {
+	return "FileVersionAbandoned", map[string]interface{}{
+		"file_id":         f.ID,
+		"version_id":      versionID,
+		"owner_id":        f.OwnerID,
+		"version":         versionNum,
+		"file_removed":    removed,
+		"current_version": f.VersionNum.Int(),
+	}
+}
+
 func NewFileVersionRestoredEvent(fileID, versionID uuid.UUID) (string, map[string]interface{}) {
 	return "FileVersionRestored", map[string]interface{}{
 		"file_id":    fileID,
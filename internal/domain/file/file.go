@@ -11,10 +11,13 @@ type File struct {
 	ID                  uuid.UUID
 	OwnerID             uuid.UUID
 	UploadedBySessionId uuid.UUID
+	FolderID            *uuid.UUID
 
 	Name         FileName
 	Mime         file_version.MimeType
 	PreviewS3Key *file_version.S3Key
+	// Checksum текущей версии; по нему клиенты синхронизации пропускают неизменённые файлы
+	Checksum *file_version.Checksum
 
 	Status file_version.FileStatus
 
@@ -23,6 +26,8 @@ type File struct {
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	// DeletedAt != nil означает, что файл лежит в корзине
+	DeletedAt *time.Time
 }
 
 func NewFile(
@@ -54,9 +59,31 @@ func (f *File) Rename(newName FileName) {
 	f.UpdatedAt = time.Now()
 }
 
+// MoveToFolder переносит файл в папку; nil означает корень пользователя
+func (f *File) MoveToFolder(folderID *uuid.UUID) {
+	f.FolderID = folderID
+	f.UpdatedAt = time.Now()
+}
+
+func (f *File) MoveToTrash() {
+	now := time.Now()
+	f.DeletedAt = &now
+	f.UpdatedAt = now
+}
+
+func (f *File) RestoreFromTrash() {
+	f.DeletedAt = nil
+	f.UpdatedAt = time.Now()
+}
+
+func (f *File) IsTrashed() bool {
+	return f.DeletedAt != nil
+}
+
 func (f *File) UpdateFromVersion(fv *file_version.FileVersion) {
 	f.Mime = fv.Mime
 	f.PreviewS3Key = fv.PreviewS3Key
+	f.Checksum = fv.Checksum
 	f.Status = fv.Status
 	f.Size = fv.Size
 	f.VersionNum = fv.VersionNum
@@ -2,18 +2,37 @@ package file
 
 import (
 	"context"
+	"time"
 
 	uuid "github.com/google/uuid"
 )
 
+// QueryRepository списочные методы не возвращают файлы из корзины, кроме GetTrashed*
 type QueryRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*File, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*File, error)
 	GetAll(ctx context.Context) ([]*File, error)
 	SearchByName(ctx context.Context, userID uuid.UUID, query string, limit int, skip int) ([]*File, int64, error)
+	GetByFolderID(ctx context.Context, folderID uuid.UUID) ([]*File, error)
+	// SearchInFolder ищет файлы только внутри папки; folderID == nil означает корень
+	SearchInFolder(ctx context.Context, userID uuid.UUID, folderID *uuid.UUID, query string, limit int, skip int) ([]*File, int64, error)
+	GetTrashedByUserID(ctx context.Context, userID uuid.UUID) ([]*File, error)
+	// GetTrashedBefore возвращает файлы, попавшие в корзину раньше before
+	GetTrashedBefore(ctx context.Context, before time.Time, limit int) ([]*File, error)
 }
 
 type CommandRepository interface {
 	Save(ctx context.Context, file *File) error
 	Delete(ctx context.Context, id uuid.UUID) error
 }
+
+type TagQueryRepository interface {
+	// GetByFileID метки файла по алфавиту
+	GetByFileID(ctx context.Context, fileID uuid.UUID) ([]Tag, error)
+}
+
+// TagCommandRepository повторное добавление или снятие отсутствующей метки ничего не меняет
+type TagCommandRepository interface {
+	Add(ctx context.Context, fileID uuid.UUID, tags []Tag) error
+	Remove(ctx context.Context, fileID uuid.UUID, tags []Tag) error
+}
@@ -0,0 +1,57 @@
+package file
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const maxTagLength = 64
+
+// Tag метка файла; хранится в нижнем регистре, чтобы "Work" и "work" были одной меткой
+type Tag struct {
+	value string
+}
+
+func NewTag(raw string) (Tag, error) {
+	tag := strings.ToLower(strings.TrimSpace(raw))
+	if tag == "" || utf8.RuneCountInString(tag) > maxTagLength {
+		return Tag{}, ErrInvalidTag
+	}
+	for _, r := range tag {
+		if unicode.IsControl(r) {
+			return Tag{}, ErrInvalidTag
+		}
+	}
+	return Tag{value: tag}, nil
+}
+
+// NewTags разбирает список меток, повторы отбрасываются
+func NewTags(raw []string) ([]Tag, error) {
+	tags := make([]Tag, 0, len(raw))
+	seen := make(map[string]struct{}, len(raw))
+	for _, r := range raw {
+		tag, err := NewTag(r)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[tag.value]; ok {
+			continue
+		}
+		seen[tag.value] = struct{}{}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+func TagStrings(tags []Tag) []string {
+	out := make([]string, len(tags))
+	for i, t := range tags {
+		out[i] = t.value
+	}
+	return out
+}
+
+func (t Tag) String() string {
+	return t.value
+}
@@ -7,4 +7,7 @@ var (
 	ErrCannotDeleteCurr  = errors.New("cannot delete current version")
 	ErrVersionProcessing = errors.New("cannot delete file, some versions are processing")
 	ErrVersionFailed     = errors.New("cannot delete file, some versions are processing")
+	ErrVersionNotStored  = errors.New("version content is not uploaded")
+	ErrInvalidChecksum   = errors.New("checksum must be a base64 sha256 or crc32c digest")
+	ErrChecksumMismatch  = errors.New("uploaded content does not match declared checksum or size")
 )
@@ -10,12 +10,22 @@ type FileVersion struct {
 	ID                  uuid.UUID
 	FileId              uuid.UUID
 	UploadedBySessionId uuid.UUID
+	// DropLinkID ссылка для загрузки, через которую пришла версия; у таких версий нет сессии
+	DropLinkID *uuid.UUID
 
 	S3Key        S3Key
 	Mime         MimeType
 	PreviewS3Key *S3Key
+	// ContentHash == nil, пока содержимое не загружено и не посчитано
+	ContentHash *ContentHash
+	// KeyID мастер-ключ, которым обёрнут ключ данных объекта; nil — объект не зашифрован
+	KeyID *string
+	// Checksum заявлен клиентом при создании версии и сверяется при завершении загрузки
+	Checksum *Checksum
 
 	Status FileStatus
+	// FailureReason объясняет, почему версия в статусе failed
+	FailureReason *string
 
 	Size       FileSize
 	VersionNum FileVersionNum
@@ -63,6 +73,22 @@ func (fv *FileVersion) SetPreviewS3Key(key S3Key) {
 	fv.UpdatedAt = time.Now()
 }
 
+// AttachBlob связывает версию с общим blob; key может отличаться от ключа, по которому шла загрузка
+func (fv *FileVersion) AttachBlob(hash ContentHash, key S3Key) {
+	fv.ContentHash = &hash
+	fv.S3Key = key
+	fv.UpdatedAt = time.Now()
+}
+
+func (fv *FileVersion) SetKeyID(keyID string) {
+	if keyID == "" {
+		fv.KeyID = nil
+	} else {
+		fv.KeyID = &keyID
+	}
+	fv.UpdatedAt = time.Now()
+}
+
 func (fv *FileVersion) SetMime(mime MimeType) {
 	fv.Mime = mime
 	fv.UpdatedAt = time.Now()
@@ -97,3 +123,8 @@ func (f *FileVersion) MarkFailed() {
 	f.Status = FileStatusFailed
 	f.UpdatedAt = time.Now()
 }
+
+func (f *FileVersion) MarkFailedWithReason(reason string) {
+	f.FailureReason = &reason
+	f.MarkFailed()
+}
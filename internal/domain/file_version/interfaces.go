@@ -2,6 +2,7 @@ package file_version
 
 import (
 	"context"
+	"time"
 
 	uuid "github.com/google/uuid"
 )
@@ -11,9 +12,27 @@ type QueryRepository interface {
 	GetByFileID(ctx context.Context, fileID uuid.UUID) ([]*FileVersion, error)
 	GetAll(ctx context.Context) ([]*FileVersion, error)
 	GetAllByStatus(ctx context.Context, status FileStatus) ([]*FileVersion, error)
+	// GetProcessingByS3Key nil, если версии, ожидающей этот объект, нет
+	GetProcessingByS3Key(ctx context.Context, s3Key S3Key) (*FileVersion, error)
+	GetProcessingBefore(ctx context.Context, before time.Time, limit int) ([]*FileVersion, error)
+	// GetAbandoned версии в processing старше before без активной multipart или tus загрузки
+	GetAbandoned(ctx context.Context, before time.Time, limit int) ([]*FileVersion, error)
+	// GetWithStaleKey версии, объект которых зашифрован не мастер-ключом keyID, по возрастанию id
+	GetWithStaleKey(ctx context.Context, keyID string, afterID uuid.UUID, limit int) ([]*FileVersion, error)
+	// GetStored загруженные и готовые версии по возрастанию id
+	GetStored(ctx context.Context, afterID uuid.UUID, limit int) ([]*FileVersion, error)
+	// GetReferencedKeys подмножество keys, на которое есть ссылки в БД
+	GetReferencedKeys(ctx context.Context, keys []string) (map[string]bool, error)
 }
 
 type CommandRepository interface {
 	Save(ctx context.Context, version *FileVersion) error
+	// AttachBlob проставляет хеш, только если его ещё нет; false — версию уже связал другой вызов
+	AttachBlob(ctx context.Context, id uuid.UUID, hash ContentHash, key S3Key) (bool, error)
+	// MarkAbandoned переводит версию в failed с причиной reason и нулевым размером, только если она
+	// ещё ждёт данных; false — загрузку успели завершить
+	MarkAbandoned(ctx context.Context, id uuid.UUID, reason string) (bool, error)
+	// SetKeyID записывает мастер-ключ объекта всем версиям с этим ключом объекта
+	SetKeyID(ctx context.Context, s3Key S3Key, keyID string) error
 	Delete(ctx context.Context, id uuid.UUID) error
 }
@@ -1,6 +1,8 @@
 package file_version
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 )
@@ -20,6 +22,65 @@ func (k S3Key) String() string {
 	return k.value
 }
 
+// ContentHash SHA-256 содержимого версии в hex, по нему версии ссылаются на общий blob
+type ContentHash struct {
+	value string
+}
+
+func NewContentHash(value string) (ContentHash, error) {
+	if len(value) != 64 {
+		return ContentHash{}, errors.New("content hash must be 64 hex characters")
+	}
+	if _, err := hex.DecodeString(value); err != nil {
+		return ContentHash{}, errors.New("content hash must be 64 hex characters")
+	}
+	return ContentHash{value: value}, nil
+}
+
+func (h ContentHash) String() string {
+	return h.value
+}
+
+type ChecksumAlgorithm string
+
+const (
+	ChecksumSHA256 ChecksumAlgorithm = "sha256"
+	ChecksumCRC32C ChecksumAlgorithm = "crc32c"
+)
+
+// Checksum контрольная сумма, заявленная клиентом при загрузке.
+// Значение хранится в base64, как в заголовках x-amz-checksum-*
+type Checksum struct {
+	algorithm ChecksumAlgorithm
+	value     string
+}
+
+func NewChecksum(algorithm, value string) (Checksum, error) {
+	var size int
+	switch ChecksumAlgorithm(algorithm) {
+	case ChecksumSHA256:
+		size = 32
+	case ChecksumCRC32C:
+		size = 4
+	default:
+		return Checksum{}, ErrInvalidChecksum
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(raw) != size {
+		return Checksum{}, ErrInvalidChecksum
+	}
+	return Checksum{algorithm: ChecksumAlgorithm(algorithm), value: value}, nil
+}
+
+func (c Checksum) Algorithm() ChecksumAlgorithm {
+	return c.algorithm
+}
+
+func (c Checksum) Value() string {
+	return c.value
+}
+
 type MimeType struct {
 	value string
 }
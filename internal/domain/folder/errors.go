@@ -0,0 +1,10 @@
+package folder
+
+import "errors"
+
+var (
+	ErrNotFound     = errors.New("folder not found")
+	ErrInvalidName  = errors.New("folder name must be 1-255 characters and must not contain slashes")
+	ErrNameConflict = errors.New("folder with this name already exists")
+	ErrInvalidMove  = errors.New("folder cannot be moved into itself or its descendant")
+)
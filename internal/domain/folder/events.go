@@ -0,0 +1,36 @@
+package folder
+
+import (
+	"github.com/google/uuid"
+)
+
+func NewFolderCreatedEvent(f *Folder) (string, map[string]interface{}) {
+	return "FolderCreated", map[string]interface{}{
+		"folder_id": f.ID,
+		"owner_id":  f.OwnerID,
+		"parent_id": f.ParentID,
+		"name":      f.Name.String(),
+	}
+}
+
+func NewFolderRenamedEvent(f *Folder) (string, map[string]interface{}) {
+	return "FolderRenamed", map[string]interface{}{
+		"folder_id": f.ID,
+		"name":      f.Name.String(),
+	}
+}
+
+func NewFolderMovedEvent(f *Folder, oldParentID *uuid.UUID) (string, map[string]interface{}) {
+	return "FolderMoved", map[string]interface{}{
+		"folder_id":     f.ID,
+		"old_parent_id": oldParentID,
+		"parent_id":     f.ParentID,
+	}
+}
+
+func NewFolderDeletedEvent(f *Folder) (string, map[string]interface{}) {
+	return "FolderDeleted", map[string]interface{}{
+		"folder_id": f.ID,
+		"owner_id":  f.OwnerID,
+	}
+}
@@ -0,0 +1,44 @@
+package folder
+
+import (
+	"time"
+
+	uuid "github.com/google/uuid"
+)
+
+type Folder struct {
+	ID       uuid.UUID
+	OwnerID  uuid.UUID
+	ParentID *uuid.UUID
+
+	Name FolderName
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewFolder(ownerID uuid.UUID, parentID *uuid.UUID, name FolderName) *Folder {
+	now := time.Now()
+	return &Folder{
+		ID:        uuid.New(),
+		OwnerID:   ownerID,
+		ParentID:  parentID,
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func (f *Folder) Rename(newName FolderName) {
+	f.Name = newName
+	f.UpdatedAt = time.Now()
+}
+
+func (f *Folder) MoveTo(parentID *uuid.UUID) {
+	f.ParentID = parentID
+	f.UpdatedAt = time.Now()
+}
+
+func (f *Folder) IsRoot() bool {
+	return f.ParentID == nil
+}
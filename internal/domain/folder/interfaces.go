@@ -0,0 +1,23 @@
+package folder
+
+import (
+	"context"
+
+	uuid "github.com/google/uuid"
+)
+
+type QueryRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*Folder, error)
+	GetByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]*Folder, error)
+	// GetChildren возвращает вложенные папки; parentID == nil означает корень пользователя
+	GetChildren(ctx context.Context, ownerID uuid.UUID, parentID *uuid.UUID) ([]*Folder, error)
+	// GetAncestors возвращает цепочку папок от корня до указанной включительно
+	GetAncestors(ctx context.Context, id uuid.UUID) ([]*Folder, error)
+	// GetDescendants возвращает все вложенные папки, самые глубокие первыми
+	GetDescendants(ctx context.Context, id uuid.UUID) ([]*Folder, error)
+}
+
+type CommandRepository interface {
+	Save(ctx context.Context, folder *Folder) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,24 @@
+package folder
+
+import (
+	"strings"
+)
+
+type FolderName struct {
+	value string
+}
+
+func NewFolderName(raw string) (FolderName, error) {
+	name := strings.TrimSpace(raw)
+	if name == "" || name == "." || name == ".." {
+		return FolderName{}, ErrInvalidName
+	}
+	if len(name) > 255 || strings.ContainsAny(name, "/\\") {
+		return FolderName{}, ErrInvalidName
+	}
+	return FolderName{value: name}, nil
+}
+
+func (f FolderName) String() string {
+	return f.value
+}
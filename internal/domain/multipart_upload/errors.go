@@ -0,0 +1,11 @@
+package multipart_upload
+
+import "errors"
+
+var (
+	ErrNotFound          = errors.New("multipart upload not found")
+	ErrNotActive         = errors.New("multipart upload is already completed, aborted or expired")
+	ErrVersionNotPending = errors.New("multipart upload can only be started for a version awaiting upload")
+	ErrInvalidPartNumber = errors.New("part number is out of range")
+	ErrInvalidParts      = errors.New("parts must cover every part number exactly once and have an etag")
+)
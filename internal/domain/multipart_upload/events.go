@@ -0,0 +1,27 @@
+package multipart_upload
+
+func NewMultipartUploadInitiatedEvent(m *MultipartUpload) (string, map[string]interface{}) {
+	return "MultipartUploadInitiated", map[string]interface{}{
+		"upload_id":  m.ID,
+		"file_id":    m.FileID,
+		"version_id": m.VersionID,
+		"part_count": m.PartCount,
+	}
+}
+
+func NewMultipartUploadCompletedEvent(m *MultipartUpload) (string, map[string]interface{}) {
+	return "MultipartUploadCompleted", map[string]interface{}{
+		"upload_id":  m.ID,
+		"file_id":    m.FileID,
+		"version_id": m.VersionID,
+	}
+}
+
+func NewMultipartUploadAbortedEvent(m *MultipartUpload) (string, map[string]interface{}) {
+	return "MultipartUploadAborted", map[string]interface{}{
+		"upload_id":  m.ID,
+		"file_id":    m.FileID,
+		"version_id": m.VersionID,
+		"expired":    m.IsExpired(),
+	}
+}
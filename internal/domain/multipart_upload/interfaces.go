@@ -0,0 +1,20 @@
+package multipart_upload
+
+import (
+	"context"
+	"time"
+
+	uuid "github.com/google/uuid"
+)
+
+type QueryRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*MultipartUpload, error)
+	GetActiveByVersionID(ctx context.Context, versionID uuid.UUID) (*MultipartUpload, error)
+	// GetExpiredActive возвращает незавершённые загрузки с истёкшим сроком
+	GetExpiredActive(ctx context.Context, now time.Time, limit int) ([]*MultipartUpload, error)
+}
+
+type CommandRepository interface {
+	Save(ctx context.Context, upload *MultipartUpload) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
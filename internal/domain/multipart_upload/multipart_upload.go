@@ -0,0 +1,97 @@
+package multipart_upload
+
+import (
+	"time"
+
+	uuid "github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+)
+
+const (
+	// S3 допускает не более 10000 частей, каждая кроме последней не меньше 5 МБ
+	MaxParts        = 10000
+	DefaultPartSize = 16 * 1024 * 1024
+)
+
+// MultipartUpload отслеживает незавершённую multipart загрузку версии файла в S3
+type MultipartUpload struct {
+	ID        uuid.UUID
+	FileID    uuid.UUID
+	VersionID uuid.UUID
+	OwnerID   uuid.UUID
+
+	S3Key    file_version.S3Key
+	UploadID string
+
+	PartSize  int64
+	PartCount int
+	Status    UploadStatus
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ExpiresAt time.Time
+}
+
+func NewMultipartUpload(
+	fileID, versionID, ownerID uuid.UUID,
+	s3Key file_version.S3Key,
+	uploadID string,
+	size uint64,
+	ttl time.Duration,
+) *MultipartUpload {
+	now := time.Now()
+	partSize, partCount := CalculateParts(size)
+	return &MultipartUpload{
+		ID:        uuid.New(),
+		FileID:    fileID,
+		VersionID: versionID,
+		OwnerID:   ownerID,
+		S3Key:     s3Key,
+		UploadID:  uploadID,
+		PartSize:  partSize,
+		PartCount: partCount,
+		Status:    UploadStatusInitiated,
+		CreatedAt: now,
+		UpdatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+}
+
+// CalculateParts подбирает размер части так, чтобы файл уложился в MaxParts частей
+func CalculateParts(size uint64) (int64, int) {
+	partSize := int64(DefaultPartSize)
+	if minForLimit := int64((size + MaxParts - 1) / MaxParts); minForLimit > partSize {
+		partSize = minForLimit
+	}
+
+	partCount := int((int64(size) + partSize - 1) / partSize)
+	if partCount == 0 {
+		partCount = 1
+	}
+	return partSize, partCount
+}
+
+func (m *MultipartUpload) IsActive() bool {
+	return m.Status.Equal(UploadStatusInitiated)
+}
+
+func (m *MultipartUpload) IsExpired() bool {
+	return time.Now().After(m.ExpiresAt)
+}
+
+func (m *MultipartUpload) ValidatePartNumber(partNumber int32) error {
+	if partNumber < 1 || int(partNumber) > m.PartCount {
+		return ErrInvalidPartNumber
+	}
+	return nil
+}
+
+func (m *MultipartUpload) MarkCompleted() {
+	m.Status = UploadStatusCompleted
+	m.UpdatedAt = time.Now()
+}
+
+func (m *MultipartUpload) MarkAborted() {
+	m.Status = UploadStatusAborted
+	m.UpdatedAt = time.Now()
+}
@@ -0,0 +1,30 @@
+package multipart_upload
+
+import "errors"
+
+type UploadStatus struct {
+	value string
+}
+
+var (
+	UploadStatusInitiated = UploadStatus{value: "initiated"}
+	UploadStatusCompleted = UploadStatus{value: "completed"}
+	UploadStatusAborted   = UploadStatus{value: "aborted"}
+)
+
+func NewUploadStatus(value string) (UploadStatus, error) {
+	switch value {
+	case "initiated", "completed", "aborted":
+		return UploadStatus{value: value}, nil
+	default:
+		return UploadStatus{}, errors.New("invalid multipart upload status")
+	}
+}
+
+func (s UploadStatus) String() string {
+	return s.value
+}
+
+func (s UploadStatus) Equal(other UploadStatus) bool {
+	return s.value == other.value
+}
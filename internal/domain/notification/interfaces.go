@@ -1,11 +1,7 @@
 package notification
 
-import (
-	"context"
-
-	"github.com/yourusername/cloud-file-storage/internal/domain/value_objects"
-)
+import "context"
 
 type MailSender interface {
-	SendMagicLink(ctx context.Context, tokenHash value_objects.TokenHash, to string, baseURL string) error
+	SendMagicLink(ctx context.Context, token string, to string, baseURL string) error
 }
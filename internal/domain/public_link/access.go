@@ -0,0 +1,36 @@
+package public_link
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Access запись журнала скачиваний по публичной ссылке
+type Access struct {
+	ID     uuid.UUID
+	LinkID uuid.UUID
+	// VersionID версия, которую отдали; nil, если версию потом удалили
+	VersionID *uuid.UUID
+	IP        string
+	UserAgent string
+	CreatedAt time.Time
+}
+
+func NewAccess(linkID, versionID uuid.UUID, ip, userAgent string) *Access {
+	return &Access{
+		ID:        uuid.New(),
+		LinkID:    linkID,
+		VersionID: &versionID,
+		IP:        ip,
+		UserAgent: userAgent,
+		CreatedAt: time.Now(),
+	}
+}
+
+func ValidateMaxDownloads(maxDownloads *int) error {
+	if maxDownloads != nil && *maxDownloads < 1 {
+		return ErrInvalidMaxDownloads
+	}
+	return nil
+}
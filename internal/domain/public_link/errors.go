@@ -3,6 +3,16 @@ package public_link
 import "errors"
 
 var (
-	ErrNotFound          = errors.New("public link not found")
-	ErrInvalidExpiryTime = errors.New("expiresAt must be in the future")
+	ErrNotFound             = errors.New("public link not found")
+	ErrExpired              = errors.New("public link has expired")
+	ErrInvalidExpiryTime    = errors.New("expiresAt must be in the future")
+	ErrInvalidPassword      = errors.New("public link password must be between 4 and 72 bytes")
+	ErrPasswordRequired     = errors.New("public link is password protected")
+	ErrWrongPassword        = errors.New("wrong public link password")
+	ErrInvalidUnlockToken   = errors.New("invalid or expired unlock token")
+	ErrLocked               = errors.New("too many wrong passwords, public link is locked for this address")
+	ErrInvalidMaxDownloads  = errors.New("max downloads must be positive")
+	ErrInvalidVersionMode   = errors.New("version_mode must be current, latest or pinned; version_id is required for pinned only")
+	ErrPinnedVersionDeleted = errors.New("pinned version of the public link was deleted")
+	ErrExhausted            = errors.New("public link download limit reached")
 )
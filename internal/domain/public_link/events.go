@@ -1,15 +1,21 @@
 package public_link
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
 func NewPublicLinkCreatedEvent(link *PublicLink) (string, map[string]interface{}) {
 	return "PublicLinkCreated", map[string]interface{}{
-		"link_id":    link.ID,
-		"file_id":    link.FileID,
-		"created_by": link.CreatedByUserID,
-		"expires_at": link.ExpiredAt,
+		"link_id":       link.ID,
+		"file_id":       link.FileID,
+		"created_by":    link.CreatedByUserID,
+		"expires_at":    link.ExpiredAt,
+		"protected":     link.HasPassword(),
+		"max_downloads": link.MaxDownloads,
+		"version_mode":  link.VersionMode.String(),
+		"version_id":    link.VersionID,
 	}
 }
 
@@ -24,3 +30,19 @@ func NewPublicLinkExpiredEvent(linkID uuid.UUID) (string, map[string]interface{}
 		"link_id": linkID,
 	}
 }
+
+func NewPublicLinkLockedEvent(linkID uuid.UUID, ip string, lockedUntil time.Time) (string, map[string]interface{}) {
+	return "PublicLinkLocked", map[string]interface{}{
+		"link_id":      linkID,
+		"ip":           ip,
+		"locked_until": lockedUntil,
+	}
+}
+
+func NewPublicLinkExhaustedEvent(link *PublicLink) (string, map[string]interface{}) {
+	return "PublicLinkExhausted", map[string]interface{}{
+		"link_id":        link.ID,
+		"file_id":        link.FileID,
+		"download_count": link.DownloadCount,
+	}
+}
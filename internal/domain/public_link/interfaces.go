@@ -2,18 +2,38 @@ package public_link
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type PublicLinkQueryRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*PublicLink, error)
-	GetByToken(ctx context.Context, token string) (*PublicLink, error)
+	// GetByTokenHash в БД хранится только SHA-256 токена
+	GetByTokenHash(ctx context.Context, tokenHash string) (*PublicLink, error)
 	GetByFileID(ctx context.Context, fileID uuid.UUID) ([]*PublicLink, error)
 	GetAll(ctx context.Context) ([]*PublicLink, error)
+	// GetUnlockAttempts nil, если с этого IP пароль ещё не ошибались
+	GetUnlockAttempts(ctx context.Context, linkID uuid.UUID, ip string) (*UnlockAttempts, error)
+	// GetAccesses журнал скачиваний ссылки, новые первыми
+	GetAccesses(ctx context.Context, linkID uuid.UUID) ([]*Access, error)
 }
 
 type PublicLinkCommandRepository interface {
 	Save(ctx context.Context, link *PublicLink) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	// RegisterUnlockFailure увеличивает счётчик ошибок и возвращает его; ошибки до windowStart забываются
+	RegisterUnlockFailure(ctx context.Context, linkID uuid.UUID, ip string, now, windowStart time.Time) (int, error)
+	// LockUnlock закрывает ввод пароля с IP до until и сбрасывает счётчик
+	LockUnlock(ctx context.Context, linkID uuid.UUID, ip string, until time.Time) error
+	ResetUnlockAttempts(ctx context.Context, linkID uuid.UUID, ip string) error
+	// IncrementDownloads атомарно засчитывает скачивание и возвращает новый счётчик;
+	// false — лимит уже выбран, счётчик не изменился
+	IncrementDownloads(ctx context.Context, linkID uuid.UUID) (int, bool, error)
+	SaveAccess(ctx context.Context, access *Access) error
+	// DeleteExpired удаляет до limit ссылок, истёкших раньше before, и возвращает их id.
+	// Строки, которые держит другая транзакция, пропускаются.
+	DeleteExpired(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error)
+	// ArchiveExpired как DeleteExpired, но сначала переносит ссылки в public_links_archive
+	ArchiveExpired(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error)
 }
@@ -12,6 +12,15 @@ type PublicLink struct {
 	CreatedByUserID uuid.UUID
 
 	TokenHash string
+	// PasswordHash bcrypt хеш пароля; nil — ссылка открывается без пароля
+	PasswordHash *string
+	// MaxDownloads nil — число скачиваний не ограничено
+	MaxDownloads  *int
+	DownloadCount int
+
+	VersionMode VersionMode
+	// VersionID только у pinned; nil у pinned — закреплённую версию удалили
+	VersionID *uuid.UUID
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -31,6 +40,7 @@ func NewPublicLink(
 		FileID:          fileID,
 		CreatedByUserID: createdByUserID,
 		TokenHash:       tokenHash,
+		VersionMode:     VersionModeCurrent,
 		ExpiredAt:       expiresAt,
 		CreatedAt:       now,
 		UpdatedAt:       now,
@@ -40,3 +50,27 @@ func NewPublicLink(
 func (p *PublicLink) IsExpired() bool {
 	return time.Now().After(p.ExpiredAt)
 }
+
+func (p *PublicLink) HasPassword() bool {
+	return p.PasswordHash != nil
+}
+
+// IsExhausted лимит скачиваний выбран; такая ссылка ведёт себя как истёкшая
+func (p *PublicLink) IsExhausted() bool {
+	return p.MaxDownloads != nil && p.DownloadCount >= *p.MaxDownloads
+}
+
+// IsActive ссылка не истекла и лимит скачиваний не выбран
+func (p *PublicLink) IsActive() bool {
+	return !p.IsExpired() && !p.IsExhausted()
+}
+
+// PinVersion задаёт режим ссылки; versionID обязателен для pinned и запрещён для остальных режимов
+func (p *PublicLink) PinVersion(mode VersionMode, versionID *uuid.UUID) error {
+	if mode.Equal(VersionModePinned) != (versionID != nil) {
+		return ErrInvalidVersionMode
+	}
+	p.VersionMode = mode
+	p.VersionID = versionID
+	return nil
+}
@@ -0,0 +1,33 @@
+package public_link
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MinPasswordLength = 4
+	// MaxPasswordLength bcrypt учитывает только первые 72 байта
+	MaxPasswordLength = 72
+)
+
+// UnlockAttempts неудачные попытки ввести пароль ссылки с одного IP
+type UnlockAttempts struct {
+	LinkID      uuid.UUID
+	IP          string
+	Failures    int
+	LockedUntil *time.Time
+	UpdatedAt   time.Time
+}
+
+func (a *UnlockAttempts) IsLocked(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
+
+func ValidatePassword(password string) error {
+	if len(password) < MinPasswordLength || len(password) > MaxPasswordLength {
+		return ErrInvalidPassword
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package public_link
+
+// VersionMode какую версию файла отдаёт ссылка
+type VersionMode struct {
+	value string
+}
+
+var (
+	// VersionModeCurrent текущая версия файла, в том числе восстановленная
+	VersionModeCurrent = VersionMode{value: "current"}
+	// VersionModeLatest самая новая готовая версия
+	VersionModeLatest = VersionMode{value: "latest"}
+	// VersionModePinned версия, выбранная при создании ссылки
+	VersionModePinned = VersionMode{value: "pinned"}
+)
+
+// NewVersionMode пустое значение — current, как у ссылок до появления режимов
+func NewVersionMode(value string) (VersionMode, error) {
+	switch value {
+	case "":
+		return VersionModeCurrent, nil
+	case "current", "latest", "pinned":
+		return VersionMode{value: value}, nil
+	default:
+		return VersionMode{}, ErrInvalidVersionMode
+	}
+}
+
+func (m VersionMode) String() string {
+	return m.value
+}
+
+func (m VersionMode) Equal(other VersionMode) bool {
+	return m.value == other.value
+}
@@ -0,0 +1,12 @@
+package queue
+
+import (
+	"context"
+
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
+)
+
+// BucketEventConsumer читает уведомления бакета; одно сообщение может нести несколько событий
+type BucketEventConsumer interface {
+	Consume(ctx context.Context) ([]storage.ObjectCreated, error)
+}
@@ -0,0 +1,7 @@
+package quota
+
+import "errors"
+
+var (
+	ErrQuotaExceeded = errors.New("storage quota exceeded")
+)
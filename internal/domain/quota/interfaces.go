@@ -0,0 +1,20 @@
+package quota
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type QueryRepository interface {
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*Quota, error)
+	GetUsageByStatus(ctx context.Context, userID uuid.UUID) ([]UsageEntry, error)
+	GetUsageByMime(ctx context.Context, userID uuid.UUID) ([]UsageEntry, error)
+}
+
+type CommandRepository interface {
+	// Lock блокирует квоту пользователя до конца транзакции. Если квоты ещё нет, она создаётся из q,
+	// а занятый объём считается по уже сохранённым версиям пользователя.
+	Lock(ctx context.Context, q *Quota) (*Quota, error)
+	Save(ctx context.Context, q *Quota) error
+}
@@ -0,0 +1,71 @@
+package quota
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Quota лимит пользователя и занятый объём. Учитываются все версии всех файлов,
+// включая незавершённые загрузки и файлы в корзине, пока они не удалены окончательно.
+type Quota struct {
+	UserID     uuid.UUID
+	LimitBytes int64
+	UsedBytes  int64
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewQuota(userID uuid.UUID, limitBytes int64) *Quota {
+	now := time.Now()
+	return &Quota{
+		UserID:     userID,
+		LimitBytes: limitBytes,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// Reserve занимает size байт или возвращает ErrQuotaExceeded
+func (q *Quota) Reserve(size int64) error {
+	if size > q.Available() {
+		return ErrQuotaExceeded
+	}
+	q.UsedBytes += size
+	q.UpdatedAt = time.Now()
+	return nil
+}
+
+// Adjust меняет занятый объём без проверки лимита: байты уже лежат в хранилище
+func (q *Quota) Adjust(delta int64) {
+	q.UsedBytes += delta
+	if q.UsedBytes < 0 {
+		q.UsedBytes = 0
+	}
+	q.UpdatedAt = time.Now()
+}
+
+func (q *Quota) Release(size int64) {
+	q.Adjust(-size)
+}
+
+func (q *Quota) Available() int64 {
+	if q.UsedBytes >= q.LimitBytes {
+		return 0
+	}
+	return q.LimitBytes - q.UsedBytes
+}
+
+// UsageEntry количество версий и их суммарный размер в одной группе разбивки
+type UsageEntry struct {
+	Key      string
+	Versions int64
+	Bytes    int64
+}
+
+type Usage struct {
+	Quota    *Quota
+	ByStatus []UsageEntry
+	ByMime   []UsageEntry
+}
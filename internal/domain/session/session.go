@@ -53,6 +53,13 @@ func (s *Session) Revoke() {
 	s.UpdatedAt = time.Now()
 }
 
+// RotateTokens заменяет пару токенов; прежние перестают действовать
+func (s *Session) RotateTokens(tokenHash, refreshTokenHash value_objects.TokenHash) {
+	s.TokenHash = tokenHash
+	s.RefreshTokenHash = refreshTokenHash
+	s.UpdatedAt = time.Now()
+}
+
 func (s *Session) UpdateLastUsed() {
 	s.LastUsedAt = time.Now()
 	s.UpdatedAt = time.Now()
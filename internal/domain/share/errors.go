@@ -0,0 +1,9 @@
+package share
+
+import "errors"
+
+var (
+	ErrNotFound             = errors.New("share not found")
+	ErrInvalidRole          = errors.New("invalid share role")
+	ErrCannotShareWithOwner = errors.New("file cannot be shared with its owner")
+)
@@ -0,0 +1,19 @@
+package share
+
+func NewFileSharedEvent(s *Share) (string, map[string]interface{}) {
+	return "FileShared", map[string]interface{}{
+		"share_id":   s.ID,
+		"file_id":    s.FileID,
+		"user_id":    s.UserID,
+		"role":       s.Role.String(),
+		"granted_by": s.GrantedBy,
+	}
+}
+
+func NewFileShareRevokedEvent(s *Share) (string, map[string]interface{}) {
+	return "FileShareRevoked", map[string]interface{}{
+		"share_id": s.ID,
+		"file_id":  s.FileID,
+		"user_id":  s.UserID,
+	}
+}
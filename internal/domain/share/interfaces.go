@@ -0,0 +1,22 @@
+package share
+
+import (
+	"context"
+
+	uuid "github.com/google/uuid"
+)
+
+type QueryRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*Share, error)
+	GetByFileID(ctx context.Context, fileID uuid.UUID) ([]*Share, error)
+	// GetByFileAndUser nil, если файл пользователю не выдан
+	GetByFileAndUser(ctx context.Context, fileID, userID uuid.UUID) (*Share, error)
+	// GetSharedWithUser доступы пользователя к файлам, которые не в корзине
+	GetSharedWithUser(ctx context.Context, userID uuid.UUID) ([]*Share, error)
+}
+
+type CommandRepository interface {
+	// Save повторная выдача тому же пользователю меняет роль
+	Save(ctx context.Context, share *Share) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,39 @@
+package share
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Share доступ зарегистрированного пользователя к чужому файлу
+type Share struct {
+	ID        uuid.UUID
+	FileID    uuid.UUID
+	UserID    uuid.UUID
+	Role      Role
+	GrantedBy uuid.UUID
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewShare(fileID, userID uuid.UUID, role Role, grantedBy uuid.UUID) *Share {
+	now := time.Now()
+
+	return &Share{
+		ID:        uuid.New(),
+		FileID:    fileID,
+		UserID:    userID,
+		Role:      role,
+		GrantedBy: grantedBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func (s *Share) ChangeRole(role Role, grantedBy uuid.UUID) {
+	s.Role = role
+	s.GrantedBy = grantedBy
+	s.UpdatedAt = time.Now()
+}
@@ -0,0 +1,47 @@
+package share
+
+// Role уровни доступа упорядочены: owner включает права editor, editor — права viewer
+type Role struct {
+	value string
+}
+
+var (
+	RoleViewer = Role{value: "viewer"}
+	RoleEditor = Role{value: "editor"}
+	RoleOwner  = Role{value: "owner"}
+)
+
+func NewRole(value string) (Role, error) {
+	switch value {
+	case "viewer", "editor", "owner":
+		return Role{value: value}, nil
+	default:
+		return Role{}, ErrInvalidRole
+	}
+}
+
+func (r Role) String() string {
+	return r.value
+}
+
+func (r Role) Equal(other Role) bool {
+	return r.value == other.value
+}
+
+// Allows true, если роль даёт права не ниже required
+func (r Role) Allows(required Role) bool {
+	return r.level() >= required.level()
+}
+
+func (r Role) level() int {
+	switch r.value {
+	case "viewer":
+		return 1
+	case "editor":
+		return 2
+	case "owner":
+		return 3
+	default:
+		return 0
+	}
+}
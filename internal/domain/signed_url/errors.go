@@ -0,0 +1,12 @@
+package signed_url
+
+import "errors"
+
+var (
+	ErrInvalidSignature   = errors.New("invalid signed url")
+	ErrExpired            = errors.New("signed url has expired")
+	ErrRevoked            = errors.New("signed url was revoked")
+	ErrPermissionDenied   = errors.New("signed url does not grant this permission")
+	ErrInvalidPermissions = errors.New("invalid signed url permissions")
+	ErrInvalidTTL         = errors.New("invalid signed url ttl")
+)
@@ -0,0 +1,12 @@
+package signed_url
+
+import (
+	"github.com/google/uuid"
+)
+
+func NewSignedURLsRevokedEvent(fileID uuid.UUID, epoch int64) (string, map[string]interface{}) {
+	return "SignedURLsRevoked", map[string]interface{}{
+		"file_id": fileID,
+		"epoch":   epoch,
+	}
+}
@@ -0,0 +1,17 @@
+package signed_url
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type QueryRepository interface {
+	// GetFileState nil, если файла нет
+	GetFileState(ctx context.Context, fileID uuid.UUID) (*FileState, error)
+}
+
+type CommandRepository interface {
+	// SetEpoch false, если файла нет
+	SetEpoch(ctx context.Context, fileID uuid.UUID, epoch int64) (bool, error)
+}
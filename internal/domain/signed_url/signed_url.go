@@ -0,0 +1,58 @@
+package signed_url
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Claims то, что подписывает URL. Время хранится с точностью до секунды, как в самом URL.
+type Claims struct {
+	// KeyID ключ, которым подписан URL; по нему проверка выбирает ключ при ротации
+	KeyID       string
+	FileID      uuid.UUID
+	VersionID   uuid.UUID
+	Permissions Permissions
+	IssuedAt    time.Time
+	ExpiresAt   time.Time
+}
+
+func NewClaims(keyID string, fileID, versionID uuid.UUID, permissions Permissions, ttl time.Duration) (*Claims, error) {
+	if ttl <= 0 {
+		return nil, ErrInvalidTTL
+	}
+
+	now := time.Unix(time.Now().Unix(), 0)
+	return &Claims{
+		KeyID:       keyID,
+		FileID:      fileID,
+		VersionID:   versionID,
+		Permissions: permissions,
+		IssuedAt:    now,
+		ExpiresAt:   now.Add(ttl),
+	}, nil
+}
+
+// Payload строка, по которой считается HMAC; формат менять нельзя, иначе выданные URL перестанут проходить проверку
+func (c *Claims) Payload() string {
+	return fmt.Sprintf("v1\n%s\n%s\n%s\n%s\n%d\n%d",
+		c.KeyID, c.FileID, c.VersionID, c.Permissions.String(), c.IssuedAt.Unix(), c.ExpiresAt.Unix())
+}
+
+func (c *Claims) IsExpired(now time.Time) bool {
+	return now.Unix() > c.ExpiresAt.Unix()
+}
+
+// RevokedBy true, если URL выдан не позже эпохи отзыва файла.
+// URL, выданные в ту же секунду, что и отзыв, тоже считаются отозванными.
+func (c *Claims) RevokedBy(epoch int64) bool {
+	return c.IssuedAt.Unix() <= epoch
+}
+
+// FileState то, что нужно знать о файле для проверки URL
+type FileState struct {
+	// Epoch unix-время последнего отзыва; 0 — URL файла не отзывались
+	Epoch   int64
+	Trashed bool
+}
@@ -0,0 +1,88 @@
+package signed_url
+
+import (
+	"sort"
+	"strings"
+)
+
+// Permission действие, которое разрешает подписанный URL
+type Permission struct {
+	value string
+}
+
+var (
+	// PermissionDownload содержимое версии
+	PermissionDownload = Permission{value: "download"}
+	// PermissionPreview превью версии
+	PermissionPreview = Permission{value: "preview"}
+)
+
+func NewPermission(value string) (Permission, error) {
+	switch value {
+	case "download", "preview":
+		return Permission{value: value}, nil
+	default:
+		return Permission{}, ErrInvalidPermissions
+	}
+}
+
+func (p Permission) String() string {
+	return p.value
+}
+
+func (p Permission) Equal(other Permission) bool {
+	return p.value == other.value
+}
+
+// Permissions набор разрешений URL; String каноничен и входит в подпись
+type Permissions struct {
+	values []string
+}
+
+// NewPermissions пустой список — только download; повторы схлопываются
+func NewPermissions(values []string) (Permissions, error) {
+	if len(values) == 0 {
+		return Permissions{values: []string{PermissionDownload.value}}, nil
+	}
+
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		p, err := NewPermission(strings.TrimSpace(v))
+		if err != nil {
+			return Permissions{}, err
+		}
+		if seen[p.value] {
+			continue
+		}
+		seen[p.value] = true
+		result = append(result, p.value)
+	}
+	sort.Strings(result)
+	return Permissions{values: result}, nil
+}
+
+// ParsePermissions разбирает String()
+func ParsePermissions(raw string) (Permissions, error) {
+	if raw == "" {
+		return Permissions{}, ErrInvalidPermissions
+	}
+	return NewPermissions(strings.Split(raw, ","))
+}
+
+func (p Permissions) Has(permission Permission) bool {
+	for _, v := range p.values {
+		if v == permission.value {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Permissions) Strings() []string {
+	return append([]string(nil), p.values...)
+}
+
+func (p Permissions) String() string {
+	return strings.Join(p.values, ",")
+}
@@ -0,0 +1,13 @@
+package storage
+
+import "errors"
+
+var (
+	ErrObjectNotFound   = errors.New("object not found")
+	ErrUploadNotFound   = errors.New("multipart upload not found")
+	ErrInvalidKey       = errors.New("invalid object key")
+	ErrSizeMismatch     = errors.New("object size does not match content length")
+	ErrInvalidSignature = errors.New("invalid url signature")
+	ErrURLExpired       = errors.New("url expired")
+	ErrPartTooLarge     = errors.New("multipart part is too large")
+)
@@ -0,0 +1,10 @@
+package storage
+
+// ObjectCreated событие s3:ObjectCreated:* из уведомлений бакета
+type ObjectCreated struct {
+	// EventName полное имя события, например s3:ObjectCreated:Put
+	EventName string
+	Key       string
+	Size      int64
+	ETag      string
+}
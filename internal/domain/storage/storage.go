@@ -2,16 +2,70 @@ package storage
 
 import (
 	"context"
+	"io"
 	"time"
 )
 
 type Storage interface {
 	GenerateUploadURL(ctx context.Context, key string, expiresIn time.Duration) (string, error)
 	GenerateUploadURLWithSize(ctx context.Context, key string, expiresIn time.Duration, fileSize int64) (string, error)
+	// GenerateUploadURLWithChecksum дополнительно подписывает контрольную сумму и возвращает заголовки,
+	// которые клиент обязан передать в PUT
+	GenerateUploadURLWithChecksum(ctx context.Context, key string, expiresIn time.Duration, fileSize int64, checksum Checksum) (string, map[string]string, error)
 	GenerateDownloadURL(ctx context.Context, key string, expiresIn time.Duration) (string, error)
 	Delete(ctx context.Context, key string) error
+	// Copy копирует объект внутри хранилища вместе с метаданными; ErrObjectNotFound, если источника нет
+	Copy(ctx context.Context, srcKey, dstKey string) error
 
-	UploadFile(ctx context.Context, key string, fileData []byte) error
-	DownloadFile(ctx context.Context, key string) ([]byte, error)
+	// Put, Get и GetRange работают потоком, объект целиком в памяти не держится.
+	// Вызывающий обязан закрыть Object.Body.
+	Put(ctx context.Context, key string, body io.Reader, info ObjectInfo) error
+	Get(ctx context.Context, key string) (*Object, error)
+	GetRange(ctx context.Context, key string, offset, length int64) (*Object, error)
 	FileExists(ctx context.Context, key string) (bool, error)
+	// Stat возвращает свойства объекта без содержимого или ErrObjectNotFound
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// List возвращает до limit объектов с префиксом prefix, ключ которых больше startAfter, по возрастанию ключа
+	List(ctx context.Context, prefix, startAfter string, limit int) ([]ObjectSummary, error)
+
+	// Multipart загрузка больших файлов
+	CreateMultipartUpload(ctx context.Context, key string, contentType string) (string, error)
+	GenerateUploadPartURL(ctx context.Context, key string, uploadID string, partNumber int32, expiresIn time.Duration) (string, error)
+	UploadPart(ctx context.Context, key string, uploadID string, partNumber int32, data []byte) (string, error)
+	CompleteMultipartUpload(ctx context.Context, key string, uploadID string, parts []CompletedPart) error
+	AbortMultipartUpload(ctx context.Context, key string, uploadID string) error
+}
+
+// Checksum контрольная сумма содержимого: Algorithm — sha256 или crc32c, Value — дайджест в base64
+type Checksum struct {
+	Algorithm string
+	Value     string
+}
+
+// CompletedPart часть multipart загрузки, подтверждённая клиентом
+type CompletedPart struct {
+	PartNumber int32
+	ETag       string
+}
+
+// ObjectInfo свойства объекта. ContentLength < 0 означает, что размер заранее неизвестен.
+type ObjectInfo struct {
+	ContentType   string
+	ContentLength int64
+	Metadata      map[string]string
+	// KeyID мастер-ключ, которым обёрнут ключ данных объекта; пусто — объект не зашифрован
+	KeyID string
+}
+
+// ObjectSummary объект из листинга хранилища
+type ObjectSummary struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// Object содержимое объекта или его диапазона; ContentLength равен размеру Body
+type Object struct {
+	Body io.ReadCloser
+	ObjectInfo
 }
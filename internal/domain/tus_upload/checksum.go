@@ -0,0 +1,59 @@
+package tus_upload
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"hash"
+	"strings"
+)
+
+// SupportedChecksumAlgorithms значение заголовка Tus-Checksum-Algorithm
+const SupportedChecksumAlgorithms = "sha1,sha256,md5"
+
+// Checksum значение заголовка Upload-Checksum: алгоритм и base64 от хеша тела PATCH
+type Checksum struct {
+	algorithm string
+	sum       []byte
+}
+
+func ParseChecksum(header string) (Checksum, error) {
+	algorithm, encoded, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok {
+		return Checksum{}, ErrInvalidChecksum
+	}
+
+	algorithm = strings.ToLower(algorithm)
+	if newHash(algorithm) == nil {
+		return Checksum{}, ErrUnsupportedChecksum
+	}
+
+	sum, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return Checksum{}, ErrInvalidChecksum
+	}
+
+	return Checksum{algorithm: algorithm, sum: sum}, nil
+}
+
+func (c Checksum) NewHash() hash.Hash {
+	return newHash(c.algorithm)
+}
+
+func (c Checksum) Matches(h hash.Hash) bool {
+	return string(h.Sum(nil)) == string(c.sum)
+}
+
+func newHash(algorithm string) hash.Hash {
+	switch algorithm {
+	case "sha1":
+		return sha1.New()
+	case "sha256":
+		return sha256.New()
+	case "md5":
+		return md5.New()
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,12 @@
+package tus_upload
+
+import "errors"
+
+var (
+	ErrNotFound            = errors.New("tus upload not found")
+	ErrOffsetMismatch      = errors.New("upload offset does not match the current offset")
+	ErrLengthExceeded      = errors.New("upload data exceeds the declared length")
+	ErrInvalidChecksum     = errors.New("invalid Upload-Checksum header")
+	ErrUnsupportedChecksum = errors.New("unsupported checksum algorithm")
+	ErrChecksumMismatch    = errors.New("checksum mismatch")
+)
@@ -0,0 +1,27 @@
+package tus_upload
+
+func NewTusUploadCreatedEvent(u *TusUpload) (string, map[string]interface{}) {
+	return "TusUploadCreated", map[string]interface{}{
+		"upload_id":  u.ID,
+		"file_id":    u.FileID,
+		"version_id": u.VersionID,
+		"length":     u.Length,
+	}
+}
+
+func NewTusUploadCompletedEvent(u *TusUpload) (string, map[string]interface{}) {
+	return "TusUploadCompleted", map[string]interface{}{
+		"upload_id":  u.ID,
+		"file_id":    u.FileID,
+		"version_id": u.VersionID,
+		"parts":      len(u.Parts),
+	}
+}
+
+func NewTusUploadTerminatedEvent(u *TusUpload) (string, map[string]interface{}) {
+	return "TusUploadTerminated", map[string]interface{}{
+		"upload_id": u.ID,
+		"file_id":   u.FileID,
+		"offset":    u.Offset,
+	}
+}
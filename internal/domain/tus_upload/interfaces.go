@@ -0,0 +1,19 @@
+package tus_upload
+
+import (
+	"context"
+
+	uuid "github.com/google/uuid"
+)
+
+type QueryRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*TusUpload, error)
+}
+
+type CommandRepository interface {
+	Create(ctx context.Context, upload *TusUpload) error
+	// Update сохраняет состояние, только если смещение в БД всё ещё равно prevOffset;
+	// иначе возвращает ErrOffsetMismatch (параллельный PATCH с другого инстанса)
+	Update(ctx context.Context, upload *TusUpload, prevOffset int64) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
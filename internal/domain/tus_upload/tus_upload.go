@@ -0,0 +1,88 @@
+package tus_upload
+
+import (
+	"fmt"
+	"time"
+
+	uuid "github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
+)
+
+// PartSize размер частей, которыми tus загрузка складывается в S3 multipart.
+// Хвост меньше PartSize хранится отдельным объектом до следующего PATCH.
+const PartSize = 8 * 1024 * 1024
+
+// TusUpload состояние возобновляемой загрузки по протоколу tus
+type TusUpload struct {
+	ID        uuid.UUID
+	FileID    uuid.UUID
+	VersionID uuid.UUID
+	OwnerID   uuid.UUID
+
+	S3Key       file_version.S3Key
+	MultipartID string
+
+	Length      int64
+	Offset      int64
+	PendingSize int64
+	Parts       []storage.CompletedPart
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewTusUpload(
+	fileID, versionID, ownerID uuid.UUID,
+	s3Key file_version.S3Key,
+	multipartID string,
+	length int64,
+) *TusUpload {
+	now := time.Now()
+	return &TusUpload{
+		ID:          uuid.New(),
+		FileID:      fileID,
+		VersionID:   versionID,
+		OwnerID:     ownerID,
+		S3Key:       s3Key,
+		MultipartID: multipartID,
+		Length:      length,
+		Parts:       make([]storage.CompletedPart, 0),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// PendingKeyAt ключ объекта с хвостом, ещё не отправленным частью в multipart.
+// Смещение в ключе делает объект неизменяемым: конкурентный PATCH не перезапишет чужой хвост.
+func (u *TusUpload) PendingKeyAt(offset int64) string {
+	return fmt.Sprintf("%s.tus-pending-%d", u.S3Key.String(), offset)
+}
+
+func (u *TusUpload) PendingKey() string {
+	return u.PendingKeyAt(u.Offset)
+}
+
+func (u *TusUpload) IsComplete() bool {
+	return u.Offset == u.Length
+}
+
+func (u *TusUpload) Remaining() int64 {
+	return u.Length - u.Offset
+}
+
+func (u *TusUpload) NextPartNumber() int32 {
+	return int32(len(u.Parts) + 1)
+}
+
+// Advance фиксирует принятые байты, новые части и размер хвоста
+func (u *TusUpload) Advance(written int64, parts []storage.CompletedPart, pendingSize int64) error {
+	if written < 0 || u.Offset+written > u.Length {
+		return ErrLengthExceeded
+	}
+	u.Offset += written
+	u.Parts = append(u.Parts, parts...)
+	u.PendingSize = pendingSize
+	u.UpdatedAt = time.Now()
+	return nil
+}
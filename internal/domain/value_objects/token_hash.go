@@ -1,15 +1,25 @@
 package value_objects
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
 	"strings"
 
 	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
 )
 
+// tokenBytes 256 бит случайности в каждом выдаваемом токене
+const tokenBytes = 32
+
+// TokenHash SHA-256 токена в hex. Сам токен отдаётся клиенту один раз и нигде не хранится
 type TokenHash struct {
 	value string
 }
 
+// NewTokenHash оборачивает уже посчитанный хеш, например прочитанный из БД
 func NewTokenHash(raw string) (TokenHash, error) {
 	token := strings.TrimSpace(raw)
 	if token == "" {
@@ -18,6 +28,35 @@ func NewTokenHash(raw string) (TokenHash, error) {
 	return TokenHash{value: token}, nil
 }
 
+// NewToken новый токен из crypto/rand и его хеш для хранения
+func NewToken() (string, TokenHash, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", TokenHash{}, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	hash, err := HashToken(token)
+	if err != nil {
+		return "", TokenHash{}, err
+	}
+	return token, hash, nil
+}
+
+// HashToken хеш токена, пришедшего от клиента; по нему ищется запись в БД
+func HashToken(token string) (TokenHash, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return TokenHash{}, domainerrors.ErrInvaliTokenHash
+	}
+	sum := sha256.Sum256([]byte(token))
+	return TokenHash{value: hex.EncodeToString(sum[:])}, nil
+}
+
+// Matches сравнивает с хешем из БД за постоянное время
+func (t TokenHash) Matches(stored string) bool {
+	return subtle.ConstantTimeCompare([]byte(t.value), []byte(stored)) == 1
+}
+
 func (t TokenHash) String() string {
 	return t.value
 }
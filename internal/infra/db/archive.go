@@ -0,0 +1,218 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/domain/archive"
+	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
+)
+
+// archiveEntry формат элемента колонки entries
+type archiveEntry struct {
+	Name       string    `json:"name"`
+	S3Key      string    `json:"s3_key,omitempty"`
+	Mime       string    `json:"mime,omitempty"`
+	Size       int64     `json:"size"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+func marshalArchiveEntries(entries []archive.Entry) ([]byte, error) {
+	out := make([]archiveEntry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, archiveEntry{Name: e.Name, S3Key: e.S3Key, Mime: e.Mime, Size: e.Size, ModifiedAt: e.ModifiedAt})
+	}
+	return json.Marshal(out)
+}
+
+func unmarshalArchiveEntries(data []byte) ([]archive.Entry, error) {
+	var in []archiveEntry
+	if err := json.Unmarshal(data, &in); err != nil {
+		return nil, err
+	}
+	entries := make([]archive.Entry, 0, len(in))
+	for _, e := range in {
+		entries = append(entries, archive.Entry{Name: e.Name, S3Key: e.S3Key, Mime: e.Mime, Size: e.Size, ModifiedAt: e.ModifiedAt})
+	}
+	return entries, nil
+}
+
+func scanArchive(scanner scannable) (*archive.Archive, error) {
+	var a archive.Archive
+	var status string
+	var entries []byte
+	var failureReason sql.NullString
+
+	if err := scanner.Scan(
+		&a.ID,
+		&a.OwnerID,
+		&status,
+		&entries,
+		&a.Size,
+		&a.S3Key,
+		&failureReason,
+		&a.CreatedAt,
+		&a.UpdatedAt,
+		&a.ExpiresAt,
+	); err != nil {
+		return nil, err
+	}
+
+	var err error
+	a.Status, err = archive.NewStatus(status)
+	if err != nil {
+		return nil, err
+	}
+
+	a.Entries, err = unmarshalArchiveEntries(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	if failureReason.Valid {
+		a.FailureReason = &failureReason.String
+	}
+
+	return &a, nil
+}
+
+func scanArchives(rows *sql.Rows) ([]*archive.Archive, error) {
+	defer rows.Close()
+
+	archives := make([]*archive.Archive, 0)
+	for rows.Next() {
+		a, err := scanArchive(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan archive: %w", err)
+		}
+		archives = append(archives, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return archives, nil
+}
+
+type ArchiveCommandRepository struct {
+}
+
+func NewArchiveCommandRepository() *ArchiveCommandRepository {
+	return &ArchiveCommandRepository{}
+}
+
+func (r *ArchiveCommandRepository) Save(ctx context.Context, a *archive.Archive) error {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return domainerrors.ErrTransactionNotFound
+	}
+
+	entries, err := marshalArchiveEntries(a.Entries)
+	if err != nil {
+		return err
+	}
+
+	query := `
+    INSERT INTO archives (id, owner_id, status, entries, size, s3_key, failure_reason,
+               created_at, updated_at, expires_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+    ON CONFLICT (id) DO UPDATE
+    SET status = EXCLUDED.status,
+        failure_reason = EXCLUDED.failure_reason,
+        updated_at = EXCLUDED.updated_at,
+        expires_at = EXCLUDED.expires_at
+    `
+	_, err = tx.ExecContext(ctx, query,
+		a.ID,
+		a.OwnerID,
+		a.Status.String(),
+		entries,
+		a.Size,
+		a.S3Key,
+		a.FailureReason,
+		a.CreatedAt,
+		a.UpdatedAt,
+		a.ExpiresAt,
+	)
+	return err
+}
+
+// ClaimPending строки блокируются с SKIP LOCKED, поэтому несколько воркеров не соберут один архив дважды
+func (r *ArchiveCommandRepository) ClaimPending(ctx context.Context, staleBefore time.Time, limit int) ([]*archive.Archive, error) {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return nil, domainerrors.ErrTransactionNotFound
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+        UPDATE archives
+        SET status = 'building', updated_at = NOW()
+        WHERE id IN (
+            SELECT id FROM archives
+            WHERE status = 'pending' OR (status = 'building' AND updated_at < $1)
+            ORDER BY created_at
+            LIMIT $2
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING id, owner_id, status, entries, size, s3_key, failure_reason,
+                  created_at, updated_at, expires_at
+    `, staleBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim archives: %w", err)
+	}
+	return scanArchives(rows)
+}
+
+func (r *ArchiveCommandRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return domainerrors.ErrTransactionNotFound
+	}
+
+	_, err := tx.ExecContext(ctx, `DELETE FROM archives WHERE id = $1`, id)
+	return err
+}
+
+type ArchiveQueryRepository struct {
+	db *sql.DB
+}
+
+func NewArchiveQueryRepository(db *sql.DB) *ArchiveQueryRepository {
+	return &ArchiveQueryRepository{db: db}
+}
+
+func (r *ArchiveQueryRepository) GetByID(ctx context.Context, id uuid.UUID) (*archive.Archive, error) {
+	row := r.db.QueryRowContext(ctx, `
+        SELECT id, owner_id, status, entries, size, s3_key, failure_reason,
+               created_at, updated_at, expires_at
+        FROM archives
+        WHERE id = $1
+    `, id)
+
+	a, err := scanArchive(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return a, err
+}
+
+// GetExpired архивы в сборке пропускаются, чтобы не удалить объект из-под воркера
+func (r *ArchiveQueryRepository) GetExpired(ctx context.Context, now time.Time, limit int) ([]*archive.Archive, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT id, owner_id, status, entries, size, s3_key, failure_reason,
+               created_at, updated_at, expires_at
+        FROM archives
+        WHERE status <> 'building' AND expires_at < $1
+        ORDER BY expires_at
+        LIMIT $2
+    `, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query archives: %w", err)
+	}
+	return scanArchives(rows)
+}
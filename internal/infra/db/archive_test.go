@@ -0,0 +1,154 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/archive"
+	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
+)
+
+var archiveColumns = []string{
+	"id", "owner_id", "status", "entries", "size", "s3_key", "failure_reason",
+	"created_at", "updated_at", "expires_at",
+}
+
+func TestArchiveCommandRepository_Save_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewArchiveCommandRepository()
+
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	a := archive.NewArchive(uuid.New(), []archive.Entry{
+		{Name: "docs/", ModifiedAt: modified},
+		{Name: "docs/a.txt", S3Key: "files/a/b/v1/a.txt", Mime: "text/plain", Size: 10, ModifiedAt: modified},
+	}, time.Hour)
+
+	entries := `[{"name":"docs/","size":0,"modified_at":"2024-01-02T03:04:05Z"},` +
+		`{"name":"docs/a.txt","s3_key":"files/a/b/v1/a.txt","mime":"text/plain","size":10,"modified_at":"2024-01-02T03:04:05Z"}]`
+
+	mock.ExpectExec(`INSERT INTO archives`).
+		WithArgs(a.ID, a.OwnerID, "pending", []byte(entries), int64(10), a.S3Key, nil,
+			a.CreatedAt, a.UpdatedAt, a.ExpiresAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.Save(ctx, a)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArchiveCommandRepository_Save_NoTransaction(t *testing.T) {
+	repo := NewArchiveCommandRepository()
+
+	err := repo.Save(context.Background(), &archive.Archive{})
+	require.ErrorIs(t, err, domainerrors.ErrTransactionNotFound)
+}
+
+func TestArchiveCommandRepository_ClaimPending_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewArchiveCommandRepository()
+
+	id, ownerID := uuid.New(), uuid.New()
+	now := time.Now()
+	staleBefore := now.Add(-time.Hour)
+
+	mock.ExpectQuery(`UPDATE archives\s+SET status = 'building'.*FOR UPDATE SKIP LOCKED`).
+		WithArgs(staleBefore, 10).
+		WillReturnRows(sqlmock.NewRows(archiveColumns).
+			AddRow(id, ownerID, "building", []byte(`[{"name":"a.txt","s3_key":"files/x/y/v1/a.txt","size":3}]`),
+				int64(3), "archives/x/y.zip", nil, now, now, now.Add(time.Hour)))
+
+	archives, err := repo.ClaimPending(ctx, staleBefore, 10)
+	require.NoError(t, err)
+	require.Len(t, archives, 1)
+	require.Equal(t, id, archives[0].ID)
+	require.True(t, archives[0].Status.Equal(archive.StatusBuilding))
+	require.Equal(t, []archive.Entry{{Name: "a.txt", S3Key: "files/x/y/v1/a.txt", Size: 3}}, archives[0].Entries)
+	require.Nil(t, archives[0].FailureReason)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArchiveQueryRepository_GetByID_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewArchiveQueryRepository(sqlDB)
+
+	id, ownerID := uuid.New(), uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT id, owner_id, status, entries, size, s3_key, failure_reason`).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(archiveColumns).
+			AddRow(id, ownerID, "failed", []byte(`[]`), int64(0), "archives/x/y.zip", "object not found",
+				now, now, now.Add(time.Hour)))
+
+	a, err := repo.GetByID(context.Background(), id)
+	require.NoError(t, err)
+	require.NotNil(t, a)
+	require.True(t, a.Status.Equal(archive.StatusFailed))
+	require.NotNil(t, a.FailureReason)
+	require.Equal(t, "object not found", *a.FailureReason)
+	require.Empty(t, a.Entries)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArchiveQueryRepository_GetByID_NoRows(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewArchiveQueryRepository(sqlDB)
+	id := uuid.New()
+
+	mock.ExpectQuery(`SELECT id, owner_id, status`).
+		WithArgs(id).
+		WillReturnError(sql.ErrNoRows)
+
+	a, err := repo.GetByID(context.Background(), id)
+	require.NoError(t, err)
+	require.Nil(t, a)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArchiveQueryRepository_GetExpired_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewArchiveQueryRepository(sqlDB)
+	now := time.Now()
+
+	mock.ExpectQuery(`FROM archives\s+WHERE status <> 'building' AND expires_at < \$1`).
+		WithArgs(now, 50).
+		WillReturnRows(sqlmock.NewRows(archiveColumns).
+			AddRow(uuid.New(), uuid.New(), "ready", []byte(`[]`), int64(0), "archives/x/y.zip", nil,
+				now, now, now.Add(-time.Minute)))
+
+	archives, err := repo.GetExpired(context.Background(), now, 50)
+	require.NoError(t, err)
+	require.Len(t, archives, 1)
+	require.True(t, archives[0].IsReady())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/yourusername/cloud-file-storage/internal/domain/blob"
+	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+)
+
+type BlobCommandRepository struct {
+}
+
+func NewBlobCommandRepository() *BlobCommandRepository {
+	return &BlobCommandRepository{}
+}
+
+// Acquire атомарно создаёт blob или увеличивает счётчик ссылок существующего
+func (r *BlobCommandRepository) Acquire(ctx context.Context, b *blob.Blob) (*blob.Blob, error) {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return nil, domainerrors.ErrTransactionNotFound
+	}
+
+	query := `
+    INSERT INTO blobs (hash, s3_key, size, ref_count, created_at, updated_at)
+    VALUES ($1, $2, $3, 1, $4, $5)
+    ON CONFLICT (hash) DO UPDATE 
+    SET ref_count = blobs.ref_count + 1, 
+        updated_at = EXCLUDED.updated_at
+    RETURNING hash, s3_key, size, ref_count, created_at, updated_at
+    `
+	row := tx.QueryRowContext(ctx, query,
+		b.Hash.String(),
+		b.S3Key.String(),
+		b.Size,
+		b.CreatedAt,
+		b.UpdatedAt,
+	)
+
+	var acquired blob.Blob
+	var hash, s3Key string
+	if err := row.Scan(&hash, &s3Key, &acquired.Size, &acquired.RefCount, &acquired.CreatedAt, &acquired.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	var err error
+	acquired.Hash, err = file_version.NewContentHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	acquired.S3Key, err = file_version.NewS3Key(s3Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &acquired, nil
+}
+
+// Release уменьшает счётчик и удаляет blob на последней ссылке. Строка остаётся
+// заблокированной до коммита, поэтому параллельный Acquire не получит удаляемый объект.
+func (r *BlobCommandRepository) Release(ctx context.Context, hash file_version.ContentHash) (bool, error) {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return false, domainerrors.ErrTransactionNotFound
+	}
+
+	var refCount int
+	err := tx.QueryRowContext(ctx,
+		`UPDATE blobs SET ref_count = ref_count - 1 WHERE hash = $1 RETURNING ref_count`,
+		hash.String(),
+	).Scan(&refCount)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if refCount > 0 {
+		return false, nil
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM blobs WHERE hash = $1`, hash.String())
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/blob"
+	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+)
+
+func newTestBlob() *blob.Blob {
+	hash, _ := file_version.NewContentHash(strings.Repeat("0f", 32))
+	key, _ := file_version.NewS3Key("files/a/b/v1/setup.exe")
+	return blob.NewBlob(hash, key, 1024)
+}
+
+func TestBlobCommandRepository_Acquire_Existing(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewBlobCommandRepository()
+	b := newTestBlob()
+	created := time.Now().Add(-time.Hour)
+
+	mock.ExpectQuery(`INSERT INTO blobs`).
+		WithArgs(b.Hash.String(), "files/a/b/v1/setup.exe", int64(1024), b.CreatedAt, b.UpdatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"hash", "s3_key", "size", "ref_count", "created_at", "updated_at"}).
+			AddRow(b.Hash.String(), "files/x/y/v1/first.exe", 1024, 2, created, b.UpdatedAt))
+
+	acquired, err := repo.Acquire(ctx, b)
+	require.NoError(t, err)
+	require.Equal(t, "files/x/y/v1/first.exe", acquired.S3Key.String())
+	require.Equal(t, 2, acquired.RefCount)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBlobCommandRepository_Release(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewBlobCommandRepository()
+	hash := newTestBlob().Hash
+
+	mock.ExpectQuery(`UPDATE blobs SET ref_count = ref_count - 1`).
+		WithArgs(hash.String()).
+		WillReturnRows(sqlmock.NewRows([]string{"ref_count"}).AddRow(1))
+
+	last, err := repo.Release(ctx, hash)
+	require.NoError(t, err)
+	require.False(t, last)
+
+	mock.ExpectQuery(`UPDATE blobs SET ref_count = ref_count - 1`).
+		WithArgs(hash.String()).
+		WillReturnRows(sqlmock.NewRows([]string{"ref_count"}).AddRow(0))
+	mock.ExpectExec(`DELETE FROM blobs`).
+		WithArgs(hash.String()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	last, err = repo.Release(ctx, hash)
+	require.NoError(t, err)
+	require.True(t, last)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBlobCommandRepository_NoTransaction(t *testing.T) {
+	repo := NewBlobCommandRepository()
+
+	_, err := repo.Acquire(context.Background(), newTestBlob())
+	require.ErrorIs(t, err, domainerrors.ErrTransactionNotFound)
+
+	_, err = repo.Release(context.Background(), newTestBlob().Hash)
+	require.ErrorIs(t, err, domainerrors.ErrTransactionNotFound)
+}
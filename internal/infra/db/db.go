@@ -1,5 +1,36 @@
 package db
 
+import (
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
 type scannable interface {
 	Scan(dest ...any) error
 }
+
+// scanIDs читает столбец id из rows и закрывает их
+func scanIDs(rows *sql.Rows) ([]uuid.UUID, error) {
+	defer rows.Close()
+
+	ids := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// nullUUID uuid.Nil пишется как NULL
+func nullUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
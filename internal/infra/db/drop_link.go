@@ -0,0 +1,203 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
+	"github.com/yourusername/cloud-file-storage/internal/domain/drop_link"
+)
+
+type DropLinkCommandRepository struct {
+}
+
+func NewDropLinkCommandRepository() *DropLinkCommandRepository {
+	return &DropLinkCommandRepository{}
+}
+
+func (r *DropLinkCommandRepository) Save(ctx context.Context, d *drop_link.DropLink) error {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return domainerrors.ErrTransactionNotFound
+	}
+
+	// Счётчики меняет только ReserveUpload, повторное сохранение их не затирает
+	query := `
+    INSERT INTO drop_links (id, owner_id, token_hash, folder_id, file_id,
+               max_files, max_total_size, allowed_mimes, file_count, total_size,
+               created_at, updated_at, expired_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+    ON CONFLICT (id) DO UPDATE
+    SET token_hash = $3, folder_id = $4, file_id = $5,
+        max_files = $6, max_total_size = $7, allowed_mimes = $8,
+        updated_at = $12, expired_at = $13
+    `
+	_, err := tx.ExecContext(ctx, query,
+		d.ID,
+		d.OwnerID,
+		d.TokenHash,
+		d.FolderID,
+		d.FileID,
+		d.MaxFiles,
+		d.MaxTotalSize,
+		pq.Array(d.AllowedMimes),
+		d.FileCount,
+		d.TotalSize,
+		d.CreatedAt,
+		d.UpdatedAt,
+		d.ExpiredAt,
+	)
+	return err
+}
+
+func (r *DropLinkCommandRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return domainerrors.ErrTransactionNotFound
+	}
+
+	_, err := tx.ExecContext(ctx, `DELETE FROM drop_links WHERE id = $1`, id)
+	return err
+}
+
+func (r *DropLinkCommandRepository) ReserveUpload(ctx context.Context, id uuid.UUID, size int64) (bool, error) {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return false, domainerrors.ErrTransactionNotFound
+	}
+
+	// Условие в WHERE не даёт параллельным загрузкам превысить лимиты
+	res, err := tx.ExecContext(ctx, `
+        UPDATE drop_links
+        SET file_count = file_count + 1, total_size = total_size + $2
+        WHERE id = $1
+          AND expired_at > NOW()
+          AND (max_files IS NULL OR file_count < max_files)
+          AND (max_total_size IS NULL OR total_size + $2 <= max_total_size)
+    `, id, size)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+type DropLinkQueryRepository struct {
+	db *sql.DB
+}
+
+func NewDropLinkQueryRepository(db *sql.DB) *DropLinkQueryRepository {
+	return &DropLinkQueryRepository{db: db}
+}
+
+func scanDropLink(scanner scannable) (*drop_link.DropLink, error) {
+	var d drop_link.DropLink
+	var folderID, fileID uuid.NullUUID
+	var maxFiles, maxTotalSize sql.NullInt64
+	var allowedMimes pq.StringArray
+
+	if err := scanner.Scan(
+		&d.ID,
+		&d.OwnerID,
+		&d.TokenHash,
+		&folderID,
+		&fileID,
+		&maxFiles,
+		&maxTotalSize,
+		&allowedMimes,
+		&d.FileCount,
+		&d.TotalSize,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+		&d.ExpiredAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if folderID.Valid {
+		d.FolderID = &folderID.UUID
+	}
+	if fileID.Valid {
+		d.FileID = &fileID.UUID
+	}
+	if maxFiles.Valid {
+		m := int(maxFiles.Int64)
+		d.MaxFiles = &m
+	}
+	if maxTotalSize.Valid {
+		d.MaxTotalSize = &maxTotalSize.Int64
+	}
+	d.AllowedMimes = []string(allowedMimes)
+	if d.AllowedMimes == nil {
+		d.AllowedMimes = []string{}
+	}
+
+	return &d, nil
+}
+
+func (r *DropLinkQueryRepository) GetByID(ctx context.Context, id uuid.UUID) (*drop_link.DropLink, error) {
+	row := r.db.QueryRowContext(ctx, `
+        SELECT id, owner_id, token_hash, folder_id, file_id,
+               max_files, max_total_size, allowed_mimes, file_count, total_size,
+               created_at, updated_at, expired_at
+        FROM drop_links
+        WHERE id = $1
+    `, id)
+
+	d, err := scanDropLink(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return d, err
+}
+
+func (r *DropLinkQueryRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*drop_link.DropLink, error) {
+	row := r.db.QueryRowContext(ctx, `
+        SELECT id, owner_id, token_hash, folder_id, file_id,
+               max_files, max_total_size, allowed_mimes, file_count, total_size,
+               created_at, updated_at, expired_at
+        FROM drop_links
+        WHERE token_hash = $1
+    `, tokenHash)
+
+	d, err := scanDropLink(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return d, err
+}
+
+func (r *DropLinkQueryRepository) GetByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]*drop_link.DropLink, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT id, owner_id, token_hash, folder_id, file_id,
+               max_files, max_total_size, allowed_mimes, file_count, total_size,
+               created_at, updated_at, expired_at
+        FROM drop_links
+        WHERE owner_id = $1
+        ORDER BY created_at DESC
+    `, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := make([]*drop_link.DropLink, 0)
+	for rows.Next() {
+		d, err := scanDropLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
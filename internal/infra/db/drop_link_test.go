@@ -0,0 +1,191 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
+	"github.com/yourusername/cloud-file-storage/internal/domain/drop_link"
+)
+
+var dropLinkColumns = []string{
+	"id", "owner_id", "token_hash", "folder_id", "file_id",
+	"max_files", "max_total_size", "allowed_mimes", "file_count", "total_size",
+	"created_at", "updated_at", "expired_at",
+}
+
+func TestDropLinkCommandRepository_Save_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewDropLinkCommandRepository()
+
+	folderID := uuid.New()
+	maxFiles := 3
+	d := drop_link.NewDropLink(uuid.New(), "token123", time.Now().Add(time.Hour))
+	require.NoError(t, d.SetTarget(&folderID, nil))
+	require.NoError(t, d.SetLimits(&maxFiles, nil, []string{"image/*", "Application/PDF"}))
+
+	mock.ExpectExec(`INSERT INTO drop_links`).
+		WithArgs(
+			d.ID,
+			d.OwnerID,
+			d.TokenHash,
+			d.FolderID,
+			d.FileID,
+			d.MaxFiles,
+			d.MaxTotalSize,
+			pq.Array([]string{"image/*", "application/pdf"}),
+			0,
+			int64(0),
+			d.CreatedAt,
+			d.UpdatedAt,
+			d.ExpiredAt,
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.Save(ctx, d)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDropLinkCommandRepository_Save_NoTransaction(t *testing.T) {
+	repo := NewDropLinkCommandRepository()
+
+	err := repo.Save(context.Background(), &drop_link.DropLink{})
+	require.ErrorIs(t, err, domainerrors.ErrTransactionNotFound)
+}
+
+func TestDropLinkCommandRepository_ReserveUpload_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewDropLinkCommandRepository()
+	id := uuid.New()
+
+	mock.ExpectExec(`UPDATE drop_links\s+SET file_count = file_count \+ 1, total_size = total_size \+ \$2`).
+		WithArgs(id, int64(1024)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ok, err := repo.ReserveUpload(ctx, id, 1024)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDropLinkCommandRepository_ReserveUpload_LimitReached(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewDropLinkCommandRepository()
+	id := uuid.New()
+
+	mock.ExpectExec(`UPDATE drop_links`).
+		WithArgs(id, int64(1024)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ok, err := repo.ReserveUpload(ctx, id, 1024)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDropLinkCommandRepository_ReserveUpload_NoTransaction(t *testing.T) {
+	repo := NewDropLinkCommandRepository()
+
+	_, err := repo.ReserveUpload(context.Background(), uuid.New(), 1)
+	require.ErrorIs(t, err, domainerrors.ErrTransactionNotFound)
+}
+
+func TestDropLinkQueryRepository_GetByToken_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewDropLinkQueryRepository(sqlDB)
+
+	id := uuid.New()
+	ownerID := uuid.New()
+	fileID := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT id, owner_id, token_hash, folder_id, file_id,`).
+		WithArgs("token123").
+		WillReturnRows(sqlmock.NewRows(dropLinkColumns).
+			AddRow(id, ownerID, "token123", nil, fileID, nil, 4096, "{image/*,application/pdf}", 1, 1024, now, now, now.Add(time.Hour)))
+
+	d, err := repo.GetByTokenHash(context.Background(), "token123")
+	require.NoError(t, err)
+	require.NotNil(t, d)
+	require.Nil(t, d.FolderID)
+	require.Equal(t, fileID, *d.FileID)
+	require.Nil(t, d.MaxFiles)
+	require.Equal(t, int64(4096), *d.MaxTotalSize)
+	require.Equal(t, []string{"image/*", "application/pdf"}, d.AllowedMimes)
+	require.True(t, d.AllowsMime("image/png"))
+	require.False(t, d.AllowsMime("text/plain"))
+	require.Equal(t, 1, d.FileCount)
+	require.Equal(t, int64(1024), d.TotalSize)
+}
+
+func TestDropLinkQueryRepository_GetByToken_NoRows(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewDropLinkQueryRepository(sqlDB)
+
+	mock.ExpectQuery(`SELECT id, owner_id, token_hash`).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	d, err := repo.GetByTokenHash(context.Background(), "missing")
+	require.NoError(t, err)
+	require.Nil(t, d)
+}
+
+func TestDropLinkQueryRepository_GetByOwnerID_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewDropLinkQueryRepository(sqlDB)
+	ownerID := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery(`FROM drop_links\s+WHERE owner_id = \$1\s+ORDER BY created_at DESC`).
+		WithArgs(ownerID).
+		WillReturnRows(sqlmock.NewRows(dropLinkColumns).
+			AddRow(uuid.New(), ownerID, "a", nil, nil, 5, nil, "{}", 0, 0, now, now, now.Add(time.Hour)))
+
+	links, err := repo.GetByOwnerID(context.Background(), ownerID)
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	require.Equal(t, 5, *links[0].MaxFiles)
+	require.Empty(t, links[0].AllowedMimes)
+	require.True(t, links[0].AllowsMime("text/plain"))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	uuid "github.com/google/uuid"
 	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
@@ -28,10 +29,17 @@ func (r *FileCommandRepository) Save(ctx context.Context, v *file.File) error {
 		}
 	}
 
+	var checksumAlgorithm, checksum sql.NullString
+	if v.Checksum != nil {
+		checksumAlgorithm = sql.NullString{String: string(v.Checksum.Algorithm()), Valid: true}
+		checksum = sql.NullString{String: v.Checksum.Value(), Valid: true}
+	}
+
 	query := `
     INSERT INTO files (id, name, preview_s3_key, mime, status, size, version_num,
-               owner_id, uploaded_by_session_id, created_at, updated_at)
-    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+               owner_id, uploaded_by_session_id, folder_id, created_at, updated_at, deleted_at,
+               checksum_algorithm, checksum)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
     ON CONFLICT (id) DO UPDATE 
     SET name = EXCLUDED.name, 
         preview_s3_key = EXCLUDED.preview_s3_key, 
@@ -40,7 +48,11 @@ func (r *FileCommandRepository) Save(ctx context.Context, v *file.File) error {
         size = EXCLUDED.size, 
         version_num = EXCLUDED.version_num,
         uploaded_by_session_id = EXCLUDED.uploaded_by_session_id, 
-        updated_at = EXCLUDED.updated_at
+        folder_id = EXCLUDED.folder_id,
+        updated_at = EXCLUDED.updated_at,
+        deleted_at = EXCLUDED.deleted_at,
+        checksum_algorithm = EXCLUDED.checksum_algorithm,
+        checksum = EXCLUDED.checksum
     `
 	_, err := tx.ExecContext(ctx, query,
 		v.ID,
@@ -51,9 +63,13 @@ func (r *FileCommandRepository) Save(ctx context.Context, v *file.File) error {
 		v.Size.Uint64(),
 		v.VersionNum.Int(),
 		v.OwnerID,
-		v.UploadedBySessionId,
+		nullUUID(v.UploadedBySessionId),
+		v.FolderID,
 		v.CreatedAt,
 		v.UpdatedAt,
+		v.DeletedAt,
+		checksumAlgorithm,
+		checksum,
 	)
 	return err
 }
@@ -88,6 +104,9 @@ func scanFile(scanner scannable) (*file.File, error) {
 	var previewS3KeyNullStr sql.NullString
 	var size uint64
 	var versionNum int
+	var folderID uuid.NullUUID
+	var deletedAt sql.NullTime
+	var checksumAlgorithm, checksum sql.NullString
 
 	if err := scanner.Scan(
 		&f.ID,
@@ -99,12 +118,30 @@ func scanFile(scanner scannable) (*file.File, error) {
 		&versionNum,
 		&f.OwnerID,
 		&f.UploadedBySessionId,
+		&folderID,
 		&f.CreatedAt,
 		&f.UpdatedAt,
+		&deletedAt,
+		&checksumAlgorithm,
+		&checksum,
 	); err != nil {
 		return nil, err
 	}
 
+	if folderID.Valid {
+		f.FolderID = &folderID.UUID
+	}
+	if deletedAt.Valid {
+		f.DeletedAt = &deletedAt.Time
+	}
+	if checksumAlgorithm.Valid && checksum.Valid {
+		c, err := file_version.NewChecksum(checksumAlgorithm.String, checksum.String)
+		if err != nil {
+			return nil, err
+		}
+		f.Checksum = &c
+	}
+
 	var err error
 
 	f.Name, err = file.NewFileName(name)
@@ -148,7 +185,8 @@ func scanFile(scanner scannable) (*file.File, error) {
 func (r *FileQueryRepository) GetByID(ctx context.Context, id uuid.UUID) (*file.File, error) {
 	row := r.db.QueryRowContext(ctx, `
         SELECT id, name, preview_s3_key, mime, status, size, version_num,
-               owner_id, uploaded_by_session_id, created_at, updated_at
+               owner_id, uploaded_by_session_id, folder_id, created_at, updated_at, deleted_at,
+               checksum_algorithm, checksum
         FROM files
         WHERE id = $1
     `, id)
@@ -163,9 +201,10 @@ func (r *FileQueryRepository) GetByID(ctx context.Context, id uuid.UUID) (*file.
 func (r *FileQueryRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*file.File, error) {
 	rows, err := r.db.QueryContext(ctx, `
         SELECT id, name, preview_s3_key, mime, status, size, version_num,
-               owner_id, uploaded_by_session_id, created_at, updated_at
+               owner_id, uploaded_by_session_id, folder_id, created_at, updated_at, deleted_at,
+               checksum_algorithm, checksum
         FROM files
-        WHERE owner_id = $1
+        WHERE owner_id = $1 AND deleted_at IS NULL
         ORDER BY created_at DESC
     `, userID)
 	if err != nil {
@@ -193,7 +232,8 @@ func (r *FileQueryRepository) GetByUserID(ctx context.Context, userID uuid.UUID)
 func (r *FileQueryRepository) GetAll(ctx context.Context) ([]*file.File, error) {
 	rows, err := r.db.QueryContext(ctx, `
         SELECT id, name, preview_s3_key, mime, status, size, version_num,
-               owner_id, uploaded_by_session_id, created_at, updated_at
+               owner_id, uploaded_by_session_id, folder_id, created_at, updated_at, deleted_at,
+               checksum_algorithm, checksum
         FROM files
     `)
 	if err != nil {
@@ -224,7 +264,7 @@ func (r *FileQueryRepository) SearchByName(ctx context.Context, userID uuid.UUID
 	countErr := r.db.QueryRowContext(ctx, `
         SELECT COUNT(*)
         FROM files
-        WHERE owner_id = $1 AND LOWER(name) LIKE LOWER($2)
+        WHERE owner_id = $1 AND deleted_at IS NULL AND LOWER(name) LIKE LOWER($2)
     `, userID, "%"+query+"%").Scan(&total)
 	if countErr != nil {
 		return nil, 0, fmt.Errorf("failed to count files: %w", countErr)
@@ -232,9 +272,10 @@ func (r *FileQueryRepository) SearchByName(ctx context.Context, userID uuid.UUID
 
 	rows, err := r.db.QueryContext(ctx, `
         SELECT id, name, preview_s3_key, mime, status, size, version_num,
-               owner_id, uploaded_by_session_id, created_at, updated_at
+               owner_id, uploaded_by_session_id, folder_id, created_at, updated_at, deleted_at,
+               checksum_algorithm, checksum
         FROM files
-        WHERE owner_id = $1 AND LOWER(name) LIKE LOWER($2)
+        WHERE owner_id = $1 AND deleted_at IS NULL AND LOWER(name) LIKE LOWER($2)
         ORDER BY created_at DESC
         LIMIT $3 OFFSET $4
     `, userID, "%"+query+"%", limit, skip)
@@ -259,3 +300,106 @@ func (r *FileQueryRepository) SearchByName(ctx context.Context, userID uuid.UUID
 
 	return files, total, nil
 }
+
+// GetByFolderID возвращает все файлы, лежащие непосредственно в папке
+func (r *FileQueryRepository) GetByFolderID(ctx context.Context, folderID uuid.UUID) ([]*file.File, error) {
+	return r.queryFiles(ctx, `
+        SELECT id, name, preview_s3_key, mime, status, size, version_num,
+               owner_id, uploaded_by_session_id, folder_id, created_at, updated_at, deleted_at,
+               checksum_algorithm, checksum
+        FROM files
+        WHERE folder_id = $1 AND deleted_at IS NULL
+        ORDER BY created_at DESC
+    `, folderID)
+}
+
+// SearchInFolder поиск файлов пользователя по названию внутри папки (nil — корень)
+func (r *FileQueryRepository) SearchInFolder(ctx context.Context, userID uuid.UUID, folderID *uuid.UUID, query string, limit int, skip int) ([]*file.File, int64, error) {
+	var total int64
+	countErr := r.db.QueryRowContext(ctx, `
+        SELECT COUNT(*)
+        FROM files
+        WHERE owner_id = $1 AND deleted_at IS NULL AND folder_id IS NOT DISTINCT FROM $2 AND LOWER(name) LIKE LOWER($3)
+    `, userID, folderID, "%"+query+"%").Scan(&total)
+	if countErr != nil {
+		return nil, 0, fmt.Errorf("failed to count files: %w", countErr)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT id, name, preview_s3_key, mime, status, size, version_num,
+               owner_id, uploaded_by_session_id, folder_id, created_at, updated_at, deleted_at,
+               checksum_algorithm, checksum
+        FROM files
+        WHERE owner_id = $1 AND deleted_at IS NULL AND folder_id IS NOT DISTINCT FROM $2 AND LOWER(name) LIKE LOWER($3)
+        ORDER BY created_at DESC
+        LIMIT $4 OFFSET $5
+    `, userID, folderID, "%"+query+"%", limit, skip)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query files: %w", err)
+	}
+	defer rows.Close()
+
+	files := make([]*file.File, 0)
+
+	for rows.Next() {
+		f, err := scanFile(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan file: %w", err)
+		}
+		files = append(files, f)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return files, total, nil
+}
+
+func (r *FileQueryRepository) GetTrashedByUserID(ctx context.Context, userID uuid.UUID) ([]*file.File, error) {
+	return r.queryFiles(ctx, `
+        SELECT id, name, preview_s3_key, mime, status, size, version_num,
+               owner_id, uploaded_by_session_id, folder_id, created_at, updated_at, deleted_at,
+               checksum_algorithm, checksum
+        FROM files
+        WHERE owner_id = $1 AND deleted_at IS NOT NULL
+        ORDER BY deleted_at DESC
+    `, userID)
+}
+
+// GetTrashedBefore возвращает файлы из корзины, срок хранения которых истёк
+func (r *FileQueryRepository) GetTrashedBefore(ctx context.Context, before time.Time, limit int) ([]*file.File, error) {
+	return r.queryFiles(ctx, `
+        SELECT id, name, preview_s3_key, mime, status, size, version_num,
+               owner_id, uploaded_by_session_id, folder_id, created_at, updated_at, deleted_at,
+               checksum_algorithm, checksum
+        FROM files
+        WHERE deleted_at IS NOT NULL AND deleted_at < $1
+        ORDER BY deleted_at
+        LIMIT $2
+    `, before, limit)
+}
+
+func (r *FileQueryRepository) queryFiles(ctx context.Context, query string, args ...any) ([]*file.File, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query files: %w", err)
+	}
+	defer rows.Close()
+
+	files := make([]*file.File, 0)
+
+	for rows.Next() {
+		f, err := scanFile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan file: %w", err)
+		}
+		files = append(files, f)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return files, nil
+}
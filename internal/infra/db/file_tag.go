@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file"
+)
+
+type FileTagCommandRepository struct {
+}
+
+func NewFileTagCommandRepository() *FileTagCommandRepository {
+	return &FileTagCommandRepository{}
+}
+
+func (r *FileTagCommandRepository) Add(ctx context.Context, fileID uuid.UUID, tags []file.Tag) error {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return domainerrors.ErrTransactionNotFound
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, `
+        INSERT INTO file_tags (file_id, tag)
+        SELECT $1, unnest($2::text[])
+        ON CONFLICT (file_id, tag) DO NOTHING
+    `, fileID, pq.Array(file.TagStrings(tags)))
+	return err
+}
+
+func (r *FileTagCommandRepository) Remove(ctx context.Context, fileID uuid.UUID, tags []file.Tag) error {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return domainerrors.ErrTransactionNotFound
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, `
+        DELETE FROM file_tags
+        WHERE file_id = $1 AND tag = ANY($2::text[])
+    `, fileID, pq.Array(file.TagStrings(tags)))
+	return err
+}
+
+type FileTagQueryRepository struct {
+	db *sql.DB
+}
+
+func NewFileTagQueryRepository(db *sql.DB) *FileTagQueryRepository {
+	return &FileTagQueryRepository{db: db}
+}
+
+func (r *FileTagQueryRepository) GetByFileID(ctx context.Context, fileID uuid.UUID) ([]file.Tag, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT tag
+        FROM file_tags
+        WHERE file_id = $1
+        ORDER BY tag
+    `, fileID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make([]file.Tag, 0)
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			return nil, err
+		}
+		tag, err := file.NewTag(raw)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
@@ -0,0 +1,125 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file"
+)
+
+func mustTags(t *testing.T, raw ...string) []file.Tag {
+	tags, err := file.NewTags(raw)
+	require.NoError(t, err)
+	return tags
+}
+
+func TestFileTagCommandRepository_Add_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewFileTagCommandRepository()
+	fileID := uuid.New()
+
+	mock.ExpectExec(`INSERT INTO file_tags \(file_id, tag\)\s+SELECT \$1, unnest\(\$2::text\[\]\)\s+ON CONFLICT \(file_id, tag\) DO NOTHING`).
+		WithArgs(fileID, pq.Array([]string{"work", "q3"})).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err = repo.Add(ctx, fileID, mustTags(t, " Work ", "q3", "WORK"))
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFileTagCommandRepository_Add_Empty(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	err = NewFileTagCommandRepository().Add(ctx, uuid.New(), nil)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFileTagCommandRepository_Remove_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewFileTagCommandRepository()
+	fileID := uuid.New()
+
+	mock.ExpectExec(`DELETE FROM file_tags\s+WHERE file_id = \$1 AND tag = ANY\(\$2::text\[\]\)`).
+		WithArgs(fileID, pq.Array([]string{"draft"})).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.Remove(ctx, fileID, mustTags(t, "Draft"))
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFileTagCommandRepository_NoTransaction(t *testing.T) {
+	repo := NewFileTagCommandRepository()
+
+	err := repo.Add(context.Background(), uuid.New(), mustTags(t, "work"))
+	require.ErrorIs(t, err, domainerrors.ErrTransactionNotFound)
+
+	err = repo.Remove(context.Background(), uuid.New(), mustTags(t, "work"))
+	require.ErrorIs(t, err, domainerrors.ErrTransactionNotFound)
+}
+
+func TestFileTagQueryRepository_GetByFileID_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewFileTagQueryRepository(sqlDB)
+	fileID := uuid.New()
+
+	mock.ExpectQuery(`SELECT tag\s+FROM file_tags\s+WHERE file_id = \$1\s+ORDER BY tag`).
+		WithArgs(fileID).
+		WillReturnRows(sqlmock.NewRows([]string{"tag"}).AddRow("q3").AddRow("work"))
+
+	tags, err := repo.GetByFileID(context.Background(), fileID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"q3", "work"}, file.TagStrings(tags))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFileTagQueryRepository_GetByFileID_Empty(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewFileTagQueryRepository(sqlDB)
+	fileID := uuid.New()
+
+	mock.ExpectQuery(`SELECT tag\s+FROM file_tags`).
+		WithArgs(fileID).
+		WillReturnRows(sqlmock.NewRows([]string{"tag"}))
+
+	tags, err := repo.GetByFileID(context.Background(), fileID)
+	require.NoError(t, err)
+	require.Empty(t, tags)
+	require.NotNil(t, tags)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
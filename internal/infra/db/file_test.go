@@ -67,6 +67,9 @@ func TestFileCommandRepository_Save_Success(t *testing.T) {
 		CreatedAt:           now,
 		UpdatedAt:           now,
 	}
+	checksum, err := file_version.NewChecksum("crc32c", "yZRlqg==")
+	require.NoError(t, err)
+	f.Checksum = &checksum
 
 	mock.ExpectExec(`INSERT INTO files`).
 		WithArgs(
@@ -79,8 +82,12 @@ func TestFileCommandRepository_Save_Success(t *testing.T) {
 			f.VersionNum.Int(),
 			f.OwnerID,
 			f.UploadedBySessionId,
+			nil,
 			f.CreatedAt,
 			f.UpdatedAt,
+			nil,
+			"crc32c",
+			"yZRlqg==",
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -146,8 +153,9 @@ func TestFileQueryRepository_GetByID_Success(t *testing.T) {
 		WithArgs(id).
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "name", "preview_s3_key", "mime", "status", "size", "version_num",
-			"owner_id", "uploaded_by_session_id", "created_at", "updated_at",
-		}).AddRow(id, "file1.txt", "preview-key", "text/plain", "uploaded", 1024, 1, ownerID, sessionID, now, now))
+			"owner_id", "uploaded_by_session_id", "folder_id", "created_at", "updated_at", "deleted_at",
+			"checksum_algorithm", "checksum",
+		}).AddRow(id, "file1.txt", "preview-key", "text/plain", "uploaded", 1024, 1, ownerID, sessionID, nil, now, now, nil, "crc32c", "yZRlqg=="))
 
 	f, err := repo.GetByID(context.Background(), id)
 	require.NoError(t, err)
@@ -158,6 +166,7 @@ func TestFileQueryRepository_GetByID_Success(t *testing.T) {
 	require.Equal(t, "uploaded", f.Status.String())
 	require.Equal(t, uint64(1024), f.Size.Uint64())
 	require.Equal(t, 1, f.VersionNum.Int())
+	require.Equal(t, file_version.ChecksumCRC32C, f.Checksum.Algorithm())
 }
 
 func TestFileQueryRepository_GetByID_NoRows(t *testing.T) {
@@ -191,9 +200,10 @@ func TestFileQueryRepository_GetAll_Success(t *testing.T) {
 	mock.ExpectQuery(`SELECT id, name, preview_s3_key, mime, status, size, version_num,`).
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "name", "preview_s3_key", "mime", "status", "size", "version_num",
-			"owner_id", "uploaded_by_session_id", "created_at", "updated_at",
-		}).AddRow(uuid.New(), "file1.txt", "preview-key", "text/plain", "uploaded", 1024, 1, ownerID, sessionID, now, now).
-			AddRow(uuid.New(), "file2.txt", "preview-key2", "image/png", "uploaded", 2048, 2, ownerID, sessionID, now, now))
+			"owner_id", "uploaded_by_session_id", "folder_id", "created_at", "updated_at", "deleted_at",
+			"checksum_algorithm", "checksum",
+		}).AddRow(uuid.New(), "file1.txt", "preview-key", "text/plain", "uploaded", 1024, 1, ownerID, sessionID, nil, now, now, nil, nil, nil).
+			AddRow(uuid.New(), "file2.txt", "preview-key2", "image/png", "uploaded", 2048, 2, ownerID, sessionID, nil, now, now, nil, nil, nil))
 
 	files, err := repo.GetAll(context.Background())
 	require.NoError(t, err)
@@ -201,3 +211,57 @@ func TestFileQueryRepository_GetAll_Success(t *testing.T) {
 	require.Equal(t, "file1.txt", files[0].Name.String())
 	require.Equal(t, "file2.txt", files[1].Name.String())
 }
+
+func TestFileQueryRepository_GetByFolderID_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewFileQueryRepository(sqlDB)
+
+	now := time.Now()
+	ownerID := uuid.New()
+	sessionID := uuid.New()
+	folderID := uuid.New()
+
+	mock.ExpectQuery(`SELECT id, name, preview_s3_key, mime, status, size, version_num,`).
+		WithArgs(folderID).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "name", "preview_s3_key", "mime", "status", "size", "version_num",
+			"owner_id", "uploaded_by_session_id", "folder_id", "created_at", "updated_at", "deleted_at",
+			"checksum_algorithm", "checksum",
+		}).AddRow(uuid.New(), "file1.txt", nil, "text/plain", "uploaded", 1024, 1, ownerID, sessionID, folderID, now, now, nil, nil, nil))
+
+	files, err := repo.GetByFolderID(context.Background(), folderID)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.NotNil(t, files[0].FolderID)
+	require.Equal(t, folderID, *files[0].FolderID)
+	require.Nil(t, files[0].PreviewS3Key)
+}
+
+func TestFileQueryRepository_GetTrashedBefore_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewFileQueryRepository(sqlDB)
+
+	now := time.Now()
+	deletedAt := now.Add(-48 * time.Hour)
+	before := now.Add(-24 * time.Hour)
+
+	mock.ExpectQuery(`WHERE deleted_at IS NOT NULL AND deleted_at < \$1`).
+		WithArgs(before, 50).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "name", "preview_s3_key", "mime", "status", "size", "version_num",
+			"owner_id", "uploaded_by_session_id", "folder_id", "created_at", "updated_at", "deleted_at",
+			"checksum_algorithm", "checksum",
+		}).AddRow(uuid.New(), "old.txt", nil, "text/plain", "ready", 10, 1, uuid.New(), uuid.New(), nil, now, now, deletedAt, nil, nil))
+
+	files, err := repo.GetTrashedBefore(context.Background(), before, 50)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.True(t, files[0].IsTrashed())
+	require.Equal(t, deletedAt, *files[0].DeletedAt)
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	uuid "github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
 	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
 )
@@ -27,10 +29,41 @@ func (r *FileVersionCommandRepository) Save(ctx context.Context, v *file_version
 		}
 	}
 
+	var contentHash sql.NullString
+	if v.ContentHash != nil {
+		contentHash = sql.NullString{
+			String: v.ContentHash.String(),
+			Valid:  true,
+		}
+	}
+
+	var keyID sql.NullString
+	if v.KeyID != nil {
+		keyID = sql.NullString{
+			String: *v.KeyID,
+			Valid:  true,
+		}
+	}
+
+	var checksumAlgorithm, checksum sql.NullString
+	if v.Checksum != nil {
+		checksumAlgorithm = sql.NullString{String: string(v.Checksum.Algorithm()), Valid: true}
+		checksum = sql.NullString{String: v.Checksum.Value(), Valid: true}
+	}
+
+	var failureReason sql.NullString
+	if v.FailureReason != nil {
+		failureReason = sql.NullString{
+			String: *v.FailureReason,
+			Valid:  true,
+		}
+	}
+
 	query := `
     INSERT INTO file_versions (id, s3_key, preview_s3_key, mime, status, size, version_num,
-               file_id, uploaded_by_session_id, created_at, updated_at)
-    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+               file_id, uploaded_by_session_id, created_at, updated_at, content_hash, key_id,
+               checksum_algorithm, checksum, failure_reason, drop_link_id)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
     ON CONFLICT (id) DO UPDATE 
     SET s3_key = EXCLUDED.s3_key, 
         preview_s3_key = EXCLUDED.preview_s3_key, 
@@ -40,7 +73,13 @@ func (r *FileVersionCommandRepository) Save(ctx context.Context, v *file_version
         version_num = EXCLUDED.version_num,
         file_id = EXCLUDED.file_id,
         uploaded_by_session_id = EXCLUDED.uploaded_by_session_id, 
-        updated_at = EXCLUDED.updated_at
+        updated_at = EXCLUDED.updated_at,
+        content_hash = EXCLUDED.content_hash,
+        key_id = EXCLUDED.key_id,
+        checksum_algorithm = EXCLUDED.checksum_algorithm,
+        checksum = EXCLUDED.checksum,
+        failure_reason = EXCLUDED.failure_reason,
+        drop_link_id = EXCLUDED.drop_link_id
     `
 	_, err := tx.ExecContext(ctx, query,
 		v.ID,
@@ -51,13 +90,74 @@ func (r *FileVersionCommandRepository) Save(ctx context.Context, v *file_version
 		v.Size.Uint64(),
 		v.VersionNum.Int(),
 		v.FileId,
-		v.UploadedBySessionId,
+		nullUUID(v.UploadedBySessionId),
 		v.CreatedAt,
 		v.UpdatedAt,
+		contentHash,
+		keyID,
+		checksumAlgorithm,
+		checksum,
+		failureReason,
+		v.DropLinkID,
 	)
 	return err
 }
 
+// AttachBlob условным UPDATE защищает от двойного учёта ссылки, если загрузку завершают параллельно
+func (r *FileVersionCommandRepository) AttachBlob(ctx context.Context, id uuid.UUID, hash file_version.ContentHash, key file_version.S3Key) (bool, error) {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return false, domainerrors.ErrTransactionNotFound
+	}
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE file_versions SET content_hash = $2, s3_key = $3 WHERE id = $1 AND content_hash IS NULL`,
+		id, hash.String(), key.String(),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// MarkAbandoned условным UPDATE не даёт затереть версию, которую завершили после того, как её сочли брошенной
+func (r *FileVersionCommandRepository) MarkAbandoned(ctx context.Context, id uuid.UUID, reason string) (bool, error) {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return false, domainerrors.ErrTransactionNotFound
+	}
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE file_versions SET status = $2, size = 0, failure_reason = $3, updated_at = NOW() WHERE id = $1 AND status = $4 AND content_hash IS NULL`,
+		id, file_version.FileStatusFailed.String(), reason, file_version.FileStatusProcessing.String(),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// SetKeyID обновляет ключ у всех версий, ссылающихся на объект: после дедупликации их может быть несколько
+func (r *FileVersionCommandRepository) SetKeyID(ctx context.Context, s3Key file_version.S3Key, keyID string) error {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return domainerrors.ErrTransactionNotFound
+	}
+
+	_, err := tx.ExecContext(ctx, `UPDATE file_versions SET key_id = $2 WHERE s3_key = $1`, s3Key.String(), keyID)
+	return err
+}
+
 func (r *FileVersionCommandRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	tx, ok := ctx.Value("tx").(*sql.Tx)
 	if !ok {
@@ -86,6 +186,9 @@ func scanFileVersion(scanner scannable) (*file_version.FileVersion, error) {
 	var v file_version.FileVersion
 	var s3Key, mime, status string
 	var previewS3KeyNullStr sql.NullString // Используем NullString для nullable поля
+	var contentHashNullStr sql.NullString
+	var keyIDNullStr sql.NullString
+	var checksumAlgorithmNullStr, checksumNullStr, failureReasonNullStr sql.NullString
 	var size uint64
 	var versionNum int
 
@@ -103,6 +206,12 @@ func scanFileVersion(scanner scannable) (*file_version.FileVersion, error) {
 		&v.UploadedBySessionId,
 		&v.CreatedAt,
 		&v.UpdatedAt,
+		&contentHashNullStr,
+		&keyIDNullStr,
+		&checksumAlgorithmNullStr,
+		&checksumNullStr,
+		&failureReasonNullStr,
+		&v.DropLinkID,
 	); err != nil {
 		return nil, err
 	}
@@ -124,6 +233,30 @@ func scanFileVersion(scanner scannable) (*file_version.FileVersion, error) {
 		v.PreviewS3Key = nil
 	}
 
+	if contentHashNullStr.Valid {
+		contentHash, err := file_version.NewContentHash(contentHashNullStr.String)
+		if err != nil {
+			return nil, err
+		}
+		v.ContentHash = &contentHash
+	}
+
+	if keyIDNullStr.Valid {
+		v.KeyID = &keyIDNullStr.String
+	}
+
+	if checksumAlgorithmNullStr.Valid && checksumNullStr.Valid {
+		checksum, err := file_version.NewChecksum(checksumAlgorithmNullStr.String, checksumNullStr.String)
+		if err != nil {
+			return nil, err
+		}
+		v.Checksum = &checksum
+	}
+
+	if failureReasonNullStr.Valid {
+		v.FailureReason = &failureReasonNullStr.String
+	}
+
 	v.Mime, err = file_version.NewMimeType(mime)
 	if err != nil {
 		return nil, err
@@ -151,7 +284,8 @@ func (r *FileVersionQueryRepository) GetByID(ctx context.Context, id uuid.UUID)
 
 	row := r.db.QueryRowContext(ctx, `
         SELECT id, s3_key, preview_s3_key, mime, status, size, version_num,
-               file_id, uploaded_by_session_id, created_at, updated_at
+               file_id, uploaded_by_session_id, created_at, updated_at, content_hash, key_id,
+               checksum_algorithm, checksum, failure_reason, drop_link_id
         FROM file_versions
         WHERE id = $1
     `, id)
@@ -166,7 +300,8 @@ func (r *FileVersionQueryRepository) GetByID(ctx context.Context, id uuid.UUID)
 func (r *FileVersionQueryRepository) GetByFileID(ctx context.Context, fileID uuid.UUID) ([]*file_version.FileVersion, error) {
 	rows, err := r.db.QueryContext(ctx, `
         SELECT id, s3_key, preview_s3_key, mime, status, size, version_num,
-               file_id, uploaded_by_session_id, created_at, updated_at
+               file_id, uploaded_by_session_id, created_at, updated_at, content_hash, key_id,
+               checksum_algorithm, checksum, failure_reason, drop_link_id
         FROM file_versions
         WHERE file_id = $1
         ORDER BY version_num DESC
@@ -197,7 +332,8 @@ func (r *FileVersionQueryRepository) GetAll(ctx context.Context) ([]*file_versio
 
 	rows, err := r.db.QueryContext(ctx, `
         SELECT id, s3_key, preview_s3_key, mime, status, size, version_num,
-               file_id, uploaded_by_session_id, created_at, updated_at
+               file_id, uploaded_by_session_id, created_at, updated_at, content_hash, key_id,
+               checksum_algorithm, checksum, failure_reason, drop_link_id
         FROM file_versions
     `)
 	if err != nil {
@@ -225,7 +361,8 @@ func (r *FileVersionQueryRepository) GetAll(ctx context.Context) ([]*file_versio
 func (r *FileVersionQueryRepository) GetAllByStatus(ctx context.Context, status file_version.FileStatus) ([]*file_version.FileVersion, error) {
 	rows, err := r.db.QueryContext(ctx, `
         SELECT id, s3_key, preview_s3_key, mime, status, size, version_num,
-               file_id, uploaded_by_session_id, created_at, updated_at
+               file_id, uploaded_by_session_id, created_at, updated_at, content_hash, key_id,
+               checksum_algorithm, checksum, failure_reason, drop_link_id
         FROM file_versions WHERE status = $1 ORDER BY created_at DESC
     `, status.String())
 	if err != nil {
@@ -248,3 +385,175 @@ func (r *FileVersionQueryRepository) GetAllByStatus(ctx context.Context, status
 
 	return fileVersions, nil
 }
+
+// GetWithStaleKey возвращает загруженные версии, чей объект зашифрован не ключом keyID или не зашифрован вовсе.
+// Постраничный обход идёт по id после afterID.
+func (r *FileVersionQueryRepository) GetWithStaleKey(ctx context.Context, keyID string, afterID uuid.UUID, limit int) ([]*file_version.FileVersion, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT id, s3_key, preview_s3_key, mime, status, size, version_num,
+               file_id, uploaded_by_session_id, created_at, updated_at, content_hash, key_id,
+               checksum_algorithm, checksum, failure_reason, drop_link_id
+        FROM file_versions
+        WHERE key_id IS DISTINCT FROM $1 AND status <> $2 AND id > $3
+        ORDER BY id
+        LIMIT $4
+    `, keyID, file_version.FileStatusProcessing.String(), afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query file versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make([]*file_version.FileVersion, 0)
+	for rows.Next() {
+		v, err := scanFileVersion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan file version: %w", err)
+		}
+		versions = append(versions, v)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return versions, nil
+}
+
+// GetStored возвращает загруженные и готовые версии, чей объект должен лежать в хранилище.
+// Постраничный обход идёт по id после afterID.
+func (r *FileVersionQueryRepository) GetStored(ctx context.Context, afterID uuid.UUID, limit int) ([]*file_version.FileVersion, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT id, s3_key, preview_s3_key, mime, status, size, version_num,
+               file_id, uploaded_by_session_id, created_at, updated_at, content_hash, key_id,
+               checksum_algorithm, checksum, failure_reason, drop_link_id
+        FROM file_versions
+        WHERE status IN ($1, $2) AND id > $3
+        ORDER BY id
+        LIMIT $4
+    `, file_version.FileStatusUploaded.String(), file_version.FileStatusReady.String(), afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query file versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make([]*file_version.FileVersion, 0)
+	for rows.Next() {
+		v, err := scanFileVersion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan file version: %w", err)
+		}
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+// GetReferencedKeys возвращает ключи из keys, на которые ссылаются версии, их превью, blob
+// или незавершённая tus загрузка (хвосты tus лежат под ключом версии с суффиксом .tus-pending-N)
+func (r *FileVersionQueryRepository) GetReferencedKeys(ctx context.Context, keys []string) (map[string]bool, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT k FROM unnest($1::text[]) AS k
+        WHERE EXISTS (SELECT 1 FROM file_versions v WHERE v.s3_key = k)
+           OR EXISTS (SELECT 1 FROM file_versions v WHERE v.preview_s3_key = k)
+           OR EXISTS (SELECT 1 FROM blobs b WHERE b.s3_key = k)
+           OR EXISTS (SELECT 1 FROM tus_uploads t WHERE starts_with(k, t.s3_key || '.tus-pending-'))
+    `, pq.Array(keys))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query referenced keys: %w", err)
+	}
+	defer rows.Close()
+
+	referenced := make(map[string]bool)
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to scan key: %w", err)
+		}
+		referenced[key] = true
+	}
+	return referenced, rows.Err()
+}
+
+// GetProcessingByS3Key возвращает версию, ожидающую загрузки объекта с ключом s3Key
+func (r *FileVersionQueryRepository) GetProcessingByS3Key(ctx context.Context, s3Key file_version.S3Key) (*file_version.FileVersion, error) {
+	row := r.db.QueryRowContext(ctx, `
+        SELECT id, s3_key, preview_s3_key, mime, status, size, version_num,
+               file_id, uploaded_by_session_id, created_at, updated_at, content_hash, key_id,
+               checksum_algorithm, checksum, failure_reason, drop_link_id
+        FROM file_versions
+        WHERE s3_key = $1 AND status = $2
+    `, s3Key.String(), file_version.FileStatusProcessing.String())
+
+	v, err := scanFileVersion(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return v, err
+}
+
+// GetAbandoned возвращает версии, ожидающие загрузки с момента раньше before, у которых нет живой
+// multipart загрузки и tus загрузки, продвинувшейся после before
+func (r *FileVersionQueryRepository) GetAbandoned(ctx context.Context, before time.Time, limit int) ([]*file_version.FileVersion, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT id, s3_key, preview_s3_key, mime, status, size, version_num,
+               file_id, uploaded_by_session_id, created_at, updated_at, content_hash, key_id,
+               checksum_algorithm, checksum, failure_reason, drop_link_id
+        FROM file_versions v
+        WHERE status = $1 AND created_at < $2
+          AND NOT EXISTS (
+              SELECT 1 FROM multipart_uploads m
+              WHERE m.version_id = v.id AND m.status = 'initiated' AND m.expires_at > NOW()
+          )
+          AND NOT EXISTS (
+              SELECT 1 FROM tus_uploads t
+              WHERE t.version_id = v.id AND t.updated_at >= $2
+          )
+        ORDER BY created_at
+        LIMIT $3
+    `, file_version.FileStatusProcessing.String(), before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query file versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make([]*file_version.FileVersion, 0)
+	for rows.Next() {
+		v, err := scanFileVersion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan file version: %w", err)
+		}
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+// GetProcessingBefore возвращает версии, ожидающие загрузки с момента раньше before, самые старые первыми
+func (r *FileVersionQueryRepository) GetProcessingBefore(ctx context.Context, before time.Time, limit int) ([]*file_version.FileVersion, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT id, s3_key, preview_s3_key, mime, status, size, version_num,
+               file_id, uploaded_by_session_id, created_at, updated_at, content_hash, key_id,
+               checksum_algorithm, checksum, failure_reason, drop_link_id
+        FROM file_versions
+        WHERE status = $1 AND created_at < $2
+        ORDER BY created_at
+        LIMIT $3
+    `, file_version.FileStatusProcessing.String(), before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query file versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make([]*file_version.FileVersion, 0)
+	for rows.Next() {
+		v, err := scanFileVersion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan file version: %w", err)
+		}
+		versions = append(versions, v)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return versions, nil
+}
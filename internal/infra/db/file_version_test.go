@@ -4,11 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
 	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
@@ -69,10 +71,22 @@ func TestFileVersionCommandRepository_Save_Success(t *testing.T) {
 	previewS3Key, err := file_version.NewS3Key("preview-key")
 	require.NoError(t, err)
 
+	contentHash, err := file_version.NewContentHash(strings.Repeat("ab", 32))
+	require.NoError(t, err)
+
+	keyID := "2025-11"
+	checksum, err := file_version.NewChecksum("sha256", "47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=")
+	require.NoError(t, err)
+	failureReason := "checksum mismatch"
+
 	v := &file_version.FileVersion{
 		ID:                  id,
 		S3Key:               mustS3Key("main-file-key"),
 		PreviewS3Key:        &previewS3Key,
+		ContentHash:         &contentHash,
+		KeyID:               &keyID,
+		Checksum:            &checksum,
+		FailureReason:       &failureReason,
 		Mime:                mustMime("image/png"),
 		Status:              mustStatus("uploaded"),
 		Size:                fileSize,
@@ -84,8 +98,8 @@ func TestFileVersionCommandRepository_Save_Success(t *testing.T) {
 	}
 
 	upsert := regexp.QuoteMeta(
-		"INSERT INTO file_versions (id, s3_key, preview_s3_key, mime, status, size, version_num, file_id, uploaded_by_session_id, created_at, updated_at) " +
-			"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) " +
+		"INSERT INTO file_versions (id, s3_key, preview_s3_key, mime, status, size, version_num, file_id, uploaded_by_session_id, created_at, updated_at, content_hash, key_id, checksum_algorithm, checksum, failure_reason, drop_link_id) " +
+			"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17) " +
 			"ON CONFLICT (id) DO UPDATE SET " +
 			"s3_key = EXCLUDED.s3_key, " +
 			"preview_s3_key = EXCLUDED.preview_s3_key, " +
@@ -95,7 +109,13 @@ func TestFileVersionCommandRepository_Save_Success(t *testing.T) {
 			"version_num = EXCLUDED.version_num, " +
 			"file_id = EXCLUDED.file_id, " +
 			"uploaded_by_session_id = EXCLUDED.uploaded_by_session_id, " +
-			"updated_at = EXCLUDED.updated_at",
+			"updated_at = EXCLUDED.updated_at, " +
+			"content_hash = EXCLUDED.content_hash, " +
+			"key_id = EXCLUDED.key_id, " +
+			"checksum_algorithm = EXCLUDED.checksum_algorithm, " +
+			"checksum = EXCLUDED.checksum, " +
+			"failure_reason = EXCLUDED.failure_reason, " +
+			"drop_link_id = EXCLUDED.drop_link_id",
 	)
 
 	var previewVal interface{}
@@ -118,6 +138,12 @@ func TestFileVersionCommandRepository_Save_Success(t *testing.T) {
 			v.UploadedBySessionId,
 			v.CreatedAt,
 			v.UpdatedAt,
+			v.ContentHash.String(),
+			"2025-11",
+			"sha256",
+			checksum.Value(),
+			failureReason,
+			v.DropLinkID,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -177,17 +203,20 @@ func TestFileVersionQueryRepository_GetByID_Success(t *testing.T) {
 	id := uuid.New()
 	fileID := uuid.New()
 	sessionID := uuid.New()
+	dropLinkID := uuid.New()
 	now := time.Now()
 
 	query := regexp.QuoteMeta(`SELECT id, s3_key, preview_s3_key, mime, status, size, version_num,
-               file_id, uploaded_by_session_id, created_at, updated_at FROM file_versions WHERE id = $1`)
+               file_id, uploaded_by_session_id, created_at, updated_at, content_hash, key_id,
+               checksum_algorithm, checksum, failure_reason, drop_link_id FROM file_versions WHERE id = $1`)
 
 	mock.ExpectQuery(query).
 		WithArgs(id).
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "s3_key", "preview_s3_key", "mime", "status", "size", "version_num",
-			"file_id", "uploaded_by_session_id", "created_at", "updated_at",
-		}).AddRow(id, "main-key", "preview-key", "image/png", "uploaded", 2048, 1, fileID, sessionID, now, now))
+			"file_id", "uploaded_by_session_id", "created_at", "updated_at", "content_hash", "key_id",
+			"checksum_algorithm", "checksum", "failure_reason", "drop_link_id",
+		}).AddRow(id, "main-key", "preview-key", "image/png", "uploaded", 2048, 1, fileID, sessionID, now, now, nil, "2025-11", nil, nil, nil, dropLinkID))
 
 	v, err := repo.GetByID(context.Background(), id)
 	require.NoError(t, err)
@@ -198,6 +227,11 @@ func TestFileVersionQueryRepository_GetByID_Success(t *testing.T) {
 	require.Equal(t, "uploaded", v.Status.String())
 	require.Equal(t, uint64(2048), v.Size.Uint64())
 	require.Equal(t, 1, v.VersionNum.Int())
+	require.Nil(t, v.ContentHash)
+	require.Equal(t, "2025-11", *v.KeyID)
+	require.Nil(t, v.Checksum)
+	require.Nil(t, v.FailureReason)
+	require.Equal(t, dropLinkID, *v.DropLinkID)
 }
 
 func TestFileVersionQueryRepository_GetByID_NoRows(t *testing.T) {
@@ -209,7 +243,8 @@ func TestFileVersionQueryRepository_GetByID_NoRows(t *testing.T) {
 	id := uuid.New()
 
 	query := regexp.QuoteMeta(`SELECT id, s3_key, preview_s3_key, mime, status, size, version_num,
-               file_id, uploaded_by_session_id, created_at, updated_at FROM file_versions WHERE id = $1`)
+               file_id, uploaded_by_session_id, created_at, updated_at, content_hash, key_id,
+               checksum_algorithm, checksum, failure_reason, drop_link_id FROM file_versions WHERE id = $1`)
 
 	mock.ExpectQuery(query).
 		WithArgs(id).
@@ -219,3 +254,161 @@ func TestFileVersionQueryRepository_GetByID_NoRows(t *testing.T) {
 	require.NoError(t, err)
 	require.Nil(t, v)
 }
+
+func TestFileVersionCommandRepository_AttachBlob_AlreadyAttached(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewFileVersionCommandRepository()
+	id := uuid.New()
+	hash, err := file_version.NewContentHash(strings.Repeat("ab", 32))
+	require.NoError(t, err)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE file_versions SET content_hash = $2, s3_key = $3 WHERE id = $1 AND content_hash IS NULL")).
+		WithArgs(id, hash.String(), "blob-key").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	attached, err := repo.AttachBlob(ctx, id, hash, mustS3Key("blob-key"))
+	require.NoError(t, err)
+	require.False(t, attached)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFileVersionCommandRepository_MarkAbandoned_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewFileVersionCommandRepository()
+	id := uuid.New()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE file_versions SET status = $2, size = 0, failure_reason = $3, updated_at = NOW() WHERE id = $1 AND status = $4 AND content_hash IS NULL")).
+		WithArgs(id, "failed", "abandoned", "processing").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	marked, err := repo.MarkAbandoned(ctx, id, "abandoned")
+	require.NoError(t, err)
+	require.True(t, marked)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFileVersionCommandRepository_MarkAbandoned_AlreadyCompleted(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewFileVersionCommandRepository()
+	id := uuid.New()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE file_versions SET status = $2")).
+		WithArgs(id, "failed", "abandoned", "processing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	marked, err := repo.MarkAbandoned(ctx, id, "abandoned")
+	require.NoError(t, err)
+	require.False(t, marked)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFileVersionCommandRepository_SetKeyID_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewFileVersionCommandRepository()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE file_versions SET key_id = $2 WHERE s3_key = $1")).
+		WithArgs("blob-key", "2025-11").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err = repo.SetKeyID(ctx, mustS3Key("blob-key"), "2025-11")
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFileVersionQueryRepository_GetAbandoned_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewFileVersionQueryRepository(sqlDB)
+	before := time.Now().Add(-24 * time.Hour)
+	now := time.Now()
+
+	mock.ExpectQuery(`NOT EXISTS \(\s*SELECT 1 FROM multipart_uploads m`).
+		WithArgs("processing", before, 10).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "s3_key", "preview_s3_key", "mime", "status", "size", "version_num",
+			"file_id", "uploaded_by_session_id", "created_at", "updated_at", "content_hash", "key_id",
+			"checksum_algorithm", "checksum", "failure_reason", "drop_link_id",
+		}).AddRow(uuid.New(), "main-key", nil, "image/png", "processing", 2048, 2, uuid.New(), uuid.New(), now, now, nil, nil, nil, nil, nil, nil))
+
+	versions, err := repo.GetAbandoned(context.Background(), before, 10)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	require.Equal(t, "processing", versions[0].Status.String())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFileVersionQueryRepository_GetStored_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewFileVersionQueryRepository(sqlDB)
+	afterID := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery(`WHERE status IN \(\$1, \$2\) AND id > \$3`).
+		WithArgs("uploaded", "ready", afterID, 10).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "s3_key", "preview_s3_key", "mime", "status", "size", "version_num",
+			"file_id", "uploaded_by_session_id", "created_at", "updated_at", "content_hash", "key_id",
+			"checksum_algorithm", "checksum", "failure_reason", "drop_link_id",
+		}).AddRow(uuid.New(), "main-key", nil, "image/png", "ready", 2048, 1, uuid.New(), uuid.New(), now, now, nil, nil, nil, nil, nil, nil))
+
+	versions, err := repo.GetStored(context.Background(), afterID, 10)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	require.Equal(t, "main-key", versions[0].S3Key.String())
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFileVersionQueryRepository_GetReferencedKeys_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewFileVersionQueryRepository(sqlDB)
+	keys := []string{"files/a", "files/b"}
+
+	mock.ExpectQuery(`SELECT k FROM unnest\(\$1::text\[\]\) AS k`).
+		WithArgs(pq.Array(keys)).
+		WillReturnRows(sqlmock.NewRows([]string{"k"}).AddRow("files/a"))
+
+	referenced, err := repo.GetReferencedKeys(context.Background(), keys)
+	require.NoError(t, err)
+	require.Equal(t, map[string]bool{"files/a": true}, referenced)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
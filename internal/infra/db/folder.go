@@ -0,0 +1,184 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
+	"github.com/yourusername/cloud-file-storage/internal/domain/folder"
+)
+
+type FolderCommandRepository struct {
+}
+
+func NewFolderCommandRepository() *FolderCommandRepository {
+	return &FolderCommandRepository{}
+}
+
+func (r *FolderCommandRepository) Save(ctx context.Context, f *folder.Folder) error {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return domainerrors.ErrTransactionNotFound
+	}
+
+	query := `
+    INSERT INTO folders (id, owner_id, parent_id, name, created_at, updated_at)
+    VALUES ($1, $2, $3, $4, $5, $6)
+    ON CONFLICT (id) DO UPDATE 
+    SET parent_id = EXCLUDED.parent_id, 
+        name = EXCLUDED.name, 
+        updated_at = EXCLUDED.updated_at
+    `
+	_, err := tx.ExecContext(ctx, query,
+		f.ID,
+		f.OwnerID,
+		f.ParentID,
+		f.Name.String(),
+		f.CreatedAt,
+		f.UpdatedAt,
+	)
+	return err
+}
+
+func (r *FolderCommandRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return domainerrors.ErrTransactionNotFound
+	}
+
+	query := `DELETE FROM folders WHERE id = $1`
+	_, err := tx.ExecContext(ctx, query, id)
+	return err
+}
+
+type FolderQueryRepository struct {
+	db *sql.DB
+}
+
+func NewFolderQueryRepository(db *sql.DB) *FolderQueryRepository {
+	return &FolderQueryRepository{db: db}
+}
+
+// scanFolder сканирует строку базы данных в объект Folder
+func scanFolder(scanner scannable) (*folder.Folder, error) {
+	var f folder.Folder
+	var name string
+	var parentID uuid.NullUUID
+
+	if err := scanner.Scan(
+		&f.ID,
+		&f.OwnerID,
+		&parentID,
+		&name,
+		&f.CreatedAt,
+		&f.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if parentID.Valid {
+		f.ParentID = &parentID.UUID
+	}
+
+	var err error
+	f.Name, err = folder.NewFolderName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+func (r *FolderQueryRepository) queryFolders(ctx context.Context, query string, args ...any) ([]*folder.Folder, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query folders: %w", err)
+	}
+	defer rows.Close()
+
+	folders := make([]*folder.Folder, 0)
+
+	for rows.Next() {
+		f, err := scanFolder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan folder: %w", err)
+		}
+		folders = append(folders, f)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return folders, nil
+}
+
+func (r *FolderQueryRepository) GetByID(ctx context.Context, id uuid.UUID) (*folder.Folder, error) {
+	row := r.db.QueryRowContext(ctx, `
+        SELECT id, owner_id, parent_id, name, created_at, updated_at
+        FROM folders
+        WHERE id = $1
+    `, id)
+
+	f, err := scanFolder(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return f, err
+}
+
+func (r *FolderQueryRepository) GetByOwnerID(ctx context.Context, ownerID uuid.UUID) ([]*folder.Folder, error) {
+	return r.queryFolders(ctx, `
+        SELECT id, owner_id, parent_id, name, created_at, updated_at
+        FROM folders
+        WHERE owner_id = $1
+        ORDER BY name
+    `, ownerID)
+}
+
+func (r *FolderQueryRepository) GetChildren(ctx context.Context, ownerID uuid.UUID, parentID *uuid.UUID) ([]*folder.Folder, error) {
+	return r.queryFolders(ctx, `
+        SELECT id, owner_id, parent_id, name, created_at, updated_at
+        FROM folders
+        WHERE owner_id = $1 AND parent_id IS NOT DISTINCT FROM $2
+        ORDER BY name
+    `, ownerID, parentID)
+}
+
+// GetAncestors поднимается по parent_id рекурсивным CTE, корень идёт первым
+func (r *FolderQueryRepository) GetAncestors(ctx context.Context, id uuid.UUID) ([]*folder.Folder, error) {
+	return r.queryFolders(ctx, `
+        WITH RECURSIVE chain AS (
+            SELECT id, owner_id, parent_id, name, created_at, updated_at, 0 AS depth
+            FROM folders
+            WHERE id = $1
+            UNION ALL
+            SELECT f.id, f.owner_id, f.parent_id, f.name, f.created_at, f.updated_at, c.depth + 1
+            FROM folders f
+            JOIN chain c ON f.id = c.parent_id
+        )
+        SELECT id, owner_id, parent_id, name, created_at, updated_at
+        FROM chain
+        ORDER BY depth DESC
+    `, id)
+}
+
+// GetDescendants спускается по дереву рекурсивным CTE, самые глубокие папки идут первыми
+func (r *FolderQueryRepository) GetDescendants(ctx context.Context, id uuid.UUID) ([]*folder.Folder, error) {
+	return r.queryFolders(ctx, `
+        WITH RECURSIVE tree AS (
+            SELECT id, owner_id, parent_id, name, created_at, updated_at, 1 AS depth
+            FROM folders
+            WHERE parent_id = $1
+            UNION ALL
+            SELECT f.id, f.owner_id, f.parent_id, f.name, f.created_at, f.updated_at, t.depth + 1
+            FROM folders f
+            JOIN tree t ON f.parent_id = t.id
+        )
+        SELECT id, owner_id, parent_id, name, created_at, updated_at
+        FROM tree
+        ORDER BY depth DESC
+    `, id)
+}
@@ -0,0 +1,142 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
+	"github.com/yourusername/cloud-file-storage/internal/domain/folder"
+)
+
+func mustFolderName(val string) folder.FolderName {
+	n, err := folder.NewFolderName(val)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func TestFolderCommandRepository_Save_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewFolderCommandRepository()
+
+	parentID := uuid.New()
+	f := folder.NewFolder(uuid.New(), &parentID, mustFolderName("docs"))
+
+	mock.ExpectExec(`INSERT INTO folders`).
+		WithArgs(f.ID, f.OwnerID, parentID, "docs", f.CreatedAt, f.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.Save(ctx, f)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFolderCommandRepository_Save_NoTransaction(t *testing.T) {
+	repo := NewFolderCommandRepository()
+
+	err := repo.Save(context.Background(), &folder.Folder{})
+	require.ErrorIs(t, err, domainerrors.ErrTransactionNotFound)
+}
+
+func TestFolderCommandRepository_Delete_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewFolderCommandRepository()
+	id := uuid.New()
+
+	mock.ExpectExec(`DELETE FROM folders WHERE id = \$1`).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.Delete(ctx, id)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFolderQueryRepository_GetByID_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewFolderQueryRepository(sqlDB)
+
+	id := uuid.New()
+	ownerID := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT id, owner_id, parent_id, name, created_at, updated_at`).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "owner_id", "parent_id", "name", "created_at", "updated_at",
+		}).AddRow(id, ownerID, nil, "docs", now, now))
+
+	f, err := repo.GetByID(context.Background(), id)
+	require.NoError(t, err)
+	require.NotNil(t, f)
+	require.Equal(t, "docs", f.Name.String())
+	require.True(t, f.IsRoot())
+}
+
+func TestFolderQueryRepository_GetByID_NoRows(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewFolderQueryRepository(sqlDB)
+	id := uuid.New()
+
+	mock.ExpectQuery(`SELECT id, owner_id, parent_id, name, created_at, updated_at`).
+		WithArgs(id).
+		WillReturnError(sql.ErrNoRows)
+
+	f, err := repo.GetByID(context.Background(), id)
+	require.NoError(t, err)
+	require.Nil(t, f)
+}
+
+func TestFolderQueryRepository_GetAncestors_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewFolderQueryRepository(sqlDB)
+
+	ownerID := uuid.New()
+	rootID := uuid.New()
+	childID := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery(`WITH RECURSIVE chain`).
+		WithArgs(childID).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "owner_id", "parent_id", "name", "created_at", "updated_at",
+		}).AddRow(rootID, ownerID, nil, "root", now, now).
+			AddRow(childID, ownerID, rootID, "child", now, now))
+
+	path, err := repo.GetAncestors(context.Background(), childID)
+	require.NoError(t, err)
+	require.Len(t, path, 2)
+	require.Equal(t, "root", path[0].Name.String())
+	require.Equal(t, rootID, *path[1].ParentID)
+}
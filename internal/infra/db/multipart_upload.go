@@ -0,0 +1,168 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/multipart_upload"
+)
+
+type MultipartUploadCommandRepository struct {
+}
+
+func NewMultipartUploadCommandRepository() *MultipartUploadCommandRepository {
+	return &MultipartUploadCommandRepository{}
+}
+
+func (r *MultipartUploadCommandRepository) Save(ctx context.Context, m *multipart_upload.MultipartUpload) error {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return domainerrors.ErrTransactionNotFound
+	}
+
+	query := `
+    INSERT INTO multipart_uploads (id, file_id, version_id, owner_id, s3_key, upload_id,
+               part_size, part_count, status, created_at, updated_at, expires_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+    ON CONFLICT (id) DO UPDATE 
+    SET status = EXCLUDED.status, 
+        updated_at = EXCLUDED.updated_at, 
+        expires_at = EXCLUDED.expires_at
+    `
+	_, err := tx.ExecContext(ctx, query,
+		m.ID,
+		m.FileID,
+		m.VersionID,
+		m.OwnerID,
+		m.S3Key.String(),
+		m.UploadID,
+		m.PartSize,
+		m.PartCount,
+		m.Status.String(),
+		m.CreatedAt,
+		m.UpdatedAt,
+		m.ExpiresAt,
+	)
+	return err
+}
+
+func (r *MultipartUploadCommandRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return domainerrors.ErrTransactionNotFound
+	}
+
+	_, err := tx.ExecContext(ctx, `DELETE FROM multipart_uploads WHERE id = $1`, id)
+	return err
+}
+
+type MultipartUploadQueryRepository struct {
+	db *sql.DB
+}
+
+func NewMultipartUploadQueryRepository(db *sql.DB) *MultipartUploadQueryRepository {
+	return &MultipartUploadQueryRepository{db: db}
+}
+
+func scanMultipartUpload(scanner scannable) (*multipart_upload.MultipartUpload, error) {
+	var m multipart_upload.MultipartUpload
+	var s3Key, status string
+
+	if err := scanner.Scan(
+		&m.ID,
+		&m.FileID,
+		&m.VersionID,
+		&m.OwnerID,
+		&s3Key,
+		&m.UploadID,
+		&m.PartSize,
+		&m.PartCount,
+		&status,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+		&m.ExpiresAt,
+	); err != nil {
+		return nil, err
+	}
+
+	key, err := file_version.NewS3Key(s3Key)
+	if err != nil {
+		return nil, err
+	}
+	m.S3Key = key
+
+	m.Status, err = multipart_upload.NewUploadStatus(status)
+	if err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+func (r *MultipartUploadQueryRepository) GetByID(ctx context.Context, id uuid.UUID) (*multipart_upload.MultipartUpload, error) {
+	row := r.db.QueryRowContext(ctx, `
+        SELECT id, file_id, version_id, owner_id, s3_key, upload_id,
+               part_size, part_count, status, created_at, updated_at, expires_at
+        FROM multipart_uploads
+        WHERE id = $1
+    `, id)
+
+	m, err := scanMultipartUpload(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return m, err
+}
+
+func (r *MultipartUploadQueryRepository) GetActiveByVersionID(ctx context.Context, versionID uuid.UUID) (*multipart_upload.MultipartUpload, error) {
+	row := r.db.QueryRowContext(ctx, `
+        SELECT id, file_id, version_id, owner_id, s3_key, upload_id,
+               part_size, part_count, status, created_at, updated_at, expires_at
+        FROM multipart_uploads
+        WHERE version_id = $1 AND status = 'initiated'
+        ORDER BY created_at DESC
+        LIMIT 1
+    `, versionID)
+
+	m, err := scanMultipartUpload(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return m, err
+}
+
+func (r *MultipartUploadQueryRepository) GetExpiredActive(ctx context.Context, now time.Time, limit int) ([]*multipart_upload.MultipartUpload, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT id, file_id, version_id, owner_id, s3_key, upload_id,
+               part_size, part_count, status, created_at, updated_at, expires_at
+        FROM multipart_uploads
+        WHERE status = 'initiated' AND expires_at < $1
+        ORDER BY expires_at
+        LIMIT $2
+    `, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query multipart uploads: %w", err)
+	}
+	defer rows.Close()
+
+	uploads := make([]*multipart_upload.MultipartUpload, 0)
+
+	for rows.Next() {
+		m, err := scanMultipartUpload(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan multipart upload: %w", err)
+		}
+		uploads = append(uploads, m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return uploads, nil
+}
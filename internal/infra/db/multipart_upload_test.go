@@ -0,0 +1,116 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/multipart_upload"
+)
+
+var multipartUploadColumns = []string{
+	"id", "file_id", "version_id", "owner_id", "s3_key", "upload_id",
+	"part_size", "part_count", "status", "created_at", "updated_at", "expires_at",
+}
+
+func TestMultipartUploadCommandRepository_Save_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewMultipartUploadCommandRepository()
+
+	key, _ := file_version.NewS3Key("files/a/b/v1/big.bin")
+	m := multipart_upload.NewMultipartUpload(uuid.New(), uuid.New(), uuid.New(), key, "s3-upload-id", 1<<30, time.Hour)
+
+	mock.ExpectExec(`INSERT INTO multipart_uploads`).
+		WithArgs(m.ID, m.FileID, m.VersionID, m.OwnerID, "files/a/b/v1/big.bin", "s3-upload-id",
+			m.PartSize, m.PartCount, "initiated", m.CreatedAt, m.UpdatedAt, m.ExpiresAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.Save(ctx, m)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMultipartUploadCommandRepository_Save_NoTransaction(t *testing.T) {
+	repo := NewMultipartUploadCommandRepository()
+
+	err := repo.Save(context.Background(), &multipart_upload.MultipartUpload{})
+	require.ErrorIs(t, err, domainerrors.ErrTransactionNotFound)
+}
+
+func TestMultipartUploadQueryRepository_GetByID_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewMultipartUploadQueryRepository(sqlDB)
+
+	id := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT id, file_id, version_id, owner_id, s3_key, upload_id`).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(multipartUploadColumns).
+			AddRow(id, uuid.New(), uuid.New(), uuid.New(), "files/key", "s3-upload-id",
+				int64(16<<20), 4, "completed", now, now, now.Add(time.Hour)))
+
+	m, err := repo.GetByID(context.Background(), id)
+	require.NoError(t, err)
+	require.NotNil(t, m)
+	require.Equal(t, "s3-upload-id", m.UploadID)
+	require.True(t, m.Status.Equal(multipart_upload.UploadStatusCompleted))
+	require.False(t, m.IsActive())
+}
+
+func TestMultipartUploadQueryRepository_GetActiveByVersionID_NoRows(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewMultipartUploadQueryRepository(sqlDB)
+	versionID := uuid.New()
+
+	mock.ExpectQuery(`FROM multipart_uploads\s+WHERE version_id = \$1 AND status = 'initiated'`).
+		WithArgs(versionID).
+		WillReturnError(sql.ErrNoRows)
+
+	m, err := repo.GetActiveByVersionID(context.Background(), versionID)
+	require.NoError(t, err)
+	require.Nil(t, m)
+}
+
+func TestMultipartUploadQueryRepository_GetExpiredActive_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewMultipartUploadQueryRepository(sqlDB)
+	now := time.Now()
+
+	mock.ExpectQuery(`WHERE status = 'initiated' AND expires_at < \$1`).
+		WithArgs(now, 50).
+		WillReturnRows(sqlmock.NewRows(multipartUploadColumns).
+			AddRow(uuid.New(), uuid.New(), uuid.New(), uuid.New(), "files/one", "u1",
+				int64(16<<20), 2, "initiated", now, now, now.Add(-time.Minute)).
+			AddRow(uuid.New(), uuid.New(), uuid.New(), uuid.New(), "files/two", "u2",
+				int64(16<<20), 1, "initiated", now, now, now.Add(-time.Hour)))
+
+	uploads, err := repo.GetExpiredActive(context.Background(), now, 50)
+	require.NoError(t, err)
+	require.Len(t, uploads, 2)
+	require.True(t, uploads[0].IsExpired())
+	require.Equal(t, "u2", uploads[1].UploadID)
+}
@@ -21,11 +21,13 @@ func (r *PublicLinkCommandRepository) Save(ctx context.Context, p *public_link.P
 
 	query := `
     INSERT INTO public_links (id, file_id, created_by_user_id, token_hash,
-               created_at, updated_at, expired_at)
-    VALUES ($1, $2, $3, $4, $5, $6, $7)
+               created_at, updated_at, expired_at, password_hash,
+               max_downloads, download_count, version_mode, version_id)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
     ON CONFLICT (id) DO UPDATE 
     SET file_id = $2, created_by_user_id = $3, token_hash = $4, 
-        created_at = $5, updated_at = $6, expired_at = $7
+        created_at = $5, updated_at = $6, expired_at = $7, password_hash = $8,
+        max_downloads = $9, version_mode = $11, version_id = $12
     `
 	_, err := tx.ExecContext(ctx, query,
 		p.ID,
@@ -35,6 +37,11 @@ func (r *PublicLinkCommandRepository) Save(ctx context.Context, p *public_link.P
 		p.CreatedAt,
 		p.UpdatedAt,
 		p.ExpiredAt,
+		p.PasswordHash,
+		p.MaxDownloads,
+		p.DownloadCount,
+		p.VersionMode.String(),
+		p.VersionID,
 	)
 	return err
 }
@@ -50,6 +57,139 @@ func (r *PublicLinkCommandRepository) Delete(ctx context.Context, id uuid.UUID)
 	return err
 }
 
+func (r *PublicLinkCommandRepository) RegisterUnlockFailure(ctx context.Context, linkID uuid.UUID, ip string, now, windowStart time.Time) (int, error) {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return 0, domainerrors.ErrTransactionNotFound
+	}
+
+	var failures int
+	err := tx.QueryRowContext(ctx, `
+        INSERT INTO public_link_unlock_attempts AS a (link_id, ip, failures, updated_at)
+        VALUES ($1, $2, 1, $3)
+        ON CONFLICT (link_id, ip) DO UPDATE
+        SET failures = CASE WHEN a.updated_at < $4 THEN 1 ELSE a.failures + 1 END,
+            updated_at = EXCLUDED.updated_at
+        RETURNING failures
+    `, linkID, ip, now, windowStart).Scan(&failures)
+	return failures, err
+}
+
+func (r *PublicLinkCommandRepository) LockUnlock(ctx context.Context, linkID uuid.UUID, ip string, until time.Time) error {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return domainerrors.ErrTransactionNotFound
+	}
+
+	_, err := tx.ExecContext(ctx, `
+        UPDATE public_link_unlock_attempts
+        SET failures = 0, locked_until = $3
+        WHERE link_id = $1 AND ip = $2
+    `, linkID, ip, until)
+	return err
+}
+
+func (r *PublicLinkCommandRepository) ResetUnlockAttempts(ctx context.Context, linkID uuid.UUID, ip string) error {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return domainerrors.ErrTransactionNotFound
+	}
+
+	_, err := tx.ExecContext(ctx, `DELETE FROM public_link_unlock_attempts WHERE link_id = $1 AND ip = $2`, linkID, ip)
+	return err
+}
+
+func (r *PublicLinkCommandRepository) IncrementDownloads(ctx context.Context, linkID uuid.UUID) (int, bool, error) {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return 0, false, domainerrors.ErrTransactionNotFound
+	}
+
+	// Условие в WHERE не даёт параллельным скачиваниям превысить лимит
+	var count int
+	err := tx.QueryRowContext(ctx, `
+        UPDATE public_links
+        SET download_count = download_count + 1
+        WHERE id = $1 AND (max_downloads IS NULL OR download_count < max_downloads)
+        RETURNING download_count
+    `, linkID).Scan(&count)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return count, true, nil
+}
+
+func (r *PublicLinkCommandRepository) SaveAccess(ctx context.Context, a *public_link.Access) error {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return domainerrors.ErrTransactionNotFound
+	}
+
+	_, err := tx.ExecContext(ctx, `
+        INSERT INTO public_link_accesses (id, link_id, version_id, ip, user_agent, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+    `, a.ID, a.LinkID, a.VersionID, a.IP, a.UserAgent, a.CreatedAt)
+	return err
+}
+
+func (r *PublicLinkCommandRepository) DeleteExpired(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return nil, domainerrors.ErrTransactionNotFound
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+        DELETE FROM public_links
+        WHERE id IN (
+            SELECT id FROM public_links
+            WHERE expired_at < $1
+            ORDER BY expired_at
+            LIMIT $2
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING id
+    `, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanIDs(rows)
+}
+
+func (r *PublicLinkCommandRepository) ArchiveExpired(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return nil, domainerrors.ErrTransactionNotFound
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+        WITH expired AS (
+            DELETE FROM public_links
+            WHERE id IN (
+                SELECT id FROM public_links
+                WHERE expired_at < $1
+                ORDER BY expired_at
+                LIMIT $2
+                FOR UPDATE SKIP LOCKED
+            )
+            RETURNING id, file_id, created_by_user_id, created_at, expired_at,
+                      password_hash IS NOT NULL AS protected, max_downloads, download_count
+        )
+        INSERT INTO public_links_archive (id, file_id, created_by_user_id, created_at, expired_at,
+                                          protected, max_downloads, download_count, archived_at)
+        SELECT id, file_id, created_by_user_id, created_at, expired_at,
+               protected, max_downloads, download_count, NOW()
+        FROM expired
+        RETURNING id
+    `, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanIDs(rows)
+}
+
 type PublicLinkQueryRepository struct {
 	db *sql.DB
 }
@@ -65,6 +205,10 @@ func scanPublicLink(scanner scannable) (*public_link.PublicLink, error) {
 	var p public_link.PublicLink
 	var tokenHash string
 	var expiredAt time.Time
+	var passwordHash sql.NullString
+	var maxDownloads sql.NullInt64
+	var versionMode string
+	var versionID uuid.NullUUID
 
 	if err := scanner.Scan(
 		&p.ID,
@@ -74,12 +218,33 @@ func scanPublicLink(scanner scannable) (*public_link.PublicLink, error) {
 		&p.CreatedAt,
 		&p.UpdatedAt,
 		&expiredAt,
+		&passwordHash,
+		&maxDownloads,
+		&p.DownloadCount,
+		&versionMode,
+		&versionID,
 	); err != nil {
 		return nil, err
 	}
 
+	mode, err := public_link.NewVersionMode(versionMode)
+	if err != nil {
+		return nil, err
+	}
+	p.VersionMode = mode
+	if versionID.Valid {
+		p.VersionID = &versionID.UUID
+	}
+
 	p.TokenHash = tokenHash
 	p.ExpiredAt = expiredAt
+	if passwordHash.Valid {
+		p.PasswordHash = &passwordHash.String
+	}
+	if maxDownloads.Valid {
+		m := int(maxDownloads.Int64)
+		p.MaxDownloads = &m
+	}
 
 	return &p, nil
 }
@@ -87,7 +252,8 @@ func scanPublicLink(scanner scannable) (*public_link.PublicLink, error) {
 func (r *PublicLinkQueryRepository) GetByID(ctx context.Context, id uuid.UUID) (*public_link.PublicLink, error) {
 	row := r.db.QueryRowContext(ctx, `
         SELECT id, file_id, created_by_user_id, token_hash,
-               created_at, updated_at, expired_at
+               created_at, updated_at, expired_at, password_hash,
+               max_downloads, download_count, version_mode, version_id
         FROM public_links
         WHERE id = $1
     `, id)
@@ -99,13 +265,14 @@ func (r *PublicLinkQueryRepository) GetByID(ctx context.Context, id uuid.UUID) (
 	return p, err
 }
 
-func (r *PublicLinkQueryRepository) GetByToken(ctx context.Context, token string) (*public_link.PublicLink, error) {
+func (r *PublicLinkQueryRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*public_link.PublicLink, error) {
 	row := r.db.QueryRowContext(ctx, `
         SELECT id, file_id, created_by_user_id, token_hash,
-               created_at, updated_at, expired_at
+               created_at, updated_at, expired_at, password_hash,
+               max_downloads, download_count, version_mode, version_id
         FROM public_links
         WHERE token_hash = $1
-    `, token)
+    `, tokenHash)
 
 	p, err := scanPublicLink(row)
 	if err == sql.ErrNoRows {
@@ -117,7 +284,8 @@ func (r *PublicLinkQueryRepository) GetByToken(ctx context.Context, token string
 func (r *PublicLinkQueryRepository) GetByFileID(ctx context.Context, fileID uuid.UUID) ([]*public_link.PublicLink, error) {
 	rows, err := r.db.QueryContext(ctx, `
         SELECT id, file_id, created_by_user_id, token_hash,
-               created_at, updated_at, expired_at
+               created_at, updated_at, expired_at, password_hash,
+               max_downloads, download_count, version_mode, version_id
         FROM public_links
         WHERE file_id = $1
     `, fileID)
@@ -145,7 +313,8 @@ func (r *PublicLinkQueryRepository) GetByFileID(ctx context.Context, fileID uuid
 func (r *PublicLinkQueryRepository) GetAll(ctx context.Context) ([]*public_link.PublicLink, error) {
 	rows, err := r.db.QueryContext(ctx, `
         SELECT id, file_id, created_by_user_id, token_hash,
-               created_at, updated_at, expired_at
+               created_at, updated_at, expired_at, password_hash,
+               max_downloads, download_count, version_mode, version_id
         FROM public_links
     `)
 	if err != nil {
@@ -168,3 +337,57 @@ func (r *PublicLinkQueryRepository) GetAll(ctx context.Context) ([]*public_link.
 
 	return links, nil
 }
+
+func (r *PublicLinkQueryRepository) GetUnlockAttempts(ctx context.Context, linkID uuid.UUID, ip string) (*public_link.UnlockAttempts, error) {
+	var a public_link.UnlockAttempts
+	var lockedUntil sql.NullTime
+
+	err := r.db.QueryRowContext(ctx, `
+        SELECT link_id, ip, failures, locked_until, updated_at
+        FROM public_link_unlock_attempts
+        WHERE link_id = $1 AND ip = $2
+    `, linkID, ip).Scan(&a.LinkID, &a.IP, &a.Failures, &lockedUntil, &a.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if lockedUntil.Valid {
+		a.LockedUntil = &lockedUntil.Time
+	}
+	return &a, nil
+}
+
+func (r *PublicLinkQueryRepository) GetAccesses(ctx context.Context, linkID uuid.UUID) ([]*public_link.Access, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT id, link_id, version_id, ip, user_agent, created_at
+        FROM public_link_accesses
+        WHERE link_id = $1
+        ORDER BY created_at DESC
+    `, linkID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accesses := make([]*public_link.Access, 0)
+	for rows.Next() {
+		var a public_link.Access
+		var versionID uuid.NullUUID
+		if err := rows.Scan(&a.ID, &a.LinkID, &versionID, &a.IP, &a.UserAgent, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		if versionID.Valid {
+			a.VersionID = &versionID.UUID
+		}
+		accesses = append(accesses, &a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return accesses, nil
+}
@@ -39,6 +39,7 @@ func TestPublicLinkCommandRepository_Save_Success(t *testing.T) {
 		CreatedAt:       now,
 		UpdatedAt:       now,
 		ExpiredAt:       expiredAt,
+		VersionMode:     public_link.VersionModeLatest,
 	}
 
 	mock.ExpectExec(`INSERT INTO public_links`).
@@ -50,6 +51,11 @@ func TestPublicLinkCommandRepository_Save_Success(t *testing.T) {
 			p.CreatedAt,
 			p.UpdatedAt,
 			p.ExpiredAt,
+			p.PasswordHash,
+			p.MaxDownloads,
+			p.DownloadCount,
+			p.VersionMode.String(),
+			p.VersionID,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -110,19 +116,26 @@ func TestPublicLinkQueryRepository_GetByID_Success(t *testing.T) {
 	userID := uuid.New()
 	now := time.Now()
 	expiredAt := now.Add(time.Hour)
+	versionID := uuid.New()
 
 	mock.ExpectQuery(`SELECT id, file_id, created_by_user_id, token_hash,`).
 		WithArgs(id).
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "file_id", "created_by_user_id", "token_hash",
-			"created_at", "updated_at", "expired_at",
-		}).AddRow(id, fileID, userID, "token123", now, now, expiredAt))
+			"created_at", "updated_at", "expired_at", "password_hash",
+			"max_downloads", "download_count", "version_mode", "version_id",
+		}).AddRow(id, fileID, userID, "token123", now, now, expiredAt, "$2a$10$hash", 1, 1, "pinned", versionID))
 
 	p, err := repo.GetByID(context.Background(), id)
 	require.NoError(t, err)
 	require.NotNil(t, p)
 	require.Equal(t, "token123", p.TokenHash)
 	require.Equal(t, fileID, p.FileID)
+	require.True(t, p.HasPassword())
+	require.Equal(t, "$2a$10$hash", *p.PasswordHash)
+	require.True(t, p.IsExhausted())
+	require.True(t, p.VersionMode.Equal(public_link.VersionModePinned))
+	require.Equal(t, versionID, *p.VersionID)
 }
 
 func TestPublicLinkQueryRepository_GetByID_NoRows(t *testing.T) {
@@ -155,9 +168,10 @@ func TestPublicLinkQueryRepository_GetByFileID_Success(t *testing.T) {
 		WithArgs(fileID).
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "file_id", "created_by_user_id", "token_hash",
-			"created_at", "updated_at", "expired_at",
-		}).AddRow(uuid.New(), fileID, uuid.New(), "token1", now, now, now.Add(time.Hour)).
-			AddRow(uuid.New(), fileID, uuid.New(), "token2", now, now, now.Add(2*time.Hour)))
+			"created_at", "updated_at", "expired_at", "password_hash",
+			"max_downloads", "download_count", "version_mode", "version_id",
+		}).AddRow(uuid.New(), fileID, uuid.New(), "token1", now, now, now.Add(time.Hour), nil, nil, 0, "current", nil).
+			AddRow(uuid.New(), fileID, uuid.New(), "token2", now, now, now.Add(2*time.Hour), nil, nil, 0, "current", nil))
 
 	links, err := repo.GetByFileID(context.Background(), fileID)
 	require.NoError(t, err)
@@ -177,9 +191,10 @@ func TestPublicLinkQueryRepository_GetAll_Success(t *testing.T) {
 	mock.ExpectQuery(`SELECT id, file_id, created_by_user_id, token_hash,`).
 		WillReturnRows(sqlmock.NewRows([]string{
 			"id", "file_id", "created_by_user_id", "token_hash",
-			"created_at", "updated_at", "expired_at",
-		}).AddRow(uuid.New(), uuid.New(), uuid.New(), "token1", now, now, now.Add(time.Hour)).
-			AddRow(uuid.New(), uuid.New(), uuid.New(), "token2", now, now, now.Add(2*time.Hour)))
+			"created_at", "updated_at", "expired_at", "password_hash",
+			"max_downloads", "download_count", "version_mode", "version_id",
+		}).AddRow(uuid.New(), uuid.New(), uuid.New(), "token1", now, now, now.Add(time.Hour), nil, nil, 0, "current", nil).
+			AddRow(uuid.New(), uuid.New(), uuid.New(), "token2", now, now, now.Add(2*time.Hour), nil, nil, 0, "current", nil))
 
 	links, err := repo.GetAll(context.Background())
 	require.NoError(t, err)
@@ -187,3 +202,257 @@ func TestPublicLinkQueryRepository_GetAll_Success(t *testing.T) {
 	require.Equal(t, "token1", links[0].TokenHash)
 	require.Equal(t, "token2", links[1].TokenHash)
 }
+
+func TestPublicLinkCommandRepository_RegisterUnlockFailure_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewPublicLinkCommandRepository()
+	linkID := uuid.New()
+	now := time.Now()
+	windowStart := now.Add(-15 * time.Minute)
+
+	mock.ExpectQuery(`INSERT INTO public_link_unlock_attempts .* ON CONFLICT \(link_id, ip\) DO UPDATE`).
+		WithArgs(linkID, "10.0.0.1", now, windowStart).
+		WillReturnRows(sqlmock.NewRows([]string{"failures"}).AddRow(3))
+
+	failures, err := repo.RegisterUnlockFailure(ctx, linkID, "10.0.0.1", now, windowStart)
+	require.NoError(t, err)
+	require.Equal(t, 3, failures)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPublicLinkCommandRepository_RegisterUnlockFailure_NoTransaction(t *testing.T) {
+	repo := NewPublicLinkCommandRepository()
+
+	_, err := repo.RegisterUnlockFailure(context.Background(), uuid.New(), "10.0.0.1", time.Now(), time.Now())
+	require.ErrorIs(t, err, domainerrors.ErrTransactionNotFound)
+}
+
+func TestPublicLinkCommandRepository_LockUnlock_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewPublicLinkCommandRepository()
+	linkID := uuid.New()
+	until := time.Now().Add(15 * time.Minute)
+
+	mock.ExpectExec(`UPDATE public_link_unlock_attempts\s+SET failures = 0, locked_until = \$3`).
+		WithArgs(linkID, "10.0.0.1", until).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.LockUnlock(ctx, linkID, "10.0.0.1", until)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPublicLinkQueryRepository_GetUnlockAttempts_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewPublicLinkQueryRepository(sqlDB)
+	linkID := uuid.New()
+	now := time.Now()
+	lockedUntil := now.Add(10 * time.Minute)
+
+	mock.ExpectQuery(`FROM public_link_unlock_attempts\s+WHERE link_id = \$1 AND ip = \$2`).
+		WithArgs(linkID, "10.0.0.1").
+		WillReturnRows(sqlmock.NewRows([]string{"link_id", "ip", "failures", "locked_until", "updated_at"}).
+			AddRow(linkID, "10.0.0.1", 0, lockedUntil, now))
+
+	a, err := repo.GetUnlockAttempts(context.Background(), linkID, "10.0.0.1")
+	require.NoError(t, err)
+	require.NotNil(t, a)
+	require.True(t, a.IsLocked(now))
+	require.False(t, a.IsLocked(lockedUntil.Add(time.Second)))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPublicLinkQueryRepository_GetUnlockAttempts_NoRows(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewPublicLinkQueryRepository(sqlDB)
+	linkID := uuid.New()
+
+	mock.ExpectQuery(`FROM public_link_unlock_attempts`).
+		WithArgs(linkID, "10.0.0.1").
+		WillReturnError(sql.ErrNoRows)
+
+	a, err := repo.GetUnlockAttempts(context.Background(), linkID, "10.0.0.1")
+	require.NoError(t, err)
+	require.Nil(t, a)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPublicLinkCommandRepository_IncrementDownloads_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewPublicLinkCommandRepository()
+	linkID := uuid.New()
+
+	mock.ExpectQuery(`UPDATE public_links\s+SET download_count = download_count \+ 1`).
+		WithArgs(linkID).
+		WillReturnRows(sqlmock.NewRows([]string{"download_count"}).AddRow(2))
+
+	count, ok, err := repo.IncrementDownloads(ctx, linkID)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, 2, count)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPublicLinkCommandRepository_IncrementDownloads_Exhausted(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewPublicLinkCommandRepository()
+	linkID := uuid.New()
+
+	mock.ExpectQuery(`UPDATE public_links`).
+		WithArgs(linkID).
+		WillReturnError(sql.ErrNoRows)
+
+	_, ok, err := repo.IncrementDownloads(ctx, linkID)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPublicLinkCommandRepository_IncrementDownloads_NoTransaction(t *testing.T) {
+	repo := NewPublicLinkCommandRepository()
+
+	_, _, err := repo.IncrementDownloads(context.Background(), uuid.New())
+	require.ErrorIs(t, err, domainerrors.ErrTransactionNotFound)
+}
+
+func TestPublicLinkCommandRepository_SaveAccess_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewPublicLinkCommandRepository()
+	a := public_link.NewAccess(uuid.New(), uuid.New(), "10.0.0.1", "curl/8.0")
+
+	mock.ExpectExec(`INSERT INTO public_link_accesses`).
+		WithArgs(a.ID, a.LinkID, a.VersionID, a.IP, a.UserAgent, a.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.SaveAccess(ctx, a)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPublicLinkQueryRepository_GetAccesses_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewPublicLinkQueryRepository(sqlDB)
+	linkID := uuid.New()
+	versionID := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery(`FROM public_link_accesses\s+WHERE link_id = \$1\s+ORDER BY created_at DESC`).
+		WithArgs(linkID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "link_id", "version_id", "ip", "user_agent", "created_at"}).
+			AddRow(uuid.New(), linkID, versionID, "10.0.0.1", "curl/8.0", now).
+			AddRow(uuid.New(), linkID, nil, "10.0.0.2", "", now.Add(-time.Minute)))
+
+	accesses, err := repo.GetAccesses(context.Background(), linkID)
+	require.NoError(t, err)
+	require.Len(t, accesses, 2)
+	require.Equal(t, versionID, *accesses[0].VersionID)
+	require.Nil(t, accesses[1].VersionID)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPublicLinkCommandRepository_DeleteExpired_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewPublicLinkCommandRepository()
+	before := time.Now()
+	id1, id2 := uuid.New(), uuid.New()
+
+	mock.ExpectQuery(`DELETE FROM public_links\s+WHERE id IN .*FOR UPDATE SKIP LOCKED`).
+		WithArgs(before, 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id1).AddRow(id2))
+
+	ids, err := repo.DeleteExpired(ctx, before, 100)
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{id1, id2}, ids)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPublicLinkCommandRepository_ArchiveExpired_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewPublicLinkCommandRepository()
+	before := time.Now()
+	id := uuid.New()
+
+	mock.ExpectQuery(`WITH expired AS \(\s+DELETE FROM public_links.*INSERT INTO public_links_archive`).
+		WithArgs(before, 100).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
+
+	ids, err := repo.ArchiveExpired(ctx, before, 100)
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{id}, ids)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPublicLinkCommandRepository_DeleteExpired_NoTransaction(t *testing.T) {
+	repo := NewPublicLinkCommandRepository()
+
+	_, err := repo.DeleteExpired(context.Background(), time.Now(), 100)
+	require.ErrorIs(t, err, domainerrors.ErrTransactionNotFound)
+
+	_, err = repo.ArchiveExpired(context.Background(), time.Now(), 100)
+	require.ErrorIs(t, err, domainerrors.ErrTransactionNotFound)
+}
@@ -0,0 +1,144 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
+	"github.com/yourusername/cloud-file-storage/internal/domain/quota"
+)
+
+type QuotaCommandRepository struct {
+}
+
+func NewQuotaCommandRepository() *QuotaCommandRepository {
+	return &QuotaCommandRepository{}
+}
+
+// Lock создаёт квоту при первом обращении и берёт блокировку строки до конца транзакции
+func (r *QuotaCommandRepository) Lock(ctx context.Context, q *quota.Quota) (*quota.Quota, error) {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return nil, domainerrors.ErrTransactionNotFound
+	}
+
+	// Для пользователей, загрузивших файлы до появления квот, занятый объём считается по версиям
+	_, err := tx.ExecContext(ctx, `
+    INSERT INTO user_quotas (user_id, limit_bytes, used_bytes, created_at, updated_at)
+    SELECT $1, $2, COALESCE(SUM(v.size), 0), $3, $4
+    FROM file_versions v
+    JOIN files f ON f.id = v.file_id
+    WHERE f.owner_id = $1
+    ON CONFLICT (user_id) DO NOTHING
+    `, q.UserID, q.LimitBytes, q.CreatedAt, q.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	row := tx.QueryRowContext(ctx, `
+    SELECT user_id, limit_bytes, used_bytes, created_at, updated_at
+    FROM user_quotas
+    WHERE user_id = $1
+    FOR UPDATE
+    `, q.UserID)
+
+	return scanQuota(row)
+}
+
+func (r *QuotaCommandRepository) Save(ctx context.Context, q *quota.Quota) error {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return domainerrors.ErrTransactionNotFound
+	}
+
+	query := `
+    INSERT INTO user_quotas (user_id, limit_bytes, used_bytes, created_at, updated_at)
+    VALUES ($1, $2, $3, $4, $5)
+    ON CONFLICT (user_id) DO UPDATE 
+    SET limit_bytes = EXCLUDED.limit_bytes, 
+        used_bytes = EXCLUDED.used_bytes, 
+        updated_at = EXCLUDED.updated_at
+    `
+	_, err := tx.ExecContext(ctx, query,
+		q.UserID,
+		q.LimitBytes,
+		q.UsedBytes,
+		q.CreatedAt,
+		q.UpdatedAt,
+	)
+	return err
+}
+
+type QuotaQueryRepository struct {
+	db *sql.DB
+}
+
+func NewQuotaQueryRepository(db *sql.DB) *QuotaQueryRepository {
+	return &QuotaQueryRepository{db: db}
+}
+
+func scanQuota(scanner scannable) (*quota.Quota, error) {
+	var q quota.Quota
+	if err := scanner.Scan(&q.UserID, &q.LimitBytes, &q.UsedBytes, &q.CreatedAt, &q.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+func (r *QuotaQueryRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*quota.Quota, error) {
+	row := r.db.QueryRowContext(ctx, `
+        SELECT user_id, limit_bytes, used_bytes, created_at, updated_at
+        FROM user_quotas
+        WHERE user_id = $1
+    `, userID)
+
+	q, err := scanQuota(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return q, err
+}
+
+func (r *QuotaQueryRepository) GetUsageByStatus(ctx context.Context, userID uuid.UUID) ([]quota.UsageEntry, error) {
+	return r.getUsage(ctx, `
+        SELECT v.status::text, COUNT(*), COALESCE(SUM(v.size), 0)
+        FROM file_versions v
+        JOIN files f ON f.id = v.file_id
+        WHERE f.owner_id = $1
+        GROUP BY v.status
+        ORDER BY v.status
+    `, userID)
+}
+
+func (r *QuotaQueryRepository) GetUsageByMime(ctx context.Context, userID uuid.UUID) ([]quota.UsageEntry, error) {
+	return r.getUsage(ctx, `
+        SELECT v.mime, COUNT(*), COALESCE(SUM(v.size), 0)
+        FROM file_versions v
+        JOIN files f ON f.id = v.file_id
+        WHERE f.owner_id = $1
+        GROUP BY v.mime
+        ORDER BY SUM(v.size) DESC, v.mime
+    `, userID)
+}
+
+func (r *QuotaQueryRepository) getUsage(ctx context.Context, query string, userID uuid.UUID) ([]quota.UsageEntry, error) {
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []quota.UsageEntry
+	for rows.Next() {
+		var e quota.UsageEntry
+		if err := rows.Scan(&e.Key, &e.Versions, &e.Bytes); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
+	"github.com/yourusername/cloud-file-storage/internal/domain/quota"
+)
+
+func TestQuotaCommandRepository_Lock(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewQuotaCommandRepository()
+	q := quota.NewQuota(uuid.New(), 1000)
+
+	mock.ExpectExec(`INSERT INTO user_quotas .* SELECT .* FROM file_versions`).
+		WithArgs(q.UserID, int64(1000), q.CreatedAt, q.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT .* FROM user_quotas .* FOR UPDATE`).
+		WithArgs(q.UserID).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "limit_bytes", "used_bytes", "created_at", "updated_at"}).
+			AddRow(q.UserID, 5000, 700, q.CreatedAt, q.UpdatedAt))
+
+	locked, err := repo.Lock(ctx, q)
+	require.NoError(t, err)
+	require.Equal(t, int64(5000), locked.LimitBytes)
+	require.Equal(t, int64(700), locked.UsedBytes)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQuotaCommandRepository_Lock_NoTransaction(t *testing.T) {
+	repo := NewQuotaCommandRepository()
+
+	_, err := repo.Lock(context.Background(), quota.NewQuota(uuid.New(), 1000))
+	require.ErrorIs(t, err, domainerrors.ErrTransactionNotFound)
+}
+
+func TestQuotaQueryRepository_GetUsageByStatus(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewQuotaQueryRepository(sqlDB)
+	userID := uuid.New()
+
+	mock.ExpectQuery(`SELECT v.status::text, COUNT\(\*\), COALESCE\(SUM\(v.size\), 0\)`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"status", "count", "sum"}).
+			AddRow("ready", 3, 3000).
+			AddRow("uploaded", 1, 50))
+
+	entries, err := repo.GetUsageByStatus(context.Background(), userID)
+	require.NoError(t, err)
+	require.Equal(t, []quota.UsageEntry{
+		{Key: "ready", Versions: 3, Bytes: 3000},
+		{Key: "uploaded", Versions: 1, Bytes: 50},
+	}, entries)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
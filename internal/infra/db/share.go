@@ -0,0 +1,162 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
+	"github.com/yourusername/cloud-file-storage/internal/domain/share"
+)
+
+func scanShare(scanner scannable) (*share.Share, error) {
+	var s share.Share
+	var role string
+
+	if err := scanner.Scan(
+		&s.ID,
+		&s.FileID,
+		&s.UserID,
+		&role,
+		&s.GrantedBy,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	var err error
+	s.Role, err = share.NewRole(role)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+func scanShares(rows *sql.Rows) ([]*share.Share, error) {
+	defer rows.Close()
+
+	shares := make([]*share.Share, 0)
+	for rows.Next() {
+		s, err := scanShare(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan share: %w", err)
+		}
+		shares = append(shares, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return shares, nil
+}
+
+type ShareCommandRepository struct {
+}
+
+func NewShareCommandRepository() *ShareCommandRepository {
+	return &ShareCommandRepository{}
+}
+
+// Save при параллельной выдаче тому же пользователю сохраняется одна строка, её id и created_at возвращаются в s
+func (r *ShareCommandRepository) Save(ctx context.Context, s *share.Share) error {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return domainerrors.ErrTransactionNotFound
+	}
+
+	query := `
+    INSERT INTO file_shares (id, file_id, user_id, role, granted_by, created_at, updated_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7)
+    ON CONFLICT (file_id, user_id) DO UPDATE
+    SET role = EXCLUDED.role,
+        granted_by = EXCLUDED.granted_by,
+        updated_at = EXCLUDED.updated_at
+    RETURNING id, created_at
+    `
+	return tx.QueryRowContext(ctx, query,
+		s.ID,
+		s.FileID,
+		s.UserID,
+		s.Role.String(),
+		s.GrantedBy,
+		s.CreatedAt,
+		s.UpdatedAt,
+	).Scan(&s.ID, &s.CreatedAt)
+}
+
+func (r *ShareCommandRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return domainerrors.ErrTransactionNotFound
+	}
+
+	_, err := tx.ExecContext(ctx, `DELETE FROM file_shares WHERE id = $1`, id)
+	return err
+}
+
+type ShareQueryRepository struct {
+	db *sql.DB
+}
+
+func NewShareQueryRepository(db *sql.DB) *ShareQueryRepository {
+	return &ShareQueryRepository{db: db}
+}
+
+func (r *ShareQueryRepository) GetByID(ctx context.Context, id uuid.UUID) (*share.Share, error) {
+	row := r.db.QueryRowContext(ctx, `
+        SELECT id, file_id, user_id, role, granted_by, created_at, updated_at
+        FROM file_shares
+        WHERE id = $1
+    `, id)
+
+	s, err := scanShare(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return s, err
+}
+
+func (r *ShareQueryRepository) GetByFileID(ctx context.Context, fileID uuid.UUID) ([]*share.Share, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT id, file_id, user_id, role, granted_by, created_at, updated_at
+        FROM file_shares
+        WHERE file_id = $1
+        ORDER BY created_at
+    `, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query shares: %w", err)
+	}
+	return scanShares(rows)
+}
+
+func (r *ShareQueryRepository) GetByFileAndUser(ctx context.Context, fileID, userID uuid.UUID) (*share.Share, error) {
+	row := r.db.QueryRowContext(ctx, `
+        SELECT id, file_id, user_id, role, granted_by, created_at, updated_at
+        FROM file_shares
+        WHERE file_id = $1 AND user_id = $2
+    `, fileID, userID)
+
+	s, err := scanShare(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return s, err
+}
+
+func (r *ShareQueryRepository) GetSharedWithUser(ctx context.Context, userID uuid.UUID) ([]*share.Share, error) {
+	rows, err := r.db.QueryContext(ctx, `
+        SELECT s.id, s.file_id, s.user_id, s.role, s.granted_by, s.created_at, s.updated_at
+        FROM file_shares s
+        JOIN files f ON f.id = s.file_id
+        WHERE s.user_id = $1 AND f.deleted_at IS NULL
+        ORDER BY s.created_at DESC
+    `, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query shares: %w", err)
+	}
+	return scanShares(rows)
+}
@@ -0,0 +1,140 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
+	"github.com/yourusername/cloud-file-storage/internal/domain/share"
+)
+
+var shareColumns = []string{
+	"id", "file_id", "user_id", "role", "granted_by", "created_at", "updated_at",
+}
+
+func TestShareCommandRepository_Save_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewShareCommandRepository()
+	s := share.NewShare(uuid.New(), uuid.New(), share.RoleEditor, uuid.New())
+
+	// строка уже была: id и created_at берутся из неё
+	existingID := uuid.New()
+	createdAt := time.Now().Add(-time.Hour)
+
+	mock.ExpectQuery(`INSERT INTO file_shares .* ON CONFLICT \(file_id, user_id\) DO UPDATE`).
+		WithArgs(s.ID, s.FileID, s.UserID, "editor", s.GrantedBy, s.CreatedAt, s.UpdatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(existingID, createdAt))
+
+	err = repo.Save(ctx, s)
+	require.NoError(t, err)
+	require.Equal(t, existingID, s.ID)
+	require.Equal(t, createdAt, s.CreatedAt)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestShareCommandRepository_Save_NoTransaction(t *testing.T) {
+	repo := NewShareCommandRepository()
+
+	err := repo.Save(context.Background(), &share.Share{})
+	require.ErrorIs(t, err, domainerrors.ErrTransactionNotFound)
+}
+
+func TestShareCommandRepository_Delete_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewShareCommandRepository()
+	id := uuid.New()
+
+	mock.ExpectExec(`DELETE FROM file_shares WHERE id = \$1`).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.Delete(ctx, id)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestShareQueryRepository_GetByFileAndUser_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewShareQueryRepository(sqlDB)
+
+	id, fileID, userID, grantedBy := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery(`FROM file_shares\s+WHERE file_id = \$1 AND user_id = \$2`).
+		WithArgs(fileID, userID).
+		WillReturnRows(sqlmock.NewRows(shareColumns).
+			AddRow(id, fileID, userID, "viewer", grantedBy, now, now))
+
+	s, err := repo.GetByFileAndUser(context.Background(), fileID, userID)
+	require.NoError(t, err)
+	require.NotNil(t, s)
+	require.Equal(t, id, s.ID)
+	require.True(t, s.Role.Equal(share.RoleViewer))
+	require.Equal(t, grantedBy, s.GrantedBy)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestShareQueryRepository_GetByFileAndUser_NoRows(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewShareQueryRepository(sqlDB)
+	fileID, userID := uuid.New(), uuid.New()
+
+	mock.ExpectQuery(`FROM file_shares`).
+		WithArgs(fileID, userID).
+		WillReturnError(sql.ErrNoRows)
+
+	s, err := repo.GetByFileAndUser(context.Background(), fileID, userID)
+	require.NoError(t, err)
+	require.Nil(t, s)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestShareQueryRepository_GetSharedWithUser_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewShareQueryRepository(sqlDB)
+	userID := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery(`JOIN files f ON f.id = s.file_id\s+WHERE s.user_id = \$1 AND f.deleted_at IS NULL`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows(shareColumns).
+			AddRow(uuid.New(), uuid.New(), userID, "editor", uuid.New(), now, now).
+			AddRow(uuid.New(), uuid.New(), userID, "owner", uuid.New(), now, now))
+
+	shares, err := repo.GetSharedWithUser(context.Background(), userID)
+	require.NoError(t, err)
+	require.Len(t, shares, 2)
+	require.True(t, shares[0].Role.Equal(share.RoleEditor))
+	require.True(t, shares[1].Role.Equal(share.RoleOwner))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
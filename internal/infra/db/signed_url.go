@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
+	"github.com/yourusername/cloud-file-storage/internal/domain/signed_url"
+)
+
+type SignedURLCommandRepository struct {
+}
+
+func NewSignedURLCommandRepository() *SignedURLCommandRepository {
+	return &SignedURLCommandRepository{}
+}
+
+func (r *SignedURLCommandRepository) SetEpoch(ctx context.Context, fileID uuid.UUID, epoch int64) (bool, error) {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return false, domainerrors.ErrTransactionNotFound
+	}
+
+	// Эпоха только растёт, даже если часы узлов расходятся
+	res, err := tx.ExecContext(ctx, `
+        UPDATE files
+        SET signed_url_epoch = GREATEST(signed_url_epoch, $2)
+        WHERE id = $1
+    `, fileID, epoch)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+type SignedURLQueryRepository struct {
+	db *sql.DB
+}
+
+func NewSignedURLQueryRepository(db *sql.DB) *SignedURLQueryRepository {
+	return &SignedURLQueryRepository{db: db}
+}
+
+func (r *SignedURLQueryRepository) GetFileState(ctx context.Context, fileID uuid.UUID) (*signed_url.FileState, error) {
+	var state signed_url.FileState
+	err := r.db.QueryRowContext(ctx, `
+        SELECT signed_url_epoch, deleted_at IS NOT NULL
+        FROM files
+        WHERE id = $1
+    `, fileID).Scan(&state.Epoch, &state.Trashed)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
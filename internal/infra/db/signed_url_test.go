@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
+)
+
+func TestSignedURLCommandRepository_SetEpoch_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewSignedURLCommandRepository()
+	fileID := uuid.New()
+
+	mock.ExpectExec(`UPDATE files\s+SET signed_url_epoch = GREATEST\(signed_url_epoch, \$2\)`).
+		WithArgs(fileID, int64(1700000000)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ok, err := repo.SetEpoch(ctx, fileID, 1700000000)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSignedURLCommandRepository_SetEpoch_FileNotFound(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewSignedURLCommandRepository()
+	fileID := uuid.New()
+
+	mock.ExpectExec(`UPDATE files`).
+		WithArgs(fileID, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ok, err := repo.SetEpoch(ctx, fileID, 1)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSignedURLCommandRepository_SetEpoch_NoTransaction(t *testing.T) {
+	repo := NewSignedURLCommandRepository()
+
+	_, err := repo.SetEpoch(context.Background(), uuid.New(), 1)
+	require.ErrorIs(t, err, domainerrors.ErrTransactionNotFound)
+}
+
+func TestSignedURLQueryRepository_GetFileState_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewSignedURLQueryRepository(sqlDB)
+	fileID := uuid.New()
+
+	mock.ExpectQuery(`SELECT signed_url_epoch, deleted_at IS NOT NULL\s+FROM files`).
+		WithArgs(fileID).
+		WillReturnRows(sqlmock.NewRows([]string{"signed_url_epoch", "trashed"}).AddRow(1700000000, true))
+
+	state, err := repo.GetFileState(context.Background(), fileID)
+	require.NoError(t, err)
+	require.NotNil(t, state)
+	require.Equal(t, int64(1700000000), state.Epoch)
+	require.True(t, state.Trashed)
+}
+
+func TestSignedURLQueryRepository_GetFileState_NoRows(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewSignedURLQueryRepository(sqlDB)
+	fileID := uuid.New()
+
+	mock.ExpectQuery(`SELECT signed_url_epoch`).
+		WithArgs(fileID).
+		WillReturnError(sql.ErrNoRows)
+
+	state, err := repo.GetFileState(context.Background(), fileID)
+	require.NoError(t, err)
+	require.Nil(t, state)
+}
@@ -0,0 +1,186 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/domain/domainerrors"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
+	"github.com/yourusername/cloud-file-storage/internal/domain/tus_upload"
+)
+
+// tusPart формат элемента колонки parts
+type tusPart struct {
+	PartNumber int32  `json:"part_number"`
+	ETag       string `json:"etag"`
+}
+
+func marshalTusParts(parts []storage.CompletedPart) ([]byte, error) {
+	out := make([]tusPart, 0, len(parts))
+	for _, p := range parts {
+		out = append(out, tusPart{PartNumber: p.PartNumber, ETag: p.ETag})
+	}
+	return json.Marshal(out)
+}
+
+func unmarshalTusParts(data []byte) ([]storage.CompletedPart, error) {
+	var in []tusPart
+	if err := json.Unmarshal(data, &in); err != nil {
+		return nil, err
+	}
+	parts := make([]storage.CompletedPart, 0, len(in))
+	for _, p := range in {
+		parts = append(parts, storage.CompletedPart{PartNumber: p.PartNumber, ETag: p.ETag})
+	}
+	return parts, nil
+}
+
+type TusUploadCommandRepository struct {
+}
+
+func NewTusUploadCommandRepository() *TusUploadCommandRepository {
+	return &TusUploadCommandRepository{}
+}
+
+func (r *TusUploadCommandRepository) Create(ctx context.Context, u *tus_upload.TusUpload) error {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return domainerrors.ErrTransactionNotFound
+	}
+
+	parts, err := marshalTusParts(u.Parts)
+	if err != nil {
+		return err
+	}
+
+	query := `
+    INSERT INTO tus_uploads (id, file_id, version_id, owner_id, s3_key, multipart_id,
+               upload_length, upload_offset, pending_size, parts, created_at, updated_at)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+    `
+	_, err = tx.ExecContext(ctx, query,
+		u.ID,
+		u.FileID,
+		u.VersionID,
+		u.OwnerID,
+		u.S3Key.String(),
+		u.MultipartID,
+		u.Length,
+		u.Offset,
+		u.PendingSize,
+		parts,
+		u.CreatedAt,
+		u.UpdatedAt,
+	)
+	return err
+}
+
+func (r *TusUploadCommandRepository) Update(ctx context.Context, u *tus_upload.TusUpload, prevOffset int64) error {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return domainerrors.ErrTransactionNotFound
+	}
+
+	parts, err := marshalTusParts(u.Parts)
+	if err != nil {
+		return err
+	}
+
+	query := `
+    UPDATE tus_uploads
+    SET upload_offset = $2, pending_size = $3, parts = $4, updated_at = $5
+    WHERE id = $1 AND upload_offset = $6
+    `
+	result, err := tx.ExecContext(ctx, query,
+		u.ID,
+		u.Offset,
+		u.PendingSize,
+		parts,
+		u.UpdatedAt,
+		prevOffset,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return tus_upload.ErrOffsetMismatch
+	}
+	return nil
+}
+
+func (r *TusUploadCommandRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tx, ok := ctx.Value("tx").(*sql.Tx)
+	if !ok {
+		return domainerrors.ErrTransactionNotFound
+	}
+
+	_, err := tx.ExecContext(ctx, `DELETE FROM tus_uploads WHERE id = $1`, id)
+	return err
+}
+
+type TusUploadQueryRepository struct {
+	db *sql.DB
+}
+
+func NewTusUploadQueryRepository(db *sql.DB) *TusUploadQueryRepository {
+	return &TusUploadQueryRepository{db: db}
+}
+
+func scanTusUpload(scanner scannable) (*tus_upload.TusUpload, error) {
+	var u tus_upload.TusUpload
+	var s3Key string
+	var parts []byte
+
+	if err := scanner.Scan(
+		&u.ID,
+		&u.FileID,
+		&u.VersionID,
+		&u.OwnerID,
+		&s3Key,
+		&u.MultipartID,
+		&u.Length,
+		&u.Offset,
+		&u.PendingSize,
+		&parts,
+		&u.CreatedAt,
+		&u.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	key, err := file_version.NewS3Key(s3Key)
+	if err != nil {
+		return nil, err
+	}
+	u.S3Key = key
+
+	u.Parts, err = unmarshalTusParts(parts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+func (r *TusUploadQueryRepository) GetByID(ctx context.Context, id uuid.UUID) (*tus_upload.TusUpload, error) {
+	row := r.db.QueryRowContext(ctx, `
+        SELECT id, file_id, version_id, owner_id, s3_key, multipart_id,
+               upload_length, upload_offset, pending_size, parts, created_at, updated_at
+        FROM tus_uploads
+        WHERE id = $1
+    `, id)
+
+	u, err := scanTusUpload(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return u, err
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
+	"github.com/yourusername/cloud-file-storage/internal/domain/tus_upload"
+)
+
+func newTestTusUpload() *tus_upload.TusUpload {
+	key, _ := file_version.NewS3Key("files/a/b/v1/video.mp4")
+	return tus_upload.NewTusUpload(uuid.New(), uuid.New(), uuid.New(), key, "s3-upload-id", 20<<20)
+}
+
+func TestTusUploadCommandRepository_Create_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewTusUploadCommandRepository()
+	u := newTestTusUpload()
+
+	mock.ExpectExec(`INSERT INTO tus_uploads`).
+		WithArgs(u.ID, u.FileID, u.VersionID, u.OwnerID, "files/a/b/v1/video.mp4", "s3-upload-id",
+			int64(20<<20), int64(0), int64(0), []byte("[]"), u.CreatedAt, u.UpdatedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.Create(ctx, u)
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTusUploadCommandRepository_Update_OffsetMismatch(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	mock.ExpectBegin()
+	tx, err := sqlDB.Begin()
+	require.NoError(t, err)
+	ctx := context.WithValue(context.Background(), "tx", tx)
+
+	repo := NewTusUploadCommandRepository()
+	u := newTestTusUpload()
+	require.NoError(t, u.Advance(8<<20, []storage.CompletedPart{{PartNumber: 1, ETag: "e1"}}, 0))
+
+	mock.ExpectExec(`UPDATE tus_uploads`).
+		WithArgs(u.ID, int64(8<<20), int64(0), []byte(`[{"part_number":1,"etag":"e1"}]`), u.UpdatedAt, int64(0)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Update(ctx, u, 0)
+	require.ErrorIs(t, err, tus_upload.ErrOffsetMismatch)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTusUploadQueryRepository_GetByID_Success(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewTusUploadQueryRepository(sqlDB)
+
+	id := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT id, file_id, version_id, owner_id, s3_key, multipart_id`).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "file_id", "version_id", "owner_id", "s3_key", "multipart_id",
+			"upload_length", "upload_offset", "pending_size", "parts", "created_at", "updated_at",
+		}).AddRow(id, uuid.New(), uuid.New(), uuid.New(), "files/key", "s3-upload-id",
+			int64(100), int64(40), int64(40), []byte(`[]`), now, now))
+
+	u, err := repo.GetByID(context.Background(), id)
+	require.NoError(t, err)
+	require.NotNil(t, u)
+	require.Equal(t, int64(60), u.Remaining())
+	require.Equal(t, int32(1), u.NextPartNumber())
+}
+
+func TestTusUploadQueryRepository_GetByID_NoRows(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+
+	repo := NewTusUploadQueryRepository(sqlDB)
+	id := uuid.New()
+
+	mock.ExpectQuery(`SELECT id, file_id, version_id, owner_id, s3_key, multipart_id`).
+		WithArgs(id).
+		WillReturnError(sql.ErrNoRows)
+
+	u, err := repo.GetByID(context.Background(), id)
+	require.NoError(t, err)
+	require.Nil(t, u)
+}
@@ -0,0 +1,119 @@
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/yourusername/cloud-file-storage/internal/domain/encryption"
+)
+
+// masterKeySize мастер-ключи — AES-256
+const masterKeySize = 32
+
+// localKeysFile формат файла ключей:
+//
+//	{"current": "2025-11", "keys": {"2025-11": "<base64, 32 байта>", "2025-05": "..."}}
+//
+// Старые ключи остаются в файле, пока команда rewrap-keys не переобернёт ими зашифрованные объекты.
+type localKeysFile struct {
+	Current string            `json:"current"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// LocalKeyProvider держит мастер-ключи из JSON файла в памяти. Подходит для разработки и тестов,
+// в проде ключи должны жить в KMS.
+type LocalKeyProvider struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+func NewLocalKeyProvider(path string) (*LocalKeyProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keys file: %w", err)
+	}
+
+	var file localKeysFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse keys file: %w", err)
+	}
+
+	keys := make(map[string][]byte, len(file.Keys))
+	for id, encoded := range file.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("master key %q is not valid base64: %w", id, err)
+		}
+		keys[id] = key
+	}
+
+	return NewLocalKeyProviderFromKeys(file.Current, keys)
+}
+
+func NewLocalKeyProviderFromKeys(current string, keys map[string][]byte) (*LocalKeyProvider, error) {
+	if _, ok := keys[current]; !ok {
+		return nil, fmt.Errorf("current master key %q is not defined", current)
+	}
+
+	p := &LocalKeyProvider{current: current, keys: make(map[string]cipher.AEAD, len(keys))}
+	for id, key := range keys {
+		if id == "" || len(id) > 255 {
+			return nil, fmt.Errorf("master key id %q must be 1-255 bytes", id)
+		}
+		if len(key) != masterKeySize {
+			return nil, fmt.Errorf("master key %q must be %d bytes", id, masterKeySize)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		p.keys[id] = aead
+	}
+
+	return p, nil
+}
+
+func (p *LocalKeyProvider) CurrentKeyID() string {
+	return p.current
+}
+
+// Wrap шифрует ключ данных AES-GCM; идентификатор мастер-ключа входит в AAD,
+// поэтому обёрнутый ключ нельзя выдать за обёрнутый другим мастер-ключом
+func (p *LocalKeyProvider) Wrap(ctx context.Context, dataKey []byte) (string, []byte, error) {
+	aead := p.keys[p.current]
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, err
+	}
+
+	return p.current, aead.Seal(nonce, nonce, dataKey, []byte(p.current)), nil
+}
+
+func (p *LocalKeyProvider) Unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	aead, ok := p.keys[keyID]
+	if !ok {
+		return nil, encryption.ErrKeyNotFound
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, encryption.ErrInvalidWrapped
+	}
+
+	nonce, sealed := wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():]
+	dataKey, err := aead.Open(nil, nonce, sealed, []byte(keyID))
+	if err != nil {
+		return nil, encryption.ErrInvalidWrapped
+	}
+	return dataKey, nil
+}
@@ -0,0 +1,62 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/encryption"
+)
+
+func TestLocalKeyProvider_FromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	keys := `{"current": "k2", "keys": {
+		"k1": "AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE=",
+		"k2": "AgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgI="
+	}}`
+	require.NoError(t, os.WriteFile(path, []byte(keys), 0o600))
+
+	p, err := NewLocalKeyProvider(path)
+	require.NoError(t, err)
+	assert.Equal(t, "k2", p.CurrentKeyID())
+
+	_, err = NewLocalKeyProviderFromKeys("missing", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)})
+	assert.Error(t, err)
+
+	_, err = NewLocalKeyProviderFromKeys("k1", map[string][]byte{"k1": []byte("short")})
+	assert.Error(t, err)
+}
+
+func TestLocalKeyProvider_WrapUnwrap(t *testing.T) {
+	ctx := context.Background()
+	p, err := NewLocalKeyProviderFromKeys("k2", map[string][]byte{
+		"k1": bytes.Repeat([]byte{1}, 32),
+		"k2": bytes.Repeat([]byte{2}, 32),
+	})
+	require.NoError(t, err)
+
+	dataKey := bytes.Repeat([]byte{7}, 32)
+	keyID, wrapped, err := p.Wrap(ctx, dataKey)
+	require.NoError(t, err)
+	assert.Equal(t, "k2", keyID)
+	assert.NotContains(t, string(wrapped), string(dataKey))
+
+	unwrapped, err := p.Unwrap(ctx, keyID, wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+
+	// Обёрнутый одним ключом нельзя развернуть другим
+	_, err = p.Unwrap(ctx, "k1", wrapped)
+	assert.ErrorIs(t, err, encryption.ErrInvalidWrapped)
+
+	_, err = p.Unwrap(ctx, "k3", wrapped)
+	assert.ErrorIs(t, err, encryption.ErrKeyNotFound)
+
+	wrapped[len(wrapped)-1] ^= 0xff
+	_, err = p.Unwrap(ctx, keyID, wrapped)
+	assert.ErrorIs(t, err, encryption.ErrInvalidWrapped)
+}
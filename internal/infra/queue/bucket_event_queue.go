@@ -0,0 +1,25 @@
+package queue
+
+import (
+	"context"
+
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
+	infra_storage "github.com/yourusername/cloud-file-storage/internal/infra/storage"
+)
+
+// KafkaBucketEventConsumer читает уведомления бакета, которые S3/MinIO публикуют в Kafka
+type KafkaBucketEventConsumer struct {
+	reader KafkaReader
+}
+
+func NewKafkaBucketEventConsumer(reader KafkaReader) *KafkaBucketEventConsumer {
+	return &KafkaBucketEventConsumer{reader: reader}
+}
+
+func (c *KafkaBucketEventConsumer) Consume(ctx context.Context) ([]storage.ObjectCreated, error) {
+	msg, err := c.reader.ReadMessage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return infra_storage.ParseBucketNotification(msg.Value)
+}
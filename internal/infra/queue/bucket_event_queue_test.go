@@ -0,0 +1,31 @@
+package queue
+
+import (
+	"context"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKafkaBucketEventConsumer_Consume(t *testing.T) {
+
+	writer, reader := NewMockQueue()
+
+	consumer := NewKafkaBucketEventConsumer(reader)
+
+	// Формат MinIO: ключ URL-кодирован, в одном сообщении может быть несколько записей
+	data := []byte(`{"EventName":"s3:ObjectCreated:Put","Key":"bucket/files/a b.txt","Records":[
+		{"eventName":"s3:ObjectCreated:Put","s3":{"object":{"key":"files%2Fa+b.txt","size":12,"eTag":"abc"}}},
+		{"eventName":"s3:ObjectRemoved:Delete","s3":{"object":{"key":"files%2Fold.txt"}}}
+	]}`)
+	err := writer.WriteMessages(context.Background(), kafka.Message{Topic: "storage-events", Value: data})
+	require.NoError(t, err)
+
+	events, err := consumer.Consume(context.Background())
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, "files/a b.txt", events[0].Key)
+	require.Equal(t, "s3:ObjectCreated:Put", events[0].EventName)
+	require.Equal(t, int64(12), events[0].Size)
+}
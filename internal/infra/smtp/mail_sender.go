@@ -10,8 +10,6 @@ import (
 	"strconv"
 	"strings"
 	"time"
-
-	"github.com/yourusername/cloud-file-storage/internal/domain/value_objects"
 )
 
 type SMTPMailSender struct {
@@ -32,7 +30,7 @@ func NewSMTPMailSender(host string, port int, name string, email string, passwor
 	}
 }
 
-func (s *SMTPMailSender) SendMagicLink(ctx context.Context, tokenHash value_objects.TokenHash, to string, baseURL string) error {
+func (s *SMTPMailSender) SendMagicLink(ctx context.Context, token string, to string, baseURL string) error {
 	c, conn, err := s.Auth(ctx)
 	if err != nil {
 		return err
@@ -44,7 +42,7 @@ func (s *SMTPMailSender) SendMagicLink(ctx context.Context, tokenHash value_obje
 		_ = conn.Close()
 	}()
 
-	link := fmt.Sprintf("%s/magic?token=%s", strings.TrimRight(baseURL, "/"), tokenHash.String())
+	link := fmt.Sprintf("%s/magic?token=%s", strings.TrimRight(baseURL, "/"), token)
 	from := s.email
 
 	if err := c.Mail(from); err != nil {
@@ -3,8 +3,6 @@ package smtp
 import (
 	"context"
 	"errors"
-
-	"github.com/yourusername/cloud-file-storage/internal/domain/value_objects"
 )
 
 type SentEmail struct {
@@ -26,7 +24,7 @@ func NewMockMailSender() *MockMailSender {
 	}
 }
 
-func (m *MockMailSender) SendMagicLink(ctx context.Context, tokenHash value_objects.TokenHash, to string, baseURL string) error {
+func (m *MockMailSender) SendMagicLink(ctx context.Context, token string, to string, baseURL string) error {
 	if m.shouldFail {
 		if m.failError != nil {
 			return m.failError
@@ -34,7 +32,6 @@ func (m *MockMailSender) SendMagicLink(ctx context.Context, tokenHash value_obje
 		return errors.New("failed to send email")
 	}
 
-	token := tokenHash.String()
 	m.sentEmails = append(m.sentEmails, SentEmail{
 		To:      to,
 		Subject: "Your Magic Link",
@@ -0,0 +1,598 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/yourusername/cloud-file-storage/internal/domain/encryption"
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
+)
+
+const (
+	// encryptedUploadsPrefix описания multipart загрузок с обёрнутым ключом данных лежат рядом с объектами
+	encryptedUploadsPrefix = ".encryption/uploads/"
+	// encryptedMaxPartSize часть шифруется в памяти, поэтому её размер ограничен
+	encryptedMaxPartSize = 64 << 20
+)
+
+// EncryptedStorage шифрует объекты на лету перед записью во вложенное хранилище.
+// У каждого объекта свой ключ данных, обёрнутый мастер-ключом из KeyProvider; формат описан в envelope.go.
+// Объекты без заголовка считаются записанными до включения шифрования и читаются как есть.
+//
+// Presigned URL вложенного хранилища отдали бы клиенту шифротекст, поэтому ссылки ведут на маршруты API,
+// подписанные HMAC, как у LocalStorage.
+type EncryptedStorage struct {
+	urlSigner
+	inner storage.Storage
+	keys  encryption.KeyProvider
+}
+
+// encryptedUpload описание multipart загрузки: все части шифруются одним ключом данных
+type encryptedUpload struct {
+	Key     string `json:"key"`
+	KeyID   string `json:"key_id"`
+	Wrapped []byte `json:"wrapped"`
+}
+
+func NewEncryptedStorage(inner storage.Storage, keys encryption.KeyProvider, baseURL, signingKey string) (*EncryptedStorage, error) {
+	signer, err := newURLSigner(baseURL, signingKey)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedStorage{urlSigner: signer, inner: inner, keys: keys}, nil
+}
+
+func (s *EncryptedStorage) CurrentKeyID() string {
+	return s.keys.CurrentKeyID()
+}
+
+func (s *EncryptedStorage) GenerateUploadURL(ctx context.Context, key string, expiresIn time.Duration) (string, error) {
+	return s.GenerateUploadURLWithSize(ctx, key, expiresIn, -1)
+}
+
+func (s *EncryptedStorage) GenerateUploadURLWithSize(ctx context.Context, key string, expiresIn time.Duration, fileSize int64) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	return s.signedURL(http.MethodPut, objectResource(key), expiresIn, fileSize), nil
+}
+
+// GenerateUploadURLWithChecksum не требует заголовков: маршрут API их не проверяет,
+// содержимое сверяется с контрольной суммой при завершении загрузки
+func (s *EncryptedStorage) GenerateUploadURLWithChecksum(ctx context.Context, key string, expiresIn time.Duration, fileSize int64, checksum storage.Checksum) (string, map[string]string, error) {
+	uploadURL, err := s.GenerateUploadURLWithSize(ctx, key, expiresIn, fileSize)
+	return uploadURL, nil, err
+}
+
+func (s *EncryptedStorage) GenerateDownloadURL(ctx context.Context, key string, expiresIn time.Duration) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	return s.signedURL(http.MethodGet, objectResource(key), expiresIn, -1), nil
+}
+
+func (s *EncryptedStorage) Delete(ctx context.Context, key string) error {
+	return s.inner.Delete(ctx, key)
+}
+
+// Copy переносит шифротекст как есть: заголовок с обёрнутым ключом данных копируется вместе с объектом,
+// а AAD сегментов не зависит от ключа объекта
+func (s *EncryptedStorage) Copy(ctx context.Context, srcKey, dstKey string) error {
+	return s.inner.Copy(ctx, srcKey, dstKey)
+}
+
+func (s *EncryptedStorage) FileExists(ctx context.Context, key string) (bool, error) {
+	return s.inner.FileExists(ctx, key)
+}
+
+// List отдаёт размеры зашифрованных объектов: для сверки с БД важны только ключи
+func (s *EncryptedStorage) List(ctx context.Context, prefix, startAfter string, limit int) ([]storage.ObjectSummary, error) {
+	return s.inner.List(ctx, prefix, startAfter, limit)
+}
+
+// Put шифрует объект новым ключом данных одним блоком
+func (s *EncryptedStorage) Put(ctx context.Context, key string, body io.Reader, info storage.ObjectInfo) error {
+	aead, header, err := s.newDataKey(ctx)
+	if err != nil {
+		return err
+	}
+
+	enc, err := newEnvelopeEncrypter(aead, body, 0, header.marshal())
+	if err != nil {
+		return err
+	}
+
+	length := int64(-1)
+	if info.ContentLength >= 0 {
+		length = header.size() + blockCipherSize(info.ContentLength)
+	}
+
+	return s.inner.Put(ctx, key, enc, storage.ObjectInfo{
+		ContentType:   info.ContentType,
+		ContentLength: length,
+		Metadata:      info.Metadata,
+	})
+}
+
+func (s *EncryptedStorage) Get(ctx context.Context, key string) (*storage.Object, error) {
+	obj, err := s.inner.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReaderSize(obj.Body, envelopeMaxHeaderSize)
+	header, err := peekEnvelopeHeader(br)
+	if err != nil {
+		obj.Body.Close()
+		return nil, err
+	}
+	if header == nil {
+		return &storage.Object{Body: readCloser{br, obj.Body}, ObjectInfo: obj.ObjectInfo}, nil
+	}
+
+	layout, aead, err := s.openEnvelope(ctx, *header, obj.ContentLength)
+	if err != nil {
+		obj.Body.Close()
+		return nil, err
+	}
+
+	reader := &envelopeReader{aead: aead, layout: layout, src: br, remaining: layout.size}
+	return &storage.Object{
+		Body:       readCloser{reader, obj.Body},
+		ObjectInfo: plainInfo(obj.ObjectInfo, layout, header.KeyID),
+	}, nil
+}
+
+// GetRange читает из вложенного хранилища только сегменты, покрывающие диапазон
+func (s *EncryptedStorage) GetRange(ctx context.Context, key string, offset, length int64) (*storage.Object, error) {
+	if offset < 0 || length <= 0 {
+		return nil, fmt.Errorf("invalid range: offset %d, length %d", offset, length)
+	}
+
+	info, header, err := s.stat(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return s.inner.GetRange(ctx, key, offset, length)
+	}
+
+	layout, aead, err := s.openEnvelope(ctx, *header, info.ContentLength)
+	if err != nil {
+		return nil, err
+	}
+	if offset >= layout.size {
+		return nil, fmt.Errorf("invalid range: offset %d beyond object size %d", offset, layout.size)
+	}
+	length = min(length, layout.size-offset)
+
+	blk, seg, skip := layout.locate(offset)
+	salt, err := s.readRange(ctx, key, layout.blockOffset(blk), envelopeSaltSize)
+	if err != nil {
+		return nil, err
+	}
+
+	lastBlk, lastSeg, _ := layout.locate(offset + length - 1)
+	start := layout.segmentOffset(blk, seg)
+	end := layout.segmentOffset(lastBlk, lastSeg) + layout.segmentCipherSize(lastBlk, lastSeg)
+
+	obj, err := s.inner.GetRange(ctx, key, start, end-start)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := &envelopeReader{
+		aead:      aead,
+		layout:    layout,
+		src:       obj.Body,
+		blk:       blk,
+		seg:       seg,
+		salt:      salt,
+		skip:      skip,
+		remaining: length,
+	}
+	objInfo := plainInfo(*info, layout, header.KeyID)
+	objInfo.ContentLength = length
+	return &storage.Object{Body: readCloser{reader, obj.Body}, ObjectInfo: objInfo}, nil
+}
+
+func (s *EncryptedStorage) Stat(ctx context.Context, key string) (*storage.ObjectInfo, error) {
+	info, header, err := s.stat(ctx, key)
+	if err != nil || header == nil {
+		return info, err
+	}
+
+	layout, err := newEnvelopeLayout(*header, info.ContentLength)
+	if err != nil {
+		return nil, err
+	}
+	plain := plainInfo(*info, layout, header.KeyID)
+	return &plain, nil
+}
+
+// Open открывает объект для http.ServeContent: каждый Seek превращается в новый GetRange
+func (s *EncryptedStorage) Open(ctx context.Context, key string) (io.ReadSeekCloser, storage.ObjectInfo, error) {
+	info, err := s.Stat(ctx, key)
+	if err != nil {
+		return nil, storage.ObjectInfo{}, err
+	}
+	return &rangeReader{ctx: ctx, storage: s, key: key, size: info.ContentLength}, *info, nil
+}
+
+// Rewrap переоборачивает ключ данных объекта текущим мастер-ключом. Шифротекст копируется
+// без изменений, меняется только заголовок. Незашифрованный объект шифруется целиком.
+func (s *EncryptedStorage) Rewrap(ctx context.Context, key string) (string, error) {
+	obj, err := s.inner.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer obj.Body.Close()
+
+	br := bufio.NewReaderSize(obj.Body, envelopeMaxHeaderSize)
+	header, err := peekEnvelopeHeader(br)
+	if err != nil {
+		return "", err
+	}
+
+	if header == nil {
+		err := s.Put(ctx, key, br, storage.ObjectInfo{
+			ContentType:   obj.ContentType,
+			ContentLength: obj.ContentLength,
+			Metadata:      obj.Metadata,
+		})
+		if err != nil {
+			return "", err
+		}
+		return s.keys.CurrentKeyID(), nil
+	}
+
+	if header.KeyID == s.keys.CurrentKeyID() {
+		return header.KeyID, nil
+	}
+
+	dataKey, err := s.keys.Unwrap(ctx, header.KeyID, header.Wrapped)
+	if err != nil {
+		return "", err
+	}
+	keyID, wrapped, err := s.keys.Wrap(ctx, dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	rewrapped := envelopeHeader{KeyID: keyID, Wrapped: wrapped, BlockSize: header.BlockSize}
+	length := int64(-1)
+	if obj.ContentLength >= 0 {
+		length = obj.ContentLength - header.size() + rewrapped.size()
+	}
+
+	err = s.inner.Put(ctx, key, io.MultiReader(bytes.NewReader(rewrapped.marshal()), br), storage.ObjectInfo{
+		ContentType:   obj.ContentType,
+		ContentLength: length,
+		Metadata:      obj.Metadata,
+	})
+	if err != nil {
+		return "", err
+	}
+	return keyID, nil
+}
+
+// CreateMultipartUpload заводит загрузку во вложенном хранилище и сохраняет рядом обёрнутый ключ данных
+func (s *EncryptedStorage) CreateMultipartUpload(ctx context.Context, key string, contentType string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+
+	_, header, err := s.newDataKey(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	uploadID, err := s.inner.CreateMultipartUpload(ctx, key, contentType)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(encryptedUpload{Key: key, KeyID: header.KeyID, Wrapped: header.Wrapped})
+	if err != nil {
+		return "", err
+	}
+	err = s.inner.Put(ctx, uploadDescriptorKey(uploadID), bytes.NewReader(data), storage.ObjectInfo{
+		ContentType:   "application/json",
+		ContentLength: int64(len(data)),
+	})
+	if err != nil {
+		_ = s.inner.AbortMultipartUpload(ctx, key, uploadID)
+		return "", fmt.Errorf("failed to save encrypted upload: %w", err)
+	}
+
+	return uploadID, nil
+}
+
+func (s *EncryptedStorage) GenerateUploadPartURL(ctx context.Context, key string, uploadID string, partNumber int32, expiresIn time.Duration) (string, error) {
+	if _, err := s.loadUpload(ctx, key, uploadID); err != nil {
+		return "", err
+	}
+	return s.signedURL(http.MethodPut, partResource(uploadID, partNumber), expiresIn, -1), nil
+}
+
+// UploadPart шифрует часть отдельным блоком; первая часть несёт заголовок объекта,
+// а её размер становится размером блока для остальных частей
+func (s *EncryptedStorage) UploadPart(ctx context.Context, key string, uploadID string, partNumber int32, data []byte) (string, error) {
+	upload, err := s.loadUpload(ctx, key, uploadID)
+	if err != nil {
+		return "", err
+	}
+	return s.uploadPart(ctx, upload, uploadID, partNumber, data)
+}
+
+func (s *EncryptedStorage) WritePart(ctx context.Context, uploadID string, partNumber int32, body io.Reader, size int64) (string, error) {
+	upload, err := s.loadUpload(ctx, "", uploadID)
+	if err != nil {
+		return "", err
+	}
+	if size > encryptedMaxPartSize {
+		return "", storage.ErrPartTooLarge
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, encryptedMaxPartSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to upload part: %w", err)
+	}
+	if len(data) > encryptedMaxPartSize {
+		return "", storage.ErrPartTooLarge
+	}
+	if size >= 0 && int64(len(data)) != size {
+		return "", storage.ErrSizeMismatch
+	}
+
+	return s.uploadPart(ctx, upload, uploadID, partNumber, data)
+}
+
+func (s *EncryptedStorage) CompleteMultipartUpload(ctx context.Context, key string, uploadID string, parts []storage.CompletedPart) error {
+	if _, err := s.loadUpload(ctx, key, uploadID); err != nil {
+		return err
+	}
+	if err := s.inner.CompleteMultipartUpload(ctx, key, uploadID, parts); err != nil {
+		return err
+	}
+	_ = s.inner.Delete(ctx, uploadDescriptorKey(uploadID))
+	return nil
+}
+
+func (s *EncryptedStorage) AbortMultipartUpload(ctx context.Context, key string, uploadID string) error {
+	if _, err := s.loadUpload(ctx, key, uploadID); err != nil {
+		return err
+	}
+	if err := s.inner.AbortMultipartUpload(ctx, key, uploadID); err != nil {
+		return err
+	}
+	_ = s.inner.Delete(ctx, uploadDescriptorKey(uploadID))
+	return nil
+}
+
+func (s *EncryptedStorage) uploadPart(ctx context.Context, upload *encryptedUpload, uploadID string, partNumber int32, data []byte) (string, error) {
+	if partNumber < 1 {
+		return "", fmt.Errorf("invalid part number %d", partNumber)
+	}
+
+	dataKey, err := s.keys.Unwrap(ctx, upload.KeyID, upload.Wrapped)
+	if err != nil {
+		return "", err
+	}
+	aead, err := newDataCipher(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	var prefix []byte
+	if partNumber == 1 {
+		header := envelopeHeader{KeyID: upload.KeyID, Wrapped: upload.Wrapped, BlockSize: int64(len(data))}
+		prefix = header.marshal()
+	}
+
+	enc, err := newEnvelopeEncrypter(aead, bytes.NewReader(data), int64(partNumber-1), prefix)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := io.ReadAll(enc)
+	if err != nil {
+		return "", err
+	}
+
+	return s.inner.UploadPart(ctx, upload.Key, uploadID, partNumber, sealed)
+}
+
+// loadUpload читает описание загрузки; пустой key пропускает сверку ключа
+func (s *EncryptedStorage) loadUpload(ctx context.Context, key, uploadID string) (*encryptedUpload, error) {
+	obj, err := s.inner.Get(ctx, uploadDescriptorKey(uploadID))
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, storage.ErrUploadNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Body.Close()
+
+	var upload encryptedUpload
+	if err := json.NewDecoder(obj.Body).Decode(&upload); err != nil {
+		return nil, fmt.Errorf("failed to read encrypted upload: %w", err)
+	}
+	if key != "" && upload.Key != key {
+		return nil, storage.ErrUploadNotFound
+	}
+
+	return &upload, nil
+}
+
+// stat возвращает свойства объекта во вложенном хранилище и его заголовок (nil — объект не зашифрован)
+func (s *EncryptedStorage) stat(ctx context.Context, key string) (*storage.ObjectInfo, *envelopeHeader, error) {
+	info, err := s.inner.Stat(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.ContentLength < int64(len(envelopeMagic)) {
+		return info, nil, nil
+	}
+
+	data, err := s.readRange(ctx, key, 0, min(info.ContentLength, int64(envelopeMaxHeaderSize)))
+	if err != nil {
+		return nil, nil, err
+	}
+	header, err := parseEnvelopeHeader(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return info, header, nil
+}
+
+func (s *EncryptedStorage) readRange(ctx context.Context, key string, offset, length int64) ([]byte, error) {
+	obj, err := s.inner.GetRange(ctx, key, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Body.Close()
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(obj.Body, data); err != nil {
+		return nil, truncatedEnvelope(err)
+	}
+	return data, nil
+}
+
+func (s *EncryptedStorage) newDataKey(ctx context.Context) (cipher.AEAD, envelopeHeader, error) {
+	dataKey := make([]byte, envelopeDataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, envelopeHeader{}, err
+	}
+
+	keyID, wrapped, err := s.keys.Wrap(ctx, dataKey)
+	if err != nil {
+		return nil, envelopeHeader{}, err
+	}
+	if len(keyID) > 255 || len(wrapped) > envelopeMaxWrappedSize {
+		return nil, envelopeHeader{}, fmt.Errorf("wrapped data key does not fit the envelope header")
+	}
+
+	aead, err := newDataCipher(dataKey)
+	if err != nil {
+		return nil, envelopeHeader{}, err
+	}
+	return aead, envelopeHeader{KeyID: keyID, Wrapped: wrapped}, nil
+}
+
+func (s *EncryptedStorage) openEnvelope(ctx context.Context, header envelopeHeader, cipherSize int64) (envelopeLayout, cipher.AEAD, error) {
+	if cipherSize < 0 {
+		return envelopeLayout{}, nil, encryption.ErrInvalidEnvelope
+	}
+	layout, err := newEnvelopeLayout(header, cipherSize)
+	if err != nil {
+		return envelopeLayout{}, nil, err
+	}
+
+	dataKey, err := s.keys.Unwrap(ctx, header.KeyID, header.Wrapped)
+	if err != nil {
+		return envelopeLayout{}, nil, err
+	}
+	aead, err := newDataCipher(dataKey)
+	if err != nil {
+		return envelopeLayout{}, nil, err
+	}
+	return layout, aead, nil
+}
+
+func newDataCipher(dataKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, encryption.ErrInvalidWrapped
+	}
+	return cipher.NewGCM(block)
+}
+
+func plainInfo(info storage.ObjectInfo, layout envelopeLayout, keyID string) storage.ObjectInfo {
+	return storage.ObjectInfo{
+		ContentType:   info.ContentType,
+		ContentLength: layout.size,
+		Metadata:      info.Metadata,
+		KeyID:         keyID,
+	}
+}
+
+// uploadDescriptorKey UploadId из S3 может содержать любые символы, поэтому в ключ идёт его хеш
+func uploadDescriptorKey(uploadID string) string {
+	sum := sha256.Sum256([]byte(uploadID))
+	return encryptedUploadsPrefix + hex.EncodeToString(sum[:])
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// rangeReader io.ReadSeekCloser поверх GetRange: диапазон запрашивается лениво при первом чтении после Seek
+type rangeReader struct {
+	ctx     context.Context
+	storage storage.Storage
+	key     string
+	size    int64
+	offset  int64
+	body    io.ReadCloser
+}
+
+func (r *rangeReader) Read(p []byte) (int, error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+	if r.body == nil {
+		obj, err := r.storage.GetRange(r.ctx, r.key, r.offset, r.size-r.offset)
+		if err != nil {
+			return 0, err
+		}
+		r.body = obj.Body
+	}
+
+	n, err := r.body.Read(p)
+	r.offset += int64(n)
+	return n, err
+}
+
+func (r *rangeReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.size
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("invalid seek offset %d", offset)
+	}
+
+	if offset != r.offset {
+		r.closeBody()
+		r.offset = offset
+	}
+	return offset, nil
+}
+
+func (r *rangeReader) Close() error {
+	r.closeBody()
+	return nil
+}
+
+func (r *rangeReader) closeBody() {
+	if r.body != nil {
+		_ = r.body.Close()
+		r.body = nil
+	}
+}
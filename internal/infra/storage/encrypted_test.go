@@ -0,0 +1,227 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/encryption"
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
+	infra_encryption "github.com/yourusername/cloud-file-storage/internal/infra/encryption"
+)
+
+var testMasterKeys = map[string][]byte{
+	"old": bytes.Repeat([]byte{1}, 32),
+	"new": bytes.Repeat([]byte{2}, 32),
+}
+
+func newTestEncryptedStorage(t *testing.T, inner *LocalStorage, current string) *EncryptedStorage {
+	keys, err := infra_encryption.NewLocalKeyProviderFromKeys(current, testMasterKeys)
+	require.NoError(t, err)
+	s, err := NewEncryptedStorage(inner, keys, "http://localhost:8080", "secret")
+	require.NoError(t, err)
+	return s
+}
+
+func randomBytes(t *testing.T, n int) []byte {
+	data := make([]byte, n)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+	return data
+}
+
+func readObject(t *testing.T, obj *storage.Object, err error) []byte {
+	require.NoError(t, err)
+	defer obj.Body.Close()
+	data, err := io.ReadAll(obj.Body)
+	require.NoError(t, err)
+	return data
+}
+
+func TestEncryptedStorage_PutGet(t *testing.T) {
+	ctx := context.Background()
+	inner := newTestLocalStorage(t)
+	s := newTestEncryptedStorage(t, inner, "old")
+
+	// Несколько сегментов и неполный последний
+	content := randomBytes(t, 3*envelopeSegmentSize+123)
+	err := s.Put(ctx, "files/a.bin", bytes.NewReader(content), storage.ObjectInfo{
+		ContentType:   "application/octet-stream",
+		ContentLength: int64(len(content)),
+	})
+	require.NoError(t, err)
+
+	obj, err := inner.Get(ctx, "files/a.bin")
+	raw := readObject(t, obj, err)
+	assert.NotContains(t, string(raw), string(content[:64]))
+
+	obj, err = s.Get(ctx, "files/a.bin")
+	assert.Equal(t, content, readObject(t, obj, err))
+	assert.Equal(t, int64(len(content)), obj.ContentLength)
+	assert.Equal(t, "old", obj.KeyID)
+
+	info, err := s.Stat(ctx, "files/a.bin")
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), info.ContentLength)
+	assert.Equal(t, "application/octet-stream", info.ContentType)
+
+	ranges := [][2]int64{{0, 10}, {envelopeSegmentSize - 5, 10}, {envelopeSegmentSize, envelopeSegmentSize}, {int64(len(content)) - 7, 100}}
+	for _, r := range ranges {
+		obj, err := s.GetRange(ctx, "files/a.bin", r[0], r[1])
+		end := min(r[0]+r[1], int64(len(content)))
+		assert.Equal(t, content[r[0]:end], readObject(t, obj, err), "range %v", r)
+		assert.Equal(t, end-r[0], obj.ContentLength)
+	}
+
+	f, _, err := s.Open(ctx, "files/a.bin")
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = f.Seek(2*envelopeSegmentSize+1, io.SeekStart)
+	require.NoError(t, err)
+	tail, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Equal(t, content[2*envelopeSegmentSize+1:], tail)
+}
+
+func TestEncryptedStorage_EmptyAndUnknownLength(t *testing.T) {
+	ctx := context.Background()
+	s := newTestEncryptedStorage(t, newTestLocalStorage(t), "old")
+
+	require.NoError(t, s.Put(ctx, "empty", bytes.NewReader(nil), storage.ObjectInfo{ContentLength: 0}))
+	obj, err := s.Get(ctx, "empty")
+	assert.Empty(t, readObject(t, obj, err))
+
+	content := randomBytes(t, envelopeSegmentSize)
+	require.NoError(t, s.Put(ctx, "stream", bytes.NewReader(content), storage.ObjectInfo{ContentLength: -1}))
+	obj, err = s.Get(ctx, "stream")
+	assert.Equal(t, content, readObject(t, obj, err))
+}
+
+func TestEncryptedStorage_PlaintextPassthrough(t *testing.T) {
+	ctx := context.Background()
+	inner := newTestLocalStorage(t)
+	s := newTestEncryptedStorage(t, inner, "old")
+
+	require.NoError(t, inner.Put(ctx, "legacy.txt", bytes.NewReader([]byte("legacy content")), storage.ObjectInfo{ContentLength: 14}))
+
+	obj, err := s.Get(ctx, "legacy.txt")
+	assert.Equal(t, "legacy content", string(readObject(t, obj, err)))
+	assert.Empty(t, obj.KeyID)
+	obj, err = s.GetRange(ctx, "legacy.txt", 7, 100)
+	assert.Equal(t, "content", string(readObject(t, obj, err)))
+}
+
+func TestEncryptedStorage_MultipartUpload(t *testing.T) {
+	ctx := context.Background()
+	inner := newTestLocalStorage(t)
+	s := newTestEncryptedStorage(t, inner, "old")
+
+	uploadID, err := s.CreateMultipartUpload(ctx, "files/big.bin", "application/octet-stream")
+	require.NoError(t, err)
+
+	partSize := 2*envelopeSegmentSize + 10
+	parts := [][]byte{randomBytes(t, partSize), randomBytes(t, partSize), randomBytes(t, 100)}
+
+	var completed []storage.CompletedPart
+	for i, part := range parts {
+		var etag string
+		if i == 1 {
+			// Часть по подписанному URL приходит через WritePart
+			etag, err = s.WritePart(ctx, uploadID, int32(i+1), bytes.NewReader(part), int64(len(part)))
+		} else {
+			etag, err = s.UploadPart(ctx, "files/big.bin", uploadID, int32(i+1), part)
+		}
+		require.NoError(t, err)
+		completed = append(completed, storage.CompletedPart{PartNumber: int32(i + 1), ETag: etag})
+	}
+	require.NoError(t, s.CompleteMultipartUpload(ctx, "files/big.bin", uploadID, completed))
+
+	content := bytes.Join(parts, nil)
+	obj, err := s.Get(ctx, "files/big.bin")
+	assert.Equal(t, content, readObject(t, obj, err))
+	assert.Equal(t, int64(len(content)), obj.ContentLength)
+
+	offset, length := int64(partSize-5), int64(partSize+20)
+	obj, err = s.GetRange(ctx, "files/big.bin", offset, length)
+	assert.Equal(t, content[offset:offset+length], readObject(t, obj, err))
+
+	exists, err := inner.FileExists(ctx, uploadDescriptorKey(uploadID))
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	_, err = s.UploadPart(ctx, "files/big.bin", uploadID, 1, []byte("late"))
+	assert.ErrorIs(t, err, storage.ErrUploadNotFound)
+}
+
+func TestEncryptedStorage_Rewrap(t *testing.T) {
+	ctx := context.Background()
+	inner := newTestLocalStorage(t)
+	old := newTestEncryptedStorage(t, inner, "old")
+
+	content := randomBytes(t, envelopeSegmentSize+1)
+	require.NoError(t, old.Put(ctx, "files/a.bin", bytes.NewReader(content), storage.ObjectInfo{ContentLength: int64(len(content))}))
+	require.NoError(t, inner.Put(ctx, "files/legacy.bin", bytes.NewReader(content), storage.ObjectInfo{ContentLength: int64(len(content))}))
+
+	rotated := newTestEncryptedStorage(t, inner, "new")
+	for _, key := range []string{"files/a.bin", "files/legacy.bin"} {
+		keyID, err := rotated.Rewrap(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, "new", keyID)
+
+		// Повторный проход ничего не меняет
+		keyID, err = rotated.Rewrap(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, "new", keyID)
+	}
+
+	// Старый мастер-ключ больше не нужен
+	keys, err := infra_encryption.NewLocalKeyProviderFromKeys("new", map[string][]byte{"new": testMasterKeys["new"]})
+	require.NoError(t, err)
+	onlyNew, err := NewEncryptedStorage(inner, keys, "http://localhost:8080", "secret")
+	require.NoError(t, err)
+
+	for _, key := range []string{"files/a.bin", "files/legacy.bin"} {
+		obj, err := onlyNew.Get(ctx, key)
+		assert.Equal(t, content, readObject(t, obj, err))
+		assert.Equal(t, "new", obj.KeyID)
+	}
+}
+
+func TestEncryptedStorage_TamperDetected(t *testing.T) {
+	ctx := context.Background()
+	inner := newTestLocalStorage(t)
+	s := newTestEncryptedStorage(t, inner, "old")
+
+	content := randomBytes(t, 2*envelopeSegmentSize)
+	require.NoError(t, s.Put(ctx, "files/a.bin", bytes.NewReader(content), storage.ObjectInfo{ContentLength: int64(len(content))}))
+
+	objectPath, _, err := inner.paths("files/a.bin")
+	require.NoError(t, err)
+	raw, err := os.ReadFile(objectPath)
+	require.NoError(t, err)
+
+	tampered := append([]byte(nil), raw...)
+	tampered[len(tampered)-envelopeSegmentSize] ^= 0xff
+	require.NoError(t, os.WriteFile(objectPath, tampered, 0o644))
+
+	obj, err := s.Get(ctx, "files/a.bin")
+	require.NoError(t, err)
+	_, err = io.ReadAll(obj.Body)
+	obj.Body.Close()
+	assert.ErrorIs(t, err, encryption.ErrDecryptionFailed)
+
+	// Отрезанный последний сегмент тоже не проходит проверку
+	truncated := raw[:len(raw)-envelopeSegmentSize-envelopeTagSize]
+	require.NoError(t, os.WriteFile(objectPath, truncated, 0o644))
+
+	obj, err = s.Get(ctx, "files/a.bin")
+	require.NoError(t, err)
+	_, err = io.ReadAll(obj.Body)
+	obj.Body.Close()
+	assert.ErrorIs(t, err, encryption.ErrDecryptionFailed)
+}
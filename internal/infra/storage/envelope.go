@@ -0,0 +1,351 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/yourusername/cloud-file-storage/internal/domain/encryption"
+)
+
+// Формат зашифрованного объекта:
+//
+//	header | block 0 | block 1 | ...
+//	header = magic(8) | len(keyID)(1) | keyID | len(wrapped)(2) | wrapped | blockSize(8)
+//	block  = salt(8) | segment 0 | segment 1 | ...
+//
+// Объект из Put — один блок (blockSize = 0). В multipart загрузке каждая часть шифруется отдельным
+// блоком, а blockSize равен размеру первой части: все части, кроме последней, должны быть одного
+// размера, как их и нарежут multipart и tus сервисы. Сегмент — до envelopeSegmentSize байт открытого
+// текста, зашифрованных AES-256-GCM с nonce = salt | номер сегмента и AAD = номер блока | признак
+// последнего сегмента блока. Поэтому сегменты нельзя переставить или отрезать незаметно,
+// а любой диапазон расшифровывается без чтения объекта целиком.
+const (
+	envelopeMagic          = "CBXENC01"
+	envelopeSegmentSize    = 64 * 1024
+	envelopeTagSize        = 16
+	envelopeSaltSize       = 8
+	envelopeDataKeySize    = 32
+	envelopeMaxWrappedSize = 1024
+	envelopeMaxHeaderSize  = len(envelopeMagic) + 1 + 255 + 2 + envelopeMaxWrappedSize + 8
+)
+
+type envelopeHeader struct {
+	KeyID     string
+	Wrapped   []byte
+	BlockSize int64
+}
+
+func (h envelopeHeader) size() int64 {
+	return int64(len(envelopeMagic) + 1 + len(h.KeyID) + 2 + len(h.Wrapped) + 8)
+}
+
+func (h envelopeHeader) marshal() []byte {
+	buf := make([]byte, 0, h.size())
+	buf = append(buf, envelopeMagic...)
+	buf = append(buf, byte(len(h.KeyID)))
+	buf = append(buf, h.KeyID...)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(h.Wrapped)))
+	buf = append(buf, h.Wrapped...)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(h.BlockSize))
+	return buf
+}
+
+// parseEnvelopeHeader разбирает заголовок из начала объекта; nil без ошибки — объект не зашифрован
+func parseEnvelopeHeader(data []byte) (*envelopeHeader, error) {
+	if !bytes.HasPrefix(data, []byte(envelopeMagic)) {
+		return nil, nil
+	}
+	data = data[len(envelopeMagic):]
+
+	if len(data) < 1 {
+		return nil, encryption.ErrInvalidEnvelope
+	}
+	keyIDLen := int(data[0])
+	data = data[1:]
+	if len(data) < keyIDLen+2 {
+		return nil, encryption.ErrInvalidEnvelope
+	}
+	h := &envelopeHeader{KeyID: string(data[:keyIDLen])}
+	data = data[keyIDLen:]
+
+	wrappedLen := int(binary.BigEndian.Uint16(data))
+	data = data[2:]
+	if wrappedLen > envelopeMaxWrappedSize || len(data) < wrappedLen+8 {
+		return nil, encryption.ErrInvalidEnvelope
+	}
+	h.Wrapped = append([]byte(nil), data[:wrappedLen]...)
+	h.BlockSize = int64(binary.BigEndian.Uint64(data[wrappedLen:]))
+	if h.BlockSize < 0 {
+		return nil, encryption.ErrInvalidEnvelope
+	}
+
+	return h, nil
+}
+
+// peekEnvelopeHeader читает заголовок из потока; для незашифрованного объекта поток остаётся нетронутым
+func peekEnvelopeHeader(br *bufio.Reader) (*envelopeHeader, error) {
+	data, err := br.Peek(envelopeMaxHeaderSize)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+
+	h, err := parseEnvelopeHeader(data)
+	if err != nil || h == nil {
+		return nil, err
+	}
+	if _, err := br.Discard(int(h.size())); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func segmentCount(plain int64) int64 {
+	if plain == 0 {
+		// Пустой блок всё равно содержит один сегмент, иначе его нельзя было бы отличить от обрезанного
+		return 1
+	}
+	return (plain + envelopeSegmentSize - 1) / envelopeSegmentSize
+}
+
+func blockCipherSize(plain int64) int64 {
+	return envelopeSaltSize + plain + envelopeTagSize*segmentCount(plain)
+}
+
+func blockPlainSize(cipherSize int64) (int64, error) {
+	body := cipherSize - envelopeSaltSize
+	if body < envelopeTagSize {
+		return 0, encryption.ErrInvalidEnvelope
+	}
+
+	full := body / (envelopeSegmentSize + envelopeTagSize)
+	rem := body % (envelopeSegmentSize + envelopeTagSize)
+	if rem == 0 {
+		return full * envelopeSegmentSize, nil
+	}
+	if rem < envelopeTagSize {
+		return 0, encryption.ErrInvalidEnvelope
+	}
+	return full*envelopeSegmentSize + rem - envelopeTagSize, nil
+}
+
+// envelopeLayout отображает смещения открытого текста на смещения в зашифрованном объекте
+type envelopeLayout struct {
+	header     envelopeHeader
+	headerSize int64
+	size       int64
+}
+
+func newEnvelopeLayout(h envelopeHeader, cipherSize int64) (envelopeLayout, error) {
+	l := envelopeLayout{header: h, headerSize: h.size()}
+	body := cipherSize - l.headerSize
+	if body < 0 {
+		return l, encryption.ErrInvalidEnvelope
+	}
+
+	if h.BlockSize == 0 {
+		size, err := blockPlainSize(body)
+		l.size = size
+		return l, err
+	}
+
+	full := body / blockCipherSize(h.BlockSize)
+	rem := body % blockCipherSize(h.BlockSize)
+	l.size = full * h.BlockSize
+	if rem > 0 {
+		last, err := blockPlainSize(rem)
+		if err != nil {
+			return l, err
+		}
+		l.size += last
+	}
+	return l, nil
+}
+
+func (l envelopeLayout) blockPlain(blk int64) int64 {
+	if l.header.BlockSize == 0 {
+		return l.size
+	}
+	return min(l.header.BlockSize, l.size-blk*l.header.BlockSize)
+}
+
+// blockOffset смещение соли блока в объекте
+func (l envelopeLayout) blockOffset(blk int64) int64 {
+	if l.header.BlockSize == 0 {
+		return l.headerSize
+	}
+	return l.headerSize + blk*blockCipherSize(l.header.BlockSize)
+}
+
+func (l envelopeLayout) segmentOffset(blk, seg int64) int64 {
+	return l.blockOffset(blk) + envelopeSaltSize + seg*(envelopeSegmentSize+envelopeTagSize)
+}
+
+func (l envelopeLayout) segmentCipherSize(blk, seg int64) int64 {
+	return min(envelopeSegmentSize, l.blockPlain(blk)-seg*envelopeSegmentSize) + envelopeTagSize
+}
+
+// locate возвращает блок, сегмент и смещение внутри сегмента для смещения открытого текста
+func (l envelopeLayout) locate(offset int64) (blk, seg, skip int64) {
+	if l.header.BlockSize > 0 {
+		blk = offset / l.header.BlockSize
+		offset %= l.header.BlockSize
+	}
+	return blk, offset / envelopeSegmentSize, offset % envelopeSegmentSize
+}
+
+func segmentNonce(salt []byte, seg int64) []byte {
+	return binary.BigEndian.AppendUint32(append([]byte(nil), salt...), uint32(seg))
+}
+
+func segmentAAD(blk int64, final bool) []byte {
+	aad := binary.BigEndian.AppendUint32(nil, uint32(blk))
+	if final {
+		return append(aad, 1)
+	}
+	return append(aad, 0)
+}
+
+// envelopeEncrypter шифрует поток одним блоком blk; prefix (заголовок) выдаётся перед солью блока
+type envelopeEncrypter struct {
+	aead cipher.AEAD
+	src  *bufio.Reader
+	blk  int64
+	seg  int64
+	salt []byte
+	out  []byte
+	buf  []byte
+	done bool
+}
+
+func newEnvelopeEncrypter(aead cipher.AEAD, src io.Reader, blk int64, prefix []byte) (*envelopeEncrypter, error) {
+	salt := make([]byte, envelopeSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	return &envelopeEncrypter{
+		aead: aead,
+		src:  bufio.NewReaderSize(src, envelopeSegmentSize),
+		blk:  blk,
+		salt: salt,
+		out:  append(append([]byte(nil), prefix...), salt...),
+		buf:  make([]byte, envelopeSegmentSize, envelopeSegmentSize+envelopeTagSize),
+	}, nil
+}
+
+func (e *envelopeEncrypter) Read(p []byte) (int, error) {
+	for len(e.out) == 0 {
+		if e.done {
+			return 0, io.EOF
+		}
+		if err := e.next(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, e.out)
+	e.out = e.out[n:]
+	return n, nil
+}
+
+func (e *envelopeEncrypter) next() error {
+	n, err := io.ReadFull(e.src, e.buf[:envelopeSegmentSize])
+	switch {
+	case err == io.EOF || err == io.ErrUnexpectedEOF:
+		e.done = true
+	case err != nil:
+		return err
+	default:
+		// Сегмент полный: последний ли он, понятно только по следующему байту
+		if _, err := e.src.Peek(1); err == io.EOF {
+			e.done = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	e.out = e.aead.Seal(e.buf[:0], segmentNonce(e.salt, e.seg), e.buf[:n], segmentAAD(e.blk, e.done))
+	e.seg++
+	return nil
+}
+
+// envelopeReader расшифровывает remaining байт, начиная с сегмента seg блока blk.
+// src начинается с этого сегмента; соль следующих блоков читается из src по ходу.
+type envelopeReader struct {
+	aead      cipher.AEAD
+	layout    envelopeLayout
+	src       io.Reader
+	blk       int64
+	seg       int64
+	salt      []byte
+	skip      int64
+	remaining int64
+	out       []byte
+	buf       []byte
+}
+
+func (r *envelopeReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.remaining <= 0 {
+			return 0, io.EOF
+		}
+		if err := r.next(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+func (r *envelopeReader) next() error {
+	if r.buf == nil {
+		r.buf = make([]byte, envelopeSegmentSize+envelopeTagSize)
+	}
+	if r.salt == nil {
+		r.salt = make([]byte, envelopeSaltSize)
+		if _, err := io.ReadFull(r.src, r.salt); err != nil {
+			return truncatedEnvelope(err)
+		}
+	}
+
+	sealed := r.buf[:r.layout.segmentCipherSize(r.blk, r.seg)]
+	if _, err := io.ReadFull(r.src, sealed); err != nil {
+		return truncatedEnvelope(err)
+	}
+
+	final := r.seg == segmentCount(r.layout.blockPlain(r.blk))-1
+	plain, err := r.aead.Open(sealed[:0], segmentNonce(r.salt, r.seg), sealed, segmentAAD(r.blk, final))
+	if err != nil {
+		return encryption.ErrDecryptionFailed
+	}
+
+	plain = plain[r.skip:]
+	r.skip = 0
+	if int64(len(plain)) > r.remaining {
+		plain = plain[:r.remaining]
+	}
+	r.remaining -= int64(len(plain))
+	r.out = plain
+
+	r.seg++
+	if final {
+		r.blk++
+		r.seg = 0
+		r.salt = nil
+	}
+	return nil
+}
+
+func truncatedEnvelope(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return encryption.ErrInvalidEnvelope
+	}
+	return err
+}
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/yourusername/cloud-file-storage/internal/config"
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
+	"github.com/yourusername/cloud-file-storage/internal/infra/encryption"
+)
+
+// NewFromConfig выбирает бэкенд по storage.driver и при storage.encryption.enabled оборачивает его
+// в EncryptedStorage. SignedStorage возвращается отдельно (nil для голого S3),
+// потому что его подписанные URL обслуживает API сервер.
+func NewFromConfig(cfg config.Immutable) (storage.Storage, SignedStorage, error) {
+	var backend storage.Storage
+	var signed SignedStorage
+
+	switch cfg.Storage.Driver {
+	case "", "s3":
+		s3, err := NewS3Storage(
+			cfg.S3.Endpoint,
+			cfg.S3.Bucket,
+			cfg.S3.AccessKeyID,
+			cfg.S3.SecretAccessKey,
+			cfg.S3.Region,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		backend = s3
+	case "local":
+		local, err := NewLocalStorage(cfg.Storage.Local.Root, cfg.Storage.BaseURL, cfg.Storage.SigningKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		backend, signed = local, local
+	default:
+		return nil, nil, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+	}
+
+	if !cfg.Storage.Encryption.Enabled {
+		return backend, signed, nil
+	}
+
+	keys, err := encryption.NewLocalKeyProvider(cfg.Storage.Encryption.KeysFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	encrypted, err := NewEncryptedStorage(backend, keys, cfg.Storage.BaseURL, cfg.Storage.SigningKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return encrypted, encrypted, nil
+}
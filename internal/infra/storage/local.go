@@ -0,0 +1,494 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
+)
+
+const (
+	localObjectsDir   = "objects"
+	localMetaDir      = "meta"
+	localMultipartDir = "multipart"
+	localUploadFile   = "upload.json"
+)
+
+// LocalStorage хранит объекты в дереве каталогов на диске.
+// Вместо presigned URL S3 выдаёт ссылки на маршруты API, подписанные HMAC.
+type LocalStorage struct {
+	urlSigner
+	root string
+}
+
+type localMeta struct {
+	ContentType string            `json:"content_type,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+type localUpload struct {
+	Key         string `json:"key"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+func NewLocalStorage(root, baseURL, signingKey string) (*LocalStorage, error) {
+	if root == "" {
+		return nil, fmt.Errorf("local storage root is not set")
+	}
+	signer, err := newURLSigner(baseURL, signingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dir := range []string{localObjectsDir, localMetaDir, localMultipartDir} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create storage directory: %w", err)
+		}
+	}
+
+	return &LocalStorage{urlSigner: signer, root: root}, nil
+}
+
+func (s *LocalStorage) GenerateUploadURL(ctx context.Context, key string, expiresIn time.Duration) (string, error) {
+	return s.GenerateUploadURLWithSize(ctx, key, expiresIn, -1)
+}
+
+// GenerateUploadURLWithSize подписывает размер вместе с URL, загрузка другого размера будет отклонена
+func (s *LocalStorage) GenerateUploadURLWithSize(ctx context.Context, key string, expiresIn time.Duration, fileSize int64) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	return s.signedURL(http.MethodPut, objectResource(key), expiresIn, fileSize), nil
+}
+
+// GenerateUploadURLWithChecksum не требует заголовков: маршрут API их не проверяет,
+// содержимое сверяется с контрольной суммой при завершении загрузки
+func (s *LocalStorage) GenerateUploadURLWithChecksum(ctx context.Context, key string, expiresIn time.Duration, fileSize int64, checksum storage.Checksum) (string, map[string]string, error) {
+	uploadURL, err := s.GenerateUploadURLWithSize(ctx, key, expiresIn, fileSize)
+	return uploadURL, nil, err
+}
+
+func (s *LocalStorage) GenerateDownloadURL(ctx context.Context, key string, expiresIn time.Duration) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	return s.signedURL(http.MethodGet, objectResource(key), expiresIn, -1), nil
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	objectPath, metaPath, err := s.paths(key)
+	if err != nil {
+		return err
+	}
+
+	// Как и в S3, удаление отсутствующего объекта не считается ошибкой
+	if err := os.Remove(objectPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete object: %w", err)
+	}
+	if err := os.Remove(metaPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete object metadata: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStorage) Copy(ctx context.Context, srcKey, dstKey string) error {
+	f, info, err := s.open(srcKey)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return s.Put(ctx, dstKey, f, info)
+}
+
+func (s *LocalStorage) Put(ctx context.Context, key string, body io.Reader, info storage.ObjectInfo) error {
+	objectPath, metaPath, err := s.paths(key)
+	if err != nil {
+		return err
+	}
+
+	if err := writeFileAtomic(objectPath, body, info.ContentLength); err != nil {
+		return fmt.Errorf("failed to put object: %w", err)
+	}
+	return writeMeta(metaPath, localMeta{ContentType: info.ContentType, Metadata: info.Metadata})
+}
+
+func (s *LocalStorage) Get(ctx context.Context, key string) (*storage.Object, error) {
+	f, info, err := s.open(key)
+	if err != nil {
+		return nil, err
+	}
+	return &storage.Object{Body: f, ObjectInfo: info}, nil
+}
+
+func (s *LocalStorage) GetRange(ctx context.Context, key string, offset, length int64) (*storage.Object, error) {
+	if offset < 0 || length <= 0 {
+		return nil, fmt.Errorf("invalid range: offset %d, length %d", offset, length)
+	}
+
+	f, info, err := s.open(key)
+	if err != nil {
+		return nil, err
+	}
+	if offset >= info.ContentLength {
+		f.Close()
+		return nil, fmt.Errorf("invalid range: offset %d beyond object size %d", offset, info.ContentLength)
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to get object: %w", err)
+	}
+
+	info.ContentLength = min(length, info.ContentLength-offset)
+	body := struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(f, info.ContentLength), f}
+
+	return &storage.Object{Body: body, ObjectInfo: info}, nil
+}
+
+func (s *LocalStorage) Open(ctx context.Context, key string) (io.ReadSeekCloser, storage.ObjectInfo, error) {
+	return s.open(key)
+}
+
+func (s *LocalStorage) open(key string) (*os.File, storage.ObjectInfo, error) {
+	objectPath, metaPath, err := s.paths(key)
+	if err != nil {
+		return nil, storage.ObjectInfo{}, err
+	}
+
+	f, err := os.Open(objectPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, storage.ObjectInfo{}, storage.ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, storage.ObjectInfo{}, fmt.Errorf("failed to get object: %w", err)
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, storage.ObjectInfo{}, fmt.Errorf("failed to get object: %w", err)
+	}
+
+	meta, err := readMeta(metaPath)
+	if err != nil {
+		f.Close()
+		return nil, storage.ObjectInfo{}, err
+	}
+
+	return f, storage.ObjectInfo{
+		ContentType:   meta.ContentType,
+		ContentLength: stat.Size(),
+		Metadata:      meta.Metadata,
+	}, nil
+}
+
+func (s *LocalStorage) Stat(ctx context.Context, key string) (*storage.ObjectInfo, error) {
+	f, info, err := s.open(key)
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+	return &info, nil
+}
+
+func (s *LocalStorage) FileExists(ctx context.Context, key string) (bool, error) {
+	objectPath, _, err := s.paths(key)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = os.Stat(objectPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// List обходит каталог, в который указывает префикс, и сортирует ключи сам:
+// порядок обхода каталогов не совпадает с порядком ключей S3
+func (s *LocalStorage) List(ctx context.Context, prefix, startAfter string, limit int) ([]storage.ObjectSummary, error) {
+	objectsRoot := filepath.Join(s.root, localObjectsDir)
+	walkRoot := objectsRoot
+	if i := strings.LastIndex(prefix, "/"); i > 0 {
+		if err := validateKey(prefix[:i]); err != nil {
+			return nil, err
+		}
+		walkRoot = filepath.Join(objectsRoot, filepath.FromSlash(prefix[:i]))
+	}
+
+	objects := make([]storage.ObjectSummary, 0)
+	err := filepath.WalkDir(walkRoot, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".tmp-") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(objectsRoot, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) || key <= startAfter {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, storage.ObjectSummary{Key: key, Size: info.Size(), LastModified: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects: %w", err)
+	}
+
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+	if limit > 0 && len(objects) > limit {
+		objects = objects[:limit]
+	}
+	return objects, nil
+}
+
+// CreateMultipartUpload заводит каталог под части загрузки
+func (s *LocalStorage) CreateMultipartUpload(ctx context.Context, key string, contentType string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+
+	uploadID := uuid.NewString()
+	dir := s.uploadDir(uploadID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+
+	data, err := json.Marshal(localUpload{Key: key, ContentType: contentType})
+	if err != nil {
+		return "", fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(dir, localUploadFile), bytes.NewReader(data), int64(len(data))); err != nil {
+		return "", fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+
+	return uploadID, nil
+}
+
+func (s *LocalStorage) GenerateUploadPartURL(ctx context.Context, key string, uploadID string, partNumber int32, expiresIn time.Duration) (string, error) {
+	if _, err := s.loadUpload(key, uploadID); err != nil {
+		return "", err
+	}
+	return s.signedURL(http.MethodPut, partResource(uploadID, partNumber), expiresIn, -1), nil
+}
+
+func (s *LocalStorage) UploadPart(ctx context.Context, key string, uploadID string, partNumber int32, data []byte) (string, error) {
+	if _, err := s.loadUpload(key, uploadID); err != nil {
+		return "", err
+	}
+	return s.WritePart(ctx, uploadID, partNumber, bytes.NewReader(data), int64(len(data)))
+}
+
+// WritePart сохраняет часть потоком и возвращает её ETag в формате S3 (MD5 в кавычках)
+func (s *LocalStorage) WritePart(ctx context.Context, uploadID string, partNumber int32, body io.Reader, size int64) (string, error) {
+	if _, err := s.loadUpload("", uploadID); err != nil {
+		return "", err
+	}
+
+	h := md5.New()
+	partPath := filepath.Join(s.uploadDir(uploadID), strconv.Itoa(int(partNumber)))
+	if err := writeFileAtomic(partPath, io.TeeReader(body, h), size); err != nil {
+		return "", fmt.Errorf("failed to upload part: %w", err)
+	}
+
+	return quoteETag(h.Sum(nil)), nil
+}
+
+// CompleteMultipartUpload склеивает части в итоговый объект, сверяя ETag каждой
+func (s *LocalStorage) CompleteMultipartUpload(ctx context.Context, key string, uploadID string, parts []storage.CompletedPart) error {
+	upload, err := s.loadUpload(key, uploadID)
+	if err != nil {
+		return err
+	}
+	objectPath, metaPath, err := s.paths(key)
+	if err != nil {
+		return err
+	}
+
+	dir := s.uploadDir(uploadID)
+	readers := make([]io.Reader, 0, len(parts))
+	for _, p := range parts {
+		f, err := os.Open(filepath.Join(dir, strconv.Itoa(int(p.PartNumber))))
+		if err != nil {
+			return fmt.Errorf("failed to complete multipart upload: part %d: %w", p.PartNumber, err)
+		}
+		defer f.Close()
+		readers = append(readers, &etagReader{r: f, h: md5.New(), part: p})
+	}
+
+	if err := writeFileAtomic(objectPath, io.MultiReader(readers...), -1); err != nil {
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+	if err := writeMeta(metaPath, localMeta{ContentType: upload.ContentType}); err != nil {
+		return err
+	}
+
+	_ = os.RemoveAll(dir)
+	return nil
+}
+
+func (s *LocalStorage) AbortMultipartUpload(ctx context.Context, key string, uploadID string) error {
+	if _, err := s.loadUpload(key, uploadID); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(s.uploadDir(uploadID)); err != nil {
+		return fmt.Errorf("failed to abort multipart upload: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStorage) paths(key string) (string, string, error) {
+	if err := validateKey(key); err != nil {
+		return "", "", err
+	}
+	rel := filepath.FromSlash(key)
+	return filepath.Join(s.root, localObjectsDir, rel), filepath.Join(s.root, localMetaDir, rel+".json"), nil
+}
+
+func (s *LocalStorage) uploadDir(uploadID string) string {
+	return filepath.Join(s.root, localMultipartDir, uploadID)
+}
+
+// loadUpload читает описание загрузки; пустой key пропускает сверку ключа
+func (s *LocalStorage) loadUpload(key, uploadID string) (*localUpload, error) {
+	if _, err := uuid.Parse(uploadID); err != nil {
+		return nil, storage.ErrUploadNotFound
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.uploadDir(uploadID), localUploadFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, storage.ErrUploadNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read multipart upload: %w", err)
+	}
+
+	var upload localUpload
+	if err := json.Unmarshal(data, &upload); err != nil {
+		return nil, fmt.Errorf("failed to read multipart upload: %w", err)
+	}
+	if key != "" && upload.Key != key {
+		return nil, storage.ErrUploadNotFound
+	}
+
+	return &upload, nil
+}
+
+// validateKey не даёт ключу выйти за пределы корня хранилища
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") || path.Clean(key) != key ||
+		key == ".." || strings.HasPrefix(key, "../") {
+		return storage.ErrInvalidKey
+	}
+	return nil
+}
+
+func quoteETag(sum []byte) string {
+	return `"` + hex.EncodeToString(sum) + `"`
+}
+
+// writeFileAtomic пишет во временный файл рядом с целевым и переименовывает его,
+// так что читатели никогда не видят объект наполовину. size < 0 отключает сверку размера.
+func writeFileAtomic(target string, r io.Reader, size int64) error {
+	dir := filepath.Dir(target)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if size >= 0 && n != size {
+		return storage.ErrSizeMismatch
+	}
+
+	return os.Rename(tmp.Name(), target)
+}
+
+func writeMeta(metaPath string, meta localMeta) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("failed to write object metadata: %w", err)
+	}
+	if err := writeFileAtomic(metaPath, bytes.NewReader(data), int64(len(data))); err != nil {
+		return fmt.Errorf("failed to write object metadata: %w", err)
+	}
+	return nil
+}
+
+func readMeta(metaPath string) (localMeta, error) {
+	var meta localMeta
+
+	data, err := os.ReadFile(metaPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return meta, nil
+	}
+	if err != nil {
+		return meta, fmt.Errorf("failed to read object metadata: %w", err)
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return meta, fmt.Errorf("failed to read object metadata: %w", err)
+	}
+	return meta, nil
+}
+
+// etagReader считает MD5 части при склейке и на EOF сверяет его с ETag, присланным клиентом
+type etagReader struct {
+	r    io.Reader
+	h    hash.Hash
+	part storage.CompletedPart
+}
+
+func (e *etagReader) Read(p []byte) (int, error) {
+	n, err := e.r.Read(p)
+	e.h.Write(p[:n])
+	if err == io.EOF && hex.EncodeToString(e.h.Sum(nil)) != strings.Trim(e.part.ETag, `"`) {
+		return n, fmt.Errorf("part %d etag mismatch", e.part.PartNumber)
+	}
+	return n, err
+}
@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
+)
+
+func newTestLocalStorage(t *testing.T) *LocalStorage {
+	s, err := NewLocalStorage(t.TempDir(), "http://localhost:8080/", "secret")
+	require.NoError(t, err)
+	return s
+}
+
+func TestLocalStorage_PutGet(t *testing.T) {
+	ctx := context.Background()
+	s := newTestLocalStorage(t)
+
+	err := s.Put(ctx, "files/a/b.txt", strings.NewReader("file content"), storage.ObjectInfo{
+		ContentType:   "text/plain",
+		ContentLength: 12,
+		Metadata:      map[string]string{"owner": "user-1"},
+	})
+	require.NoError(t, err)
+
+	obj, err := s.Get(ctx, "files/a/b.txt")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(obj.Body)
+	obj.Body.Close()
+	assert.Equal(t, "file content", string(data))
+	assert.Equal(t, "text/plain", obj.ContentType)
+	assert.Equal(t, int64(12), obj.ContentLength)
+	assert.Equal(t, "user-1", obj.Metadata["owner"])
+
+	obj, err = s.GetRange(ctx, "files/a/b.txt", 5, 100)
+	require.NoError(t, err)
+	data, _ = io.ReadAll(obj.Body)
+	obj.Body.Close()
+	assert.Equal(t, "content", string(data))
+	assert.Equal(t, int64(7), obj.ContentLength)
+
+	exists, err := s.FileExists(ctx, "files/a/b.txt")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	assert.NoError(t, s.Delete(ctx, "files/a/b.txt"))
+	assert.NoError(t, s.Delete(ctx, "files/a/b.txt"))
+
+	_, err = s.Get(ctx, "files/a/b.txt")
+	assert.ErrorIs(t, err, storage.ErrObjectNotFound)
+}
+
+func TestLocalStorage_PutSizeMismatch(t *testing.T) {
+	ctx := context.Background()
+	s := newTestLocalStorage(t)
+
+	err := s.Put(ctx, "key", strings.NewReader("short"), storage.ObjectInfo{ContentLength: 10})
+	assert.ErrorIs(t, err, storage.ErrSizeMismatch)
+
+	exists, _ := s.FileExists(ctx, "key")
+	assert.False(t, exists)
+}
+
+func TestLocalStorage_Copy(t *testing.T) {
+	ctx := context.Background()
+	s := newTestLocalStorage(t)
+
+	require.NoError(t, s.Put(ctx, "files/a/b.txt", strings.NewReader("file content"), storage.ObjectInfo{
+		ContentType:   "text/plain",
+		ContentLength: 12,
+		Metadata:      map[string]string{"owner": "user-1"},
+	}))
+
+	require.NoError(t, s.Copy(ctx, "files/a/b.txt", "files/c/d.txt"))
+
+	obj, err := s.Get(ctx, "files/c/d.txt")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(obj.Body)
+	obj.Body.Close()
+	assert.Equal(t, "file content", string(data))
+	assert.Equal(t, "text/plain", obj.ContentType)
+	assert.Equal(t, "user-1", obj.Metadata["owner"])
+
+	assert.ErrorIs(t, s.Copy(ctx, "files/missing", "files/e"), storage.ErrObjectNotFound)
+}
+
+func TestLocalStorage_List(t *testing.T) {
+	ctx := context.Background()
+	s := newTestLocalStorage(t)
+
+	for _, key := range []string{"files/b/c.txt", "files/a/b.txt", "files/a.txt", "other/x.txt"} {
+		require.NoError(t, s.Put(ctx, key, strings.NewReader("x"), storage.ObjectInfo{ContentLength: 1}))
+	}
+
+	keys := func(objects []storage.ObjectSummary) []string {
+		out := make([]string, 0, len(objects))
+		for _, o := range objects {
+			out = append(out, o.Key)
+		}
+		return out
+	}
+
+	// Порядок как у S3: "files/a.txt" < "files/a/b.txt"
+	objects, err := s.List(ctx, "files/", "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"files/a.txt", "files/a/b.txt", "files/b/c.txt"}, keys(objects))
+	assert.Equal(t, int64(1), objects[0].Size)
+	assert.False(t, objects[0].LastModified.IsZero())
+
+	objects, err = s.List(ctx, "files/", "files/a.txt", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"files/a/b.txt"}, keys(objects))
+
+	objects, err = s.List(ctx, "files/a", "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"files/a.txt", "files/a/b.txt"}, keys(objects))
+
+	objects, err = s.List(ctx, "missing/", "", 0)
+	require.NoError(t, err)
+	assert.Empty(t, objects)
+}
+
+func TestLocalStorage_InvalidKey(t *testing.T) {
+	ctx := context.Background()
+	s := newTestLocalStorage(t)
+
+	for _, key := range []string{"", "/etc/passwd", "../outside", "a/../../b", "a//b"} {
+		err := s.Put(ctx, key, strings.NewReader("x"), storage.ObjectInfo{ContentLength: 1})
+		assert.ErrorIs(t, err, storage.ErrInvalidKey, key)
+	}
+}
+
+func TestLocalStorage_SignedURL(t *testing.T) {
+	ctx := context.Background()
+	s := newTestLocalStorage(t)
+	now := time.Unix(1_700_000_000, 0)
+	s.now = func() time.Time { return now }
+
+	raw, err := s.GenerateUploadURLWithSize(ctx, "files/a/my file.txt", 5*time.Minute, 42)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(raw, "http://localhost:8080/api/v1/storage/objects/files/a/my%20file.txt?"))
+
+	u, err := url.Parse(raw)
+	require.NoError(t, err)
+
+	size, err := s.VerifyObjectURL(http.MethodPut, "files/a/my file.txt", u.Query())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), size)
+
+	_, err = s.VerifyObjectURL(http.MethodGet, "files/a/my file.txt", u.Query())
+	assert.ErrorIs(t, err, storage.ErrInvalidSignature)
+
+	tampered := u.Query()
+	tampered.Set("size", "43")
+	_, err = s.VerifyObjectURL(http.MethodPut, "files/a/my file.txt", tampered)
+	assert.ErrorIs(t, err, storage.ErrInvalidSignature)
+
+	now = now.Add(6 * time.Minute)
+	_, err = s.VerifyObjectURL(http.MethodPut, "files/a/my file.txt", u.Query())
+	assert.ErrorIs(t, err, storage.ErrURLExpired)
+}
+
+func TestLocalStorage_MultipartUpload(t *testing.T) {
+	ctx := context.Background()
+	s := newTestLocalStorage(t)
+
+	uploadID, err := s.CreateMultipartUpload(ctx, "files/big.bin", "application/zip")
+	require.NoError(t, err)
+
+	raw, err := s.GenerateUploadPartURL(ctx, "files/big.bin", uploadID, 2, time.Minute)
+	require.NoError(t, err)
+	u, _ := url.Parse(raw)
+	assert.NoError(t, s.VerifyPartURL(uploadID, 2, u.Query()))
+	assert.ErrorIs(t, s.VerifyPartURL(uploadID, 3, u.Query()), storage.ErrInvalidSignature)
+
+	etag1, err := s.UploadPart(ctx, "files/big.bin", uploadID, 1, []byte("hello "))
+	require.NoError(t, err)
+	etag2, err := s.WritePart(ctx, uploadID, 2, bytes.NewReader([]byte("world")), 5)
+	require.NoError(t, err)
+
+	err = s.CompleteMultipartUpload(ctx, "files/big.bin", uploadID, []storage.CompletedPart{
+		{PartNumber: 1, ETag: etag1},
+		{PartNumber: 2, ETag: "\"bad\""},
+	})
+	assert.Error(t, err)
+
+	err = s.CompleteMultipartUpload(ctx, "files/big.bin", uploadID, []storage.CompletedPart{
+		{PartNumber: 1, ETag: etag1},
+		{PartNumber: 2, ETag: etag2},
+	})
+	require.NoError(t, err)
+
+	obj, err := s.Get(ctx, "files/big.bin")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(obj.Body)
+	obj.Body.Close()
+	assert.Equal(t, "hello world", string(data))
+	assert.Equal(t, "application/zip", obj.ContentType)
+
+	assert.ErrorIs(t, s.AbortMultipartUpload(ctx, "files/big.bin", uploadID), storage.ErrUploadNotFound)
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
+)
+
+const objectCreatedPrefix = "ObjectCreated:"
+
+// bucketNotification формат уведомлений S3 и MinIO (Kafka и webhook цели шлют одно и то же тело)
+type bucketNotification struct {
+	Records []struct {
+		EventName string `json:"eventName"`
+		S3        struct {
+			Object struct {
+				Key  string `json:"key"`
+				Size int64  `json:"size"`
+				ETag string `json:"eTag"`
+			} `json:"object"`
+		} `json:"s3"`
+	} `json:"Records"`
+}
+
+// ParseBucketNotification возвращает события создания объектов; остальные события пропускаются.
+// Ключи в уведомлениях URL-кодированы.
+func ParseBucketNotification(data []byte) ([]storage.ObjectCreated, error) {
+	var n bucketNotification
+	if err := json.Unmarshal(data, &n); err != nil {
+		return nil, fmt.Errorf("failed to parse bucket notification: %w", err)
+	}
+
+	events := make([]storage.ObjectCreated, 0, len(n.Records))
+	for _, r := range n.Records {
+		// AWS присылает ObjectCreated:Put, MinIO — s3:ObjectCreated:Put
+		name := strings.TrimPrefix(r.EventName, "s3:")
+		if !strings.HasPrefix(name, objectCreatedPrefix) {
+			continue
+		}
+
+		key, err := url.QueryUnescape(r.S3.Object.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse bucket notification: invalid key %q: %w", r.S3.Object.Key, err)
+		}
+
+		events = append(events, storage.ObjectCreated{
+			EventName: "s3:" + name,
+			Key:       key,
+			Size:      r.S3.Object.Size,
+			ETag:      r.S3.Object.ETag,
+		})
+	}
+
+	return events, nil
+}
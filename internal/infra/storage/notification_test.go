@@ -0,0 +1,26 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBucketNotification(t *testing.T) {
+	// Формат AWS: имя события без префикса s3:
+	events, err := ParseBucketNotification([]byte(`{"Records":[
+		{"eventName":"ObjectCreated:CompleteMultipartUpload","s3":{"object":{"key":"files/%D0%BE%D1%82%D1%87%D1%91%D1%82.pdf","size":2048}}}
+	]}`))
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "s3:ObjectCreated:CompleteMultipartUpload", events[0].EventName)
+	assert.Equal(t, "files/отчёт.pdf", events[0].Key)
+
+	events, err = ParseBucketNotification([]byte(`{"Records":[{"eventName":"s3:TestEvent"}]}`))
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	_, err = ParseBucketNotification([]byte(`not json`))
+	assert.Error(t, err)
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -15,6 +16,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
 )
 
 type S3Deleter interface {
@@ -22,13 +25,27 @@ type S3Deleter interface {
 	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
 	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
 	HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error)
+	CreateMultipartUpload(ctx context.Context, params *s3.CreateMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error)
+	UploadPart(ctx context.Context, params *s3.UploadPartInput, optFns ...func(*s3.Options)) (*s3.UploadPartOutput, error)
+	CompleteMultipartUpload(ctx context.Context, params *s3.CompleteMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error)
+	AbortMultipartUpload(ctx context.Context, params *s3.AbortMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error)
+	ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error)
+	CopyObject(ctx context.Context, params *s3.CopyObjectInput, optFns ...func(*s3.Options)) (*s3.CopyObjectOutput, error)
+	UploadPartCopy(ctx context.Context, params *s3.UploadPartCopyInput, optFns ...func(*s3.Options)) (*s3.UploadPartCopyOutput, error)
 }
 
 type S3Presigner interface {
 	PresignPutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.PresignOptions)) (*v4.PresignedHTTPRequest, error)
 	PresignGetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.PresignOptions)) (*v4.PresignedHTTPRequest, error)
+	PresignUploadPart(ctx context.Context, params *s3.UploadPartInput, optFns ...func(*s3.PresignOptions)) (*v4.PresignedHTTPRequest, error)
 }
 
+const (
+	// copyObjectMaxSize предел одного CopyObject в S3
+	copyObjectMaxSize = 5 << 30
+	copyPartSize      = 512 << 20
+)
+
 type S3Storage struct {
 	client        S3Deleter
 	presignClient S3Presigner
@@ -107,6 +124,45 @@ func (s *S3Storage) GenerateUploadURLWithSize(ctx context.Context, key string, e
 	return request.URL, nil
 }
 
+// GenerateUploadURLWithChecksum подписывает заголовок x-amz-checksum-*: S3 сам отклонит PUT, если содержимое не совпадёт
+func (s *S3Storage) GenerateUploadURLWithChecksum(ctx context.Context, key string, expiresIn time.Duration, fileSize int64, checksum storage.Checksum) (string, map[string]string, error) {
+	if s.presignClient == nil {
+		return "", nil, fmt.Errorf("presign client is not initialized")
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(key),
+		ContentLength: aws.Int64(fileSize),
+	}
+	switch checksum.Algorithm {
+	case "sha256":
+		input.ChecksumSHA256 = aws.String(checksum.Value)
+	case "crc32c":
+		input.ChecksumCRC32C = aws.String(checksum.Value)
+	default:
+		return "", nil, fmt.Errorf("unsupported checksum algorithm: %s", checksum.Algorithm)
+	}
+
+	request, err := s.presignClient.PresignPutObject(ctx, input, func(opts *s3.PresignOptions) {
+		opts.Expires = expiresIn
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate presigned upload URL with checksum: %w", err)
+	}
+
+	// Host и Content-Length HTTP клиент выставляет сам, остальные подписанные заголовки передаёт клиент
+	headers := make(map[string]string, len(request.SignedHeader))
+	for name, values := range request.SignedHeader {
+		if len(values) == 0 || strings.EqualFold(name, "Host") || strings.EqualFold(name, "Content-Length") {
+			continue
+		}
+		headers[strings.ToLower(name)] = values[0]
+	}
+
+	return request.URL, headers, nil
+}
+
 func (s *S3Storage) GenerateDownloadURL(ctx context.Context, key string, expiresIn time.Duration) (string, error) {
 	if s.presignClient == nil {
 		return "", fmt.Errorf("presign client is not initialized")
@@ -136,35 +192,144 @@ func (s *S3Storage) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
-func (s *S3Storage) UploadFile(ctx context.Context, key string, fileData []byte) error {
-	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket: aws.String(s.bucket),
-		Key:    aws.String(key),
-		Body:   bytes.NewReader(fileData),
+// Copy копирует объект на стороне S3, данные через приложение не идут. CopyObject ограничен 5GB,
+// объекты больше копируются частями через UploadPartCopy.
+func (s *S3Storage) Copy(ctx context.Context, srcKey, dstKey string) error {
+	info, err := s.Stat(ctx, srcKey)
+	if err != nil {
+		return err
+	}
+	source := url.PathEscape(s.bucket + "/" + srcKey)
+
+	if info.ContentLength > copyObjectMaxSize {
+		return s.copyMultipart(ctx, source, dstKey, info)
+	}
+
+	_, err = s.client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(s.bucket),
+		Key:        aws.String(dstKey),
+		CopySource: aws.String(source),
 	})
 	if err != nil {
-		return fmt.Errorf("failed to upload file: %w", err)
+		return fmt.Errorf("failed to copy object: %w", err)
 	}
 	return nil
 }
 
-func (s *S3Storage) DownloadFile(ctx context.Context, key string) ([]byte, error) {
-	output, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+// copyMultipart в отличие от CopyObject не переносит метаданные сам, поэтому они берутся из info
+func (s *S3Storage) copyMultipart(ctx context.Context, source, dstKey string, info *storage.ObjectInfo) error {
+	input := &s3.CreateMultipartUploadInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(dstKey),
+		Metadata: info.Metadata,
+	}
+	if info.ContentType != "" {
+		input.ContentType = aws.String(info.ContentType)
+	}
+	output, err := s.client.CreateMultipartUpload(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+	uploadID := aws.ToString(output.UploadId)
+
+	parts := make([]storage.CompletedPart, 0, info.ContentLength/copyPartSize+1)
+	for offset, partNumber := int64(0), int32(1); offset < info.ContentLength; offset, partNumber = offset+copyPartSize, partNumber+1 {
+		end := min(offset+copyPartSize, info.ContentLength) - 1
+		part, err := s.client.UploadPartCopy(ctx, &s3.UploadPartCopyInput{
+			Bucket:          aws.String(s.bucket),
+			Key:             aws.String(dstKey),
+			UploadId:        aws.String(uploadID),
+			PartNumber:      aws.Int32(partNumber),
+			CopySource:      aws.String(source),
+			CopySourceRange: aws.String(fmt.Sprintf("bytes=%d-%d", offset, end)),
+		})
+		if err != nil {
+			_ = s.AbortMultipartUpload(ctx, dstKey, uploadID)
+			return fmt.Errorf("failed to copy object part: %w", err)
+		}
+		etag := ""
+		if part.CopyPartResult != nil {
+			etag = aws.ToString(part.CopyPartResult.ETag)
+		}
+		parts = append(parts, storage.CompletedPart{PartNumber: partNumber, ETag: etag})
+	}
+
+	if err := s.CompleteMultipartUpload(ctx, dstKey, uploadID, parts); err != nil {
+		_ = s.AbortMultipartUpload(ctx, dstKey, uploadID)
+		return err
+	}
+	return nil
+}
+
+// Put загружает объект потоком. Для тела без Seek подпись payload пропускается,
+// иначе SDK пришлось бы вычитать его целиком ради SHA256.
+func (s *S3Storage) Put(ctx context.Context, key string, body io.Reader, info storage.ObjectInfo) error {
+	input := &s3.PutObjectInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(key),
+		Body:     body,
+		Metadata: info.Metadata,
+	}
+	if info.ContentType != "" {
+		input.ContentType = aws.String(info.ContentType)
+	}
+	if info.ContentLength >= 0 {
+		input.ContentLength = aws.Int64(info.ContentLength)
+	}
+
+	var optFns []func(*s3.Options)
+	if _, ok := body.(io.Seeker); !ok {
+		optFns = append(optFns, s3.WithAPIOptions(v4.SwapComputePayloadSHA256ForUnsignedPayloadMiddleware))
+	}
+
+	_, err := s.client.PutObject(ctx, input, optFns...)
+	if err != nil {
+		return fmt.Errorf("failed to put object: %w", err)
+	}
+	return nil
+}
+
+func (s *S3Storage) Get(ctx context.Context, key string) (*storage.Object, error) {
+	return s.getObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(s.bucket),
 		Key:    aws.String(key),
 	})
+}
+
+// GetRange возвращает length байт начиная с offset; у последнего диапазона байт может быть меньше
+func (s *S3Storage) GetRange(ctx context.Context, key string, offset, length int64) (*storage.Object, error) {
+	if offset < 0 || length <= 0 {
+		return nil, fmt.Errorf("invalid range: offset %d, length %d", offset, length)
+	}
+
+	return s.getObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)),
+	})
+}
+
+func (s *S3Storage) getObject(ctx context.Context, input *s3.GetObjectInput) (*storage.Object, error) {
+	output, err := s.client.GetObject(ctx, input)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get object: %w", err)
 	}
-	defer output.Body.Close()
 
-	buf := new(bytes.Buffer)
-	_, err = io.Copy(buf, output.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read object body: %w", err)
+	obj := &storage.Object{
+		Body: output.Body,
+		ObjectInfo: storage.ObjectInfo{
+			ContentLength: -1,
+			Metadata:      output.Metadata,
+		},
+	}
+	if output.ContentType != nil {
+		obj.ContentType = *output.ContentType
+	}
+	if output.ContentLength != nil {
+		obj.ContentLength = *output.ContentLength
 	}
 
-	return buf.Bytes(), nil
+	return obj, nil
 }
 
 func (s *S3Storage) FileExists(ctx context.Context, key string) (bool, error) {
@@ -182,3 +347,151 @@ func (s *S3Storage) FileExists(ctx context.Context, key string) (bool, error) {
 
 	return true, nil
 }
+
+func (s *S3Storage) Stat(ctx context.Context, key string) (*storage.ObjectInfo, error) {
+	output, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "NotFound") {
+			return nil, storage.ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to stat object: %w", err)
+	}
+
+	info := &storage.ObjectInfo{ContentLength: -1, Metadata: output.Metadata}
+	if output.ContentType != nil {
+		info.ContentType = *output.ContentType
+	}
+	if output.ContentLength != nil {
+		info.ContentLength = *output.ContentLength
+	}
+	return info, nil
+}
+
+// List читает одну страницу ListObjectsV2; S3 отдаёт не больше 1000 ключей за запрос
+func (s *S3Storage) List(ctx context.Context, prefix, startAfter string, limit int) ([]storage.ObjectSummary, error) {
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}
+	if startAfter != "" {
+		input.StartAfter = aws.String(startAfter)
+	}
+	if limit > 0 {
+		input.MaxKeys = aws.Int32(int32(limit))
+	}
+
+	output, err := s.client.ListObjectsV2(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects: %w", err)
+	}
+
+	objects := make([]storage.ObjectSummary, 0, len(output.Contents))
+	for _, obj := range output.Contents {
+		summary := storage.ObjectSummary{Key: aws.ToString(obj.Key), Size: aws.ToInt64(obj.Size)}
+		if obj.LastModified != nil {
+			summary.LastModified = *obj.LastModified
+		}
+		objects = append(objects, summary)
+	}
+	return objects, nil
+}
+
+// CreateMultipartUpload начинает multipart загрузку и возвращает UploadId из S3
+func (s *S3Storage) CreateMultipartUpload(ctx context.Context, key string, contentType string) (string, error) {
+	input := &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+
+	output, err := s.client.CreateMultipartUpload(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+	if output.UploadId == nil {
+		return "", fmt.Errorf("failed to create multipart upload: empty upload id")
+	}
+
+	return *output.UploadId, nil
+}
+
+// GenerateUploadPartURL генерирует presigned URL для загрузки одной части
+func (s *S3Storage) GenerateUploadPartURL(ctx context.Context, key string, uploadID string, partNumber int32, expiresIn time.Duration) (string, error) {
+	if s.presignClient == nil {
+		return "", fmt.Errorf("presign client is not initialized")
+	}
+
+	request, err := s.presignClient.PresignUploadPart(ctx, &s3.UploadPartInput{
+		Bucket:     aws.String(s.bucket),
+		Key:        aws.String(key),
+		UploadId:   aws.String(uploadID),
+		PartNumber: aws.Int32(partNumber),
+	}, func(opts *s3.PresignOptions) {
+		opts.Expires = expiresIn
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to generate presigned upload part URL: %w", err)
+	}
+
+	return request.URL, nil
+}
+
+// UploadPart загружает часть multipart загрузки через API и возвращает её ETag
+func (s *S3Storage) UploadPart(ctx context.Context, key string, uploadID string, partNumber int32, data []byte) (string, error) {
+	output, err := s.client.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(key),
+		UploadId:      aws.String(uploadID),
+		PartNumber:    aws.Int32(partNumber),
+		Body:          bytes.NewReader(data),
+		ContentLength: aws.Int64(int64(len(data))),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload part: %w", err)
+	}
+	if output.ETag == nil {
+		return "", fmt.Errorf("failed to upload part: empty etag")
+	}
+
+	return *output.ETag, nil
+}
+
+func (s *S3Storage) CompleteMultipartUpload(ctx context.Context, key string, uploadID string, parts []storage.CompletedPart) error {
+	completed := make([]types.CompletedPart, 0, len(parts))
+	for _, p := range parts {
+		completed = append(completed, types.CompletedPart{
+			ETag:       aws.String(p.ETag),
+			PartNumber: aws.Int32(p.PartNumber),
+		})
+	}
+
+	_, err := s.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{
+			Parts: completed,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+	return nil
+}
+
+func (s *S3Storage) AbortMultipartUpload(ctx context.Context, key string, uploadID string) error {
+	_, err := s.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to abort multipart upload: %w", err)
+	}
+	return nil
+}
@@ -3,14 +3,19 @@ package storage
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
+	"net/http"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
 )
 
 type MockS3Client struct {
@@ -39,13 +44,48 @@ func (m *MockS3Client) HeadObject(ctx context.Context, params *s3.HeadObjectInpu
 	return &s3.HeadObjectOutput{}, args.Error(1)
 }
 
+func (m *MockS3Client) CreateMultipartUpload(ctx context.Context, params *s3.CreateMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error) {
+	args := m.Called(ctx, params)
+	return &s3.CreateMultipartUploadOutput{UploadId: aws.String("upload-id")}, args.Error(1)
+}
+
+func (m *MockS3Client) UploadPart(ctx context.Context, params *s3.UploadPartInput, optFns ...func(*s3.Options)) (*s3.UploadPartOutput, error) {
+	args := m.Called(ctx, params)
+	return &s3.UploadPartOutput{ETag: aws.String("part-etag")}, args.Error(1)
+}
+
+func (m *MockS3Client) CompleteMultipartUpload(ctx context.Context, params *s3.CompleteMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error) {
+	args := m.Called(ctx, params)
+	return &s3.CompleteMultipartUploadOutput{}, args.Error(1)
+}
+
+func (m *MockS3Client) ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(*s3.ListObjectsV2Output), args.Error(1)
+}
+
+func (m *MockS3Client) CopyObject(ctx context.Context, params *s3.CopyObjectInput, optFns ...func(*s3.Options)) (*s3.CopyObjectOutput, error) {
+	args := m.Called(ctx, params)
+	return &s3.CopyObjectOutput{}, args.Error(1)
+}
+
+func (m *MockS3Client) UploadPartCopy(ctx context.Context, params *s3.UploadPartCopyInput, optFns ...func(*s3.Options)) (*s3.UploadPartCopyOutput, error) {
+	args := m.Called(ctx, params)
+	return &s3.UploadPartCopyOutput{CopyPartResult: &types.CopyPartResult{ETag: aws.String("part-etag")}}, args.Error(1)
+}
+
+func (m *MockS3Client) AbortMultipartUpload(ctx context.Context, params *s3.AbortMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error) {
+	args := m.Called(ctx, params)
+	return &s3.AbortMultipartUploadOutput{}, args.Error(1)
+}
+
 type MockS3Presigner struct {
 	mock.Mock
 }
 
 func (m *MockS3Presigner) PresignPutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.PresignOptions)) (*v4.PresignedHTTPRequest, error) {
 	args := m.Called(ctx, params)
-	return &v4.PresignedHTTPRequest{URL: "http://upload-url"}, args.Error(1)
+	return args.Get(0).(*v4.PresignedHTTPRequest), args.Error(1)
 }
 
 func (m *MockS3Presigner) PresignGetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.PresignOptions)) (*v4.PresignedHTTPRequest, error) {
@@ -53,6 +93,11 @@ func (m *MockS3Presigner) PresignGetObject(ctx context.Context, params *s3.GetOb
 	return &v4.PresignedHTTPRequest{URL: "http://download-url"}, args.Error(1)
 }
 
+func (m *MockS3Presigner) PresignUploadPart(ctx context.Context, params *s3.UploadPartInput, optFns ...func(*s3.PresignOptions)) (*v4.PresignedHTTPRequest, error) {
+	args := m.Called(ctx, params)
+	return &v4.PresignedHTTPRequest{URL: "http://upload-part-url"}, args.Error(1)
+}
+
 func TestGenerateUploadURL(t *testing.T) {
 	ctx := context.Background()
 	mockPresigner := new(MockS3Presigner)
@@ -75,6 +120,36 @@ func TestGenerateUploadURL(t *testing.T) {
 	mockPresigner.AssertExpectations(t)
 }
 
+func TestGenerateUploadURLWithChecksum(t *testing.T) {
+	ctx := context.Background()
+	mockPresigner := new(MockS3Presigner)
+
+	mockPresigner.
+		On("PresignPutObject", ctx, mock.MatchedBy(func(in *s3.PutObjectInput) bool {
+			return in.ChecksumSHA256 != nil && *in.ChecksumSHA256 == "c2hhMjU2" && *in.ContentLength == 42
+		})).
+		Return(&v4.PresignedHTTPRequest{
+			URL: "http://upload-url",
+			SignedHeader: http.Header{
+				"Host":                  {"s3.example.com"},
+				"Content-Length":        {"42"},
+				"X-Amz-Checksum-Sha256": {"c2hhMjU2"},
+			},
+		}, nil)
+
+	s := &S3Storage{presignClient: mockPresigner, bucket: "test-bucket"}
+
+	url, headers, err := s.GenerateUploadURLWithChecksum(ctx, "test-key", 5*time.Minute, 42, storage.Checksum{Algorithm: "sha256", Value: "c2hhMjU2"})
+	assert.NoError(t, err)
+	assert.Equal(t, "http://upload-url", url)
+	assert.Equal(t, map[string]string{"x-amz-checksum-sha256": "c2hhMjU2"}, headers)
+
+	_, _, err = s.GenerateUploadURLWithChecksum(ctx, "test-key", 5*time.Minute, 42, storage.Checksum{Algorithm: "md5", Value: "x"})
+	assert.Error(t, err)
+
+	mockPresigner.AssertExpectations(t)
+}
+
 func TestGenerateDownloadURL(t *testing.T) {
 	ctx := context.Background()
 	mockPresigner := new(MockS3Presigner)
@@ -117,16 +192,17 @@ func TestDelete(t *testing.T) {
 	mockClient.AssertExpectations(t)
 }
 
-func TestUploadFile(t *testing.T) {
+func TestPut(t *testing.T) {
 	ctx := context.Background()
 	mockPresigner := new(MockS3Presigner)
 	mockClient := new(MockS3Client)
 
-	fileData := []byte("file content")
-
 	mockClient.
 		On("PutObject", ctx, mock.MatchedBy(func(input *s3.PutObjectInput) bool {
-			return *input.Key == "test-key"
+			return *input.Key == "test-key" &&
+				*input.ContentType == "image/png" &&
+				*input.ContentLength == 12 &&
+				input.Metadata["owner"] == "user-1"
 		})).
 		Return(&s3.PutObjectOutput{}, nil)
 
@@ -136,19 +212,23 @@ func TestUploadFile(t *testing.T) {
 		bucket:        "test-bucket",
 	}
 
-	err := s.UploadFile(ctx, "test-key", fileData)
+	err := s.Put(ctx, "test-key", bytes.NewReader([]byte("file content")), storage.ObjectInfo{
+		ContentType:   "image/png",
+		ContentLength: 12,
+		Metadata:      map[string]string{"owner": "user-1"},
+	})
 	assert.NoError(t, err)
 	mockClient.AssertExpectations(t)
 }
 
-func TestDownloadFile(t *testing.T) {
+func TestGet(t *testing.T) {
 	ctx := context.Background()
 	mockPresigner := new(MockS3Presigner)
 	mockClient := new(MockS3Client)
 
 	mockClient.
 		On("GetObject", ctx, mock.MatchedBy(func(input *s3.GetObjectInput) bool {
-			return *input.Key == "test-key"
+			return *input.Key == "test-key" && input.Range == nil
 		})).
 		Return(&s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader([]byte("file content")))}, nil)
 
@@ -158,9 +238,193 @@ func TestDownloadFile(t *testing.T) {
 		bucket:        "test-bucket",
 	}
 
-	data, err := s.DownloadFile(ctx, "test-key")
+	obj, err := s.Get(ctx, "test-key")
+	assert.NoError(t, err)
+	defer obj.Body.Close()
+
+	data, err := io.ReadAll(obj.Body)
 	assert.NoError(t, err)
 	assert.Equal(t, []byte("file content"), data)
 
 	mockClient.AssertExpectations(t)
 }
+
+func TestGetRange(t *testing.T) {
+	ctx := context.Background()
+	mockPresigner := new(MockS3Presigner)
+	mockClient := new(MockS3Client)
+
+	mockClient.
+		On("GetObject", ctx, mock.MatchedBy(func(input *s3.GetObjectInput) bool {
+			return *input.Key == "test-key" && *input.Range == "bytes=100-163"
+		})).
+		Return(&s3.GetObjectOutput{}, nil)
+
+	s := &S3Storage{
+		client:        mockClient,
+		presignClient: mockPresigner,
+		bucket:        "test-bucket",
+	}
+
+	obj, err := s.GetRange(ctx, "test-key", 100, 64)
+	assert.NoError(t, err)
+	obj.Body.Close()
+
+	_, err = s.GetRange(ctx, "test-key", 0, 0)
+	assert.Error(t, err)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestMultipartUpload(t *testing.T) {
+	ctx := context.Background()
+	mockPresigner := new(MockS3Presigner)
+	mockClient := new(MockS3Client)
+
+	mockClient.
+		On("CreateMultipartUpload", ctx, mock.AnythingOfType("*s3.CreateMultipartUploadInput")).
+		Return(&s3.CreateMultipartUploadOutput{}, nil)
+	mockPresigner.
+		On("PresignUploadPart", ctx, mock.MatchedBy(func(in *s3.UploadPartInput) bool {
+			return *in.UploadId == "upload-id" && *in.PartNumber == 2
+		})).
+		Return(&v4.PresignedHTTPRequest{URL: "http://upload-part-url"}, nil)
+	mockClient.
+		On("UploadPart", ctx, mock.MatchedBy(func(in *s3.UploadPartInput) bool {
+			return *in.PartNumber == 1 && *in.ContentLength == 4
+		})).
+		Return(&s3.UploadPartOutput{}, nil)
+	mockClient.
+		On("CompleteMultipartUpload", ctx, mock.MatchedBy(func(in *s3.CompleteMultipartUploadInput) bool {
+			return len(in.MultipartUpload.Parts) == 2 && *in.MultipartUpload.Parts[1].ETag == "etag-2"
+		})).
+		Return(&s3.CompleteMultipartUploadOutput{}, nil)
+	mockClient.
+		On("AbortMultipartUpload", ctx, mock.AnythingOfType("*s3.AbortMultipartUploadInput")).
+		Return(&s3.AbortMultipartUploadOutput{}, nil)
+
+	s := &S3Storage{
+		client:        mockClient,
+		presignClient: mockPresigner,
+		bucket:        "test-bucket",
+	}
+
+	uploadID, err := s.CreateMultipartUpload(ctx, "test-key", "application/zip")
+	assert.NoError(t, err)
+	assert.Equal(t, "upload-id", uploadID)
+
+	url, err := s.GenerateUploadPartURL(ctx, "test-key", uploadID, 2, 5*time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://upload-part-url", url)
+
+	etag, err := s.UploadPart(ctx, "test-key", uploadID, 1, []byte("data"))
+	assert.NoError(t, err)
+	assert.Equal(t, "part-etag", etag)
+
+	err = s.CompleteMultipartUpload(ctx, "test-key", uploadID, []storage.CompletedPart{
+		{PartNumber: 1, ETag: "etag-1"},
+		{PartNumber: 2, ETag: "etag-2"},
+	})
+	assert.NoError(t, err)
+
+	err = s.AbortMultipartUpload(ctx, "test-key", uploadID)
+	assert.NoError(t, err)
+
+	mockClient.AssertExpectations(t)
+	mockPresigner.AssertExpectations(t)
+}
+
+func TestStat(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(MockS3Client)
+
+	mockClient.
+		On("HeadObject", ctx, mock.MatchedBy(func(input *s3.HeadObjectInput) bool {
+			return *input.Key == "test-key"
+		})).
+		Return(nil, nil)
+	mockClient.
+		On("HeadObject", ctx, mock.MatchedBy(func(input *s3.HeadObjectInput) bool {
+			return *input.Key == "missing-key"
+		})).
+		Return(nil, errors.New("api error NotFound: Not Found"))
+
+	s := &S3Storage{
+		client:        mockClient,
+		presignClient: new(MockS3Presigner),
+		bucket:        "test-bucket",
+	}
+
+	info, err := s.Stat(ctx, "test-key")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-1), info.ContentLength)
+
+	_, err = s.Stat(ctx, "missing-key")
+	assert.ErrorIs(t, err, storage.ErrObjectNotFound)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestList(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(MockS3Client)
+	modified := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mockClient.
+		On("ListObjectsV2", ctx, mock.MatchedBy(func(input *s3.ListObjectsV2Input) bool {
+			return *input.Bucket == "test-bucket" && *input.Prefix == "files/" &&
+				*input.StartAfter == "files/a" && *input.MaxKeys == 2
+		})).
+		Return(&s3.ListObjectsV2Output{Contents: []types.Object{
+			{Key: aws.String("files/b"), Size: aws.Int64(10), LastModified: &modified},
+			{Key: aws.String("files/c"), Size: aws.Int64(20)},
+		}}, nil)
+
+	s := &S3Storage{
+		client:        mockClient,
+		presignClient: new(MockS3Presigner),
+		bucket:        "test-bucket",
+	}
+
+	objects, err := s.List(ctx, "files/", "files/a", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []storage.ObjectSummary{
+		{Key: "files/b", Size: 10, LastModified: modified},
+		{Key: "files/c", Size: 20},
+	}, objects)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestCopy(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(MockS3Client)
+
+	mockClient.
+		On("HeadObject", ctx, mock.MatchedBy(func(input *s3.HeadObjectInput) bool {
+			return *input.Key == "files/a b.txt"
+		})).
+		Return(nil, nil)
+	mockClient.
+		On("HeadObject", ctx, mock.MatchedBy(func(input *s3.HeadObjectInput) bool {
+			return *input.Key == "missing-key"
+		})).
+		Return(nil, errors.New("api error NotFound: Not Found"))
+	mockClient.
+		On("CopyObject", ctx, mock.MatchedBy(func(input *s3.CopyObjectInput) bool {
+			return *input.Bucket == "test-bucket" && *input.Key == "files/copy.txt" &&
+				*input.CopySource == "test-bucket%2Ffiles%2Fa%20b.txt"
+		})).
+		Return(nil, nil)
+
+	s := &S3Storage{
+		client:        mockClient,
+		presignClient: new(MockS3Presigner),
+		bucket:        "test-bucket",
+	}
+
+	assert.NoError(t, s.Copy(ctx, "files/a b.txt", "files/copy.txt"))
+	assert.ErrorIs(t, s.Copy(ctx, "missing-key", "files/copy.txt"), storage.ErrObjectNotFound)
+
+	mockClient.AssertExpectations(t)
+}
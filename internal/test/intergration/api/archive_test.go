@@ -0,0 +1,186 @@
+package api_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+)
+
+// readZip возвращает размеры элементов архива по именам
+func readZip(t *testing.T, data []byte) map[string]uint64 {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	entries := make(map[string]uint64, len(zr.File))
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		n, err := io.Copy(io.Discard, rc)
+		require.NoError(t, err)
+		rc.Close()
+		entries[f.Name] = uint64(n)
+	}
+	return entries
+}
+
+func moveFile(t *testing.T, env *TestEnv, fileID uuid.UUID, folderID string, accessToken string) {
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/"+fileID.String()+"/move", map[string]interface{}{
+		"folder_id": folderID,
+	}, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+}
+
+func TestArchive_StreamsFilesAndFolders(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+	first := createFileAndUpload(t, env, "report.bin", 1024, "application/octet-stream", accessToken, file_version.FileStatusReady)
+	second := createFileAndUpload(t, env, "report.bin", 2048, "application/octet-stream", accessToken, file_version.FileStatusReady)
+
+	docsID := createFolder(t, env, "docs", nil, accessToken)
+	nestedID := createFolder(t, env, "nested", &docsID, accessToken)
+	createFolder(t, env, "empty", &docsID, accessToken)
+	inner := createFileAndUpload(t, env, "inner.bin", 512, "application/octet-stream", accessToken, file_version.FileStatusReady)
+	moveFile(t, env, inner, nestedID, accessToken)
+	// Незагруженный файл в папке пропускается, а не ломает архив
+	pending := createFile(t, env, "pending.bin", 100, "application/octet-stream", accessToken)
+	moveFile(t, env, pending, docsID, accessToken)
+
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/archive", map[string]interface{}{
+		"files": []map[string]interface{}{
+			{"file_id": first.String()},
+			{"file_id": second.String(), "version_num": 1},
+		},
+		"folder_ids": []string{docsID},
+	}, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+
+	entries := readZip(t, w.Body.Bytes())
+	assert.Equal(t, map[string]uint64{
+		"report.bin":            1024,
+		"report (1).bin":        2048,
+		"docs/":                 0,
+		"docs/nested/":          0,
+		"docs/empty/":           0,
+		"docs/nested/inner.bin": 512,
+	}, entries)
+}
+
+func TestArchive_LargeArchiveBuiltInBackground(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+	otherToken := createUserAndLogin(t, env, "other@mail.ru", "other")
+	first := createFileAndUpload(t, env, "a.bin", 40*1024, "application/octet-stream", accessToken, file_version.FileStatusReady)
+	second := createFileAndUpload(t, env, "b.bin", 40*1024, "application/octet-stream", accessToken, file_version.FileStatusReady)
+
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/archive", map[string]interface{}{
+		"files": []map[string]interface{}{
+			{"file_id": first.String()},
+			{"file_id": second.String()},
+		},
+	}, accessToken)
+	require.Equal(t, 202, w.Code, w.Body.String())
+	response := ParseJSONResponse(t, w)
+	archiveID := response["id"].(string)
+	assert.Equal(t, "pending", response["status"])
+	assert.Equal(t, float64(2), response["entries"])
+	assert.Nil(t, response["download_url"])
+
+	w = env.NewRequestWithAuth(t, "GET", "/api/v1/files/archive/"+archiveID, nil, otherToken)
+	assert.Equal(t, 403, w.Code, w.Body.String())
+
+	ctx := context.Background()
+	archives, err := env.ArchiveService.ClaimPending(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, archives, 1)
+	require.NoError(t, env.ArchiveService.Build(ctx, archives[0]))
+	assert.True(t, objectExists(t, env, archives[0].S3Key))
+
+	w = env.NewRequestWithAuth(t, "GET", "/api/v1/files/archive/"+archiveID, nil, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	response = ParseJSONResponse(t, w)
+	assert.Equal(t, "ready", response["status"])
+	downloadURL, ok := response["download_url"].(string)
+	require.True(t, ok, "download_url not found")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(downloadURL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, 200, resp.StatusCode)
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	entries := readZip(t, data)
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	assert.Equal(t, []string{"a.bin", "b.bin"}, names)
+	assert.Equal(t, uint64(40*1024), entries["a.bin"])
+
+	// Истёкший архив удаляется вместе с объектом
+	archives[0].ExpiresAt = time.Now().Add(-time.Minute)
+	require.NoError(t, env.ArchiveService.Delete(ctx, archives[0]))
+	assert.False(t, objectExists(t, env, archives[0].S3Key))
+	w = env.NewRequestWithAuth(t, "GET", "/api/v1/files/archive/"+archiveID, nil, accessToken)
+	assert.Equal(t, 404, w.Code, w.Body.String())
+}
+
+func TestArchive_Errors(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+	otherToken := createUserAndLogin(t, env, "other@mail.ru", "other")
+	processingID := createFile(t, env, "pending.bin", 1024, "application/octet-stream", accessToken)
+	folderID := createFolder(t, env, "docs", nil, accessToken)
+
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/archive", map[string]interface{}{}, accessToken)
+	assert.Equal(t, 400, w.Code, w.Body.String())
+
+	w = env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/archive", map[string]interface{}{
+		"files": []map[string]interface{}{{"file_id": processingID.String()}},
+	}, accessToken)
+	assert.Equal(t, 409, w.Code, w.Body.String())
+
+	w = env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/archive", map[string]interface{}{
+		"files": []map[string]interface{}{{"file_id": processingID.String(), "version_num": 5}},
+	}, accessToken)
+	assert.Equal(t, 404, w.Code, w.Body.String())
+
+	w = env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/archive", map[string]interface{}{
+		"files": []map[string]interface{}{{"file_id": processingID.String()}},
+	}, otherToken)
+	assert.Equal(t, 404, w.Code, w.Body.String())
+
+	w = env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/archive", map[string]interface{}{
+		"folder_ids": []string{folderID},
+	}, otherToken)
+	assert.Equal(t, 404, w.Code, w.Body.String())
+
+	w = env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/archive", map[string]interface{}{
+		"folder_ids": []string{"not-a-uuid"},
+	}, accessToken)
+	assert.Equal(t, 400, w.Code, w.Body.String())
+
+	w = env.NewRequestWithAuth(t, "GET", "/api/v1/files/archive/"+uuid.NewString(), nil, accessToken)
+	assert.Equal(t, 404, w.Code, w.Body.String())
+}
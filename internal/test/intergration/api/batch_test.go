@@ -0,0 +1,220 @@
+package api_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+)
+
+// batchResults возвращает элементы results ответа POST /files/batch
+func batchResults(t *testing.T, env *TestEnv, body map[string]interface{}, accessToken string) []map[string]interface{} {
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/batch", body, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	response := ParseJSONResponse(t, w)
+
+	raw, ok := response["results"].([]interface{})
+	require.True(t, ok, "results not found")
+	results := make([]map[string]interface{}, 0, len(raw))
+	for _, r := range raw {
+		results = append(results, r.(map[string]interface{}))
+	}
+	return results
+}
+
+func batchErrorCode(result map[string]interface{}) string {
+	e, ok := result["error"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	return e["code"].(string)
+}
+
+func TestBatch_AppliesOperationsIndependently(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+	otherToken := createUserAndLogin(t, env, "other@mail.ru", "other")
+	renamed := createFileWithStatus(t, env, "old.txt", 10, "text/plain", accessToken, file_version.FileStatusReady)
+	moved := createFileWithStatus(t, env, "moved.txt", 10, "text/plain", accessToken, file_version.FileStatusReady)
+	deleted := createFileWithStatus(t, env, "deleted.txt", 10, "text/plain", accessToken, file_version.FileStatusReady)
+	notTrashed := createFileWithStatus(t, env, "active.txt", 10, "text/plain", accessToken, file_version.FileStatusReady)
+	foreign := createFileWithStatus(t, env, "foreign.txt", 10, "text/plain", otherToken, file_version.FileStatusReady)
+	folderID := createFolder(t, env, "docs", nil, accessToken)
+
+	results := batchResults(t, env, map[string]interface{}{
+		"operations": []map[string]interface{}{
+			{"op": "rename", "file_id": renamed.String(), "name": "new.txt"},
+			{"op": "move", "file_id": moved.String(), "folder_id": folderID},
+			{"op": "delete", "file_id": deleted.String()},
+			{"op": "restore", "file_id": notTrashed.String()},
+			{"op": "delete", "file_id": foreign.String()},
+			{"op": "delete", "file_id": uuid.NewString()},
+		},
+	}, accessToken)
+	require.Len(t, results, 6)
+	assert.Equal(t, true, results[0]["ok"])
+	assert.Equal(t, true, results[1]["ok"])
+	assert.Equal(t, true, results[2]["ok"])
+	assert.Equal(t, "FILE_NOT_IN_TRASH", batchErrorCode(results[3]))
+	assert.Equal(t, "ACCESS_DENIED", batchErrorCode(results[4]))
+	assert.Equal(t, float64(403), results[4]["error"].(map[string]interface{})["status"])
+	assert.Equal(t, "FILE_NOT_FOUND", batchErrorCode(results[5]))
+
+	ctx := context.Background()
+	f, err := env.FileService.GetByID(ctx, renamed)
+	require.NoError(t, err)
+	assert.Equal(t, "new.txt", f.Name.String())
+	f, err = env.FileService.GetByID(ctx, moved)
+	require.NoError(t, err)
+	require.NotNil(t, f.FolderID)
+	assert.Equal(t, folderID, f.FolderID.String())
+	_, err = env.FileService.GetTrashedByID(ctx, deleted)
+	require.NoError(t, err)
+	_, err = env.FileService.GetByID(ctx, foreign)
+	require.NoError(t, err)
+
+	// Восстановление из корзины и перенос в корень
+	results = batchResults(t, env, map[string]interface{}{
+		"operations": []map[string]interface{}{
+			{"op": "restore", "file_id": deleted.String()},
+			{"op": "move", "file_id": moved.String(), "folder_id": "root"},
+		},
+	}, accessToken)
+	assert.Equal(t, true, results[0]["ok"])
+	assert.Equal(t, true, results[1]["ok"])
+	f, err = env.FileService.GetByID(ctx, moved)
+	require.NoError(t, err)
+	assert.Nil(t, f.FolderID)
+}
+
+func TestBatch_AtomicRollsBackOnFailure(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+	otherToken := createUserAndLogin(t, env, "other@mail.ru", "other")
+	first := createFileWithStatus(t, env, "first.txt", 10, "text/plain", accessToken, file_version.FileStatusReady)
+	second := createFileWithStatus(t, env, "second.txt", 10, "text/plain", accessToken, file_version.FileStatusReady)
+	otherFolder := createFolder(t, env, "theirs", nil, otherToken)
+
+	results := batchResults(t, env, map[string]interface{}{
+		"atomic": true,
+		"operations": []map[string]interface{}{
+			{"op": "rename", "file_id": first.String(), "name": "renamed.txt"},
+			{"op": "move", "file_id": second.String(), "folder_id": otherFolder},
+		},
+	}, accessToken)
+	require.Len(t, results, 2)
+	assert.Equal(t, "BATCH_ABORTED", batchErrorCode(results[0]))
+	assert.Equal(t, "ACCESS_DENIED", batchErrorCode(results[1]))
+
+	f, err := env.FileService.GetByID(context.Background(), first)
+	require.NoError(t, err)
+	assert.Equal(t, "first.txt", f.Name.String())
+
+	results = batchResults(t, env, map[string]interface{}{
+		"atomic": true,
+		"operations": []map[string]interface{}{
+			{"op": "rename", "file_id": first.String(), "name": "renamed.txt"},
+			{"op": "delete", "file_id": second.String()},
+		},
+	}, accessToken)
+	assert.Equal(t, true, results[0]["ok"])
+	assert.Equal(t, true, results[1]["ok"])
+	f, err = env.FileService.GetByID(context.Background(), first)
+	require.NoError(t, err)
+	assert.Equal(t, "renamed.txt", f.Name.String())
+}
+
+func TestBatch_InvalidInput(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+	fileID := uuid.NewString()
+
+	cases := []map[string]interface{}{
+		{"operations": []map[string]interface{}{}},
+		{"operations": []map[string]interface{}{{"op": "tag", "file_id": fileID}}},
+		{"operations": []map[string]interface{}{{"op": "rename", "file_id": fileID}}},
+		{"operations": []map[string]interface{}{{"op": "delete", "file_id": "not-a-uuid"}}},
+		{"operations": []map[string]interface{}{
+			{"op": "delete", "file_id": fileID},
+			{"op": "restore", "file_id": fileID},
+		}},
+	}
+	for _, body := range cases {
+		w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/batch", body, accessToken)
+		assert.Equal(t, 400, w.Code, w.Body.String())
+	}
+}
+
+// fileTags метки из GET /files/{id}
+func fileTags(t *testing.T, env *TestEnv, fileID uuid.UUID, accessToken string) []interface{} {
+	w := env.NewRequestWithAuth(t, "GET", "/api/v1/files/"+fileID.String(), nil, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	tags, ok := ParseJSONResponse(t, w)["tags"].([]interface{})
+	require.True(t, ok, "tags not found")
+	return tags
+}
+
+func TestBatch_TagsFiles(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+	viewerToken := createUserAndLogin(t, env, "viewer@mail.ru", "viewer")
+	first := createFileWithStatus(t, env, "first.txt", 10, "text/plain", accessToken, file_version.FileStatusReady)
+	second := createFileWithStatus(t, env, "second.txt", 10, "text/plain", accessToken, file_version.FileStatusReady)
+	shareFile(t, env, second, "viewer@mail.ru", "viewer", accessToken)
+
+	assert.Empty(t, fileTags(t, env, first, accessToken))
+
+	results := batchResults(t, env, map[string]interface{}{
+		"operations": []map[string]interface{}{
+			{"op": "tag", "file_id": first.String(), "add_tags": []string{"Work", " q3 ", "work", "draft"}},
+			{"op": "tag", "file_id": second.String(), "add_tags": []string{""}},
+		},
+	}, accessToken)
+	require.Len(t, results, 2)
+	assert.Equal(t, true, results[0]["ok"])
+	assert.Equal(t, "INVALID_TAG", batchErrorCode(results[1]))
+	assert.Equal(t, []interface{}{"draft", "q3", "work"}, fileTags(t, env, first, accessToken))
+
+	// Повторная метка не дублируется, снятие отсутствующей ничего не ломает
+	results = batchResults(t, env, map[string]interface{}{
+		"operations": []map[string]interface{}{
+			{"op": "tag", "file_id": first.String(), "add_tags": []string{"WORK"}, "remove_tags": []string{"draft", "missing"}},
+		},
+	}, accessToken)
+	assert.Equal(t, true, results[0]["ok"])
+	assert.Equal(t, []interface{}{"q3", "work"}, fileTags(t, env, first, accessToken))
+
+	// Ставить метки может editor, viewer — нет
+	results = batchResults(t, env, map[string]interface{}{
+		"operations": []map[string]interface{}{
+			{"op": "tag", "file_id": second.String(), "add_tags": []string{"work"}},
+		},
+	}, viewerToken)
+	assert.Equal(t, "ACCESS_DENIED", batchErrorCode(results[0]))
+
+	// В атомарном пакете метки откатываются вместе с остальными операциями
+	results = batchResults(t, env, map[string]interface{}{
+		"atomic": true,
+		"operations": []map[string]interface{}{
+			{"op": "tag", "file_id": first.String(), "add_tags": []string{"archived"}},
+			{"op": "restore", "file_id": second.String()},
+		},
+	}, accessToken)
+	assert.Equal(t, "BATCH_ABORTED", batchErrorCode(results[0]))
+	assert.Equal(t, []interface{}{"q3", "work"}, fileTags(t, env, first, accessToken))
+}
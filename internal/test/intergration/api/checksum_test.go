@@ -0,0 +1,135 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"hash/crc32"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+)
+
+// uploadWithHeaders выполняет PUT по presigned URL с заголовками из upload_headers
+func uploadWithHeaders(t *testing.T, presignedURL string, headers map[string]interface{}, data []byte) int {
+	req, err := http.NewRequest("PUT", presignedURL, bytes.NewReader(data))
+	require.NoError(t, err)
+	for name, value := range headers {
+		req.Header.Set(name, value.(string))
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestChecksum_VerifiedOnCompletion(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+
+	content := []byte("checksum verified content")
+	sum := sha256.Sum256(content)
+	checksum := base64.StdEncoding.EncodeToString(sum[:])
+
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files", map[string]interface{}{
+		"name":               "doc.txt",
+		"size":               len(content),
+		"mime":               "text/plain",
+		"checksum_algorithm": "sha256",
+		"checksum":           checksum,
+	}, accessToken)
+	require.Equal(t, 201, w.Code, w.Body.String())
+	response := ParseJSONResponse(t, w)
+	fileID := uuid.MustParse(response["file_id"].(string))
+
+	headers, ok := response["upload_headers"].(map[string]interface{})
+	require.True(t, ok, "upload_headers not found")
+	assert.Equal(t, checksum, headers["x-amz-checksum-sha256"])
+
+	// S3 отклоняет PUT, если содержимое не совпадает с подписанным заголовком
+	assert.NotEqual(t, 200, uploadWithHeaders(t, response["upload_url"].(string), headers, []byte("tampered content here....")))
+	require.Equal(t, 200, uploadWithHeaders(t, response["upload_url"].(string), headers, content))
+
+	version := currentVersion(t, env, fileID)
+	require.NoError(t, env.VersionService.CompleteUpload(context.Background(), version.ID))
+	assert.Equal(t, file_version.FileStatusUploaded, currentVersion(t, env, fileID).Status)
+
+	w = env.NewRequestWithAuth(t, "GET", "/api/v1/files/"+fileID.String(), nil, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	file := ParseJSONResponse(t, w)
+	assert.Equal(t, map[string]interface{}{"algorithm": "sha256", "value": checksum}, file["checksum"])
+}
+
+func TestChecksum_MismatchMarksVersionFailed(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+
+	declared := []byte("declared content")
+	checksum := base64.StdEncoding.EncodeToString(binary.BigEndian.AppendUint32(nil, crc32.Checksum(declared, crc32.MakeTable(crc32.Castagnoli))))
+
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files", map[string]interface{}{
+		"name":               "doc.txt",
+		"size":               len(declared),
+		"mime":               "text/plain",
+		"checksum_algorithm": "crc32c",
+		"checksum":           checksum,
+	}, accessToken)
+	require.Equal(t, 201, w.Code, w.Body.String())
+	fileID := uuid.MustParse(ParseJSONResponse(t, w)["file_id"].(string))
+
+	// Объект записан в обход подписанного URL, поэтому расхождение ловит только CompleteUpload
+	version := currentVersion(t, env, fileID)
+	other := []byte("different bytes!")
+	_, err := env.S3.GetClient().PutObject(context.Background(), env.S3.GetBucket(), version.S3Key.String(),
+		bytes.NewReader(other), int64(len(other)), minio.PutObjectOptions{})
+	require.NoError(t, err)
+
+	err = env.VersionService.CompleteUpload(context.Background(), version.ID)
+	require.ErrorIs(t, err, file_version.ErrChecksumMismatch)
+
+	version = currentVersion(t, env, fileID)
+	assert.Equal(t, file_version.FileStatusFailed, version.Status)
+	require.NotNil(t, version.FailureReason)
+	assert.Contains(t, *version.FailureReason, "crc32c mismatch")
+	assert.Nil(t, version.ContentHash)
+
+	w = env.NewRequestWithAuth(t, "GET", "/api/v1/files/"+fileID.String()+"/versions", nil, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "failure_reason")
+}
+
+func TestChecksum_InvalidChecksum(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+
+	for _, body := range []map[string]interface{}{
+		{"checksum_algorithm": "md5", "checksum": "1B2M2Y8AsgTpgAmY7PhCfg=="},
+		{"checksum_algorithm": "sha256", "checksum": "not-base64"},
+		{"checksum_algorithm": "crc32c", "checksum": base64.StdEncoding.EncodeToString(make([]byte, 32))},
+		{"checksum": "AAAAAA=="},
+	} {
+		body["name"] = "doc.txt"
+		body["size"] = 16
+		body["mime"] = "text/plain"
+		w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files", body, accessToken)
+		assert.Equal(t, 400, w.Code, w.Body.String())
+	}
+}
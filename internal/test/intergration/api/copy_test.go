@@ -0,0 +1,70 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+)
+
+func TestCopyFile_CopiesObjectAndSharesPreview(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+	sourceID := createFileAndUpload(t, env, "report.pdf", 1024, "application/pdf", accessToken, file_version.FileStatusReady)
+	source := currentVersion(t, env, sourceID)
+
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/"+sourceID.String()+"/copy", map[string]interface{}{
+		"name": "report (copy).pdf",
+	}, accessToken)
+	require.Equal(t, 201, w.Code, w.Body.String())
+	response := ParseJSONResponse(t, w)
+	copyID := uuid.MustParse(response["id"].(string))
+	assert.NotEqual(t, sourceID, copyID)
+	assert.Equal(t, "report (copy).pdf", response["name"])
+	assert.Equal(t, "ready", response["status"])
+	assert.Equal(t, float64(1), response["version_num"])
+
+	copied := currentVersion(t, env, copyID)
+	assert.NotEqual(t, source.S3Key, copied.S3Key)
+	assert.True(t, objectExists(t, env, copied.S3Key.String()))
+	require.NotNil(t, source.PreviewS3Key)
+	assert.Equal(t, source.PreviewS3Key, copied.PreviewS3Key)
+	assert.Equal(t, float64(2048), getUsage(t, env, accessToken)["used_bytes"])
+
+	// Удаление копии не трогает объект и превью источника
+	w = env.NewRequestWithAuth(t, "DELETE", "/api/v1/files/"+copyID.String(), nil, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	w = env.NewRequestWithAuth(t, "DELETE", "/api/v1/files/"+copyID.String()+"/permanent", nil, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.False(t, objectExists(t, env, copied.S3Key.String()))
+	assert.True(t, objectExists(t, env, source.S3Key.String()))
+	assert.Equal(t, float64(1024), getUsage(t, env, accessToken)["used_bytes"])
+}
+
+func TestCopyFile_Errors(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+	otherToken := createUserAndLogin(t, env, "other@mail.ru", "other")
+	processingID := createFile(t, env, "pending.pdf", 1024, "application/pdf", accessToken)
+
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/"+processingID.String()+"/copy", map[string]interface{}{}, accessToken)
+	assert.Equal(t, 409, w.Code, w.Body.String())
+
+	w = env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/"+processingID.String()+"/copy", map[string]interface{}{
+		"version_num": 7,
+	}, accessToken)
+	assert.Equal(t, 404, w.Code, w.Body.String())
+
+	w = env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/"+processingID.String()+"/copy", map[string]interface{}{}, otherToken)
+	assert.Equal(t, 403, w.Code, w.Body.String())
+
+	w = env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/"+uuid.NewString()+"/copy", map[string]interface{}{}, accessToken)
+	assert.Equal(t, 404, w.Code, w.Body.String())
+}
@@ -0,0 +1,68 @@
+package api_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+)
+
+func currentVersion(t *testing.T, env *TestEnv, fileID uuid.UUID) *file_version.FileVersion {
+	versions, err := env.VersionService.GetVersionsByFileID(context.Background(), fileID)
+	require.NoError(t, err)
+	require.NotEmpty(t, versions)
+	return versions[0]
+}
+
+func objectExists(t *testing.T, env *TestEnv, key string) bool {
+	_, err := env.S3.GetClient().StatObject(context.Background(), env.S3.GetBucket(), key, minio.StatObjectOptions{})
+	return err == nil
+}
+
+func TestDedup_SameContentSharesBlob(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	alice := createUserAndLogin(t, env, "alice@mail.ru", "alice")
+	bob := createUserAndLogin(t, env, "bob@mail.ru", "bob")
+
+	first := createFileAndUpload(t, env, "installer.exe", 2048, "application/octet-stream", alice, file_version.FileStatusReady)
+	second := createFileAndUpload(t, env, "setup.exe", 2048, "application/octet-stream", bob, file_version.FileStatusReady)
+
+	v1 := currentVersion(t, env, first)
+	v2 := currentVersion(t, env, second)
+	require.NotNil(t, v1.ContentHash)
+	require.NotNil(t, v2.ContentHash)
+	assert.Equal(t, *v1.ContentHash, *v2.ContentHash)
+	assert.Equal(t, v1.S3Key, v2.S3Key, "duplicate content must point at the existing object")
+
+	// Удаление одного из файлов не трогает объект, пока на него ссылается второй
+	require.NoError(t, env.FileService.Purge(context.Background(), first))
+	assert.True(t, objectExists(t, env, v2.S3Key.String()))
+
+	w := env.NewRequestWithAuth(t, "GET", fmt.Sprintf("/api/v1/files/%s/versions/1/content", second), nil, bob)
+	assert.Equal(t, 200, w.Code, w.Body.String())
+
+	require.NoError(t, env.FileService.Purge(context.Background(), second))
+	assert.False(t, objectExists(t, env, v2.S3Key.String()))
+}
+
+func TestDedup_DifferentContentKeepsOwnObject(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+
+	first := createFileAndUpload(t, env, "a.bin", 1024, "application/octet-stream", accessToken, file_version.FileStatusReady)
+	second := createFileAndUpload(t, env, "b.bin", 2048, "application/octet-stream", accessToken, file_version.FileStatusReady)
+
+	v1 := currentVersion(t, env, first)
+	v2 := currentVersion(t, env, second)
+	assert.NotEqual(t, *v1.ContentHash, *v2.ContentHash)
+	assert.NotEqual(t, v1.S3Key, v2.S3Key)
+}
@@ -0,0 +1,150 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+)
+
+func createDropLink(t *testing.T, env *TestEnv, body map[string]interface{}, accessToken string) map[string]interface{} {
+	body["expires_in"] = "1h"
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/drop-links", body, accessToken)
+	require.Equal(t, 201, w.Code, w.Body.String())
+	return ParseJSONResponse(t, w)
+}
+
+// dropFile загружает метаданные файла по ссылке без авторизации
+func dropFile(t *testing.T, env *TestEnv, token, name string, size uint64, mime string) (int, map[string]interface{}) {
+	w := env.NewJSONRequest(t, "POST", "/api/v1/public-drop-links/"+token+"/files", map[string]interface{}{
+		"name": name,
+		"size": size,
+		"mime": mime,
+	})
+	return w.Code, ParseJSONResponse(t, w)
+}
+
+func TestDropLinks_UploadIntoFolder(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "owner@mail.ru", "owner")
+	folderID := createFolder(t, env, "Inbox", nil, accessToken)
+	link := createDropLink(t, env, map[string]interface{}{
+		"folder_id":     folderID,
+		"max_files":     2,
+		"allowed_mimes": []string{"application/pdf", "image/*"},
+	}, accessToken)
+	token := link["token"].(string)
+	assert.Equal(t, folderID, link["folder_id"])
+
+	w := env.NewRequest(t, "GET", "/api/v1/public-drop-links/"+token, nil)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	info := ParseJSONResponse(t, w)
+	assert.Equal(t, "folder", info["target"])
+	assert.Equal(t, float64(2), info["remaining_files"])
+
+	code, resp := dropFile(t, env, token, "notes.txt", 1024, "text/plain")
+	require.Equal(t, 415, code, resp)
+
+	code, resp = dropFile(t, env, token, "contract.pdf", 1024, "application/pdf")
+	require.Equal(t, 201, code, resp)
+	fileID, err := uuid.Parse(resp["file_id"].(string))
+	require.NoError(t, err)
+	require.NoError(t, uploadFileToS3(t, resp["upload_url"].(string), make([]byte, 1024)))
+	waitForFileStatus(t, env, fileID, accessToken, file_version.FileStatusReady)
+
+	// Файл принадлежит владельцу ссылки и лежит в её папке
+	w = env.NewRequestWithAuth(t, "GET", "/api/v1/files/"+fileID.String(), nil, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.Equal(t, folderID, ParseJSONResponse(t, w)["folder_id"])
+
+	v := currentVersion(t, env, fileID)
+	require.NotNil(t, v.DropLinkID)
+	assert.Equal(t, link["id"], v.DropLinkID.String())
+	assert.Equal(t, 1, countRows(t, env, `SELECT COUNT(*) FROM events WHERE name = 'DropLinkFileReceived'`))
+
+	code, resp = dropFile(t, env, token, "photo.png", 1024, "image/png")
+	require.Equal(t, 201, code, resp)
+
+	code, resp = dropFile(t, env, token, "third.pdf", 1024, "application/pdf")
+	assert.Equal(t, 403, code, resp)
+	assert.Equal(t, "DROP_LINK_LIMIT_REACHED", resp["code"])
+
+	code, _ = dropFile(t, env, "missing-token", "a.pdf", 1024, "application/pdf")
+	assert.Equal(t, 404, code)
+}
+
+func TestDropLinks_UploadNewVersions(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "owner@mail.ru", "owner")
+	fileID := createFileAndUpload(t, env, "report.pdf", 1024, "application/pdf", accessToken, file_version.FileStatusReady)
+	link := createDropLink(t, env, map[string]interface{}{
+		"file_id":        fileID.String(),
+		"max_total_size": 3000,
+	}, accessToken)
+	token := link["token"].(string)
+
+	code, resp := dropFile(t, env, token, "report.pdf", 2048, "application/pdf")
+	require.Equal(t, 201, code, resp)
+	assert.Equal(t, fileID.String(), resp["file_id"])
+	assert.Equal(t, float64(2), resp["version_num"])
+
+	// Вторая загрузка превысила бы суммарный размер
+	code, resp = dropFile(t, env, token, "report.pdf", 2048, "application/pdf")
+	assert.Equal(t, 403, code, resp)
+
+	w := env.NewRequestWithAuth(t, "DELETE", "/api/v1/drop-links/"+link["id"].(string), nil, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+
+	code, _ = dropFile(t, env, token, "report.pdf", 10, "application/pdf")
+	assert.Equal(t, 404, code)
+}
+
+func TestDropLinks_Validation(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	accessToken := createUserAndLogin(t, env, "owner@mail.ru", "owner")
+	otherToken := createUserAndLogin(t, env, "other@mail.ru", "other")
+	fileID := createFileWithStatus(t, env, "doc.pdf", 1024, "application/pdf", accessToken, file_version.FileStatusReady)
+	folderID := createFolder(t, env, "Inbox", nil, accessToken)
+
+	cases := []struct {
+		name  string
+		body  map[string]interface{}
+		token string
+		code  int
+	}{
+		{"folder and file", map[string]interface{}{"folder_id": folderID, "file_id": fileID.String()}, accessToken, 400},
+		{"zero max files", map[string]interface{}{"max_files": 0, "max_total_size": -1}, accessToken, 400},
+		{"bad mime", map[string]interface{}{"allowed_mimes": []string{"pdf"}}, accessToken, 400},
+		{"foreign file", map[string]interface{}{"file_id": fileID.String()}, otherToken, 403},
+		{"foreign folder", map[string]interface{}{"folder_id": folderID}, otherToken, 403},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.body["expires_in"] = "1h"
+			w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/drop-links", tc.body, tc.token)
+			assert.Equal(t, tc.code, w.Code, w.Body.String())
+		})
+	}
+
+	// Ссылки видит и удаляет только владелец
+	link := createDropLink(t, env, map[string]interface{}{}, accessToken)
+	w := env.NewRequestWithAuth(t, "GET", "/api/v1/drop-links", nil, otherToken)
+	require.Equal(t, 200, w.Code)
+	assert.Equal(t, float64(0), ParseJSONResponse(t, w)["total"])
+
+	w = env.NewRequestWithAuth(t, "DELETE", "/api/v1/drop-links/"+link["id"].(string), nil, otherToken)
+	assert.Equal(t, 404, w.Code, w.Body.String())
+
+	w = env.NewRequestWithAuth(t, "GET", "/api/v1/drop-links", nil, accessToken)
+	require.Equal(t, 200, w.Code)
+	assert.Equal(t, float64(1), ParseJSONResponse(t, w)["total"])
+}
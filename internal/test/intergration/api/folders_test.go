@@ -0,0 +1,132 @@
+package api_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+)
+
+func createFolder(t *testing.T, env *TestEnv, name string, parentID *string, accessToken string) string {
+	body := map[string]interface{}{
+		"name":      name,
+		"parent_id": parentID,
+	}
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/folders", body, accessToken)
+	require.Equal(t, 201, w.Code, "Failed to create folder: %s", w.Body.String())
+
+	response := ParseJSONResponse(t, w)
+	folderID, ok := response["id"].(string)
+	require.True(t, ok, "id not found in response")
+	return folderID
+}
+
+func TestFolders_Create_And_GetPath(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+
+	rootID := createFolder(t, env, "Documents", nil, accessToken)
+	childID := createFolder(t, env, "Reports", &rootID, accessToken)
+
+	w := env.NewRequestWithAuth(t, "GET", "/api/v1/folders/"+childID, nil, accessToken)
+	require.Equal(t, 200, w.Code)
+
+	response := ParseJSONResponse(t, w)
+	assert.Equal(t, "Reports", response["name"])
+	assert.Equal(t, rootID, response["parent_id"])
+
+	path, ok := response["path"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, path, 2)
+	assert.Equal(t, "Documents", path[0].(map[string]interface{})["name"])
+	assert.Equal(t, "Reports", path[1].(map[string]interface{})["name"])
+}
+
+func TestFolders_Create_NameConflict(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+
+	createFolder(t, env, "Documents", nil, accessToken)
+
+	body := map[string]interface{}{"name": "Documents"}
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/folders", body, accessToken)
+	assert.Equal(t, 409, w.Code)
+}
+
+func TestFolders_Move_IntoDescendant(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+
+	rootID := createFolder(t, env, "A", nil, accessToken)
+	childID := createFolder(t, env, "B", &rootID, accessToken)
+
+	body := map[string]interface{}{"parent_id": childID}
+	w := env.NewJSONRequestWithAuth(t, "POST", fmt.Sprintf("/api/v1/folders/%s/move", rootID), body, accessToken)
+	assert.Equal(t, 400, w.Code)
+}
+
+func TestFolders_ListFiles_ScopedToFolder(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+
+	folderID := createFolder(t, env, "Photos", nil, accessToken)
+
+	body := map[string]interface{}{
+		"name":      "photo.jpg",
+		"size":      1024,
+		"mime":      "image/jpeg",
+		"folder_id": folderID,
+	}
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files", body, accessToken)
+	require.Equal(t, 201, w.Code, w.Body.String())
+
+	createFile(t, env, "root.txt", 100, "text/plain", accessToken)
+
+	w = env.NewRequestWithAuth(t, "GET", "/api/v1/files?folder_id="+folderID, nil, accessToken)
+	require.Equal(t, 200, w.Code)
+	files := ParseJSONResponse(t, w)["files"].([]interface{})
+	require.Len(t, files, 1)
+	assert.Equal(t, "photo.jpg", files[0].(map[string]interface{})["name"])
+	assert.Equal(t, folderID, files[0].(map[string]interface{})["folder_id"])
+
+	w = env.NewRequestWithAuth(t, "GET", "/api/v1/files?folder_id=root", nil, accessToken)
+	require.Equal(t, 200, w.Code)
+	files = ParseJSONResponse(t, w)["files"].([]interface{})
+	require.Len(t, files, 1)
+	assert.Equal(t, "root.txt", files[0].(map[string]interface{})["name"])
+}
+
+func TestFolders_Delete_CascadesFiles(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+
+	rootID := createFolder(t, env, "Projects", nil, accessToken)
+	childID := createFolder(t, env, "Old", &rootID, accessToken)
+
+	fileID := createFileWithStatus(t, env, "notes.txt", 100, "text/plain", accessToken, file_version.FileStatusReady)
+
+	body := map[string]interface{}{"folder_id": childID}
+	w := env.NewJSONRequestWithAuth(t, "POST", fmt.Sprintf("/api/v1/files/%s/move", fileID), body, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+
+	w = env.NewRequestWithAuth(t, "DELETE", "/api/v1/folders/"+rootID, nil, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+
+	w = env.NewRequestWithAuth(t, "GET", "/api/v1/folders/"+childID, nil, accessToken)
+	assert.Equal(t, 404, w.Code)
+
+	w = env.NewRequestWithAuth(t, "GET", "/api/v1/files/"+fileID.String(), nil, accessToken)
+	assert.Equal(t, 404, w.Code)
+}
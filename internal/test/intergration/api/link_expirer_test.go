@@ -0,0 +1,80 @@
+package api_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+)
+
+// expireLink переносит срок действия ссылки в прошлое
+func expireLink(t *testing.T, env *TestEnv, token string, ago time.Duration) {
+	_, err := env.DB.DB.ExecContext(context.Background(),
+		`UPDATE public_links SET expired_at = $1 WHERE token_hash = $2`, time.Now().Add(-ago), tokenHash(t, token))
+	require.NoError(t, err)
+}
+
+func countRows(t *testing.T, env *TestEnv, query string, args ...any) int {
+	var n int
+	require.NoError(t, env.DB.DB.QueryRowContext(context.Background(), query, args...).Scan(&n))
+	return n
+}
+
+func TestLinkExpirer_DeletesExpiredLinks(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+	ctx := context.Background()
+
+	accessToken := createUserAndLogin(t, env, "owner@mail.ru", "owner")
+	fileID := createFileWithStatus(t, env, "doc.pdf", 1024, "application/pdf", accessToken, file_version.FileStatusReady)
+	expired := createPublicLink(t, env, fileID, "", accessToken)
+	recent := createPublicLink(t, env, fileID, "", accessToken)
+	active := createPublicLink(t, env, fileID, "", accessToken)
+	expireLink(t, env, expired, 48*time.Hour)
+	expireLink(t, env, recent, time.Minute)
+
+	// Ссылки, истёкшие позже before, остаются до следующего прохода
+	n, err := env.PublicLinkService.ExpireBatch(ctx, time.Now().Add(-time.Hour), 10, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	assert.Equal(t, 0, countRows(t, env, `SELECT COUNT(*) FROM public_links WHERE token_hash = $1`, tokenHash(t, expired)))
+	assert.Equal(t, 1, countRows(t, env, `SELECT COUNT(*) FROM public_links WHERE token_hash = $1`, tokenHash(t, recent)))
+	assert.Equal(t, 1, countRows(t, env, `SELECT COUNT(*) FROM public_links WHERE token_hash = $1`, tokenHash(t, active)))
+	assert.Equal(t, 1, countRows(t, env, `SELECT COUNT(*) FROM events WHERE name = 'PublicLinkExpired'`))
+	assert.Equal(t, 0, countRows(t, env, `SELECT COUNT(*) FROM public_links_archive`))
+
+	n, err = env.PublicLinkService.ExpireBatch(ctx, time.Now().Add(-time.Hour), 10, false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestLinkExpirer_ArchivesInBatches(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+	ctx := context.Background()
+
+	accessToken := createUserAndLogin(t, env, "owner@mail.ru", "owner")
+	fileID := createFileWithStatus(t, env, "doc.pdf", 1024, "application/pdf", accessToken, file_version.FileStatusReady)
+	for i := 0; i < 3; i++ {
+		token := createPublicLink(t, env, fileID, "", accessToken)
+		expireLink(t, env, token, time.Hour)
+	}
+
+	n, err := env.PublicLinkService.ExpireBatch(ctx, time.Now(), 2, true)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	n, err = env.PublicLinkService.ExpireBatch(ctx, time.Now(), 2, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	assert.Equal(t, 0, countRows(t, env, `SELECT COUNT(*) FROM public_links`))
+	assert.Equal(t, 3, countRows(t, env, `SELECT COUNT(*) FROM public_links_archive WHERE file_id = $1`, fileID))
+	assert.Equal(t, 3, countRows(t, env, `SELECT COUNT(*) FROM events WHERE name = 'PublicLinkExpired'`))
+}
@@ -0,0 +1,154 @@
+package api_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func initiateMultipartUpload(t *testing.T, env *TestEnv, fileID string, accessToken string) map[string]interface{} {
+	w := env.NewRequestWithAuth(t, "POST", fmt.Sprintf("/api/v1/files/%s/versions/1/uploads", fileID), nil, accessToken)
+	require.Equal(t, 201, w.Code, "Failed to initiate multipart upload: %s", w.Body.String())
+	return ParseJSONResponse(t, w)
+}
+
+func uploadPartToS3(t *testing.T, presignedURL string, data []byte) string {
+	req, err := http.NewRequest("PUT", presignedURL, bytes.NewReader(data))
+	require.NoError(t, err)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, 200, resp.StatusCode)
+	return resp.Header.Get("ETag")
+}
+
+func TestMultipart_LargeFile_Initiate(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+
+	body := map[string]interface{}{
+		"name":      "huge.bin",
+		"size":      uint64(6 * 1024 * 1024 * 1024),
+		"mime":      "application/octet-stream",
+		"multipart": true,
+	}
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files", body, accessToken)
+	require.Equal(t, 201, w.Code, w.Body.String())
+	fileID := ParseJSONResponse(t, w)["file_id"].(string)
+
+	upload := initiateMultipartUpload(t, env, fileID, accessToken)
+	assert.Equal(t, "initiated", upload["status"])
+	assert.Equal(t, float64(384), upload["part_count"])
+
+	// Повторный вызов возвращает ту же активную загрузку
+	again := initiateMultipartUpload(t, env, fileID, accessToken)
+	assert.Equal(t, upload["id"], again["id"])
+
+	partsURL := fmt.Sprintf("/api/v1/files/%s/versions/1/uploads/%s/parts", fileID, upload["id"])
+	w = env.NewJSONRequestWithAuth(t, "POST", partsURL, map[string]interface{}{"part_numbers": []int{1, 384}}, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	parts := ParseJSONResponse(t, w)["parts"].([]interface{})
+	assert.Len(t, parts, 2)
+
+	w = env.NewJSONRequestWithAuth(t, "POST", partsURL, map[string]interface{}{"part_numbers": []int{385}}, accessToken)
+	assert.Equal(t, 400, w.Code)
+}
+
+func TestMultipart_Complete_Success(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+	fileID := createFile(t, env, "small.bin", 1024, "application/octet-stream", accessToken).String()
+
+	upload := initiateMultipartUpload(t, env, fileID, accessToken)
+	require.Equal(t, float64(1), upload["part_count"])
+	base := fmt.Sprintf("/api/v1/files/%s/versions/1/uploads/%s", fileID, upload["id"])
+
+	w := env.NewJSONRequestWithAuth(t, "POST", base+"/parts", map[string]interface{}{"part_numbers": []int{1}}, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	part := ParseJSONResponse(t, w)["parts"].([]interface{})[0].(map[string]interface{})
+
+	etag := uploadPartToS3(t, part["url"].(string), make([]byte, 1024))
+	require.NotEmpty(t, etag)
+
+	body := map[string]interface{}{
+		"parts": []map[string]interface{}{{"part_number": 1, "etag": etag}},
+	}
+	w = env.NewJSONRequestWithAuth(t, "POST", base+"/complete", body, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.Equal(t, "completed", ParseJSONResponse(t, w)["status"])
+
+	w = env.NewRequestWithAuth(t, "GET", "/api/v1/files/"+fileID, nil, accessToken)
+	require.Equal(t, 200, w.Code)
+	assert.NotEqual(t, "processing", ParseJSONResponse(t, w)["status"])
+}
+
+func TestMultipart_Complete_MissingParts(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+
+	body := map[string]interface{}{
+		"name":      "big.bin",
+		"size":      uint64(64 * 1024 * 1024),
+		"mime":      "application/octet-stream",
+		"multipart": true,
+	}
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files", body, accessToken)
+	require.Equal(t, 201, w.Code)
+	fileID := ParseJSONResponse(t, w)["file_id"].(string)
+
+	upload := initiateMultipartUpload(t, env, fileID, accessToken)
+	base := fmt.Sprintf("/api/v1/files/%s/versions/1/uploads/%s", fileID, upload["id"])
+
+	complete := map[string]interface{}{
+		"parts": []map[string]interface{}{{"part_number": 1, "etag": "\"abc\""}},
+	}
+	w = env.NewJSONRequestWithAuth(t, "POST", base+"/complete", complete, accessToken)
+	assert.Equal(t, 400, w.Code)
+}
+
+func TestMultipart_Abort(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+	fileID := createFile(t, env, "abort.bin", 1024, "application/octet-stream", accessToken).String()
+
+	upload := initiateMultipartUpload(t, env, fileID, accessToken)
+	base := fmt.Sprintf("/api/v1/files/%s/versions/1/uploads/%s", fileID, upload["id"])
+
+	w := env.NewRequestWithAuth(t, "DELETE", base, nil, accessToken)
+	require.Equal(t, 204, w.Code)
+
+	w = env.NewJSONRequestWithAuth(t, "POST", base+"/parts", map[string]interface{}{"part_numbers": []int{1}}, accessToken)
+	assert.Equal(t, 409, w.Code)
+
+	// После отмены можно начать новую загрузку
+	again := initiateMultipartUpload(t, env, fileID, accessToken)
+	assert.NotEqual(t, upload["id"], again["id"])
+}
+
+func TestMultipart_AccessDenied(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	ownerToken := createUserAndLogin(t, env, "owner@mail.ru", "owner")
+	otherToken := createUserAndLogin(t, env, "other@mail.ru", "other")
+	fileID := createFile(t, env, "private.bin", 1024, "application/octet-stream", ownerToken).String()
+
+	w := env.NewRequestWithAuth(t, "POST", fmt.Sprintf("/api/v1/files/%s/versions/1/uploads", fileID), nil, otherToken)
+	assert.Equal(t, 403, w.Code)
+}
@@ -0,0 +1,72 @@
+package api_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+)
+
+func bucketNotification(eventName, key string) *bytes.Reader {
+	return bytes.NewReader([]byte(fmt.Sprintf(
+		`{"EventName":%q,"Records":[{"eventName":%q,"s3":{"object":{"key":%q,"size":1024}}}]}`,
+		eventName, eventName, url.QueryEscape(key),
+	)))
+}
+
+func TestBucketNotification_CompletesUpload(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	// Без FileChecker загрузку может завершить только уведомление
+	env.CancelWorkers()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files", map[string]interface{}{
+		"name": "report final.pdf",
+		"size": 1024,
+		"mime": "application/pdf",
+	}, accessToken)
+	require.Equal(t, 201, w.Code, w.Body.String())
+	response := ParseJSONResponse(t, w)
+	fileID := uuid.MustParse(response["file_id"].(string))
+	require.NoError(t, uploadFileToS3(t, response["upload_url"].(string), make([]byte, 1024)))
+
+	version := currentVersion(t, env, fileID)
+	require.Equal(t, file_version.FileStatusProcessing, version.Status)
+
+	// Чужие события и ключи игнорируются
+	w = env.NewRequestWithAuth(t, "POST", "/api/v1/storage/notifications", bucketNotification("s3:ObjectRemoved:Delete", version.S3Key.String()), testWebhookToken)
+	assert.Equal(t, 204, w.Code, w.Body.String())
+	w = env.NewRequestWithAuth(t, "POST", "/api/v1/storage/notifications", bucketNotification("s3:ObjectCreated:Put", "previews/unknown.png"), testWebhookToken)
+	assert.Equal(t, 204, w.Code, w.Body.String())
+	assert.Equal(t, file_version.FileStatusProcessing, currentVersion(t, env, fileID).Status)
+
+	w = env.NewRequestWithAuth(t, "POST", "/api/v1/storage/notifications", bucketNotification("s3:ObjectCreated:Put", version.S3Key.String()), testWebhookToken)
+	assert.Equal(t, 204, w.Code, w.Body.String())
+
+	version = currentVersion(t, env, fileID)
+	assert.Equal(t, file_version.FileStatusUploaded, version.Status)
+	assert.NotNil(t, version.ContentHash)
+
+	// Повторная доставка того же уведомления ничего не меняет
+	w = env.NewRequestWithAuth(t, "POST", "/api/v1/storage/notifications", bucketNotification("s3:ObjectCreated:Put", version.S3Key.String()), testWebhookToken)
+	assert.Equal(t, 204, w.Code, w.Body.String())
+}
+
+func TestBucketNotification_InvalidToken(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	w := env.NewRequestWithAuth(t, "POST", "/api/v1/storage/notifications", bucketNotification("s3:ObjectCreated:Put", "files/a"), "wrong-token")
+	assert.Equal(t, 401, w.Code)
+
+	w = env.NewRequestWithAuth(t, "POST", "/api/v1/storage/notifications", bytes.NewReader([]byte("not json")), testWebhookToken)
+	assert.Equal(t, 400, w.Code)
+
+}
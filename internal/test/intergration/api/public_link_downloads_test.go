@@ -0,0 +1,95 @@
+package api_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+)
+
+func TestPublicLinks_MaxDownloads(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "owner@mail.ru", "owner")
+	fileID := createFileAndUpload(t, env, "price.pdf", 1024, "application/pdf", accessToken, file_version.FileStatusReady)
+	base := "/api/v1/files/" + fileID.String() + "/public-links"
+
+	w := env.NewJSONRequestWithAuth(t, "POST", base, map[string]interface{}{
+		"expires_in":    "1h",
+		"max_downloads": 1,
+	}, accessToken)
+	require.Equal(t, 201, w.Code, w.Body.String())
+	link := ParseJSONResponse(t, w)
+	assert.Equal(t, float64(1), link["max_downloads"])
+	assert.Equal(t, float64(0), link["download_count"])
+	token := link["token"].(string)
+	linkID := link["id"].(string)
+
+	req := httptest.NewRequest("GET", "/api/v1/public-links/"+token, nil)
+	req.Header.Set("User-Agent", "pricing-client/1.0")
+	w = httptest.NewRecorder()
+	env.Server.ServeHTTP(w, req)
+	require.Equal(t, 200, w.Code, w.Body.String())
+
+	// Одноразовая ссылка после скачивания ведёт себя как истёкшая
+	w = env.NewRequest(t, "GET", "/api/v1/public-links/"+token, nil)
+	require.Equal(t, 404, w.Code, w.Body.String())
+
+	w = env.NewRequestWithAuth(t, "GET", base, nil, accessToken)
+	require.Equal(t, 200, w.Code)
+	assert.Equal(t, float64(0), ParseJSONResponse(t, w)["total"])
+
+	// Журнал доступен и у исчерпанной ссылки
+	w = env.NewRequestWithAuth(t, "GET", base+"/"+linkID+"/accesses", nil, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	response := ParseJSONResponse(t, w)
+	require.Equal(t, float64(1), response["total"])
+	access := response["accesses"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "pricing-client/1.0", access["user_agent"])
+	assert.NotEmpty(t, access["ip"])
+	assert.Equal(t, currentVersion(t, env, fileID).ID.String(), access["version_id"])
+}
+
+func TestPublicLinks_AccessLog(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	ownerToken := createUserAndLogin(t, env, "owner@mail.ru", "owner")
+	otherToken := createUserAndLogin(t, env, "other@mail.ru", "other")
+	fileID := createFileAndUpload(t, env, "open.pdf", 1024, "application/pdf", ownerToken, file_version.FileStatusReady)
+	base := "/api/v1/files/" + fileID.String() + "/public-links"
+
+	w := env.NewJSONRequestWithAuth(t, "POST", base, map[string]interface{}{
+		"expires_in":    "1h",
+		"max_downloads": 0,
+	}, ownerToken)
+	require.Equal(t, 400, w.Code, w.Body.String())
+
+	w = env.NewJSONRequestWithAuth(t, "POST", base, map[string]interface{}{"expires_in": "1h"}, ownerToken)
+	require.Equal(t, 201, w.Code, w.Body.String())
+	link := ParseJSONResponse(t, w)
+	assert.Nil(t, link["max_downloads"])
+	token := link["token"].(string)
+	linkID := link["id"].(string)
+
+	for i := 0; i < 3; i++ {
+		w = env.NewRequest(t, "GET", "/api/v1/public-links/"+token, nil)
+		require.Equal(t, 200, w.Code, w.Body.String())
+	}
+
+	w = env.NewRequestWithAuth(t, "GET", base, nil, ownerToken)
+	require.Equal(t, 200, w.Code)
+	links := ParseJSONResponse(t, w)["links"].([]interface{})
+	require.Len(t, links, 1)
+	assert.Equal(t, float64(3), links[0].(map[string]interface{})["download_count"])
+
+	w = env.NewRequestWithAuth(t, "GET", base+"/"+linkID+"/accesses", nil, ownerToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.Equal(t, float64(3), ParseJSONResponse(t, w)["total"])
+
+	w = env.NewRequestWithAuth(t, "GET", base+"/"+linkID+"/accesses", nil, otherToken)
+	assert.Equal(t, 403, w.Code)
+}
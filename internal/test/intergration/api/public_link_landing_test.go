@@ -0,0 +1,152 @@
+package api_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+)
+
+const browserAccept = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+
+// browserRequest запрос, как его шлёт браузер; body — поля HTML формы
+func browserRequest(t *testing.T, env *TestEnv, method, path string, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(form.Encode()))
+	req.Header.Set("Accept", browserAccept)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	w := httptest.NewRecorder()
+	env.Server.ServeHTTP(w, req)
+	return w
+}
+
+func TestPublicLinks_LandingPage(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	accessToken := createUserAndLogin(t, env, "owner@mail.ru", "owner")
+	fileID := createFileWithStatus(t, env, "q3 <report>.pdf", 2048, "application/pdf", accessToken, file_version.FileStatusReady)
+	token := createPublicLink(t, env, fileID, "", accessToken)
+
+	w := browserRequest(t, env, "GET", "/api/v1/public-links/"+token, nil)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	body := w.Body.String()
+	assert.Contains(t, body, `<meta property="og:title" content="q3 &lt;report&gt;.pdf">`)
+	assert.Contains(t, body, `<meta property="og:description" content="2.0 KB · application/pdf">`)
+	assert.Contains(t, body, `<meta property="og:url" content="http://example.com/api/v1/public-links/`+token+`">`)
+	assert.NotContains(t, body, "og:image")
+	assert.Contains(t, body, `href="/api/v1/public-links/`+token+`?download=1"`)
+
+	// Страница скачиванием не считается
+	assert.Equal(t, 0, countRows(t, env, `SELECT COUNT(*) FROM public_link_accesses`))
+
+	w = browserRequest(t, env, "GET", "/api/v1/public-links/"+token+"?download=1", nil)
+	require.Equal(t, 302, w.Code, w.Body.String())
+	assert.NotEmpty(t, w.Header().Get("Location"))
+	assert.Equal(t, 1, countRows(t, env, `SELECT COUNT(*) FROM public_link_accesses`))
+
+	// Клиенты API по-прежнему получают JSON
+	w = env.NewRequest(t, "GET", "/api/v1/public-links/"+token, nil)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.Equal(t, "q3 <report>.pdf", ParseJSONResponse(t, w)["file_name"])
+
+	w = browserRequest(t, env, "GET", "/api/v1/public-links/missing-token", nil)
+	assert.Equal(t, 404, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, w.Body.String(), "This link is not available")
+}
+
+func TestPublicLinks_LandingPagePreview(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	accessToken := createUserAndLogin(t, env, "owner@mail.ru", "owner")
+	fileID := createFileWithStatus(t, env, "photo.png", 4096, "image/png", accessToken, file_version.FileStatusReady)
+	token := createPublicLink(t, env, fileID, "", accessToken)
+
+	// Пока превью нет, страница без картинки
+	w := browserRequest(t, env, "GET", "/api/v1/public-links/"+token, nil)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.NotContains(t, w.Body.String(), "og:image")
+
+	previewKey, err := file_version.NewS3Key("previews/photo_preview.png")
+	require.NoError(t, err)
+	require.NoError(t, env.VersionService.UpdatePreview(context.Background(), currentVersion(t, env, fileID).ID, previewKey))
+
+	w = browserRequest(t, env, "GET", "/api/v1/public-links/"+token, nil)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	body := w.Body.String()
+	assert.Contains(t, body, `<meta property="og:image" content="`)
+	assert.Contains(t, body, "photo_preview.png")
+	assert.Contains(t, body, `<img class="preview"`)
+	assert.Contains(t, body, `summary_large_image`)
+}
+
+func TestPublicLinks_LandingPagePassword(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	accessToken := createUserAndLogin(t, env, "owner@mail.ru", "owner")
+	fileID := createFileWithStatus(t, env, "secret.pdf", 1024, "application/pdf", accessToken, file_version.FileStatusReady)
+	token := createPublicLink(t, env, fileID, "s3cret", accessToken)
+
+	// Имя файла не видно до ввода пароля
+	w := browserRequest(t, env, "GET", "/api/v1/public-links/"+token, nil)
+	require.Equal(t, 401, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), `action="/api/v1/public-links/unlock/`+token+`"`)
+	assert.NotContains(t, w.Body.String(), "secret.pdf")
+
+	w = browserRequest(t, env, "POST", "/api/v1/public-links/unlock/"+token, url.Values{"password": {"wrong"}})
+	require.Equal(t, 401, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "Wrong link password")
+
+	w = browserRequest(t, env, "POST", "/api/v1/public-links/unlock/"+token, url.Values{"password": {"s3cret"}})
+	require.Equal(t, 303, w.Code, w.Body.String())
+	location, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/public-links/"+token, location.Path)
+	unlockToken := location.Query().Get("unlock_token")
+	require.NotEmpty(t, unlockToken)
+
+	w = browserRequest(t, env, "GET", location.String(), nil)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "secret.pdf")
+	assert.Contains(t, w.Body.String(), "unlock_token="+url.QueryEscape(unlockToken))
+}
+
+func TestPublicLinks_InternalErrorsRendered(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	accessToken := createUserAndLogin(t, env, "owner@mail.ru", "owner")
+	fileID := createFileWithStatus(t, env, "doc.pdf", 1024, "application/pdf", accessToken, file_version.FileStatusReady)
+	token := createPublicLink(t, env, fileID, "", accessToken)
+
+	// Маршрут вне ErrorMiddleware: ошибка сервиса не должна превращаться в пустой 200
+	_, err := env.DB.DB.ExecContext(context.Background(),
+		`UPDATE file_versions SET status = 'processing' WHERE file_id = $1`, fileID)
+	require.NoError(t, err)
+
+	w := env.NewRequest(t, "GET", "/api/v1/public-links/"+token, nil)
+	require.Equal(t, 400, w.Code, w.Body.String())
+	assert.NotEmpty(t, ParseJSONResponse(t, w)["error"])
+
+	w = browserRequest(t, env, "GET", "/api/v1/public-links/"+token+"?download=1", nil)
+	require.Equal(t, 400, w.Code, w.Body.String())
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, w.Body.String(), "This link is not available")
+	assert.Equal(t, 0, countRows(t, env, `SELECT COUNT(*) FROM public_link_accesses`))
+}
@@ -0,0 +1,158 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	public_link_service "github.com/yourusername/cloud-file-storage/internal/app/public_link"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+)
+
+// createPublicLink создаёт ссылку и возвращает её токен
+func createPublicLink(t *testing.T, env *TestEnv, fileID uuid.UUID, password string, accessToken string) string {
+	body := map[string]interface{}{"expires_in": "1h"}
+	if password != "" {
+		body["password"] = password
+	}
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/"+fileID.String()+"/public-links", body, accessToken)
+	require.Equal(t, 201, w.Code, w.Body.String())
+
+	response := ParseJSONResponse(t, w)
+	assert.Equal(t, password != "", response["has_password"])
+	token, ok := response["token"].(string)
+	require.True(t, ok, "token not found in response")
+	return token
+}
+
+// unlockFrom отправляет пароль с адреса ip
+func unlockFrom(t *testing.T, env *TestEnv, token, password, ip string) *httptest.ResponseRecorder {
+	body, err := json.Marshal(map[string]string{"password": password})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("POST", "/api/v1/public-links/unlock/"+token, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = ip + ":4321"
+
+	w := httptest.NewRecorder()
+	env.Server.ServeHTTP(w, req)
+	return w
+}
+
+func TestPublicLinks_PasswordProtected(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "owner@mail.ru", "owner")
+	fileID := createFileAndUpload(t, env, "secret.pdf", 1024, "application/pdf", accessToken, file_version.FileStatusReady)
+	token := createPublicLink(t, env, fileID, "s3cret", accessToken)
+
+	// Без токена разблокировки — вызов с адресом разблокировки
+	w := env.NewRequest(t, "GET", "/api/v1/public-links/"+token, nil)
+	require.Equal(t, 401, w.Code, w.Body.String())
+	assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+	response := ParseJSONResponse(t, w)
+	assert.Equal(t, "PASSWORD_REQUIRED", response["code"])
+	assert.Equal(t, "/api/v1/public-links/unlock/"+token, response["unlock_url"])
+
+	w = env.NewRequest(t, "GET", "/api/v1/public-links/"+token+"?unlock_token=123.forged", nil)
+	require.Equal(t, 401, w.Code)
+	assert.Equal(t, "INVALID_UNLOCK_TOKEN", ParseJSONResponse(t, w)["code"])
+
+	w = unlockFrom(t, env, token, "wrong", "10.0.0.1")
+	require.Equal(t, 401, w.Code, w.Body.String())
+	assert.Equal(t, "WRONG_LINK_PASSWORD", ParseJSONResponse(t, w)["code"])
+
+	w = unlockFrom(t, env, token, "s3cret", "10.0.0.1")
+	require.Equal(t, 200, w.Code, w.Body.String())
+	unlockToken, ok := ParseJSONResponse(t, w)["unlock_token"].(string)
+	require.True(t, ok, "unlock_token not found in response")
+
+	req := httptest.NewRequest("GET", "/api/v1/public-links/"+token, nil)
+	req.Header.Set("X-Unlock-Token", unlockToken)
+	w = httptest.NewRecorder()
+	env.Server.ServeHTTP(w, req)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.NotEmpty(t, ParseJSONResponse(t, w)["download_url"])
+
+	w = env.NewRequest(t, "GET", "/api/v1/public-links/"+token+"?unlock_token="+unlockToken, nil)
+	require.Equal(t, 200, w.Code, w.Body.String())
+}
+
+func TestPublicLinks_UnlockTokenAcceptedByOtherNodes(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	accessToken := createUserAndLogin(t, env, "owner@mail.ru", "owner")
+	fileID := createFileWithStatus(t, env, "secret.pdf", 1024, "application/pdf", accessToken, file_version.FileStatusReady)
+	token := createPublicLink(t, env, fileID, "s3cret", accessToken)
+
+	w := unlockFrom(t, env, token, "s3cret", "10.0.0.1")
+	require.Equal(t, 200, w.Code, w.Body.String())
+	unlockToken := ParseJSONResponse(t, w)["unlock_token"].(string)
+
+	link, err := env.PublicLinkService.GetByToken(context.Background(), token)
+	require.NoError(t, err)
+
+	// Другой узел с тем же ключом принимает токен, с другим ключом — нет
+	otherNode, err := public_link_service.NewPublicLinkService(nil, nil, nil, nil, *env.UOW, testUnlockKey, time.Minute, testUnlockMaxAttempts, time.Minute)
+	require.NoError(t, err)
+	assert.NoError(t, otherNode.CheckUnlock(link, unlockToken))
+
+	foreignNode, err := public_link_service.NewPublicLinkService(nil, nil, nil, nil, *env.UOW, "other-unlock-key", time.Minute, testUnlockMaxAttempts, time.Minute)
+	require.NoError(t, err)
+	assert.Error(t, foreignNode.CheckUnlock(link, unlockToken))
+
+	// Без ключа сервис не создаётся, а не подписывает случайным ключом
+	_, err = public_link_service.NewPublicLinkService(nil, nil, nil, nil, *env.UOW, "", time.Minute, testUnlockMaxAttempts, time.Minute)
+	require.Error(t, err)
+}
+
+func TestPublicLinks_PasswordLockout(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "owner@mail.ru", "owner")
+	fileID := createFileAndUpload(t, env, "secret.pdf", 1024, "application/pdf", accessToken, file_version.FileStatusReady)
+	token := createPublicLink(t, env, fileID, "s3cret", accessToken)
+
+	for i := 0; i < testUnlockMaxAttempts; i++ {
+		w := unlockFrom(t, env, token, "wrong", "10.0.0.1")
+		require.Equal(t, 401, w.Code, w.Body.String())
+	}
+
+	// После блокировки с этого адреса не проходит даже верный пароль
+	w := unlockFrom(t, env, token, "s3cret", "10.0.0.1")
+	require.Equal(t, 429, w.Code, w.Body.String())
+	assert.Equal(t, "LINK_LOCKED", ParseJSONResponse(t, w)["code"])
+
+	// Другой адрес блокировка не затрагивает
+	w = unlockFrom(t, env, token, "s3cret", "10.0.0.2")
+	require.Equal(t, 200, w.Code, w.Body.String())
+}
+
+func TestPublicLinks_PasswordValidation(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "owner@mail.ru", "owner")
+	fileID := createFileAndUpload(t, env, "open.pdf", 1024, "application/pdf", accessToken, file_version.FileStatusReady)
+
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/"+fileID.String()+"/public-links", map[string]interface{}{
+		"expires_in": "1h",
+		"password":   "abc",
+	}, accessToken)
+	require.Equal(t, 400, w.Code, w.Body.String())
+
+	// Ссылка без пароля открывается как раньше
+	token := createPublicLink(t, env, fileID, "", accessToken)
+	w = env.NewRequest(t, "GET", "/api/v1/public-links/"+token, nil)
+	require.Equal(t, 200, w.Code, w.Body.String())
+}
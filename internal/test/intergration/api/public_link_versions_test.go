@@ -0,0 +1,139 @@
+package api_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+)
+
+// addReadyVersion загружает новую версию размера size и сразу переводит её в ready
+func addReadyVersion(t *testing.T, env *TestEnv, fileID uuid.UUID, size uint64, accessToken string) uuid.UUID {
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/"+fileID.String()+"/versions", map[string]interface{}{
+		"name": "doc.pdf",
+		"size": size,
+		"mime": "application/pdf",
+	}, accessToken)
+	require.Equal(t, 201, w.Code, w.Body.String())
+
+	versionID, err := uuid.Parse(ParseJSONResponse(t, w)["version_id"].(string))
+	require.NoError(t, err)
+
+	err = env.UOW.Do(context.Background(), func(ctx context.Context) error {
+		f, err := env.FileService.GetByID(ctx, fileID)
+		if err != nil {
+			return err
+		}
+		v, err := env.VersionService.GetVersionByID(ctx, versionID)
+		if err != nil {
+			return err
+		}
+		key, err := file_version.NewS3Key(fmt.Sprintf("test-files/%s/%s", fileID, versionID))
+		if err != nil {
+			return err
+		}
+		v.S3Key = key
+		v.MarkReady()
+		f.MarkReady()
+		if err := env.FileCommandRepo.Save(ctx, f); err != nil {
+			return err
+		}
+		return env.FileVersionCommandRepo.Save(ctx, v)
+	})
+	require.NoError(t, err)
+	return versionID
+}
+
+func createVersionLink(t *testing.T, env *TestEnv, fileID uuid.UUID, body map[string]interface{}, accessToken string) string {
+	body["expires_in"] = "1h"
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/"+fileID.String()+"/public-links", body, accessToken)
+	require.Equal(t, 201, w.Code, w.Body.String())
+	return ParseJSONResponse(t, w)["token"].(string)
+}
+
+// servedSize размер версии, которую отдаёт ссылка
+func servedSize(t *testing.T, env *TestEnv, token string) float64 {
+	w := env.NewRequest(t, "GET", "/api/v1/public-links/"+token, nil)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	return ParseJSONResponse(t, w)["file_size"].(float64)
+}
+
+func TestPublicLinks_VersionModes(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+	ctx := context.Background()
+
+	accessToken := createUserAndLogin(t, env, "owner@mail.ru", "owner")
+	fileID := createFileWithStatus(t, env, "doc.pdf", 1024, "application/pdf", accessToken, file_version.FileStatusReady)
+	v1 := currentVersion(t, env, fileID).ID
+
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/"+fileID.String()+"/public-links", map[string]interface{}{
+		"expires_in": "1h",
+		"version_id": v1.String(),
+	}, accessToken)
+	require.Equal(t, 201, w.Code, w.Body.String())
+	link := ParseJSONResponse(t, w)
+	assert.Equal(t, "pinned", link["version_mode"])
+	assert.Equal(t, v1.String(), link["version_id"])
+	pinned := link["token"].(string)
+
+	latest := createVersionLink(t, env, fileID, map[string]interface{}{"version_mode": "latest"}, accessToken)
+	current := createVersionLink(t, env, fileID, map[string]interface{}{}, accessToken)
+
+	addReadyVersion(t, env, fileID, 2048, accessToken)
+	assert.Equal(t, float64(1024), servedSize(t, env, pinned))
+	assert.Equal(t, float64(2048), servedSize(t, env, latest))
+	assert.Equal(t, float64(2048), servedSize(t, env, current))
+
+	// После восстановления v1 current следует за ним, latest остаётся на новейшей версии
+	w = env.NewRequestWithAuth(t, "POST", "/api/v1/files/"+fileID.String()+"/versions/1/restore", nil, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.Equal(t, float64(1024), servedSize(t, env, pinned))
+	assert.Equal(t, float64(2048), servedSize(t, env, latest))
+	assert.Equal(t, float64(1024), servedSize(t, env, current))
+
+	// Удалённая закреплённая версия — ссылка отвечает 410, а не отдаёт другую версию
+	w = env.NewRequestWithAuth(t, "POST", "/api/v1/files/"+fileID.String()+"/versions/2/restore", nil, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	require.NoError(t, env.VersionService.DeleteVersion(ctx, fileID, v1))
+
+	w = env.NewRequest(t, "GET", "/api/v1/public-links/"+pinned, nil)
+	assert.Equal(t, 410, w.Code, w.Body.String())
+	assert.Equal(t, float64(2048), servedSize(t, env, latest))
+}
+
+func TestPublicLinks_VersionModeValidation(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	accessToken := createUserAndLogin(t, env, "owner@mail.ru", "owner")
+	fileID := createFileWithStatus(t, env, "doc.pdf", 1024, "application/pdf", accessToken, file_version.FileStatusReady)
+	otherID := createFileWithStatus(t, env, "other.pdf", 1024, "application/pdf", accessToken, file_version.FileStatusReady)
+	v1 := currentVersion(t, env, fileID).ID
+	base := "/api/v1/files/" + fileID.String() + "/public-links"
+
+	cases := []struct {
+		name string
+		body map[string]interface{}
+		code int
+	}{
+		{"unknown mode", map[string]interface{}{"version_mode": "oldest"}, 400},
+		{"pinned without version", map[string]interface{}{"version_mode": "pinned"}, 400},
+		{"latest with version", map[string]interface{}{"version_mode": "latest", "version_id": v1.String()}, 400},
+		{"version of another file", map[string]interface{}{"version_id": currentVersion(t, env, otherID).ID.String()}, 404},
+		{"unknown version", map[string]interface{}{"version_id": uuid.New().String()}, 404},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.body["expires_in"] = "1h"
+			w := env.NewJSONRequestWithAuth(t, "POST", base, tc.body, accessToken)
+			assert.Equal(t, tc.code, w.Code, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/quota"
+)
+
+func getUsage(t *testing.T, env *TestEnv, accessToken string) map[string]interface{} {
+	w := env.NewRequestWithAuth(t, "GET", "/api/v1/users/me/usage", nil, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	return ParseJSONResponse(t, w)
+}
+
+func TestQuota_Usage(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+
+	usage := getUsage(t, env, accessToken)
+	assert.Equal(t, float64(testQuotaLimit), usage["limit_bytes"])
+	assert.Equal(t, float64(0), usage["used_bytes"])
+
+	createFile(t, env, "a.txt", 1024, "text/plain", accessToken)
+	createFile(t, env, "b.txt", 512, "text/plain", accessToken)
+	createFile(t, env, "c.png", 2048, "image/png", accessToken)
+
+	usage = getUsage(t, env, accessToken)
+	assert.Equal(t, float64(3584), usage["used_bytes"])
+	assert.Equal(t, float64(testQuotaLimit-3584), usage["available_bytes"])
+
+	byMime := usage["by_mime"].(map[string]interface{})
+	text := byMime["text/plain"].(map[string]interface{})
+	assert.Equal(t, float64(2), text["versions"])
+	assert.Equal(t, float64(1536), text["bytes"])
+	png := byMime["image/png"].(map[string]interface{})
+	assert.Equal(t, float64(2048), png["bytes"])
+
+	var total float64
+	for _, entry := range usage["by_status"].(map[string]interface{}) {
+		total += entry.(map[string]interface{})["bytes"].(float64)
+	}
+	assert.Equal(t, float64(3584), total)
+}
+
+func TestQuota_Exceeded(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+	userID := getUserIDFromToken(t, env, accessToken)
+
+	first := createFileWithStatus(t, env, "a.bin", 3000, "application/octet-stream", accessToken, file_version.FileStatusReady)
+
+	_, err := env.DB.DB.ExecContext(context.Background(),
+		`UPDATE user_quotas SET limit_bytes = 4096 WHERE user_id = $1`, userID)
+	require.NoError(t, err)
+
+	body := map[string]interface{}{
+		"name": "b.bin",
+		"size": 2000,
+		"mime": "application/octet-stream",
+	}
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files", body, accessToken)
+	require.Equal(t, 413, w.Code, w.Body.String())
+	assert.Equal(t, "QUOTA_EXCEEDED", ParseJSONResponse(t, w)["code"])
+
+	w = env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/"+first.String()+"/versions", body, accessToken)
+	require.Equal(t, 413, w.Code, w.Body.String())
+
+	// Удаление в корзину место не освобождает, окончательное удаление — освобождает
+	w = env.NewRequestWithAuth(t, "DELETE", "/api/v1/files/"+first.String(), nil, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.Equal(t, float64(3000), getUsage(t, env, accessToken)["used_bytes"])
+
+	require.NoError(t, env.FileService.Purge(context.Background(), first))
+	assert.Equal(t, float64(0), getUsage(t, env, accessToken)["used_bytes"])
+
+	w = env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files", body, accessToken)
+	require.Equal(t, 201, w.Code, w.Body.String())
+}
+
+func TestQuota_UploadBeyondDeclaredSizeRejected(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+	ctx := context.Background()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+	userID := getUserIDFromToken(t, env, accessToken)
+	fileID := createFileWithStatus(t, env, "a.bin", 1000, "application/octet-stream", accessToken, file_version.FileStatusReady)
+
+	_, err := env.DB.DB.ExecContext(ctx, `UPDATE user_quotas SET limit_bytes = 4096 WHERE user_id = $1`, userID)
+	require.NoError(t, err)
+
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/"+fileID.String()+"/versions", map[string]interface{}{
+		"name": "a.bin",
+		"size": 1,
+		"mime": "application/octet-stream",
+	}, accessToken)
+	require.Equal(t, 201, w.Code, w.Body.String())
+	versionID := uuid.MustParse(ParseJSONResponse(t, w)["version_id"].(string))
+	assert.Equal(t, float64(1001), getUsage(t, env, accessToken)["used_bytes"])
+
+	// Заявлен 1 байт, а загружено больше лимита: так можно сделать частями multipart или в обход URL
+	version, err := env.VersionService.GetVersionByID(ctx, versionID)
+	require.NoError(t, err)
+	oversized := bytes.Repeat([]byte("x"), 5000)
+	_, err = env.S3.GetClient().PutObject(ctx, env.S3.GetBucket(), version.S3Key.String(),
+		bytes.NewReader(oversized), int64(len(oversized)), minio.PutObjectOptions{})
+	require.NoError(t, err)
+
+	err = env.VersionService.CompleteUpload(ctx, versionID)
+	require.ErrorIs(t, err, quota.ErrQuotaExceeded)
+
+	version, err = env.VersionService.GetVersionByID(ctx, versionID)
+	require.NoError(t, err)
+	assert.Equal(t, file_version.FileStatusFailed, version.Status)
+	assert.Equal(t, uint64(1), version.Size.Uint64())
+	require.NotNil(t, version.FailureReason)
+	assert.Contains(t, *version.FailureReason, "declared 1")
+	assert.Nil(t, version.ContentHash)
+	assert.False(t, objectExists(t, env, version.S3Key.String()))
+
+	// Лишние байты квоте не засчитаны
+	assert.Equal(t, float64(1001), getUsage(t, env, accessToken)["used_bytes"])
+}
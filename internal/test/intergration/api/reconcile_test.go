@@ -0,0 +1,58 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+)
+
+func TestReconcile_ReportsAndDeletesOrphans(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+
+	// Загруженный объект ожидающей версии не сирота
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files", map[string]interface{}{
+		"name": "kept.bin",
+		"size": 16,
+		"mime": "application/octet-stream",
+	}, accessToken)
+	require.Equal(t, 201, w.Code, w.Body.String())
+	response := ParseJSONResponse(t, w)
+	require.NoError(t, uploadFileToS3(t, response["upload_url"].(string), make([]byte, 16)))
+	keptKey := currentVersion(t, env, uuid.MustParse(response["file_id"].(string))).S3Key.String()
+
+	// Версия в статусе ready, объекта которой в бакете нет
+	missingFileID := createFileWithStatus(t, env, "missing.pdf", 1024, "application/pdf", accessToken, file_version.FileStatusReady)
+	missingVersion := currentVersion(t, env, missingFileID)
+
+	ctx := context.Background()
+	orphanKey := "files/" + uuid.NewString() + "/orphan.bin"
+	_, err := env.S3.GetClient().PutObject(ctx, env.S3.GetBucket(), orphanKey, bytes.NewReader([]byte("orphan")), 6, minio.PutObjectOptions{})
+	require.NoError(t, err)
+
+	report, err := env.ReconcileService.Reconcile(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Scanned)
+	require.Len(t, report.Orphans, 1)
+	assert.Equal(t, orphanKey, report.Orphans[0].Key)
+	assert.Equal(t, int64(6), report.Orphans[0].Size)
+	assert.Equal(t, 0, report.Deleted)
+	require.Len(t, report.Missing, 1)
+	assert.Equal(t, missingVersion.ID, report.Missing[0].VersionID)
+	assert.True(t, objectExists(t, env, orphanKey))
+
+	report, err = env.ReconcileService.Reconcile(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Deleted)
+	assert.False(t, objectExists(t, env, orphanKey))
+	assert.True(t, objectExists(t, env, keptKey))
+}
@@ -16,16 +16,28 @@ import (
 	"github.com/yourusername/cloud-file-storage/internal/api"
 	auth_handlers "github.com/yourusername/cloud-file-storage/internal/api/handlers/auth"
 	files_handler "github.com/yourusername/cloud-file-storage/internal/api/handlers/files"
+	folders_handler "github.com/yourusername/cloud-file-storage/internal/api/handlers/folders"
 	metrics_handler "github.com/yourusername/cloud-file-storage/internal/api/handlers/metrics"
+	notifications_handler "github.com/yourusername/cloud-file-storage/internal/api/handlers/notifications"
+	tus_handler "github.com/yourusername/cloud-file-storage/internal/api/handlers/tus"
 	users_handler "github.com/yourusername/cloud-file-storage/internal/api/handlers/users"
 	"github.com/yourusername/cloud-file-storage/internal/app"
+	archive_service "github.com/yourusername/cloud-file-storage/internal/app/archive"
 	auth_service "github.com/yourusername/cloud-file-storage/internal/app/auth"
+	drop_link_service "github.com/yourusername/cloud-file-storage/internal/app/drop_link"
 	event_service "github.com/yourusername/cloud-file-storage/internal/app/event"
 	file_service "github.com/yourusername/cloud-file-storage/internal/app/file"
 	file_version_service "github.com/yourusername/cloud-file-storage/internal/app/file_version"
+	folder_service "github.com/yourusername/cloud-file-storage/internal/app/folder"
 	magic_link_service "github.com/yourusername/cloud-file-storage/internal/app/magic_link"
+	multipart_upload_service "github.com/yourusername/cloud-file-storage/internal/app/multipart_upload"
 	public_link_service "github.com/yourusername/cloud-file-storage/internal/app/public_link"
+	quota_service "github.com/yourusername/cloud-file-storage/internal/app/quota"
+	reconcile_service "github.com/yourusername/cloud-file-storage/internal/app/reconcile"
 	session_service "github.com/yourusername/cloud-file-storage/internal/app/session"
+	share_service "github.com/yourusername/cloud-file-storage/internal/app/share"
+	signed_url_service "github.com/yourusername/cloud-file-storage/internal/app/signed_url"
+	tus_upload_service "github.com/yourusername/cloud-file-storage/internal/app/tus_upload"
 	user_service "github.com/yourusername/cloud-file-storage/internal/app/user"
 	"github.com/yourusername/cloud-file-storage/internal/infra/db"
 	"github.com/yourusername/cloud-file-storage/internal/infra/queue"
@@ -35,6 +47,28 @@ import (
 	"github.com/yourusername/cloud-file-storage/internal/workers"
 )
 
+// testQuotaLimit с запасом покрывает загрузки остальных тестов; квоты проверяются с лимитом, выставленным в БД
+const testQuotaLimit = 100 * 1024 * 1024 * 1024
+
+const testWebhookToken = "test-webhook-token"
+
+// testArchiveSyncLimit архивы больше этого собираются воркером, а не отдаются потоком
+const testArchiveSyncLimit = 64 * 1024
+
+// testUnlockMaxAttempts неверных паролей публичной ссылки до блокировки
+const testUnlockMaxAttempts = 3
+
+// testUnlockKey подписывает токены разблокировки публичных ссылок
+const testUnlockKey = "test-unlock-key"
+
+// Ключи подписанных URL: текущий и прежний, оставшийся после ротации только для проверки
+const (
+	testSignedURLKeyID    = "k2"
+	testSignedURLKey      = "test-signed-url-key-2"
+	testSignedURLOldKeyID = "k1"
+	testSignedURLOldKey   = "test-signed-url-key-1"
+)
+
 type TestEnv struct {
 	Server         *api.Server
 	DB             *test.TestDatabase
@@ -44,7 +78,14 @@ type TestEnv struct {
 	UserService    *user_service.UserService
 	FileService    *file_service.FileService
 	VersionService *file_version_service.FileVersionService
-	MailSender     *smtp.MockMailSender
+	// ReconcileService без grace: в тестах объекты сверяются сразу после записи
+	ReconcileService *reconcile_service.ReconcileService
+	// ArchiveService воркер архивов в тестах не запущен, сборку вызывают напрямую
+	ArchiveService *archive_service.ArchiveService
+	// PublicLinkService истёкшие ссылки в тестах снимают напрямую, без link-expirer
+	PublicLinkService *public_link_service.PublicLinkService
+	SignedURLService  *signed_url_service.SignedURLService
+	MailSender        *smtp.MockMailSender
 
 	// Репозитории
 	FileCommandRepo        *db.FileCommandRepository
@@ -85,7 +126,12 @@ func SetupTestEnvironment(t *testing.T) (*TestEnv, func()) {
 	userQueryRepo := db.NewUserQueryRepository(testDB.DB)
 	fileVersionQueryRepo := db.NewFileVersionQueryRepository(testDB.DB)
 	fileQueryRepo := db.NewFileQueryRepository(testDB.DB)
+	fileTagQueryRepo := db.NewFileTagQueryRepository(testDB.DB)
 	publicLinkQueryRepository := db.NewPublicLinkQueryRepository(testDB.DB)
+	dropLinkQueryRepo := db.NewDropLinkQueryRepository(testDB.DB)
+	folderQueryRepo := db.NewFolderQueryRepository(testDB.DB)
+	tusQueryRepo := db.NewTusUploadQueryRepository(testDB.DB)
+	multipartQueryRepo := db.NewMultipartUploadQueryRepository(testDB.DB)
 
 	eventCommandRepository := db.NewEventCommandRepository()
 	magicLinkCommandRepo := db.NewMagicLinkCommandRepository()
@@ -93,7 +139,19 @@ func SetupTestEnvironment(t *testing.T) (*TestEnv, func()) {
 	userCommandRepo := db.NewUserCommandRepository()
 	fileVersionCommandRepo := db.NewFileVersionCommandRepository()
 	fileCommandRepo := db.NewFileCommandRepository()
+	fileTagCommandRepo := db.NewFileTagCommandRepository()
 	publicLinkCommandRepository := db.NewPublicLinkCommandRepository()
+	dropLinkCommandRepo := db.NewDropLinkCommandRepository()
+	folderCommandRepo := db.NewFolderCommandRepository()
+	multipartCommandRepo := db.NewMultipartUploadCommandRepository()
+	tusCommandRepo := db.NewTusUploadCommandRepository()
+	blobCommandRepo := db.NewBlobCommandRepository()
+	quotaCommandRepo := db.NewQuotaCommandRepository()
+	quotaQueryRepo := db.NewQuotaQueryRepository(testDB.DB)
+	archiveQueryRepo := db.NewArchiveQueryRepository(testDB.DB)
+	archiveCommandRepo := db.NewArchiveCommandRepository()
+	shareQueryRepo := db.NewShareQueryRepository(testDB.DB)
+	shareCommandRepo := db.NewShareCommandRepository()
 
 	uow := app.NewUnitOfWork(testDB.DB)
 
@@ -145,11 +203,24 @@ func SetupTestEnvironment(t *testing.T) (*TestEnv, func()) {
 		*uow,
 	)
 
+	quotaService := quota_service.NewQuotaService(quotaQueryRepo, quotaCommandRepo, testQuotaLimit)
+
+	shareService := share_service.NewShareService(
+		shareQueryRepo,
+		shareCommandRepo,
+		fileQueryRepo,
+		userQueryRepo,
+		eventService,
+		*uow,
+	)
+
 	versionService := file_version_service.NewFileVersionService(
 		fileQueryRepo,
 		fileCommandRepo,
 		fileVersionQueryRepo,
 		fileVersionCommandRepo,
+		blobCommandRepo,
+		quotaService,
 		s3Storage,
 		previewConsumer,
 		previewProducer,
@@ -160,19 +231,99 @@ func SetupTestEnvironment(t *testing.T) (*TestEnv, func()) {
 	fileService := file_service.NewFileService(
 		fileQueryRepo,
 		fileCommandRepo,
+		fileTagQueryRepo,
+		fileTagCommandRepo,
 		fileVersionQueryRepo,
 		fileVersionCommandRepo,
+		blobCommandRepo,
+		folderQueryRepo,
+		shareService,
+		quotaService,
+		s3Storage,
+		eventService,
+		*uow,
+	)
+
+	multipartService := multipart_upload_service.NewMultipartUploadService(
+		multipartQueryRepo,
+		multipartCommandRepo,
+		versionService,
+		s3Storage,
+		eventService,
+		*uow,
+		time.Hour,
+	)
+
+	tusService := tus_upload_service.NewTusUploadService(
+		tusQueryRepo,
+		tusCommandRepo,
+		versionService,
+		fileService,
+		s3Storage,
+		eventService,
+		*uow,
+	)
+
+	folderService := folder_service.NewFolderService(
+		folderQueryRepo,
+		folderCommandRepo,
+		fileService,
 		eventService,
 		*uow,
 	)
 
-	publicLinkService := public_link_service.NewPublicLinkService(
+	publicLinkService, err := public_link_service.NewPublicLinkService(
 		publicLinkQueryRepository,
 		publicLinkCommandRepository,
+		fileVersionQueryRepo,
+		eventService,
+		*uow,
+		testUnlockKey,
+		time.Minute,
+		testUnlockMaxAttempts,
+		time.Minute,
+	)
+	require.NoError(t, err)
+
+	dropLinkService := drop_link_service.NewDropLinkService(
+		dropLinkQueryRepo,
+		dropLinkCommandRepo,
+		versionService,
 		eventService,
 		*uow,
 	)
 
+	// Прежний ключ testSignedURLOldKeyID принимается только для проверки, как после ротации
+	signedURLService, err := signed_url_service.NewSignedURLService(
+		db.NewSignedURLQueryRepository(testDB.DB),
+		db.NewSignedURLCommandRepository(),
+		fileVersionQueryRepo,
+		s3Storage,
+		eventService,
+		*uow,
+		testSignedURLKeyID,
+		testSignedURLKey,
+		map[string]string{testSignedURLOldKeyID: testSignedURLOldKey},
+		24*time.Hour,
+		time.Minute,
+	)
+	require.NoError(t, err)
+
+	archiveService := archive_service.NewArchiveService(
+		archiveQueryRepo,
+		archiveCommandRepo,
+		fileQueryRepo,
+		fileVersionQueryRepo,
+		folderQueryRepo,
+		shareService,
+		s3Storage,
+		eventService,
+		*uow,
+		testArchiveSyncLimit,
+		15*time.Minute,
+		time.Hour,
+	)
+
 	userService := user_service.NewUserService(
 		userQueryRepo,
 		userCommandRepo,
@@ -191,18 +342,24 @@ func SetupTestEnvironment(t *testing.T) (*TestEnv, func()) {
 	)
 
 	authHandler := auth_handlers.NewAuthHandler(authService, sessionService)
-	userHandler := users_handler.NewUserHandler(userService)
-	fileHandler := files_handler.NewFileHandler(versionService, fileService, publicLinkService)
+	userHandler := users_handler.NewUserHandler(userService, quotaService)
+	fileHandler := files_handler.NewFileHandler(versionService, fileService, publicLinkService, folderService, multipartService, archiveService, shareService, dropLinkService, signedURLService)
+	folderHandler := folders_handler.NewFolderHandler(folderService)
+	tusHandler := tus_handler.NewTusHandler(tusService, folderService)
 	metricHandler := metrics_handler.NewMetricsHandler()
 
-	server := api.NewServer(authHandler, userHandler, fileHandler, metricHandler, authService)
+	notificationHandler := notifications_handler.NewNotificationHandler(versionService, testWebhookToken)
+
+	server := api.NewServer(authHandler, userHandler, fileHandler, folderHandler, tusHandler, nil, notificationHandler, metricHandler, authService)
 
 	// Создаем контекст для управления воркерами
+	reconcileService := reconcile_service.NewReconcileService(fileVersionQueryRepo, s3Storage, 0, 100)
+
 	workerCtx, cancelWorkers := context.WithCancel(ctx)
 
 	// Создаем и запускаем воркеры
 	previewWorker := workers.NewPreviewWorker(s3Storage, previewConsumer, versionService)
-	fileChecker := workers.NewFileChecker(versionService, *uow, s3Storage, time.Second*1)
+	fileChecker := workers.NewFileChecker(versionService, *uow, s3Storage, time.Second*1, 0, 100)
 	metricWorker := workers.NewMetricsWorker(eventConsumer, time.Second*1)
 	publishWorker := workers.NewPublishEventsWorker(eventService, time.Second*1, 5, 3)
 
@@ -224,6 +381,10 @@ func SetupTestEnvironment(t *testing.T) (*TestEnv, func()) {
 		UserService:            userService,
 		FileService:            fileService,
 		VersionService:         versionService,
+		ReconcileService:       reconcileService,
+		ArchiveService:         archiveService,
+		PublicLinkService:      publicLinkService,
+		SignedURLService:       signedURLService,
 		MailSender:             mailSender,
 		FileCommandRepo:        fileCommandRepo,
 		FileVersionCommandRepo: fileVersionCommandRepo,
@@ -0,0 +1,185 @@
+package api_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+)
+
+// shareFile выдаёт доступ и возвращает id доступа
+func shareFile(t *testing.T, env *TestEnv, fileID uuid.UUID, email, role string, accessToken string) string {
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/"+fileID.String()+"/shares", map[string]interface{}{
+		"email": email,
+		"role":  role,
+	}, accessToken)
+	require.Equal(t, 201, w.Code, w.Body.String())
+
+	response := ParseJSONResponse(t, w)
+	assert.Equal(t, role, response["role"])
+	shareID, ok := response["id"].(string)
+	require.True(t, ok, "id not found in response")
+	return shareID
+}
+
+func TestShares_RolesLimitAccess(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	ownerToken := createUserAndLogin(t, env, "owner@mail.ru", "owner")
+	teammateToken := createUserAndLogin(t, env, "teammate@mail.ru", "teammate")
+	ownerID := getUserIDFromToken(t, env, ownerToken)
+	fileID := createFileWithStatus(t, env, "plan.txt", 10, "text/plain", ownerToken, file_version.FileStatusReady)
+	base := "/api/v1/files/" + fileID.String()
+
+	// До выдачи доступа файл чужой
+	w := env.NewRequestWithAuth(t, "GET", base, nil, teammateToken)
+	assert.Equal(t, 403, w.Code)
+
+	shareID := shareFile(t, env, fileID, "teammate@mail.ru", "viewer", ownerToken)
+
+	// viewer читает и скачивает
+	w = env.NewRequestWithAuth(t, "GET", base, nil, teammateToken)
+	assert.Equal(t, 200, w.Code, w.Body.String())
+	w = env.NewRequestWithAuth(t, "GET", base+"/versions", nil, teammateToken)
+	assert.Equal(t, 200, w.Code, w.Body.String())
+	w = env.NewRequestWithAuth(t, "GET", base+"/versions/1/content", nil, teammateToken)
+	assert.Equal(t, 200, w.Code, w.Body.String())
+
+	// но не меняет файл
+	newVersion := map[string]interface{}{"name": "plan.txt", "size": 20, "mime": "text/plain"}
+	w = env.NewJSONRequestWithAuth(t, "POST", base+"/versions", newVersion, teammateToken)
+	assert.Equal(t, 403, w.Code)
+	w = env.NewJSONRequestWithAuth(t, "PATCH", base, map[string]interface{}{"name": "renamed.txt"}, teammateToken)
+	assert.Equal(t, 403, w.Code)
+
+	// Повторная выдача меняет роль, а не создаёт второй доступ
+	assert.Equal(t, shareID, shareFile(t, env, fileID, "teammate@mail.ru", "editor", ownerToken))
+
+	w = env.NewJSONRequestWithAuth(t, "POST", base+"/versions", newVersion, teammateToken)
+	require.Equal(t, 201, w.Code, w.Body.String())
+	// Версия редактора хранится под префиксом владельца
+	v := currentVersion(t, env, fileID)
+	assert.Equal(t, 2, v.VersionNum.Int())
+	assert.True(t, strings.HasPrefix(v.S3Key.String(), "files/"+ownerID.String()+"/"), v.S3Key.String())
+
+	// editor не удаляет файл и не управляет доступами
+	w = env.NewRequestWithAuth(t, "DELETE", base, nil, teammateToken)
+	assert.Equal(t, 403, w.Code)
+	w = env.NewJSONRequestWithAuth(t, "POST", base+"/shares", map[string]interface{}{
+		"email": "teammate@mail.ru", "role": "owner",
+	}, teammateToken)
+	assert.Equal(t, 403, w.Code)
+	w = env.NewRequestWithAuth(t, "GET", base+"/public-links", nil, teammateToken)
+	assert.Equal(t, 403, w.Code)
+
+	// Доступный файл виден в списке «доступные мне», но не в списке своих файлов
+	w = env.NewRequestWithAuth(t, "GET", "/api/v1/files/shared", nil, teammateToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	response := ParseJSONResponse(t, w)
+	assert.Equal(t, float64(1), response["total"])
+	shared := response["files"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "editor", shared["role"])
+	assert.Equal(t, shareID, shared["share_id"])
+	assert.Equal(t, ownerID.String(), shared["owner_id"])
+	assert.Equal(t, fileID.String(), shared["file"].(map[string]interface{})["id"])
+
+	w = env.NewRequestWithAuth(t, "GET", "/api/v1/files", nil, teammateToken)
+	require.Equal(t, 200, w.Code)
+	assert.Equal(t, float64(0), ParseJSONResponse(t, w)["total"])
+
+	// Получатель может отказаться от доступа сам
+	w = env.NewRequestWithAuth(t, "DELETE", base+"/shares/"+shareID, nil, teammateToken)
+	require.Equal(t, 204, w.Code, w.Body.String())
+	w = env.NewRequestWithAuth(t, "GET", base, nil, teammateToken)
+	assert.Equal(t, 403, w.Code)
+}
+
+func TestShares_OwnerRoleManagesFile(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	ownerToken := createUserAndLogin(t, env, "owner@mail.ru", "owner")
+	coOwnerToken := createUserAndLogin(t, env, "coowner@mail.ru", "coowner")
+	createUserAndLogin(t, env, "viewer@mail.ru", "viewer")
+	fileID := createFileWithStatus(t, env, "plan.txt", 10, "text/plain", ownerToken, file_version.FileStatusReady)
+	base := "/api/v1/files/" + fileID.String()
+
+	shareFile(t, env, fileID, "coowner@mail.ru", "owner", ownerToken)
+
+	// Роль owner позволяет выдавать и отзывать чужие доступы
+	viewerShareID := shareFile(t, env, fileID, "viewer@mail.ru", "viewer", coOwnerToken)
+	w := env.NewRequestWithAuth(t, "GET", base+"/shares", nil, coOwnerToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.Equal(t, float64(2), ParseJSONResponse(t, w)["total"])
+	w = env.NewRequestWithAuth(t, "DELETE", base+"/shares/"+viewerShareID, nil, coOwnerToken)
+	require.Equal(t, 204, w.Code, w.Body.String())
+
+	// Файл переносится только в папки настоящего владельца
+	coOwnerFolder := createFolder(t, env, "mine", nil, coOwnerToken)
+	w = env.NewJSONRequestWithAuth(t, "POST", base+"/move", map[string]interface{}{"folder_id": coOwnerFolder}, coOwnerToken)
+	assert.Equal(t, 403, w.Code)
+	ownerFolder := createFolder(t, env, "docs", nil, ownerToken)
+	w = env.NewJSONRequestWithAuth(t, "POST", base+"/move", map[string]interface{}{"folder_id": ownerFolder}, coOwnerToken)
+	assert.Equal(t, 200, w.Code, w.Body.String())
+
+	w = env.NewRequestWithAuth(t, "DELETE", base, nil, coOwnerToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+
+	// Файл в корзине пропадает из списка «доступные мне»
+	w = env.NewRequestWithAuth(t, "GET", "/api/v1/files/shared", nil, coOwnerToken)
+	require.Equal(t, 200, w.Code)
+	assert.Equal(t, float64(0), ParseJSONResponse(t, w)["total"])
+}
+
+func TestShares_Validation(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	ownerToken := createUserAndLogin(t, env, "owner@mail.ru", "owner")
+	otherToken := createUserAndLogin(t, env, "other@mail.ru", "other")
+	fileID := createFileWithStatus(t, env, "plan.txt", 10, "text/plain", ownerToken, file_version.FileStatusReady)
+	otherFileID := createFileWithStatus(t, env, "other.txt", 10, "text/plain", ownerToken, file_version.FileStatusReady)
+	base := "/api/v1/files/" + fileID.String()
+
+	cases := []struct {
+		body   map[string]interface{}
+		status int
+		code   string
+	}{
+		{map[string]interface{}{"email": "owner@mail.ru", "role": "viewer"}, 400, "CANNOT_SHARE_WITH_OWNER"},
+		{map[string]interface{}{"email": "nobody@mail.ru", "role": "viewer"}, 404, "USER_NOT_FOUND"},
+		{map[string]interface{}{"email": "other@mail.ru", "role": "admin"}, 400, ""},
+		{map[string]interface{}{"email": "not-an-email", "role": "viewer"}, 400, ""},
+	}
+	for _, c := range cases {
+		w := env.NewJSONRequestWithAuth(t, "POST", base+"/shares", c.body, ownerToken)
+		assert.Equal(t, c.status, w.Code, w.Body.String())
+		if c.code != "" {
+			assert.Equal(t, c.code, ParseJSONResponse(t, w)["code"])
+		}
+	}
+
+	// Чужой пользователь не видит и не отзывает доступы
+	shareID := shareFile(t, env, fileID, "other@mail.ru", "viewer", ownerToken)
+	thirdToken := createUserAndLogin(t, env, "third@mail.ru", "third")
+	w := env.NewRequestWithAuth(t, "GET", base+"/shares", nil, thirdToken)
+	assert.Equal(t, 403, w.Code)
+	w = env.NewRequestWithAuth(t, "DELETE", base+"/shares/"+shareID, nil, thirdToken)
+	assert.Equal(t, 403, w.Code)
+
+	// Доступ другого файла по этому пути не находится
+	w = env.NewRequestWithAuth(t, "DELETE", "/api/v1/files/"+otherFileID.String()+"/shares/"+shareID, nil, ownerToken)
+	assert.Equal(t, 404, w.Code)
+	assert.Equal(t, "SHARE_NOT_FOUND", ParseJSONResponse(t, w)["code"])
+
+	// Доступ выдан к одному файлу, остальные файлы владельца закрыты
+	w = env.NewRequestWithAuth(t, "GET", "/api/v1/files/"+otherFileID.String(), nil, otherToken)
+	assert.Equal(t, 403, w.Code)
+}
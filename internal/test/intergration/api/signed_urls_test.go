@@ -0,0 +1,193 @@
+package api_test
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	signed_url_service "github.com/yourusername/cloud-file-storage/internal/app/signed_url"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/signed_url"
+)
+
+func createSignedURL(t *testing.T, env *TestEnv, fileID uuid.UUID, body map[string]interface{}, accessToken string) map[string]interface{} {
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/"+fileID.String()+"/signed-urls", body, accessToken)
+	require.Equal(t, 201, w.Code, w.Body.String())
+	return ParseJSONResponse(t, w)
+}
+
+// signedCode код ошибки ответа на подписанный URL; пустой при перенаправлении в хранилище
+func signedCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	if w.Code == 302 {
+		assert.NotEmpty(t, w.Header().Get("Location"))
+		return ""
+	}
+	code, _ := ParseJSONResponse(t, w)["code"].(string)
+	return code
+}
+
+func TestSignedURLs_DownloadAndRevoke(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "owner@mail.ru", "owner")
+	otherToken := createUserAndLogin(t, env, "other@mail.ru", "other")
+	fileID := createFileAndUpload(t, env, "report.pdf", 1024, "application/pdf", accessToken, file_version.FileStatusReady)
+
+	resp := createSignedURL(t, env, fileID, map[string]interface{}{"expires_in": "2h"}, accessToken)
+	assert.Equal(t, currentVersion(t, env, fileID).ID.String(), resp["version_id"])
+	assert.Equal(t, testSignedURLKeyID, resp["key_id"])
+	assert.Nil(t, resp["preview_url"])
+	downloadURL := resp["download_url"].(string)
+
+	w := env.NewRequest(t, "GET", downloadURL, nil)
+	require.Equal(t, 302, w.Code, w.Body.String())
+	assert.Equal(t, "", signedCode(t, w))
+
+	// Превью этим URL не разрешено
+	w = env.NewRequest(t, "GET", strings.Replace(downloadURL, "/content?", "/preview?", 1), nil)
+	assert.Equal(t, 403, w.Code)
+	assert.Equal(t, "SIGNED_URL_PERMISSION_DENIED", signedCode(t, w))
+
+	// Любое изменение подписанных полей ломает подпись
+	parsed, err := url.Parse(downloadURL)
+	require.NoError(t, err)
+	for _, field := range []string{"exp", "perm", "v", "sig"} {
+		t.Run("tampered "+field, func(t *testing.T) {
+			q := parsed.Query()
+			switch field {
+			case "exp":
+				q.Set("exp", "9999999999")
+			case "perm":
+				q.Set("perm", "download,preview")
+			case "v":
+				q.Set("v", uuid.New().String())
+			case "sig":
+				q.Set("sig", "AAAA"+q.Get("sig")[4:])
+			}
+			w := env.NewRequest(t, "GET", parsed.Path+"?"+q.Encode(), nil)
+			assert.Equal(t, 403, w.Code)
+			assert.Equal(t, "INVALID_SIGNED_URL", signedCode(t, w))
+		})
+	}
+
+	w = env.NewRequest(t, "GET", strings.Replace(downloadURL, fileID.String(), uuid.New().String(), 1), nil)
+	assert.Equal(t, "INVALID_SIGNED_URL", signedCode(t, w))
+
+	// Отзывать может только владелец
+	w = env.NewRequestWithAuth(t, "DELETE", "/api/v1/files/"+fileID.String()+"/signed-urls", nil, otherToken)
+	assert.Equal(t, 403, w.Code, w.Body.String())
+
+	w = env.NewRequestWithAuth(t, "DELETE", "/api/v1/files/"+fileID.String()+"/signed-urls", nil, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.Equal(t, 1, countRows(t, env, `SELECT COUNT(*) FROM events WHERE name = 'SignedURLsRevoked'`))
+
+	w = env.NewRequest(t, "GET", downloadURL, nil)
+	assert.Equal(t, 403, w.Code)
+	assert.Equal(t, "SIGNED_URL_REVOKED", signedCode(t, w))
+
+	// URL, выданные после отзыва, действуют
+	time.Sleep(time.Second)
+	resp = createSignedURL(t, env, fileID, map[string]interface{}{"permissions": []string{"download"}}, accessToken)
+	w = env.NewRequest(t, "GET", resp["download_url"].(string), nil)
+	assert.Equal(t, 302, w.Code, w.Body.String())
+}
+
+func TestSignedURLs_KeyRotationAndOfflineMinting(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "owner@mail.ru", "owner")
+	fileID := createFileAndUpload(t, env, "report.pdf", 1024, "application/pdf", accessToken, file_version.FileStatusReady)
+	versionID := currentVersion(t, env, fileID).ID
+	download, err := signed_url.NewPermissions(nil)
+	require.NoError(t, err)
+
+	// Доверенный сервис подписывает URL сам, без API и БД
+	mint := func(keyID, key string, ttl time.Duration) string {
+		minter, err := signed_url_service.NewSignedURLService(nil, nil, nil, nil, nil, *env.UOW, keyID, key, nil, 0, 0)
+		require.NoError(t, err)
+		claims, err := signed_url.NewClaims(keyID, fileID, versionID, download, ttl)
+		require.NoError(t, err)
+		return "/api/v1/signed/files/" + fileID.String() + "/content?" + minter.Sign(claims)
+	}
+
+	w := env.NewRequest(t, "GET", mint(testSignedURLKeyID, testSignedURLKey, time.Hour), nil)
+	assert.Equal(t, 302, w.Code, w.Body.String())
+
+	// Прежний ключ после ротации ещё проверяет выданные им URL
+	w = env.NewRequest(t, "GET", mint(testSignedURLOldKeyID, testSignedURLOldKey, time.Hour), nil)
+	assert.Equal(t, 302, w.Code, w.Body.String())
+
+	w = env.NewRequest(t, "GET", mint("k0", "retired-key", time.Hour), nil)
+	assert.Equal(t, "INVALID_SIGNED_URL", signedCode(t, w))
+
+	w = env.NewRequest(t, "GET", mint(testSignedURLOldKeyID, "wrong-key", time.Hour), nil)
+	assert.Equal(t, "INVALID_SIGNED_URL", signedCode(t, w))
+
+	// Без ключа подписи сервис не создаётся, а не подписывает случайным ключом
+	_, err = signed_url_service.NewSignedURLService(nil, nil, nil, nil, nil, *env.UOW, testSignedURLKeyID, "", nil, 0, 0)
+	require.Error(t, err)
+
+	// Истёкший URL
+	minter, err := signed_url_service.NewSignedURLService(nil, nil, nil, nil, nil, *env.UOW, testSignedURLKeyID, testSignedURLKey, nil, 0, 0)
+	require.NoError(t, err)
+	claims, err := signed_url.NewClaims(testSignedURLKeyID, fileID, versionID, download, time.Hour)
+	require.NoError(t, err)
+	claims.IssuedAt = claims.IssuedAt.Add(-2 * time.Hour)
+	claims.ExpiresAt = claims.ExpiresAt.Add(-2 * time.Hour)
+	w = env.NewRequest(t, "GET", "/api/v1/signed/files/"+fileID.String()+"/content?"+minter.Sign(claims), nil)
+	assert.Equal(t, 403, w.Code)
+	assert.Equal(t, "SIGNED_URL_EXPIRED", signedCode(t, w))
+}
+
+func TestSignedURLs_Validation(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	accessToken := createUserAndLogin(t, env, "owner@mail.ru", "owner")
+	otherToken := createUserAndLogin(t, env, "other@mail.ru", "other")
+	fileID := createFileWithStatus(t, env, "doc.pdf", 1024, "application/pdf", accessToken, file_version.FileStatusReady)
+	otherFileID := createFileWithStatus(t, env, "other.pdf", 1024, "application/pdf", accessToken, file_version.FileStatusReady)
+	otherVersionID := currentVersion(t, env, otherFileID).ID.String()
+
+	cases := []struct {
+		name  string
+		body  map[string]interface{}
+		token string
+		code  int
+	}{
+		{"too long", map[string]interface{}{"expires_in": "48h"}, accessToken, 400},
+		{"negative ttl", map[string]interface{}{"expires_in": "-1h"}, accessToken, 400},
+		{"bad permission", map[string]interface{}{"permissions": []string{"upload"}}, accessToken, 400},
+		{"version of another file", map[string]interface{}{"version_id": otherVersionID}, accessToken, 404},
+		{"not owner", map[string]interface{}{}, otherToken, 403},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/"+fileID.String()+"/signed-urls", tc.body, tc.token)
+			assert.Equal(t, tc.code, w.Code, w.Body.String())
+		})
+	}
+
+	resp := createSignedURL(t, env, fileID, map[string]interface{}{"permissions": []string{"preview", "download"}}, accessToken)
+	assert.Equal(t, []interface{}{"download", "preview"}, resp["permissions"])
+	require.NotNil(t, resp["preview_url"])
+
+	// У версии нет превью
+	w := env.NewRequest(t, "GET", resp["preview_url"].(string), nil)
+	assert.Equal(t, 404, w.Code, w.Body.String())
+
+	// Файл в корзине не отдаётся; состояние файла узел помнит, поэтому URL до корзины не открывали
+	resp = createSignedURL(t, env, otherFileID, map[string]interface{}{}, accessToken)
+	w = env.NewRequestWithAuth(t, "DELETE", "/api/v1/files/"+otherFileID.String(), nil, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	w = env.NewRequest(t, "GET", resp["download_url"].(string), nil)
+	assert.Equal(t, 404, w.Code, w.Body.String())
+}
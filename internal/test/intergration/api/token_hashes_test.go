@@ -0,0 +1,89 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/value_objects"
+)
+
+// tokenHash то, что лежит в колонке token_hash для выданного клиенту токена
+func tokenHash(t *testing.T, token string) string {
+	hash, err := value_objects.HashToken(token)
+	require.NoError(t, err)
+	return hash.String()
+}
+
+func TestTokens_StoredOnlyAsHashes(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	accessToken, refreshToken := createUserAndGetTokens(t, env, "owner@mail.ru", "owner")
+	magicToken := env.MailSender.GetTokenForEmail("owner@mail.ru")
+	fileID := createFileWithStatus(t, env, "doc.pdf", 1024, "application/pdf", accessToken, file_version.FileStatusReady)
+	publicToken := createPublicLink(t, env, fileID, "", accessToken)
+	dropToken := createDropLink(t, env, map[string]interface{}{}, accessToken)["token"].(string)
+
+	cases := []struct {
+		name  string
+		query string
+		token string
+	}{
+		{"access token", `SELECT COUNT(*) FROM sessions WHERE token_hash = $1`, accessToken},
+		{"refresh token", `SELECT COUNT(*) FROM sessions WHERE refresh_token_hash = $1`, refreshToken},
+		{"magic link", `SELECT COUNT(*) FROM magic_links WHERE token_hash = $1`, magicToken},
+		{"public link", `SELECT COUNT(*) FROM public_links WHERE token_hash = $1`, publicToken},
+		{"drop link", `SELECT COUNT(*) FROM drop_links WHERE token_hash = $1`, dropToken},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// 256 бит в base64url без паддинга
+			assert.Len(t, tc.token, 43)
+			assert.Equal(t, 0, countRows(t, env, tc.query, tc.token))
+			assert.Equal(t, 1, countRows(t, env, tc.query, tokenHash(t, tc.token)))
+		})
+	}
+
+	// Списки ссылок токенов не показывают: их больше негде взять
+	w := env.NewRequestWithAuth(t, "GET", "/api/v1/files/"+fileID.String()+"/public-links", nil, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	assert.NotContains(t, w.Body.String(), publicToken)
+	assert.NotContains(t, w.Body.String(), `"token"`)
+
+	// По хешу из БД доступа нет
+	w = env.NewRequest(t, "GET", "/api/v1/public-links/"+tokenHash(t, publicToken), nil)
+	assert.Equal(t, 404, w.Code, w.Body.String())
+	w = env.NewRequestWithAuth(t, "GET", "/api/v1/users/me", nil, tokenHash(t, accessToken))
+	assert.Equal(t, 401, w.Code, w.Body.String())
+
+	w = env.NewRequest(t, "GET", "/api/v1/public-links/"+publicToken, nil)
+	assert.Equal(t, 200, w.Code, w.Body.String())
+}
+
+func TestTokens_RefreshRotatesPair(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	accessToken, refreshToken := createUserAndGetTokens(t, env, "owner@mail.ru", "owner")
+
+	w := env.NewJSONRequest(t, "POST", "/api/v1/auth/tokens/refresh", map[string]interface{}{"refresh_token": refreshToken})
+	require.Equal(t, 200, w.Code, w.Body.String())
+	resp := ParseJSONResponse(t, w)
+	newAccessToken := resp["access_token"].(string)
+	newRefreshToken := resp["refresh_token"].(string)
+	assert.NotEqual(t, accessToken, newAccessToken)
+	assert.NotEqual(t, refreshToken, newRefreshToken)
+
+	w = env.NewRequestWithAuth(t, "GET", "/api/v1/users/me", nil, newAccessToken)
+	assert.Equal(t, 200, w.Code, w.Body.String())
+
+	// Прежняя пара больше не действует
+	w = env.NewRequestWithAuth(t, "GET", "/api/v1/users/me", nil, accessToken)
+	assert.Equal(t, 401, w.Code, w.Body.String())
+	w = env.NewJSONRequest(t, "POST", "/api/v1/auth/tokens/refresh", map[string]interface{}{"refresh_token": refreshToken})
+	assert.NotEqual(t, 200, w.Code, w.Body.String())
+}
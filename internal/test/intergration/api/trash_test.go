@@ -0,0 +1,105 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+)
+
+func TestTrash_DeleteAndRestore(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken, _ := createUserAndGetTokens(t, env, "test@mail.ru", "test")
+	fileID := createFileWithStatus(t, env, "keep.pdf", 128, "application/pdf", accessToken, file_version.FileStatusReady)
+
+	w := env.NewRequestWithAuth(t, "DELETE", "/api/v1/files/"+fileID.String(), nil, accessToken)
+	require.Equal(t, 200, w.Code)
+
+	w = env.NewRequestWithAuth(t, "GET", "/api/v1/files/trash", nil, accessToken)
+	require.Equal(t, 200, w.Code)
+	files := ParseJSONResponse(t, w)["files"].([]interface{})
+	require.Len(t, files, 1)
+	trashed := files[0].(map[string]interface{})
+	assert.Equal(t, fileID.String(), trashed["id"])
+	assert.NotEmpty(t, trashed["deleted_at"])
+
+	w = env.NewRequestWithAuth(t, "POST", "/api/v1/files/"+fileID.String()+"/restore", nil, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+
+	w = env.NewRequestWithAuth(t, "GET", "/api/v1/files/"+fileID.String(), nil, accessToken)
+	require.Equal(t, 200, w.Code)
+}
+
+func TestTrash_PublicLinkToTrashedFile(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+	fileID := createFileWithStatus(t, env, "keep.pdf", 128, "application/pdf", accessToken, file_version.FileStatusReady)
+	token := createPublicLink(t, env, fileID, "", accessToken)
+
+	w := env.NewRequestWithAuth(t, "DELETE", "/api/v1/files/"+fileID.String(), nil, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+
+	// Ссылка остаётся за файлом в корзине, но не открывается
+	w = env.NewRequest(t, "GET", "/api/v1/public-links/"+token, nil)
+	require.Equal(t, 404, w.Code, w.Body.String())
+	assert.Equal(t, "file not found", ParseJSONResponse(t, w)["error"])
+
+	w = browserRequest(t, env, "GET", "/api/v1/public-links/"+token, nil)
+	require.Equal(t, 404, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "This link is not available")
+
+	w = env.NewRequestWithAuth(t, "POST", "/api/v1/files/"+fileID.String()+"/restore", nil, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+
+	w = env.NewRequest(t, "GET", "/api/v1/public-links/"+token, nil)
+	assert.Equal(t, 200, w.Code, w.Body.String())
+}
+
+func TestTrash_Restore_NotInTrash(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken, _ := createUserAndGetTokens(t, env, "test@mail.ru", "test")
+	fileID := createFileWithStatus(t, env, "active.pdf", 128, "application/pdf", accessToken, file_version.FileStatusReady)
+
+	w := env.NewRequestWithAuth(t, "POST", "/api/v1/files/"+fileID.String()+"/restore", nil, accessToken)
+	require.Equal(t, 409, w.Code)
+}
+
+func TestTrash_DeletePermanently(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken, _ := createUserAndGetTokens(t, env, "test@mail.ru", "test")
+	fileID := createFileWithStatus(t, env, "gone.pdf", 128, "application/pdf", accessToken, file_version.FileStatusReady)
+
+	w := env.NewRequestWithAuth(t, "DELETE", "/api/v1/files/"+fileID.String(), nil, accessToken)
+	require.Equal(t, 200, w.Code)
+
+	w = env.NewRequestWithAuth(t, "DELETE", "/api/v1/files/"+fileID.String()+"/permanent", nil, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+
+	w = env.NewRequestWithAuth(t, "POST", "/api/v1/files/"+fileID.String()+"/restore", nil, accessToken)
+	require.Equal(t, 404, w.Code)
+}
+
+func TestTrash_DeletePermanently_AccessDenied(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	token1, _ := createUserAndGetTokens(t, env, "user1@mail.ru", "User 1")
+	fileID := createFileWithStatus(t, env, "protected.pdf", 128, "application/pdf", token1, file_version.FileStatusReady)
+
+	w := env.NewRequestWithAuth(t, "DELETE", "/api/v1/files/"+fileID.String(), nil, token1)
+	require.Equal(t, 200, w.Code)
+
+	token2, _ := createUserAndGetTokens(t, env, "user2@mail.ru", "User 2")
+	w = env.NewRequestWithAuth(t, "DELETE", "/api/v1/files/"+fileID.String()+"/permanent", nil, token2)
+	require.Equal(t, 403, w.Code)
+}
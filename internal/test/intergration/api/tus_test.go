@@ -0,0 +1,161 @@
+package api_test
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tusRequest(t *testing.T, env *TestEnv, method, path string, body []byte, headers map[string]string, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	req.Header.Set("Tus-Resumable", "1.0.0")
+	req.Header.Set("Authorization", "Bearer "+token)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	w := httptest.NewRecorder()
+	env.Server.ServeHTTP(w, req)
+	return w
+}
+
+func createTusUpload(t *testing.T, env *TestEnv, name string, length int, token string) (string, string) {
+	meta := "filename " + base64.StdEncoding.EncodeToString([]byte(name)) +
+		",filetype " + base64.StdEncoding.EncodeToString([]byte("application/octet-stream"))
+
+	w := tusRequest(t, env, "POST", "/api/v1/uploads", nil, map[string]string{
+		"Upload-Length":   strconv.Itoa(length),
+		"Upload-Metadata": meta,
+	}, token)
+	require.Equal(t, 201, w.Code, w.Body.String())
+	assert.Equal(t, "1.0.0", w.Header().Get("Tus-Resumable"))
+
+	location := w.Header().Get("Location")
+	require.NotEmpty(t, location)
+	return location, w.Header().Get("X-File-ID")
+}
+
+func TestTus_Options(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	w := env.NewRequest(t, "OPTIONS", "/api/v1/uploads", nil)
+	require.Equal(t, 204, w.Code)
+	assert.Equal(t, "1.0.0", w.Header().Get("Tus-Version"))
+	assert.Contains(t, w.Header().Get("Tus-Extension"), "checksum")
+}
+
+func TestTus_ResumeAndComplete(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+	location, fileID := createTusUpload(t, env, "resumable.bin", 2048, accessToken)
+
+	patch := map[string]string{"Content-Type": "application/offset+octet-stream", "Upload-Offset": "0"}
+	w := tusRequest(t, env, "PATCH", location, make([]byte, 1000), patch, accessToken)
+	require.Equal(t, 204, w.Code, w.Body.String())
+	assert.Equal(t, "1000", w.Header().Get("Upload-Offset"))
+
+	// Клиент переподключился и узнаёт, откуда продолжать
+	w = tusRequest(t, env, "HEAD", location, nil, nil, accessToken)
+	require.Equal(t, 200, w.Code)
+	assert.Equal(t, "1000", w.Header().Get("Upload-Offset"))
+	assert.Equal(t, "2048", w.Header().Get("Upload-Length"))
+
+	rest := make([]byte, 1048)
+	sum := sha1.Sum(rest)
+	patch = map[string]string{
+		"Content-Type":    "application/offset+octet-stream",
+		"Upload-Offset":   "1000",
+		"Upload-Checksum": "sha1 " + base64.StdEncoding.EncodeToString(sum[:]),
+	}
+	w = tusRequest(t, env, "PATCH", location, rest, patch, accessToken)
+	require.Equal(t, 204, w.Code, w.Body.String())
+	assert.Equal(t, "2048", w.Header().Get("Upload-Offset"))
+
+	w = env.NewRequestWithAuth(t, "GET", "/api/v1/files/"+fileID, nil, accessToken)
+	require.Equal(t, 200, w.Code)
+	assert.NotEqual(t, "processing", ParseJSONResponse(t, w)["status"])
+}
+
+func TestTus_OffsetMismatch(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+	location, _ := createTusUpload(t, env, "file.bin", 100, accessToken)
+
+	patch := map[string]string{"Content-Type": "application/offset+octet-stream", "Upload-Offset": "50"}
+	w := tusRequest(t, env, "PATCH", location, make([]byte, 50), patch, accessToken)
+	assert.Equal(t, 409, w.Code)
+}
+
+func TestTus_ChecksumMismatch(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+	location, _ := createTusUpload(t, env, "file.bin", 100, accessToken)
+
+	sum := sha1.Sum([]byte("other data"))
+	patch := map[string]string{
+		"Content-Type":    "application/offset+octet-stream",
+		"Upload-Offset":   "0",
+		"Upload-Checksum": "sha1 " + base64.StdEncoding.EncodeToString(sum[:]),
+	}
+	w := tusRequest(t, env, "PATCH", location, make([]byte, 100), patch, accessToken)
+	assert.Equal(t, 460, w.Code)
+
+	w = tusRequest(t, env, "HEAD", location, nil, nil, accessToken)
+	require.Equal(t, 200, w.Code)
+	assert.Equal(t, "0", w.Header().Get("Upload-Offset"))
+}
+
+func TestTus_UnsupportedVersion(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+
+	w := tusRequest(t, env, "POST", "/api/v1/uploads", nil, map[string]string{
+		"Tus-Resumable": "0.2.2",
+		"Upload-Length": "10",
+	}, accessToken)
+	assert.Equal(t, 412, w.Code)
+}
+
+func TestTus_Terminate(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+	location, fileID := createTusUpload(t, env, "cancel.bin", 100, accessToken)
+
+	w := tusRequest(t, env, "DELETE", location, nil, nil, accessToken)
+	require.Equal(t, 204, w.Code)
+
+	w = tusRequest(t, env, "HEAD", location, nil, nil, accessToken)
+	assert.Equal(t, 404, w.Code)
+
+	w = env.NewRequestWithAuth(t, "GET", "/api/v1/files/"+fileID, nil, accessToken)
+	assert.Equal(t, 404, w.Code)
+}
+
+func TestTus_AccessDenied(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+
+	ownerToken := createUserAndLogin(t, env, "owner@mail.ru", "owner")
+	otherToken := createUserAndLogin(t, env, "other@mail.ru", "other")
+	location, _ := createTusUpload(t, env, "private.bin", 100, ownerToken)
+
+	w := tusRequest(t, env, "HEAD", location, nil, nil, otherToken)
+	assert.Equal(t, 403, w.Code)
+}
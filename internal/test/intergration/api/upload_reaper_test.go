@@ -0,0 +1,166 @@
+package api_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	file_version_service "github.com/yourusername/cloud-file-storage/internal/app/file_version"
+	quota_service "github.com/yourusername/cloud-file-storage/internal/app/quota"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
+	"github.com/yourusername/cloud-file-storage/internal/infra/db"
+)
+
+// completingStorage не видит объект, но перед ответом успевает завершить загрузку —
+// так завершение проскакивает между проверкой объекта и транзакцией снятия
+type completingStorage struct {
+	storage.Storage
+	complete func()
+}
+
+func (s completingStorage) FileExists(ctx context.Context, key string) (bool, error) {
+	s.complete()
+	return false, nil
+}
+
+func TestUploadReaper_RemovesFileWithOnlyAbandonedVersion(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+	fileID := createFile(t, env, "never.pdf", 2048, "application/pdf", accessToken)
+	assert.Equal(t, float64(2048), getUsage(t, env, accessToken)["used_bytes"])
+
+	ctx := context.Background()
+	versions, err := env.VersionService.GetAbandonedUploads(ctx, 0, 100)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+
+	abandoned, err := env.VersionService.AbandonUpload(ctx, versions[0].ID)
+	require.NoError(t, err)
+	assert.True(t, abandoned)
+
+	w := env.NewRequestWithAuth(t, "GET", "/api/v1/files/"+fileID.String(), nil, accessToken)
+	assert.Equal(t, 404, w.Code)
+	assert.Equal(t, float64(0), getUsage(t, env, accessToken)["used_bytes"])
+}
+
+func TestUploadReaper_RollsBackToLastReadyVersion(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+	fileID := createFileWithStatus(t, env, "report.pdf", 1024, "application/pdf", accessToken, file_version.FileStatusReady)
+
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/"+fileID.String()+"/versions", map[string]interface{}{
+		"name": "report.pdf",
+		"size": 4096,
+		"mime": "application/pdf",
+	}, accessToken)
+	require.Equal(t, 201, w.Code, w.Body.String())
+	versionID := uuid.MustParse(ParseJSONResponse(t, w)["version_id"].(string))
+
+	abandoned, err := env.VersionService.AbandonUpload(context.Background(), versionID)
+	require.NoError(t, err)
+	assert.True(t, abandoned)
+
+	version, err := env.VersionService.GetVersionByID(context.Background(), versionID)
+	require.NoError(t, err)
+	assert.Equal(t, file_version.FileStatusFailed, version.Status)
+	require.NotNil(t, version.FailureReason)
+	assert.Equal(t, float64(1024), getUsage(t, env, accessToken)["used_bytes"])
+
+	w = env.NewRequestWithAuth(t, "GET", "/api/v1/files/"+fileID.String(), nil, accessToken)
+	require.Equal(t, 200, w.Code, w.Body.String())
+	file := ParseJSONResponse(t, w)
+	assert.Equal(t, float64(1), file["current_version"])
+	assert.Equal(t, "ready", file["status"])
+
+	// Номер брошенной версии занят, следующая загрузка получает новый
+	w = env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files/"+fileID.String()+"/versions", map[string]interface{}{
+		"name": "report.pdf",
+		"size": 512,
+		"mime": "application/pdf",
+	}, accessToken)
+	require.Equal(t, 201, w.Code, w.Body.String())
+	assert.Equal(t, float64(3), ParseJSONResponse(t, w)["version_num"])
+
+	// Повторный вызов по уже снятой версии ничего не делает
+	abandoned, err = env.VersionService.AbandonUpload(context.Background(), versionID)
+	require.NoError(t, err)
+	assert.False(t, abandoned)
+}
+
+func TestUploadReaper_SkipsUploadedObject(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files", map[string]interface{}{
+		"name": "late.pdf",
+		"size": 1024,
+		"mime": "application/pdf",
+	}, accessToken)
+	require.Equal(t, 201, w.Code, w.Body.String())
+	response := ParseJSONResponse(t, w)
+	require.NoError(t, uploadFileToS3(t, response["upload_url"].(string), make([]byte, 1024)))
+
+	abandoned, err := env.VersionService.AbandonUpload(context.Background(), uuid.MustParse(response["version_id"].(string)))
+	require.NoError(t, err)
+	assert.False(t, abandoned)
+	assert.Equal(t, file_version.FileStatusProcessing, currentVersion(t, env, uuid.MustParse(response["file_id"].(string))).Status)
+}
+
+func TestUploadReaper_SkipsUploadCompletedDuringCheck(t *testing.T) {
+	env, cleanup := SetupTestEnvironment(t)
+	defer cleanup()
+	env.CancelWorkers()
+
+	accessToken := createUserAndLogin(t, env, "test@mail.ru", "test")
+
+	w := env.NewJSONRequestWithAuth(t, "POST", "/api/v1/files", map[string]interface{}{
+		"name": "late.pdf",
+		"size": 1024,
+		"mime": "application/pdf",
+	}, accessToken)
+	require.Equal(t, 201, w.Code, w.Body.String())
+	response := ParseJSONResponse(t, w)
+	fileID := uuid.MustParse(response["file_id"].(string))
+	versionID := uuid.MustParse(response["version_id"].(string))
+	require.NoError(t, uploadFileToS3(t, response["upload_url"].(string), make([]byte, 1024)))
+
+	ctx := context.Background()
+	reaper := file_version_service.NewFileVersionService(
+		db.NewFileQueryRepository(env.DB.DB),
+		env.FileCommandRepo,
+		db.NewFileVersionQueryRepository(env.DB.DB),
+		env.FileVersionCommandRepo,
+		db.NewBlobCommandRepository(),
+		quota_service.NewQuotaService(db.NewQuotaQueryRepository(env.DB.DB), db.NewQuotaCommandRepository(), testQuotaLimit),
+		completingStorage{complete: func() {
+			require.NoError(t, env.VersionService.CompleteUpload(ctx, versionID))
+		}},
+		nil,
+		nil,
+		nil,
+		*env.UOW,
+	)
+
+	abandoned, err := reaper.AbandonUpload(ctx, versionID)
+	require.NoError(t, err)
+	assert.False(t, abandoned)
+
+	version := currentVersion(t, env, fileID)
+	assert.Equal(t, versionID, version.ID)
+	assert.Equal(t, file_version.FileStatusUploaded, version.Status)
+	assert.Nil(t, version.FailureReason)
+	assert.Equal(t, uint64(1024), version.Size.Uint64())
+	assert.Equal(t, float64(1024), getUsage(t, env, accessToken)["used_bytes"])
+}
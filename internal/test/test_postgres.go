@@ -72,11 +72,21 @@ func (td *TestDatabase) Terminate(ctx context.Context) error {
 
 func (td *TestDatabase) CleanDB(ctx context.Context) error {
 	tables := []string{
+		"tus_uploads",
+		"multipart_uploads",
+		"file_shares",
+		"public_link_accesses",
+		"public_link_unlock_attempts",
 		"public_links",
+		"public_links_archive",
 		"file_versions",
+		"drop_links",
+		"blobs",
 		"files",
+		"folders",
 		"sessions",
 		"magic_links",
+		"user_quotas",
 		"users",
 		"events",
 	}
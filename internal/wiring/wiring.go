@@ -0,0 +1,124 @@
+// Package wiring общая сборка зависимостей для бинарников из cmd
+package wiring
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/yourusername/cloud-file-storage/internal/app"
+	event_service "github.com/yourusername/cloud-file-storage/internal/app/event"
+	public_link_service "github.com/yourusername/cloud-file-storage/internal/app/public_link"
+	signed_url_service "github.com/yourusername/cloud-file-storage/internal/app/signed_url"
+	"github.com/yourusername/cloud-file-storage/internal/config"
+	"github.com/yourusername/cloud-file-storage/internal/domain/queue"
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
+	"github.com/yourusername/cloud-file-storage/internal/infra/db"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const metricsShutdownTimeout = 5 * time.Second
+
+func InitTracer() func() {
+	exporter, err := stdouttrace.New(
+		stdouttrace.WithPrettyPrint(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+	)
+	otel.SetTracerProvider(tp)
+
+	return func() {
+		_ = tp.Shutdown(context.Background())
+	}
+}
+
+// LoadConfig подхватывает .env и читает базовый и dev конфиг; непустой httpAddr переопределяет http.addr
+func LoadConfig(httpAddr string) (config.Snapshot, error) {
+	if err := godotenv.Load(".env"); err != nil {
+		log.Printf("Warning: .env file not found: %v", err)
+	}
+	return config.Load("configs/config.base.yaml", "configs/config.dev.yaml", httpAddr)
+}
+
+// ShutdownContext отменяется по SIGINT и SIGTERM
+func ShutdownContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// NewEventService producer нужен только процессу, который публикует outbox; остальным хватает nil
+func NewEventService(dbConn *sql.DB, producer queue.EventProducer, instanceID string, uow *app.UnitOfWork) *event_service.EventService {
+	return event_service.NewEventService(
+		db.NewEventQueryRepository(dbConn),
+		db.NewEventCommandRepository(),
+		producer,
+		instanceID,
+		*uow,
+	)
+}
+
+// NewPublicLinkService без public_links.unlock_signing_key процесс не стартует
+func NewPublicLinkService(dbConn *sql.DB, cfg config.Immutable, eventService *event_service.EventService, uow *app.UnitOfWork) (*public_link_service.PublicLinkService, error) {
+	return public_link_service.NewPublicLinkService(
+		db.NewPublicLinkQueryRepository(dbConn),
+		db.NewPublicLinkCommandRepository(),
+		db.NewFileVersionQueryRepository(dbConn),
+		eventService,
+		*uow,
+		cfg.PublicLinks.UnlockSigningKey,
+		cfg.PublicLinks.UnlockTTL,
+		cfg.PublicLinks.MaxAttempts,
+		cfg.PublicLinks.Lockout,
+	)
+}
+
+// NewSignedURLService подписанные URL проверяются любым процессом с тем же jwt.signingkey;
+// без ключа процесс не стартует
+func NewSignedURLService(dbConn *sql.DB, cfg config.Immutable, objectStorage storage.Storage, eventService *event_service.EventService, uow *app.UnitOfWork) (*signed_url_service.SignedURLService, error) {
+	return signed_url_service.NewSignedURLService(
+		db.NewSignedURLQueryRepository(dbConn),
+		db.NewSignedURLCommandRepository(),
+		db.NewFileVersionQueryRepository(dbConn),
+		objectStorage,
+		eventService,
+		*uow,
+		cfg.JWT.KeyID,
+		cfg.JWT.SigningKey,
+		cfg.JWT.VerifyKeys,
+		cfg.SignedURLs.MaxTTL,
+		cfg.SignedURLs.StateTTL,
+	)
+}
+
+// ServeMetrics отдаёт Prometheus метрики на addr/metrics до отмены ctx
+func ServeMetrics(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), metricsShutdownTimeout)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
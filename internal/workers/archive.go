@@ -0,0 +1,89 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	archive_service "github.com/yourusername/cloud-file-storage/internal/app/archive"
+)
+
+// ArchiveWorker собирает большие ZIP архивы и удаляет архивы с истёкшим сроком хранения
+type ArchiveWorker struct {
+	archiveService *archive_service.ArchiveService
+	interval       time.Duration
+	batchSize      int
+}
+
+const (
+	defaultArchiveInterval = 30 * time.Second
+	defaultArchiveBatch    = 10
+)
+
+func NewArchiveWorker(archiveService *archive_service.ArchiveService, interval time.Duration, batchSize int) *ArchiveWorker {
+	if interval <= 0 {
+		interval = defaultArchiveInterval
+	}
+	if batchSize <= 0 {
+		batchSize = defaultArchiveBatch
+	}
+	return &ArchiveWorker{
+		archiveService: archiveService,
+		interval:       interval,
+		batchSize:      batchSize,
+	}
+}
+
+// Start запускает фоновый воркер
+func (w *ArchiveWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	log.Println("ArchiveWorker started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("ArchiveWorker stopped by context")
+			return
+		case <-ticker.C:
+			if err := w.buildPending(ctx); err != nil {
+				log.Printf("ArchiveWorker error: %v", err)
+			}
+			if err := w.deleteExpired(ctx); err != nil {
+				log.Printf("ArchiveWorker error: %v", err)
+			}
+		}
+	}
+}
+
+func (w *ArchiveWorker) buildPending(ctx context.Context) error {
+	archives, err := w.archiveService.ClaimPending(ctx, w.batchSize)
+	if err != nil {
+		return fmt.Errorf("failed to claim archives: %w", err)
+	}
+
+	for _, a := range archives {
+		if err := w.archiveService.Build(ctx, a); err != nil {
+			log.Printf("ArchiveWorker failed to build archive %s: %v", a.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (w *ArchiveWorker) deleteExpired(ctx context.Context) error {
+	archives, err := w.archiveService.GetExpired(ctx, w.batchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get expired archives: %w", err)
+	}
+
+	for _, a := range archives {
+		if err := w.archiveService.Delete(ctx, a); err != nil {
+			log.Printf("ArchiveWorker failed to delete archive %s: %v", a.ID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	file_version_service "github.com/yourusername/cloud-file-storage/internal/app/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/queue"
+)
+
+// BucketNotificationWorker завершает загрузки по уведомлениям s3:ObjectCreated:* из Kafka,
+// не дожидаясь, пока версию найдёт FileChecker
+type BucketNotificationWorker struct {
+	consumer       queue.BucketEventConsumer
+	versionService *file_version_service.FileVersionService
+	retryDelay     time.Duration
+}
+
+func NewBucketNotificationWorker(
+	consumer queue.BucketEventConsumer,
+	versionService *file_version_service.FileVersionService,
+	retryDelay time.Duration,
+) *BucketNotificationWorker {
+	return &BucketNotificationWorker{
+		consumer:       consumer,
+		versionService: versionService,
+		retryDelay:     retryDelay,
+	}
+}
+
+func (w *BucketNotificationWorker) Handle(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		events, err := w.consumer.Consume(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return err
+			}
+			time.Sleep(w.retryDelay)
+			continue
+		}
+
+		for _, e := range events {
+			// Ошибка не теряет загрузку: версия останется в processing до FileChecker
+			if _, err := w.versionService.CompleteUploadByKey(ctx, e.Key); err != nil {
+				log.Printf("BucketNotificationWorker failed to complete %s: %v", e.Key, err)
+			}
+		}
+	}
+}
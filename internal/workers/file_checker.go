@@ -3,6 +3,7 @@ package workers
 import (
 	"context"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/yourusername/cloud-file-storage/internal/app"
@@ -10,12 +11,20 @@ import (
 	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
 )
 
+const (
+	defaultCheckInterval = 10 * time.Minute
+	defaultCheckBatch    = 100
+)
+
+// FileChecker страховка для уведомлений бакета: редко проверяет версии, застрявшие в processing
+// дольше grace, на случай если уведомление о загрузке потерялось
 type FileChecker struct {
 	fileVersionService *file_version_service.FileVersionService
 	uow                app.UnitOfWork
 	storage            storage.Storage
 	interval           time.Duration
-	maxRetries         int
+	grace              time.Duration
+	batchSize          int
 }
 
 func NewFileChecker(
@@ -23,13 +32,22 @@ func NewFileChecker(
 	uow app.UnitOfWork,
 	storage storage.Storage,
 	interval time.Duration,
+	grace time.Duration,
+	batchSize int,
 ) *FileChecker {
+	if interval <= 0 {
+		interval = defaultCheckInterval
+	}
+	if batchSize <= 0 {
+		batchSize = defaultCheckBatch
+	}
 	return &FileChecker{
 		fileVersionService: fileVersionService,
 		uow:                uow,
 		storage:            storage,
 		interval:           interval,
-		maxRetries:         3,
+		grace:              grace,
+		batchSize:          batchSize,
 	}
 }
 
@@ -43,45 +61,28 @@ func (fc *FileChecker) Start(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			err := fc.checkAndUpdateFiles(ctx)
-			if err != nil {
-
+			if err := fc.checkAndUpdateFiles(ctx); err != nil {
+				log.Printf("FileChecker error: %v", err)
 			}
 		}
 	}
 }
 
-// checkAndUpdateFiles проверяет файлы в статусе "processing"
+// checkAndUpdateFiles проверяет одну пачку самых старых версий в статусе processing
 func (fc *FileChecker) checkAndUpdateFiles(ctx context.Context) error {
-
-	versions, err := fc.fileVersionService.GetVersionsByStatus(ctx, "processing")
+	versions, err := fc.fileVersionService.GetStaleProcessing(ctx, fc.grace, fc.batchSize)
 	if err != nil {
-		return fmt.Errorf("failed to get versions by status: %w", err)
+		return fmt.Errorf("failed to get processing versions: %w", err)
 	}
 
-	if len(versions) == 0 {
-		return nil
-	}
-
-	successCount := 0
-	errorCount := 0
-
 	for _, version := range versions {
-
 		exists, err := fc.storage.FileExists(ctx, version.S3Key.String())
-		if err != nil {
-			errorCount++
+		if err != nil || !exists {
 			continue
 		}
 
-		if exists {
-			err := fc.fileVersionService.CompleteUpload(ctx, version.ID)
-			if err != nil {
-				errorCount++
-				continue
-			}
-
-			successCount++
+		if err := fc.fileVersionService.CompleteUpload(ctx, version.ID); err != nil {
+			log.Printf("FileChecker failed to complete version %s: %v", version.ID, err)
 		}
 	}
 
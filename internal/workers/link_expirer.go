@@ -0,0 +1,143 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	public_link_service "github.com/yourusername/cloud-file-storage/internal/app/public_link"
+)
+
+var (
+	publicLinksExpiredTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "public_links_expired_total",
+			Help: "Total number of expired public links removed by link-expirer",
+		},
+		[]string{"mode"},
+	)
+
+	linkExpirerRunsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "link_expirer_runs_total",
+			Help: "Total number of link-expirer runs",
+		},
+		[]string{"status"},
+	)
+
+	linkExpirerRunDuration = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "link_expirer_run_duration_seconds",
+			Help:    "Time taken by one link-expirer run",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60},
+		},
+	)
+
+	linkExpirerLastSuccess = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "link_expirer_last_success_timestamp_seconds",
+			Help: "Unix time of the last successful link-expirer run",
+		},
+	)
+)
+
+// LinkExpirerWorker удаляет или архивирует публичные ссылки, истёкшие больше retention назад
+type LinkExpirerWorker struct {
+	publicLinkService *public_link_service.PublicLinkService
+	interval          time.Duration
+	retention         time.Duration
+	batchSize         int
+	archive           bool
+}
+
+const (
+	defaultLinkExpirerInterval = time.Hour
+	defaultLinkExpirerBatch    = 500
+)
+
+// NewLinkExpirerWorker retention держит истёкшие ссылки, чтобы владелец успел посмотреть журнал скачиваний;
+// нулевой retention — ссылки удаляются сразу после истечения
+func NewLinkExpirerWorker(publicLinkService *public_link_service.PublicLinkService, interval, retention time.Duration, batchSize int, archive bool) *LinkExpirerWorker {
+	if interval <= 0 {
+		interval = defaultLinkExpirerInterval
+	}
+	if retention < 0 {
+		retention = 0
+	}
+	if batchSize <= 0 {
+		batchSize = defaultLinkExpirerBatch
+	}
+	return &LinkExpirerWorker{
+		publicLinkService: publicLinkService,
+		interval:          interval,
+		retention:         retention,
+		batchSize:         batchSize,
+		archive:           archive,
+	}
+}
+
+// Start первый проход делает сразу: отдельный процесс не должен ждать interval после деплоя
+func (w *LinkExpirerWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	log.Println("LinkExpirerWorker started")
+
+	w.run(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("LinkExpirerWorker stopped by context")
+			return
+		case <-ticker.C:
+			w.run(ctx)
+		}
+	}
+}
+
+func (w *LinkExpirerWorker) run(ctx context.Context) {
+	started := time.Now()
+	removed, err := w.expire(ctx)
+	linkExpirerRunDuration.Observe(time.Since(started).Seconds())
+
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		linkExpirerRunsTotal.WithLabelValues("error").Inc()
+		log.Printf("LinkExpirerWorker error after %d links: %v", removed, err)
+		return
+	}
+
+	linkExpirerRunsTotal.WithLabelValues("success").Inc()
+	linkExpirerLastSuccess.SetToCurrentTime()
+	if removed > 0 {
+		log.Printf("LinkExpirerWorker removed %d expired links", removed)
+	}
+}
+
+// expire снимает пачки, пока не останется истёкших ссылок; каждая пачка — своя транзакция,
+// поэтому остановка между пачками ничего не теряет
+func (w *LinkExpirerWorker) expire(ctx context.Context) (int, error) {
+	mode := "delete"
+	if w.archive {
+		mode = "archive"
+	}
+
+	before := time.Now().Add(-w.retention)
+	total := 0
+	for ctx.Err() == nil {
+		n, err := w.publicLinkService.ExpireBatch(ctx, before, w.batchSize, w.archive)
+		if err != nil {
+			return total, err
+		}
+		publicLinksExpiredTotal.WithLabelValues(mode).Add(float64(n))
+		total += n
+		if n < w.batchSize {
+			break
+		}
+	}
+	return total, ctx.Err()
+}
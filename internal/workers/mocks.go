@@ -1,21 +1,40 @@
 package workers
 
 import (
+	"bytes"
 	"context"
+	"io"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
 )
 
 type MockStorage struct {
-	GenerateUploadURLFunc         func(ctx context.Context, key string, expiresIn time.Duration) (string, error)
-	GenerateUploadURLWithSizeFunc func(ctx context.Context, key string, expiresIn time.Duration, fileSize int64) (string, error)
-	GenerateDownloadURLFunc       func(ctx context.Context, key string, expiresIn time.Duration) (string, error)
-	DeleteFunc                    func(ctx context.Context, key string) error
+	GenerateUploadURLFunc             func(ctx context.Context, key string, expiresIn time.Duration) (string, error)
+	GenerateUploadURLWithSizeFunc     func(ctx context.Context, key string, expiresIn time.Duration, fileSize int64) (string, error)
+	GenerateUploadURLWithChecksumFunc func(ctx context.Context, key string, expiresIn time.Duration, fileSize int64, checksum storage.Checksum) (string, map[string]string, error)
+	GenerateDownloadURLFunc           func(ctx context.Context, key string, expiresIn time.Duration) (string, error)
+	DeleteFunc                        func(ctx context.Context, key string) error
 
-	UploadFileFunc   func(ctx context.Context, key string, fileData []byte) error
-	DownloadFileFunc func(ctx context.Context, key string) ([]byte, error)
-	FileExistsFunc   func(ctx context.Context, key string) (bool, error)
+	PutFunc        func(ctx context.Context, key string, body io.Reader, info storage.ObjectInfo) error
+	GetFunc        func(ctx context.Context, key string) (*storage.Object, error)
+	GetRangeFunc   func(ctx context.Context, key string, offset, length int64) (*storage.Object, error)
+	FileExistsFunc func(ctx context.Context, key string) (bool, error)
+	StatFunc       func(ctx context.Context, key string) (*storage.ObjectInfo, error)
+	ListFunc       func(ctx context.Context, prefix, startAfter string, limit int) ([]storage.ObjectSummary, error)
+	CopyFunc       func(ctx context.Context, srcKey, dstKey string) error
+
+	CreateMultipartUploadFunc   func(ctx context.Context, key string, contentType string) (string, error)
+	GenerateUploadPartURLFunc   func(ctx context.Context, key string, uploadID string, partNumber int32, expiresIn time.Duration) (string, error)
+	UploadPartFunc              func(ctx context.Context, key string, uploadID string, partNumber int32, data []byte) (string, error)
+	CompleteMultipartUploadFunc func(ctx context.Context, key string, uploadID string, parts []storage.CompletedPart) error
+	AbortMultipartUploadFunc    func(ctx context.Context, key string, uploadID string) error
+}
+
+// emptyObject значение по умолчанию, чтобы вызывающий код мог закрыть Body без проверки на nil
+func emptyObject() *storage.Object {
+	return &storage.Object{Body: io.NopCloser(bytes.NewReader(nil))}
 }
 
 func (m *MockStorage) GenerateUploadURL(ctx context.Context, key string, expiresIn time.Duration) (string, error) {
@@ -30,6 +49,12 @@ func (m *MockStorage) GenerateUploadURLWithSize(ctx context.Context, key string,
 	}
 	return "", nil
 }
+func (m *MockStorage) GenerateUploadURLWithChecksum(ctx context.Context, key string, expiresIn time.Duration, fileSize int64, checksum storage.Checksum) (string, map[string]string, error) {
+	if m.GenerateUploadURLWithChecksumFunc != nil {
+		return m.GenerateUploadURLWithChecksumFunc(ctx, key, expiresIn, fileSize, checksum)
+	}
+	return "", nil, nil
+}
 func (m *MockStorage) GenerateDownloadURL(ctx context.Context, key string, expiresIn time.Duration) (string, error) {
 	if m.GenerateDownloadURLFunc != nil {
 		return m.GenerateDownloadURLFunc(ctx, key, expiresIn)
@@ -42,17 +67,23 @@ func (m *MockStorage) Delete(ctx context.Context, key string) error {
 	}
 	return nil
 }
-func (m *MockStorage) UploadFile(ctx context.Context, key string, fileData []byte) error {
-	if m.UploadFileFunc != nil {
-		return m.UploadFileFunc(ctx, key, fileData)
+func (m *MockStorage) Put(ctx context.Context, key string, body io.Reader, info storage.ObjectInfo) error {
+	if m.PutFunc != nil {
+		return m.PutFunc(ctx, key, body, info)
 	}
 	return nil
 }
-func (m *MockStorage) DownloadFile(ctx context.Context, key string) ([]byte, error) {
-	if m.DownloadFileFunc != nil {
-		return m.DownloadFileFunc(ctx, key)
+func (m *MockStorage) Get(ctx context.Context, key string) (*storage.Object, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, key)
 	}
-	return nil, nil
+	return emptyObject(), nil
+}
+func (m *MockStorage) GetRange(ctx context.Context, key string, offset, length int64) (*storage.Object, error) {
+	if m.GetRangeFunc != nil {
+		return m.GetRangeFunc(ctx, key, offset, length)
+	}
+	return emptyObject(), nil
 }
 func (m *MockStorage) FileExists(ctx context.Context, key string) (bool, error) {
 	if m.FileExistsFunc != nil {
@@ -60,6 +91,54 @@ func (m *MockStorage) FileExists(ctx context.Context, key string) (bool, error)
 	}
 	return false, nil
 }
+func (m *MockStorage) Stat(ctx context.Context, key string) (*storage.ObjectInfo, error) {
+	if m.StatFunc != nil {
+		return m.StatFunc(ctx, key)
+	}
+	return &storage.ObjectInfo{}, nil
+}
+func (m *MockStorage) Copy(ctx context.Context, srcKey, dstKey string) error {
+	if m.CopyFunc != nil {
+		return m.CopyFunc(ctx, srcKey, dstKey)
+	}
+	return nil
+}
+func (m *MockStorage) List(ctx context.Context, prefix, startAfter string, limit int) ([]storage.ObjectSummary, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, prefix, startAfter, limit)
+	}
+	return nil, nil
+}
+func (m *MockStorage) CreateMultipartUpload(ctx context.Context, key string, contentType string) (string, error) {
+	if m.CreateMultipartUploadFunc != nil {
+		return m.CreateMultipartUploadFunc(ctx, key, contentType)
+	}
+	return "", nil
+}
+func (m *MockStorage) GenerateUploadPartURL(ctx context.Context, key string, uploadID string, partNumber int32, expiresIn time.Duration) (string, error) {
+	if m.GenerateUploadPartURLFunc != nil {
+		return m.GenerateUploadPartURLFunc(ctx, key, uploadID, partNumber, expiresIn)
+	}
+	return "", nil
+}
+func (m *MockStorage) UploadPart(ctx context.Context, key string, uploadID string, partNumber int32, data []byte) (string, error) {
+	if m.UploadPartFunc != nil {
+		return m.UploadPartFunc(ctx, key, uploadID, partNumber, data)
+	}
+	return "", nil
+}
+func (m *MockStorage) CompleteMultipartUpload(ctx context.Context, key string, uploadID string, parts []storage.CompletedPart) error {
+	if m.CompleteMultipartUploadFunc != nil {
+		return m.CompleteMultipartUploadFunc(ctx, key, uploadID, parts)
+	}
+	return nil
+}
+func (m *MockStorage) AbortMultipartUpload(ctx context.Context, key string, uploadID string) error {
+	if m.AbortMultipartUploadFunc != nil {
+		return m.AbortMultipartUploadFunc(ctx, key, uploadID)
+	}
+	return nil
+}
 
 // MockPreviewProducer - мок для PreviewProducer
 type MockPreviewProducer struct {
@@ -0,0 +1,71 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	multipart_upload_service "github.com/yourusername/cloud-file-storage/internal/app/multipart_upload"
+)
+
+// MultipartAbortWorker прерывает брошенные multipart загрузки, чтобы незавершённые части не занимали место в S3
+type MultipartAbortWorker struct {
+	multipartService *multipart_upload_service.MultipartUploadService
+	interval         time.Duration
+	batchSize        int
+}
+
+const (
+	defaultAbortInterval = 10 * time.Minute
+	defaultAbortBatch    = 100
+)
+
+func NewMultipartAbortWorker(multipartService *multipart_upload_service.MultipartUploadService, interval time.Duration, batchSize int) *MultipartAbortWorker {
+	if interval <= 0 {
+		interval = defaultAbortInterval
+	}
+	if batchSize <= 0 {
+		batchSize = defaultAbortBatch
+	}
+	return &MultipartAbortWorker{
+		multipartService: multipartService,
+		interval:         interval,
+		batchSize:        batchSize,
+	}
+}
+
+// Start запускает фоновый воркер
+func (w *MultipartAbortWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	log.Println("MultipartAbortWorker started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("MultipartAbortWorker stopped by context")
+			return
+		case <-ticker.C:
+			if err := w.abortExpired(ctx); err != nil {
+				log.Printf("MultipartAbortWorker error: %v", err)
+			}
+		}
+	}
+}
+
+func (w *MultipartAbortWorker) abortExpired(ctx context.Context) error {
+	uploads, err := w.multipartService.GetExpired(ctx, w.batchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get expired multipart uploads: %w", err)
+	}
+
+	for _, u := range uploads {
+		if err := w.multipartService.Abort(ctx, u.ID); err != nil {
+			log.Printf("MultipartAbortWorker failed to abort upload %s: %v", u.ID, err)
+		}
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"image"
 	"path/filepath"
 	"strings"
 	"time"
@@ -17,6 +18,15 @@ import (
 	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
 )
 
+const (
+	// Заголовка такого размера хватает image.DecodeConfig даже с крупным EXIF
+	previewHeaderSize = 256 * 1024
+	// Декодированное изображение занимает ~4 байта на пиксель, больше 50 Мп не раскрываем
+	maxPreviewPixels = 50_000_000
+)
+
+var errPreviewSourceTooLarge = errors.New("image is too large for preview")
+
 type PreviewWorker struct {
 	storage            storage.Storage
 	consumer           queue.PreviewConsumer
@@ -112,13 +122,18 @@ func (w *PreviewWorker) ProcessVersion(ctx context.Context, versionID uuid.UUID)
 			return fmt.Errorf("failed to create preview s3 key: %w", err)
 		}
 
-		if err := w.generateAndUploadImagePreview(ctx, origKey, genKey.String()); err != nil {
+		err = w.generateAndUploadImagePreview(ctx, origKey, genKey.String())
+		switch {
+		case err == nil:
+			previewKey = &genKey
+		case errors.Is(err, errPreviewSourceTooLarge):
+			// Слишком большие изображения получают превью по умолчанию
+		default:
 			return fmt.Errorf("failed to generate and upload image preview: %w", err)
 		}
+	}
 
-		previewKey = &genKey
-	} else {
-
+	if previewKey == nil {
 		genKey, err := file_version.NewS3Key(addPreviewSuffix("default_preview.svg"))
 		if err != nil {
 			return fmt.Errorf("failed to create default preview key: %w", err)
@@ -134,12 +149,17 @@ func (w *PreviewWorker) ProcessVersion(ctx context.Context, versionID uuid.UUID)
 }
 
 func (w *PreviewWorker) generateAndUploadImagePreview(ctx context.Context, fileKey, previewKey string) error {
-	imgData, err := w.storage.DownloadFile(ctx, fileKey)
+	if err := w.checkImageDimensions(ctx, fileKey); err != nil {
+		return err
+	}
+
+	obj, err := w.storage.Get(ctx, fileKey)
 	if err != nil {
 		return fmt.Errorf("failed to download file: %w", err)
 	}
+	defer obj.Body.Close()
 
-	img, err := imaging.Decode(bytes.NewReader(imgData))
+	img, err := imaging.Decode(obj.Body)
 	if err != nil {
 		return fmt.Errorf("failed to decode image: %w", err)
 	}
@@ -152,7 +172,10 @@ func (w *PreviewWorker) generateAndUploadImagePreview(ctx context.Context, fileK
 		return fmt.Errorf("failed to encode thumbnail: %w", err)
 	}
 
-	err = w.storage.UploadFile(ctx, previewKey, buf.Bytes())
+	err = w.storage.Put(ctx, previewKey, bytes.NewReader(buf.Bytes()), storage.ObjectInfo{
+		ContentType:   formatContentType(w.format),
+		ContentLength: int64(buf.Len()),
+	})
 	if err != nil {
 		return fmt.Errorf("failed to upload preview to storage: %w", err)
 	}
@@ -160,6 +183,42 @@ func (w *PreviewWorker) generateAndUploadImagePreview(ctx context.Context, fileK
 	return nil
 }
 
+// checkImageDimensions читает только заголовок изображения и отсекает те,
+// что при декодировании не поместятся в память воркера
+func (w *PreviewWorker) checkImageDimensions(ctx context.Context, fileKey string) error {
+	header, err := w.storage.GetRange(ctx, fileKey, 0, previewHeaderSize)
+	if err != nil {
+		return fmt.Errorf("failed to read image header: %w", err)
+	}
+	defer header.Body.Close()
+
+	cfg, _, err := image.DecodeConfig(header.Body)
+	if err != nil {
+		return fmt.Errorf("failed to decode image header: %w", err)
+	}
+
+	if int64(cfg.Width)*int64(cfg.Height) > maxPreviewPixels {
+		return errPreviewSourceTooLarge
+	}
+
+	return nil
+}
+
+func formatContentType(format imaging.Format) string {
+	switch format {
+	case imaging.JPEG:
+		return "image/jpeg"
+	case imaging.GIF:
+		return "image/gif"
+	case imaging.BMP:
+		return "image/bmp"
+	case imaging.TIFF:
+		return "image/tiff"
+	default:
+		return "image/png"
+	}
+}
+
 func isImageFile(fileKey string) bool {
 	ext := strings.ToLower(filepath.Ext(fileKey))
 	switch ext {
@@ -3,20 +3,25 @@ package workers
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
+	"hash/crc32"
 	"image"
 	"image/color"
 	"image/png"
+	"io"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	file_version_service "github.com/yourusername/cloud-file-storage/internal/app/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/file_version"
+	"github.com/yourusername/cloud-file-storage/internal/domain/storage"
 )
 
-func createTestPNG() []byte {
-	img := image.NewRGBA(image.Rect(0, 0, 100, 100))
-	for y := 0; y < 100; y++ {
-		for x := 0; x < 100; x++ {
+func createTestPNG(width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
 			img.Set(x, y, color.RGBA{255, 0, 0, 255})
 		}
 	}
@@ -28,12 +33,31 @@ func createTestPNG() []byte {
 	return buf.Bytes()
 }
 
+// pngHeader возвращает сигнатуру и IHDR без данных пикселей
+func pngHeader(width, height uint32) []byte {
+	data := createTestPNG(1, 1)[:33]
+	binary.BigEndian.PutUint32(data[16:20], width)
+	binary.BigEndian.PutUint32(data[20:24], height)
+	binary.BigEndian.PutUint32(data[29:33], crc32.ChecksumIEEE(data[12:29]))
+	return data
+}
+
+func pngObject(data []byte) *storage.Object {
+	return &storage.Object{
+		Body:       io.NopCloser(bytes.NewReader(data)),
+		ObjectInfo: storage.ObjectInfo{ContentType: "image/png", ContentLength: int64(len(data))},
+	}
+}
+
 func TestPreviewWorker_Handle(t *testing.T) {
 	mockStorage := &MockStorage{
-		DownloadFileFunc: func(ctx context.Context, key string) ([]byte, error) {
-			return createTestPNG(), nil
+		GetFunc: func(ctx context.Context, key string) (*storage.Object, error) {
+			return pngObject(createTestPNG(100, 100)), nil
 		},
-		UploadFileFunc: func(ctx context.Context, key string, fileData []byte) error {
+		GetRangeFunc: func(ctx context.Context, key string, offset, length int64) (*storage.Object, error) {
+			return pngObject(createTestPNG(100, 100)), nil
+		},
+		PutFunc: func(ctx context.Context, key string, body io.Reader, info storage.ObjectInfo) error {
 			return nil
 		},
 	}
@@ -62,3 +86,69 @@ func TestPreviewWorker_Handle(t *testing.T) {
 		t.Errorf("expected at least 2 calls, got %d", callCount)
 	}
 }
+
+func TestPreviewWorker_ProcessVersion_StreamsImage(t *testing.T) {
+	var putInfo storage.ObjectInfo
+	var putData []byte
+	mockStorage := &MockStorage{
+		GetFunc: func(ctx context.Context, key string) (*storage.Object, error) {
+			return pngObject(createTestPNG(400, 300)), nil
+		},
+		GetRangeFunc: func(ctx context.Context, key string, offset, length int64) (*storage.Object, error) {
+			return pngObject(createTestPNG(400, 300)), nil
+		},
+		PutFunc: func(ctx context.Context, key string, body io.Reader, info storage.ObjectInfo) error {
+			putInfo = info
+			var err error
+			putData, err = io.ReadAll(body)
+			return err
+		},
+	}
+
+	key, _ := file_version.NewS3Key("files/owner/file/v1/photo.png")
+	mockService := &file_version_service.MockFileVersionService{
+		GetVersionByIDFunc: func(ctx context.Context, versionID uuid.UUID) (*file_version.FileVersion, error) {
+			return &file_version.FileVersion{ID: versionID, S3Key: key}, nil
+		},
+	}
+
+	worker := NewPreviewWorker(mockStorage, &MockPreviewConsumer{}, mockService)
+	if err := worker.ProcessVersion(context.Background(), uuid.New()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if putInfo.ContentType != "image/png" || putInfo.ContentLength != int64(len(putData)) {
+		t.Errorf("unexpected preview info: %+v, %d bytes", putInfo, len(putData))
+	}
+	if len(mockService.UpdatePreviewCalls) != 1 || mockService.UpdatePreviewCalls[0].PreviewKey.String() != "files/owner/file/v1/photo_preview.png" {
+		t.Errorf("unexpected preview updates: %+v", mockService.UpdatePreviewCalls)
+	}
+}
+
+func TestPreviewWorker_ProcessVersion_TooLargeImage(t *testing.T) {
+	mockStorage := &MockStorage{
+		GetRangeFunc: func(ctx context.Context, key string, offset, length int64) (*storage.Object, error) {
+			return pngObject(pngHeader(10000, 10000)), nil
+		},
+		GetFunc: func(ctx context.Context, key string) (*storage.Object, error) {
+			t.Fatal("full object must not be downloaded")
+			return nil, nil
+		},
+	}
+
+	key, _ := file_version.NewS3Key("files/owner/file/v1/huge.png")
+	mockService := &file_version_service.MockFileVersionService{
+		GetVersionByIDFunc: func(ctx context.Context, versionID uuid.UUID) (*file_version.FileVersion, error) {
+			return &file_version.FileVersion{ID: versionID, S3Key: key}, nil
+		},
+	}
+
+	worker := NewPreviewWorker(mockStorage, &MockPreviewConsumer{}, mockService)
+	if err := worker.ProcessVersion(context.Background(), uuid.New()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(mockService.UpdatePreviewCalls) != 1 || mockService.UpdatePreviewCalls[0].PreviewKey.String() != "default_preview_preview.svg" {
+		t.Errorf("expected default preview, got %+v", mockService.UpdatePreviewCalls)
+	}
+}
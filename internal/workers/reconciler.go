@@ -0,0 +1,64 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	reconcile_service "github.com/yourusername/cloud-file-storage/internal/app/reconcile"
+)
+
+const defaultReconcileInterval = 24 * time.Hour
+
+// ReconcilerWorker периодически сверяет бакет с БД. Без deleteOrphans только пишет в лог,
+// что нашёл: удаление включают, когда отчёты проверены вручную.
+type ReconcilerWorker struct {
+	reconcileService *reconcile_service.ReconcileService
+	interval         time.Duration
+	deleteOrphans    bool
+}
+
+func NewReconcilerWorker(reconcileService *reconcile_service.ReconcileService, interval time.Duration, deleteOrphans bool) *ReconcilerWorker {
+	if interval <= 0 {
+		interval = defaultReconcileInterval
+	}
+	return &ReconcilerWorker{
+		reconcileService: reconcileService,
+		interval:         interval,
+		deleteOrphans:    deleteOrphans,
+	}
+}
+
+// Start запускает фоновый воркер
+func (w *ReconcilerWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	log.Println("ReconcilerWorker started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("ReconcilerWorker stopped by context")
+			return
+		case <-ticker.C:
+			w.reconcile(ctx)
+		}
+	}
+}
+
+func (w *ReconcilerWorker) reconcile(ctx context.Context) {
+	report, err := w.reconcileService.Reconcile(ctx, w.deleteOrphans)
+	if err != nil {
+		log.Printf("ReconcilerWorker error: %v", err)
+	}
+
+	for _, o := range report.Orphans {
+		log.Printf("ReconcilerWorker orphaned object %s (%d bytes, modified %s)", o.Key, o.Size, o.LastModified.UTC().Format(time.RFC3339))
+	}
+	for _, m := range report.Missing {
+		log.Printf("ReconcilerWorker version %s of file %s has no object %s", m.VersionID, m.FileID, m.Key)
+	}
+	log.Printf("ReconcilerWorker scanned %d objects: %d orphaned, %d deleted, %d versions without object",
+		report.Scanned, len(report.Orphans), report.Deleted, len(report.Missing))
+}
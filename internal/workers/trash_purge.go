@@ -0,0 +1,80 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	file_service "github.com/yourusername/cloud-file-storage/internal/app/file"
+)
+
+// TrashPurgeWorker окончательно удаляет файлы, пролежавшие в корзине дольше retention
+type TrashPurgeWorker struct {
+	fileService *file_service.FileService
+	retention   time.Duration
+	interval    time.Duration
+	batchSize   int
+}
+
+const (
+	defaultTrashRetention = 30 * 24 * time.Hour
+	defaultPurgeInterval  = time.Hour
+	defaultPurgeBatch     = 100
+)
+
+// NewTrashPurgeWorker нулевые значения заменяются значениями по умолчанию,
+// чтобы незаданный retention не приводил к мгновенной очистке корзины
+func NewTrashPurgeWorker(fileService *file_service.FileService, retention, interval time.Duration, batchSize int) *TrashPurgeWorker {
+	if retention <= 0 {
+		retention = defaultTrashRetention
+	}
+	if interval <= 0 {
+		interval = defaultPurgeInterval
+	}
+	if batchSize <= 0 {
+		batchSize = defaultPurgeBatch
+	}
+	return &TrashPurgeWorker{
+		fileService: fileService,
+		retention:   retention,
+		interval:    interval,
+		batchSize:   batchSize,
+	}
+}
+
+// Start запускает фоновый воркер
+func (w *TrashPurgeWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	log.Println("TrashPurgeWorker started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("TrashPurgeWorker stopped by context")
+			return
+		case <-ticker.C:
+			if err := w.purgeExpired(ctx); err != nil {
+				log.Printf("TrashPurgeWorker error: %v", err)
+			}
+		}
+	}
+}
+
+// purgeExpired удаляет одну пачку просроченных файлов; ошибка по одному файлу не останавливает пачку
+func (w *TrashPurgeWorker) purgeExpired(ctx context.Context) error {
+	files, err := w.fileService.GetExpiredTrash(ctx, w.retention, w.batchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get expired trash: %w", err)
+	}
+
+	for _, f := range files {
+		if err := w.fileService.Purge(ctx, f.ID); err != nil {
+			log.Printf("TrashPurgeWorker failed to purge file %s: %v", f.ID, err)
+		}
+	}
+
+	return nil
+}
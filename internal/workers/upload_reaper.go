@@ -0,0 +1,81 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	file_version_service "github.com/yourusername/cloud-file-storage/internal/app/file_version"
+)
+
+// UploadReaperWorker снимает загрузки, которые клиент создал, но так и не выполнил:
+// иначе версии остаются в processing навсегда, а FileChecker проверяет их каждый цикл
+type UploadReaperWorker struct {
+	versionService *file_version_service.FileVersionService
+	abandonAfter   time.Duration
+	interval       time.Duration
+	batchSize      int
+}
+
+const (
+	defaultAbandonAfter  = 24 * time.Hour
+	defaultReapInterval  = time.Hour
+	defaultReapBatchSize = 100
+)
+
+// NewUploadReaperWorker abandonAfter должен быть больше срока presigned URL, иначе
+// воркер снимет загрузку, которую клиент ещё выполняет
+func NewUploadReaperWorker(versionService *file_version_service.FileVersionService, abandonAfter, interval time.Duration, batchSize int) *UploadReaperWorker {
+	if abandonAfter <= 0 {
+		abandonAfter = defaultAbandonAfter
+	}
+	if interval <= 0 {
+		interval = defaultReapInterval
+	}
+	if batchSize <= 0 {
+		batchSize = defaultReapBatchSize
+	}
+	return &UploadReaperWorker{
+		versionService: versionService,
+		abandonAfter:   abandonAfter,
+		interval:       interval,
+		batchSize:      batchSize,
+	}
+}
+
+// Start запускает фоновый воркер
+func (w *UploadReaperWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	log.Println("UploadReaperWorker started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("UploadReaperWorker stopped by context")
+			return
+		case <-ticker.C:
+			if err := w.reapAbandoned(ctx); err != nil {
+				log.Printf("UploadReaperWorker error: %v", err)
+			}
+		}
+	}
+}
+
+// reapAbandoned снимает одну пачку брошенных загрузок; ошибка по одной версии не останавливает пачку
+func (w *UploadReaperWorker) reapAbandoned(ctx context.Context) error {
+	versions, err := w.versionService.GetAbandonedUploads(ctx, w.abandonAfter, w.batchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get abandoned uploads: %w", err)
+	}
+
+	for _, v := range versions {
+		if _, err := w.versionService.AbandonUpload(ctx, v.ID); err != nil {
+			log.Printf("UploadReaperWorker failed to abandon version %s: %v", v.ID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+-- Удаление привязки файлов к папкам
+DROP INDEX IF EXISTS idx_files_owner_folder;
+ALTER TABLE files DROP CONSTRAINT IF EXISTS fk_files_folder_id;
+ALTER TABLE files DROP COLUMN IF EXISTS folder_id;
+
+-- Удаление триггера
+DROP TRIGGER IF EXISTS update_folders_updated_at ON folders;
+
+-- Удаление индексов
+DROP INDEX IF EXISTS idx_folders_owner_parent_name;
+DROP INDEX IF EXISTS idx_folders_parent_id;
+DROP INDEX IF EXISTS idx_folders_owner_id;
+
+-- Удаление таблицы
+DROP TABLE IF EXISTS folders;
@@ -0,0 +1,57 @@
+-- Создание таблицы папок (иерархия через parent_id)
+CREATE TABLE IF NOT EXISTS folders (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id UUID NOT NULL,
+    parent_id UUID NULL,
+
+    name VARCHAR(255) NOT NULL,
+
+    -- Временные метки
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    -- Внешние ключи
+    CONSTRAINT fk_folders_owner_id
+        FOREIGN KEY (owner_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+
+    -- Удаление вложенных папок выполняет сервис, чтобы каскадно удалить файлы
+    CONSTRAINT fk_folders_parent_id
+        FOREIGN KEY (parent_id)
+        REFERENCES folders(id),
+
+    CONSTRAINT chk_folders_not_self_parent CHECK (parent_id IS NULL OR parent_id <> id)
+);
+
+-- Индексы для оптимизации запросов
+CREATE INDEX idx_folders_owner_id ON folders(owner_id);
+CREATE INDEX idx_folders_parent_id ON folders(parent_id);
+
+-- Уникальность имени среди соседних папок (NULL parent_id = корень)
+CREATE UNIQUE INDEX idx_folders_owner_parent_name
+    ON folders(owner_id, COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'::uuid), name);
+
+-- Привязка файлов к папкам
+ALTER TABLE files
+ADD COLUMN folder_id UUID NULL;
+
+ALTER TABLE files
+ADD CONSTRAINT fk_files_folder_id
+    FOREIGN KEY (folder_id)
+    REFERENCES folders(id);
+
+CREATE INDEX idx_files_owner_folder ON files(owner_id, folder_id, created_at DESC);
+
+-- Комментарии для документации
+COMMENT ON TABLE folders IS 'Таблица папок пользователя';
+COMMENT ON COLUMN folders.owner_id IS 'ID владельца папки';
+COMMENT ON COLUMN folders.parent_id IS 'ID родительской папки (NULL для корня)';
+COMMENT ON COLUMN folders.name IS 'Имя папки (value object FolderName)';
+COMMENT ON COLUMN files.folder_id IS 'ID папки файла (NULL для корня)';
+
+-- Триггер для автоматического обновления updated_at
+CREATE TRIGGER update_folders_updated_at
+    BEFORE UPDATE ON folders
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,12 @@
+-- Возврат внешнего ключа без SET NULL
+ALTER TABLE files DROP CONSTRAINT IF EXISTS fk_files_folder_id;
+ALTER TABLE files
+ADD CONSTRAINT fk_files_folder_id
+    FOREIGN KEY (folder_id)
+    REFERENCES folders(id);
+
+-- Удаление индекса
+DROP INDEX IF EXISTS idx_files_deleted_at;
+
+-- Удаление колонки
+ALTER TABLE files DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,16 @@
+-- Мягкое удаление файлов (корзина)
+ALTER TABLE files
+ADD COLUMN deleted_at TIMESTAMP NULL;
+
+-- Индекс для очистки корзины по сроку хранения
+CREATE INDEX idx_files_deleted_at ON files(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Файлы из удалённой папки остаются в корзине и восстанавливаются в корень
+ALTER TABLE files DROP CONSTRAINT IF EXISTS fk_files_folder_id;
+ALTER TABLE files
+ADD CONSTRAINT fk_files_folder_id
+    FOREIGN KEY (folder_id)
+    REFERENCES folders(id)
+    ON DELETE SET NULL;
+
+COMMENT ON COLUMN files.deleted_at IS 'Время перемещения файла в корзину (NULL для активных файлов)';
@@ -0,0 +1,9 @@
+-- Удаление триггера
+DROP TRIGGER IF EXISTS update_multipart_uploads_updated_at ON multipart_uploads;
+
+-- Удаление индексов
+DROP INDEX IF EXISTS idx_multipart_uploads_active_expires_at;
+DROP INDEX IF EXISTS idx_multipart_uploads_version_id;
+
+-- Удаление таблицы
+DROP TABLE IF EXISTS multipart_uploads;
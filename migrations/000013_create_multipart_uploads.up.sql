@@ -0,0 +1,54 @@
+-- Создание таблицы multipart загрузок в S3
+CREATE TABLE IF NOT EXISTS multipart_uploads (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    file_id UUID NOT NULL,
+    version_id UUID NOT NULL,
+    owner_id UUID NOT NULL,
+
+    s3_key VARCHAR(1024) NOT NULL,
+    upload_id VARCHAR(1024) NOT NULL,
+
+    part_size BIGINT NOT NULL,
+    part_count INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'initiated',
+
+    -- Временные метки
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL,
+
+    -- Внешние ключи
+    CONSTRAINT fk_multipart_uploads_file_id
+        FOREIGN KEY (file_id)
+        REFERENCES files(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_multipart_uploads_version_id
+        FOREIGN KEY (version_id)
+        REFERENCES file_versions(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT chk_multipart_uploads_status CHECK (status IN ('initiated', 'completed', 'aborted')),
+    CONSTRAINT chk_multipart_uploads_part_count CHECK (part_count BETWEEN 1 AND 10000)
+);
+
+-- Индексы для оптимизации запросов
+CREATE INDEX idx_multipart_uploads_version_id ON multipart_uploads(version_id);
+
+-- Поиск просроченных незавершённых загрузок для воркера
+CREATE INDEX idx_multipart_uploads_active_expires_at
+    ON multipart_uploads(expires_at)
+    WHERE status = 'initiated';
+
+-- Комментарии для документации
+COMMENT ON TABLE multipart_uploads IS 'Таблица multipart загрузок больших файлов';
+COMMENT ON COLUMN multipart_uploads.upload_id IS 'UploadId, выданный S3';
+COMMENT ON COLUMN multipart_uploads.part_size IS 'Размер части в байтах (последняя часть может быть меньше)';
+COMMENT ON COLUMN multipart_uploads.status IS 'Статус загрузки: initiated, completed, aborted';
+COMMENT ON COLUMN multipart_uploads.expires_at IS 'После этого времени незавершённая загрузка прерывается воркером';
+
+-- Триггер для автоматического обновления updated_at
+CREATE TRIGGER update_multipart_uploads_updated_at
+    BEFORE UPDATE ON multipart_uploads
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,9 @@
+-- Удаление триггера
+DROP TRIGGER IF EXISTS update_tus_uploads_updated_at ON tus_uploads;
+
+-- Удаление индексов
+DROP INDEX IF EXISTS idx_tus_uploads_version_id;
+DROP INDEX IF EXISTS idx_tus_uploads_owner_id;
+
+-- Удаление таблицы
+DROP TABLE IF EXISTS tus_uploads;
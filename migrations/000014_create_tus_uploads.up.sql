@@ -0,0 +1,49 @@
+-- Создание таблицы возобновляемых загрузок по протоколу tus
+CREATE TABLE IF NOT EXISTS tus_uploads (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    file_id UUID NOT NULL,
+    version_id UUID NOT NULL,
+    owner_id UUID NOT NULL,
+
+    s3_key VARCHAR(1024) NOT NULL,
+    multipart_id VARCHAR(1024) NOT NULL,
+
+    upload_length BIGINT NOT NULL,
+    upload_offset BIGINT NOT NULL DEFAULT 0,
+    pending_size BIGINT NOT NULL DEFAULT 0,
+    parts JSONB NOT NULL DEFAULT '[]'::jsonb,
+
+    -- Временные метки
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    -- Внешние ключи
+    CONSTRAINT fk_tus_uploads_file_id
+        FOREIGN KEY (file_id)
+        REFERENCES files(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_tus_uploads_version_id
+        FOREIGN KEY (version_id)
+        REFERENCES file_versions(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT chk_tus_uploads_offset CHECK (upload_offset BETWEEN 0 AND upload_length)
+);
+
+-- Индексы для оптимизации запросов
+CREATE INDEX idx_tus_uploads_owner_id ON tus_uploads(owner_id);
+CREATE INDEX idx_tus_uploads_version_id ON tus_uploads(version_id);
+
+-- Комментарии для документации
+COMMENT ON TABLE tus_uploads IS 'Таблица возобновляемых загрузок (tus 1.0)';
+COMMENT ON COLUMN tus_uploads.multipart_id IS 'UploadId multipart загрузки в S3';
+COMMENT ON COLUMN tus_uploads.upload_offset IS 'Количество принятых байт (Upload-Offset)';
+COMMENT ON COLUMN tus_uploads.pending_size IS 'Размер хвоста, ещё не отправленного частью в S3';
+COMMENT ON COLUMN tus_uploads.parts IS 'Загруженные части S3: [{part_number, etag}]';
+
+-- Триггер для автоматического обновления updated_at
+CREATE TRIGGER update_tus_uploads_updated_at
+    BEFORE UPDATE ON tus_uploads
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,10 @@
+-- Удаление ссылки версий на blob
+DROP INDEX IF EXISTS idx_file_versions_content_hash;
+ALTER TABLE file_versions DROP CONSTRAINT IF EXISTS fk_file_versions_content_hash;
+ALTER TABLE file_versions DROP COLUMN IF EXISTS content_hash;
+
+-- Удаление триггера
+DROP TRIGGER IF EXISTS update_blobs_updated_at ON blobs;
+
+-- Удаление таблицы
+DROP TABLE IF EXISTS blobs;
@@ -0,0 +1,33 @@
+-- Создание таблицы blob: содержимое, общее для версий с одинаковым SHA-256
+CREATE TABLE IF NOT EXISTS blobs (
+    hash CHAR(64) PRIMARY KEY,
+    s3_key VARCHAR(1000) NOT NULL,
+    size BIGINT NOT NULL CHECK (size >= 0),
+    ref_count INT NOT NULL CHECK (ref_count >= 0),
+
+    -- Временные метки
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Комментарии для документации
+COMMENT ON TABLE blobs IS 'Дедуплицированное содержимое версий файлов';
+COMMENT ON COLUMN blobs.s3_key IS 'Ключ объекта, с которым содержимое было загружено впервые';
+COMMENT ON COLUMN blobs.ref_count IS 'Количество версий, ссылающихся на blob';
+
+-- Триггер для автоматического обновления updated_at
+CREATE TRIGGER update_blobs_updated_at
+    BEFORE UPDATE ON blobs
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Хеш содержимого версии; NULL, пока загрузка не завершена, и у версий, загруженных до дедупликации
+ALTER TABLE file_versions
+ADD COLUMN content_hash CHAR(64) NULL;
+
+ALTER TABLE file_versions
+ADD CONSTRAINT fk_file_versions_content_hash
+    FOREIGN KEY (content_hash)
+    REFERENCES blobs(hash);
+
+CREATE INDEX idx_file_versions_content_hash ON file_versions(content_hash);
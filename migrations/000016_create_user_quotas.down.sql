@@ -0,0 +1,5 @@
+-- Удаление триггера
+DROP TRIGGER IF EXISTS update_user_quotas_updated_at ON user_quotas;
+
+-- Удаление таблицы
+DROP TABLE IF EXISTS user_quotas;
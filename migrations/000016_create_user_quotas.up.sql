@@ -0,0 +1,25 @@
+-- Создание таблицы квот: лимит и занятый объём хранилища пользователя
+CREATE TABLE IF NOT EXISTS user_quotas (
+    user_id UUID PRIMARY KEY,
+    limit_bytes BIGINT NOT NULL CHECK (limit_bytes >= 0),
+    used_bytes BIGINT NOT NULL DEFAULT 0 CHECK (used_bytes >= 0),
+
+    -- Временные метки
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_user_quotas_user_id
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+-- Комментарии для документации
+COMMENT ON TABLE user_quotas IS 'Квоты пользователей на объём хранилища';
+COMMENT ON COLUMN user_quotas.used_bytes IS 'Суммарный размер всех версий файлов пользователя, включая незавершённые загрузки и корзину';
+
+-- Триггер для автоматического обновления updated_at
+CREATE TRIGGER update_user_quotas_updated_at
+    BEFORE UPDATE ON user_quotas
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
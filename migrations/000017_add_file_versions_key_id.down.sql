@@ -0,0 +1,3 @@
+-- Удаление идентификатора мастер-ключа версий
+DROP INDEX IF EXISTS idx_file_versions_key_id;
+ALTER TABLE file_versions DROP COLUMN IF EXISTS key_id;
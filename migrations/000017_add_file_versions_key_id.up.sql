@@ -0,0 +1,8 @@
+-- Мастер-ключ, которым обёрнут ключ данных объекта версии; NULL — объект не зашифрован
+ALTER TABLE file_versions
+ADD COLUMN key_id VARCHAR(255) NULL;
+
+COMMENT ON COLUMN file_versions.key_id IS 'Идентификатор мастер-ключа envelope шифрования объекта';
+
+-- Ротация ключей ищет версии, зашифрованные не текущим ключом
+CREATE INDEX idx_file_versions_key_id ON file_versions(key_id);
@@ -0,0 +1,7 @@
+-- Удаление контрольных сумм и причины ошибки
+ALTER TABLE files DROP COLUMN IF EXISTS checksum;
+ALTER TABLE files DROP COLUMN IF EXISTS checksum_algorithm;
+
+ALTER TABLE file_versions DROP COLUMN IF EXISTS failure_reason;
+ALTER TABLE file_versions DROP COLUMN IF EXISTS checksum;
+ALTER TABLE file_versions DROP COLUMN IF EXISTS checksum_algorithm;
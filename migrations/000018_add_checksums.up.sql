@@ -0,0 +1,17 @@
+-- Контрольная сумма, заявленная клиентом при загрузке; NULL — клиент её не передал
+ALTER TABLE file_versions
+ADD COLUMN checksum_algorithm VARCHAR(16) NULL,
+ADD COLUMN checksum VARCHAR(64) NULL,
+ADD COLUMN failure_reason TEXT NULL;
+
+COMMENT ON COLUMN file_versions.checksum_algorithm IS 'Алгоритм контрольной суммы: sha256 или crc32c';
+COMMENT ON COLUMN file_versions.checksum IS 'Контрольная сумма содержимого в base64';
+COMMENT ON COLUMN file_versions.failure_reason IS 'Причина перехода версии в статус failed';
+
+-- Файл хранит контрольную сумму текущей версии, чтобы клиенты синхронизации не запрашивали версии
+ALTER TABLE files
+ADD COLUMN checksum_algorithm VARCHAR(16) NULL,
+ADD COLUMN checksum VARCHAR(64) NULL;
+
+COMMENT ON COLUMN files.checksum_algorithm IS 'Алгоритм контрольной суммы текущей версии';
+COMMENT ON COLUMN files.checksum IS 'Контрольная сумма текущей версии в base64';
@@ -0,0 +1,3 @@
+-- Удаление индексов по ключам объектов
+DROP INDEX IF EXISTS idx_blobs_s3_key;
+DROP INDEX IF EXISTS idx_file_versions_preview_s3_key;
@@ -0,0 +1,3 @@
+-- Индексы для сверки объектов хранилища с БД: по ключу ищутся превью и blob
+CREATE INDEX IF NOT EXISTS idx_file_versions_preview_s3_key ON file_versions(preview_s3_key);
+CREATE INDEX IF NOT EXISTS idx_blobs_s3_key ON blobs(s3_key);
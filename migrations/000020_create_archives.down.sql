@@ -0,0 +1,9 @@
+-- Удаление триггера
+DROP TRIGGER IF EXISTS update_archives_updated_at ON archives;
+
+-- Удаление индексов
+DROP INDEX IF EXISTS idx_archives_expires_at;
+DROP INDEX IF EXISTS idx_archives_unfinished_created_at;
+
+-- Удаление таблицы
+DROP TABLE IF EXISTS archives;
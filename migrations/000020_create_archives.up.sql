@@ -0,0 +1,45 @@
+-- Создание таблицы ZIP архивов, которые собираются воркером
+CREATE TABLE IF NOT EXISTS archives (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id UUID NOT NULL,
+
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    entries JSONB NOT NULL DEFAULT '[]'::jsonb,
+    size BIGINT NOT NULL DEFAULT 0,
+    s3_key VARCHAR(1024) NOT NULL,
+    failure_reason TEXT NULL,
+
+    -- Временные метки
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL,
+
+    -- Внешние ключи
+    CONSTRAINT fk_archives_owner_id
+        FOREIGN KEY (owner_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT chk_archives_status CHECK (status IN ('pending', 'building', 'ready', 'failed'))
+);
+
+-- Очередь сборки для воркера
+CREATE INDEX idx_archives_unfinished_created_at
+    ON archives(created_at)
+    WHERE status IN ('pending', 'building');
+
+-- Поиск архивов с истёкшим сроком хранения
+CREATE INDEX idx_archives_expires_at ON archives(expires_at);
+
+-- Комментарии для документации
+COMMENT ON TABLE archives IS 'ZIP архивы нескольких файлов, собираемые асинхронно';
+COMMENT ON COLUMN archives.entries IS 'Содержимое архива: [{name, s3_key, mime, size, modified_at}]';
+COMMENT ON COLUMN archives.size IS 'Суммарный размер файлов до сжатия';
+COMMENT ON COLUMN archives.s3_key IS 'Временный объект с собранным архивом';
+COMMENT ON COLUMN archives.expires_at IS 'После этого времени объект архива и запись удаляются воркером';
+
+-- Триггер для автоматического обновления updated_at
+CREATE TRIGGER update_archives_updated_at
+    BEFORE UPDATE ON archives
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
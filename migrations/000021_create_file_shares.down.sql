@@ -0,0 +1,8 @@
+-- Удаление триггера
+DROP TRIGGER IF EXISTS update_file_shares_updated_at ON file_shares;
+
+-- Удаление индексов
+DROP INDEX IF EXISTS idx_file_shares_user_id;
+
+-- Удаление таблицы
+DROP TABLE IF EXISTS file_shares;
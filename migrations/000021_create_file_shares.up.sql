@@ -0,0 +1,45 @@
+-- Создание таблицы доступов пользователей к чужим файлам
+CREATE TABLE IF NOT EXISTS file_shares (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    file_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    granted_by UUID NOT NULL,
+
+    -- Временные метки
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    -- Внешние ключи
+    CONSTRAINT fk_file_shares_file_id
+        FOREIGN KEY (file_id)
+        REFERENCES files(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_file_shares_user_id
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_file_shares_granted_by
+        FOREIGN KEY (granted_by)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT uq_file_shares_file_user UNIQUE (file_id, user_id),
+    CONSTRAINT chk_file_shares_role CHECK (role IN ('viewer', 'editor', 'owner'))
+);
+
+-- Список «доступные мне»
+CREATE INDEX idx_file_shares_user_id ON file_shares(user_id);
+
+-- Комментарии для документации
+COMMENT ON TABLE file_shares IS 'Доступы зарегистрированных пользователей к файлам других пользователей';
+COMMENT ON COLUMN file_shares.role IS 'viewer — чтение и скачивание, editor — новые версии и переименование, owner — управление файлом и доступами';
+COMMENT ON COLUMN file_shares.granted_by IS 'Пользователь, выдавший или последним изменивший доступ';
+
+-- Триггер для автоматического обновления updated_at
+CREATE TRIGGER update_file_shares_updated_at
+    BEFORE UPDATE ON file_shares
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,5 @@
+-- Удаление таблицы попыток
+DROP TABLE IF EXISTS public_link_unlock_attempts;
+
+-- Удаление колонки
+ALTER TABLE public_links DROP COLUMN IF EXISTS password_hash;
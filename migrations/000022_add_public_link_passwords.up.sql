@@ -0,0 +1,25 @@
+-- Необязательный пароль публичной ссылки
+ALTER TABLE public_links
+ADD COLUMN password_hash VARCHAR(255) NULL;
+
+COMMENT ON COLUMN public_links.password_hash IS 'bcrypt хеш пароля (NULL — ссылка без пароля)';
+
+-- Неудачные попытки ввести пароль ссылки по IP
+CREATE TABLE IF NOT EXISTS public_link_unlock_attempts (
+    link_id UUID NOT NULL,
+    ip VARCHAR(45) NOT NULL,
+    failures INT NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (link_id, ip),
+
+    CONSTRAINT fk_public_link_unlock_attempts_link_id
+        FOREIGN KEY (link_id)
+        REFERENCES public_links(id)
+        ON DELETE CASCADE
+);
+
+COMMENT ON TABLE public_link_unlock_attempts IS 'Счётчики неверных паролей публичных ссылок для блокировки перебора';
+COMMENT ON COLUMN public_link_unlock_attempts.failures IS 'Неверные пароли с последней блокировки';
+COMMENT ON COLUMN public_link_unlock_attempts.locked_until IS 'До этого времени ввод пароля с IP отклоняется';
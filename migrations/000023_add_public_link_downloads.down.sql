@@ -0,0 +1,8 @@
+-- Удаление журнала скачиваний
+DROP TABLE IF EXISTS public_link_accesses;
+
+-- Удаление колонок
+ALTER TABLE public_links
+DROP CONSTRAINT IF EXISTS chk_public_links_max_downloads,
+DROP COLUMN IF EXISTS download_count,
+DROP COLUMN IF EXISTS max_downloads;
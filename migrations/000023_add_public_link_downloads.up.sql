@@ -0,0 +1,35 @@
+-- Лимит и счётчик скачиваний публичной ссылки
+ALTER TABLE public_links
+ADD COLUMN max_downloads INT NULL,
+ADD COLUMN download_count INT NOT NULL DEFAULT 0,
+ADD CONSTRAINT chk_public_links_max_downloads CHECK (max_downloads IS NULL OR max_downloads > 0);
+
+COMMENT ON COLUMN public_links.max_downloads IS 'Сколько раз можно скачать по ссылке (NULL — без ограничения)';
+COMMENT ON COLUMN public_links.download_count IS 'Сколько раз по ссылке уже скачивали';
+
+-- Журнал скачиваний по публичным ссылкам
+CREATE TABLE IF NOT EXISTS public_link_accesses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    link_id UUID NOT NULL,
+    version_id UUID NULL,
+    ip VARCHAR(45) NOT NULL,
+    user_agent TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    -- Внешние ключи
+    CONSTRAINT fk_public_link_accesses_link_id
+        FOREIGN KEY (link_id)
+        REFERENCES public_links(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_public_link_accesses_version_id
+        FOREIGN KEY (version_id)
+        REFERENCES file_versions(id)
+        ON DELETE SET NULL
+);
+
+-- Журнал ссылки от новых записей к старым
+CREATE INDEX idx_public_link_accesses_link_created ON public_link_accesses(link_id, created_at DESC);
+
+COMMENT ON TABLE public_link_accesses IS 'Скачивания по публичным ссылкам';
+COMMENT ON COLUMN public_link_accesses.version_id IS 'Отданная версия файла (NULL — версия удалена)';
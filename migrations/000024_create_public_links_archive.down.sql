@@ -0,0 +1,2 @@
+-- Удаление таблицы
+DROP TABLE IF EXISTS public_links_archive;
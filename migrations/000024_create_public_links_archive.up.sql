@@ -0,0 +1,21 @@
+-- Архив истёкших публичных ссылок, куда их переносит link-expirer
+CREATE TABLE IF NOT EXISTS public_links_archive (
+    id UUID PRIMARY KEY,
+    file_id UUID NOT NULL,
+    created_by_user_id UUID NOT NULL,
+    protected BOOLEAN NOT NULL DEFAULT FALSE,
+    max_downloads INT NULL,
+    download_count INT NOT NULL DEFAULT 0,
+
+    -- Временные метки
+    created_at TIMESTAMP NOT NULL,
+    expired_at TIMESTAMP NOT NULL,
+    archived_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Выборка архива по файлу
+CREATE INDEX idx_public_links_archive_file_id ON public_links_archive(file_id);
+
+-- Комментарии для документации
+COMMENT ON TABLE public_links_archive IS 'Истёкшие публичные ссылки без токена и пароля; внешних ключей нет, архив переживает файл';
+COMMENT ON COLUMN public_links_archive.protected IS 'Ссылка была защищена паролем';
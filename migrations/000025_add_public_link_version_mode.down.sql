@@ -0,0 +1,6 @@
+-- Удаление колонок
+ALTER TABLE public_links
+DROP CONSTRAINT IF EXISTS fk_public_links_version_id,
+DROP CONSTRAINT IF EXISTS chk_public_links_version_mode,
+DROP COLUMN IF EXISTS version_id,
+DROP COLUMN IF EXISTS version_mode;
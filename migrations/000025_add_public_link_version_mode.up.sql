@@ -0,0 +1,12 @@
+-- Какую версию файла отдаёт публичная ссылка
+ALTER TABLE public_links
+ADD COLUMN version_mode VARCHAR(10) NOT NULL DEFAULT 'current',
+ADD COLUMN version_id UUID NULL,
+ADD CONSTRAINT chk_public_links_version_mode CHECK (version_mode IN ('current', 'latest', 'pinned')),
+ADD CONSTRAINT fk_public_links_version_id
+    FOREIGN KEY (version_id)
+    REFERENCES file_versions(id)
+    ON DELETE SET NULL;
+
+COMMENT ON COLUMN public_links.version_mode IS 'current — текущая версия файла, latest — новейшая готовая, pinned — версия version_id';
+COMMENT ON COLUMN public_links.version_id IS 'Закреплённая версия (NULL у pinned — версия удалена, ссылка больше не отдаёт файл)';
@@ -0,0 +1,13 @@
+-- Версии и файлы без сессии появляются только из ссылок для загрузки, без NOT NULL им не остаться
+DELETE FROM file_versions WHERE uploaded_by_session_id IS NULL;
+DELETE FROM files WHERE uploaded_by_session_id IS NULL;
+
+ALTER TABLE file_versions
+DROP CONSTRAINT IF EXISTS fk_file_versions_drop_link_id,
+DROP COLUMN IF EXISTS drop_link_id,
+ALTER COLUMN uploaded_by_session_id SET NOT NULL;
+
+ALTER TABLE files
+ALTER COLUMN uploaded_by_session_id SET NOT NULL;
+
+DROP TABLE IF EXISTS drop_links;
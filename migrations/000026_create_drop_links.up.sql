@@ -0,0 +1,72 @@
+-- Ссылки для загрузки файлов без аккаунта
+CREATE TABLE IF NOT EXISTS drop_links (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id UUID NOT NULL,
+
+    -- Токен для доступа
+    token_hash VARCHAR(255) NOT NULL UNIQUE,
+
+    -- Куда попадают загрузки: папка (NULL — корень) или версии одного файла
+    folder_id UUID NULL,
+    file_id UUID NULL,
+
+    -- Ограничения
+    max_files INT NULL,
+    max_total_size BIGINT NULL,
+    allowed_mimes TEXT[] NOT NULL DEFAULT '{}',
+
+    -- Счётчики принятых загрузок
+    file_count INT NOT NULL DEFAULT 0,
+    total_size BIGINT NOT NULL DEFAULT 0,
+
+    -- Временные метки
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expired_at TIMESTAMP NOT NULL,
+
+    -- Внешние ключи
+    CONSTRAINT fk_drop_links_owner_id
+        FOREIGN KEY (owner_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_drop_links_folder_id
+        FOREIGN KEY (folder_id)
+        REFERENCES folders(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_drop_links_file_id
+        FOREIGN KEY (file_id)
+        REFERENCES files(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT chk_drop_links_target CHECK (folder_id IS NULL OR file_id IS NULL),
+    CONSTRAINT chk_drop_links_max_files CHECK (max_files IS NULL OR max_files > 0),
+    CONSTRAINT chk_drop_links_max_total_size CHECK (max_total_size IS NULL OR max_total_size > 0)
+);
+
+CREATE INDEX idx_drop_links_owner_created ON drop_links(owner_id, created_at DESC);
+CREATE INDEX idx_drop_links_expired_at ON drop_links(expired_at);
+
+COMMENT ON TABLE drop_links IS 'Ссылки, по которым можно загрузить файлы владельцу без аккаунта';
+COMMENT ON COLUMN drop_links.folder_id IS 'Папка для новых файлов (NULL вместе с file_id — корень владельца)';
+COMMENT ON COLUMN drop_links.file_id IS 'Файл, в который загрузки попадают новыми версиями';
+COMMENT ON COLUMN drop_links.allowed_mimes IS 'Разрешённые MIME типы, type/* — весь тип; пустой массив — любые';
+COMMENT ON COLUMN drop_links.file_count IS 'Сколько загрузок принято по ссылке';
+COMMENT ON COLUMN drop_links.total_size IS 'Заявленный суммарный размер принятых загрузок в байтах';
+
+-- Загрузка по ссылке не привязана к сессии, вместо неё версия помнит ссылку
+ALTER TABLE files
+ALTER COLUMN uploaded_by_session_id DROP NOT NULL;
+
+ALTER TABLE file_versions
+ALTER COLUMN uploaded_by_session_id DROP NOT NULL,
+ADD COLUMN drop_link_id UUID NULL,
+ADD CONSTRAINT fk_file_versions_drop_link_id
+    FOREIGN KEY (drop_link_id)
+    REFERENCES drop_links(id)
+    ON DELETE SET NULL;
+
+CREATE INDEX idx_file_versions_drop_link_id ON file_versions(drop_link_id);
+
+COMMENT ON COLUMN file_versions.drop_link_id IS 'Ссылка для загрузки, через которую пришла версия (NULL — загрузка из сессии или ссылку удалили)';
@@ -0,0 +1,2 @@
+ALTER TABLE files
+DROP COLUMN IF EXISTS signed_url_epoch;
@@ -0,0 +1,5 @@
+-- Эпоха отзыва подписанных URL файла: URL, выданные не позже неё, не действуют
+ALTER TABLE files
+ADD COLUMN signed_url_epoch BIGINT NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN files.signed_url_epoch IS 'Unix-время последнего отзыва подписанных URL (0 — не отзывались)';
@@ -0,0 +1,5 @@
+-- Хеши не обратить: прежний код искал бы по самим токенам, поэтому выданные токены гасим
+UPDATE sessions SET is_revoked = TRUE, updated_at = NOW() WHERE NOT is_revoked;
+UPDATE magic_links SET expired_at = LEAST(expired_at, NOW()), updated_at = NOW();
+UPDATE public_links SET expired_at = LEAST(expired_at, NOW()), updated_at = NOW();
+UPDATE drop_links SET expired_at = LEAST(expired_at, NOW()), updated_at = NOW();
@@ -0,0 +1,30 @@
+-- В колонках token_hash теперь только SHA-256 токена в hex. До этого там лежали сами токены:
+-- хешируем их на месте, чтобы в БД не осталось рабочих значений. Выданные раньше токены
+-- продолжают действовать льготный срок, после чего сессии и ссылки истекают и выдаются заново.
+UPDATE sessions
+SET token_hash = encode(sha256(convert_to(token_hash, 'UTF8')), 'hex'),
+    refresh_token_hash = encode(sha256(convert_to(refresh_token_hash, 'UTF8')), 'hex'),
+    expired_at = LEAST(expired_at, NOW() + INTERVAL '1 day'),
+    updated_at = NOW();
+
+-- Магические ссылки живут минуты, льготный срок им не нужен
+UPDATE magic_links
+SET token_hash = encode(sha256(convert_to(token_hash, 'UTF8')), 'hex'),
+    expired_at = LEAST(expired_at, NOW()),
+    updated_at = NOW();
+
+UPDATE public_links
+SET token_hash = encode(sha256(convert_to(token_hash, 'UTF8')), 'hex'),
+    expired_at = LEAST(expired_at, NOW() + INTERVAL '7 days'),
+    updated_at = NOW();
+
+UPDATE drop_links
+SET token_hash = encode(sha256(convert_to(token_hash, 'UTF8')), 'hex'),
+    expired_at = LEAST(expired_at, NOW() + INTERVAL '7 days'),
+    updated_at = NOW();
+
+COMMENT ON COLUMN sessions.token_hash IS 'SHA-256 токена доступа в hex; сам токен не хранится';
+COMMENT ON COLUMN sessions.refresh_token_hash IS 'SHA-256 refresh токена в hex; сам токен не хранится';
+COMMENT ON COLUMN magic_links.token_hash IS 'SHA-256 токена магической ссылки в hex; сам токен не хранится';
+COMMENT ON COLUMN public_links.token_hash IS 'SHA-256 токена публичной ссылки в hex; сам токен не хранится';
+COMMENT ON COLUMN drop_links.token_hash IS 'SHA-256 токена ссылки для загрузки в hex; сам токен не хранится';
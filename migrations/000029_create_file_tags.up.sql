@@ -0,0 +1,19 @@
+-- Метки файлов; набор меток у файла без повторов
+CREATE TABLE IF NOT EXISTS file_tags (
+    file_id UUID NOT NULL,
+    tag VARCHAR(64) NOT NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (file_id, tag),
+
+    CONSTRAINT fk_file_tags_file_id
+        FOREIGN KEY (file_id)
+        REFERENCES files(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_file_tags_tag ON file_tags(tag);
+
+COMMENT ON TABLE file_tags IS 'Метки, которые пользователи ставят на файлы';
+COMMENT ON COLUMN file_tags.tag IS 'Метка в нижнем регистре без пробелов по краям';